@@ -1,7 +1,8 @@
 package handler
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"net/http"
 	"sync"
 	"strings"
@@ -14,7 +15,12 @@ import (
 	"pansou/plugin"
 	jsonutil "pansou/util/json"
 	"pansou/util"
+	"pansou/util/auth"
 	"pansou/util/cache"
+	"pansou/util/export"
+	"pansou/util/logger"
+	"pansou/util/query"
+	"pansou/util/ranking"
 
 	// 导入所有插件以触发init函数自动注册
 	_ "pansou/plugin/hunhepan"
@@ -67,22 +73,17 @@ var (
 	app           *gin.Engine
 )
 
-// CORSMiddleware CORS中间件
-func corsMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-		c.Header("Access-Control-Allow-Origin", origin)
-		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		c.Header("Access-Control-Allow-Credentials", "true")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
+// apiKeyMiddleware 加载API密钥并返回鉴权中间件
+// 密钥配置错误时拒绝所有搜索请求，避免实例在无鉴权状态下对外开放
+func apiKeyMiddleware() gin.HandlerFunc {
+	store, err := auth.LoadKeyStore(config.AppConfig.APIKeys, config.AppConfig.APIKeysFile)
+	if err != nil {
+		logger.Error(context.Background(), "加载API密钥失败", "error", err)
+		return func(c *gin.Context) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, model.NewErrorResponse(500, "API密钥配置错误"))
 		}
-
-		c.Next()
 	}
+	return auth.APIKeyMiddleware(store)
 }
 
 // Handler 是 Vercel 的入口函数
@@ -91,16 +92,19 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 		// 初始化配置
 		config.Init()
 
+		// 初始化日志
+		logger.Init(config.AppConfig.LogLevel, config.AppConfig.LogFormat, config.AppConfig.PluginLogLevels)
+
 		// 初始化HTTP客户端（插件需要）
 		util.InitHTTPClient()
 
 		// 初始化缓存写入管理器
 		globalCacheWriteManager, err := cache.NewDelayedBatchWriteManager()
 		if err != nil {
-			fmt.Printf("⚠️ 缓存写入管理器创建失败: %v\n", err)
+			logger.Warn(context.Background(), "缓存写入管理器创建失败", "error", err)
 		} else {
 			if err := globalCacheWriteManager.Initialize(); err != nil {
-				fmt.Printf("⚠️ 缓存写入管理器初始化失败: %v\n", err)
+				logger.Warn(context.Background(), "缓存写入管理器初始化失败", "error", err)
 			} else {
 				// 将缓存写入管理器注入到service包
 				service.SetGlobalCacheWriteManager(globalCacheWriteManager)
@@ -127,9 +131,9 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 		// 注册全局插件（根据配置过滤）
 		if config.AppConfig.AsyncPluginEnabled {
 			pluginManager.RegisterGlobalPluginsWithFilter(config.AppConfig.EnabledPlugins)
-			fmt.Printf("✅ 已注册 %d 个搜索插件\n", len(pluginManager.GetPlugins()))
+			logger.Info(context.Background(), "已注册搜索插件", "count", len(pluginManager.GetPlugins()))
 		} else {
-			fmt.Println("⚠️ 异步插件已禁用")
+			logger.Warn(context.Background(), "异步插件已禁用")
 		}
 
 		// 创建搜索服务
@@ -141,11 +145,12 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 
 		// 添加中间件
 		app.Use(gin.Recovery())
-		app.Use(corsMiddleware())
+		app.Use(auth.CORSMiddleware(config.AppConfig.CORSAllowedOrigins))
 
-		// 设置路由
-		app.GET("/api/search", searchHandler)
-		app.POST("/api/search", searchHandler)
+		// 设置路由，搜索接口需要API密钥（未配置密钥时不校验）
+		search := app.Group("/api", apiKeyMiddleware())
+		search.GET("/search", searchHandler)
+		search.POST("/search", searchHandler)
 
 		// 根路径返回简单的HTML
 		app.GET("/", func(c *gin.Context) {
@@ -184,121 +189,10 @@ func searchHandler(c *gin.Context) {
 	// 根据请求方法不同处理参数
 	if c.Request.Method == http.MethodGet {
 		// GET方式：从URL参数获取
-		// 获取keyword，必填参数
-		// 兼容两种参数名
-keyword := c.Query("kw")
-if keyword == "" {
-    keyword = c.Query("keyword")  // 添加这行兼容前端
-}
-		
-		// 处理channels参数，支持逗号分隔
-		channelsStr := c.Query("channels")
-		var channels []string
-		// 只有当参数非空时才处理
-		if channelsStr != "" && channelsStr != " " {
-			parts := strings.Split(channelsStr, ",")
-			for _, part := range parts {
-				trimmed := strings.TrimSpace(part)
-				if trimmed != "" {
-					channels = append(channels, trimmed)
-				}
-			}
-		}
-		
-		// 处理并发数
-		concurrency := 0
-		concStr := c.Query("conc")
-		if concStr != "" && concStr != " " {
-			concurrency = util.StringToInt(concStr)
-		}
-		
-		// 处理强制刷新
-		forceRefresh := false
-		refreshStr := c.Query("refresh")
-		if refreshStr != "" && refreshStr != " " && refreshStr == "true" {
-			forceRefresh = true
-		}
-		
-		// 处理结果类型和来源类型
-		resultType := c.Query("res")
-		if resultType == "" || resultType == " " {
-			resultType = "merge" // 直接设置为默认值merge
-		}
-		
-		sourceType := c.Query("src")
-		if sourceType == "" || sourceType == " " {
-			sourceType = "all" // 直接设置为默认值all
-		}
-		
-		// 处理plugins参数，支持逗号分隔
-		var plugins []string
-		// 检查请求中是否存在plugins参数
-		if c.Request.URL.Query().Has("plugins") {
-			pluginsStr := c.Query("plugins")
-			// 判断参数是否非空
-			if pluginsStr != "" && pluginsStr != " " {
-				parts := strings.Split(pluginsStr, ",")
-				for _, part := range parts {
-					trimmed := strings.TrimSpace(part)
-					if trimmed != "" {
-						plugins = append(plugins, trimmed)
-					}
-				}
-			}
-		} else {
-			// 如果请求中不存在plugins参数，设置为nil
-			plugins = nil
-		}
-		
-		// 处理cloud_types参数，支持逗号分隔
-		var cloudTypes []string
-		// 检查请求中是否存在cloud_types参数
-		if c.Request.URL.Query().Has("cloud_types") {
-			cloudTypesStr := c.Query("cloud_types")
-			// 判断参数是否非空
-			if cloudTypesStr != "" && cloudTypesStr != " " {
-				parts := strings.Split(cloudTypesStr, ",")
-				for _, part := range parts {
-					trimmed := strings.TrimSpace(part)
-					if trimmed != "" {
-						cloudTypes = append(cloudTypes, trimmed)
-					}
-				}
-			}
-		} else {
-			// 如果请求中不存在cloud_types参数，设置为nil
-			cloudTypes = nil
-		}
-		
-		// 处理ext参数，JSON格式
-		var ext map[string]interface{}
-		extStr := c.Query("ext")
-		if extStr != "" && extStr != " " {
-			// 处理特殊情况：ext={}
-			if extStr == "{}" {
-				ext = make(map[string]interface{})
-			} else {
-				if err := jsonutil.Unmarshal([]byte(extStr), &ext); err != nil {
-					c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的ext参数格式: "+err.Error()))
-					return
-				}
-			}
-		}
-		// 确保ext不为nil
-		if ext == nil {
-			ext = make(map[string]interface{})
-		}
-
-		req = model.SearchRequest{
-			Keyword:      keyword,
-			Channels:     channels,
-			Concurrency:  concurrency,
-			ForceRefresh: forceRefresh,
-			ResultType:   resultType,
-			SourceType:   sourceType,
-			Plugins:      plugins,
-			CloudTypes:   cloudTypes, // 添加cloud_types到请求中
-			Ext:          ext,
+		req, err = query.ParseRequest(c.Request.URL.Query())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+			return
 		}
 	} else {
 		// POST方式：从请求体获取
@@ -314,6 +208,33 @@ if keyword == "" {
 		}
 	}
 	
+	// 解析关键词中的查询语法，只保留正向关键词用于搜索
+	if err := query.Apply(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	// 检查排序方式
+	if !ranking.IsValidSort(req.Sort) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的sort参数，支持relevance、time、source"))
+		return
+	}
+
+	// 检查分组方式
+	if !service.IsValidGroup(req.Group) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的group参数，支持type、work"))
+		return
+	}
+
+	// 检查响应格式，未指定时按Accept头选择
+	if req.Format = strings.ToLower(strings.TrimSpace(req.Format)); req.Format == "" {
+		req.Format = export.Negotiate(c.GetHeader("Accept"))
+	}
+	if !export.IsValidFormat(req.Format) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的format参数，支持json、csv、jsonl、rss、atom"))
+		return
+	}
+
 	// 检查并设置默认值
 	if len(req.Channels) == 0 {
 		req.Channels = config.AppConfig.DefaultChannels
@@ -343,14 +264,38 @@ if keyword == "" {
 			req.Plugins = nil
 		}
 	}
-	
-	// 启用调试输出
-	fmt.Printf("🔧 [调试] 搜索参数: keyword=%s, channels=%v, concurrency=%d, refresh=%v, resultType=%s, sourceType=%s, plugins=%v, cloudTypes=%v\n",
-		req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes)
 
-	// 执行搜索
-	result, err := searchService.Search(req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext)
+	// 按插件声明的参数校验ext
+	ext, err := searchService.ValidateExt(req.SourceType, req.Plugins, req.Ext)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+	req.Ext = ext
+
+	// 为本次请求生成请求ID，贯穿服务和插件日志
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" || len(requestID) > 64 {
+		requestID = logger.NewRequestID()
+	}
+	c.Header("X-Request-ID", requestID)
+	ctx := logger.WithRequestID(c.Request.Context(), requestID)
+
+	logger.Debug(ctx, "搜索参数", "keyword", req.Keyword, "channels", req.Channels, "concurrency", req.Concurrency,
+		"refresh", req.ForceRefresh, "res", req.ResultType, "src", req.SourceType, "plugins", req.Plugins, "cloud_types", req.CloudTypes)
+
+	// 执行搜索，请求分页时后续页直接从第一页的结果快照中读取
+	var result model.SearchResponse
+	if req.Page > 0 || req.PageSize > 0 || req.Cursor != "" {
+		result, err = searchService.SearchPage(ctx, req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext, req.ResultOptions(), req.Page, req.PageSize, req.Cursor)
+	} else {
+		result, err = searchService.SearchWithContext(ctx, req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext, req.ResultOptions())
+	}
 	
+	if errors.Is(err, service.ErrInvalidCursor) || errors.Is(err, service.ErrInvalidPage) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
 	if err != nil {
 		response := model.NewErrorResponse(500, "搜索失败: "+err.Error())
 		jsonData, _ := jsonutil.Marshal(response)
@@ -358,6 +303,26 @@ if keyword == "" {
 		return
 	}
 
+	// 检测链接有效性
+	if req.CheckLinks {
+		searchService.CheckLinks(ctx, &result, req.DropDead)
+	}
+
+	// 按作品分组
+	if req.Group == service.GroupByWork {
+		service.GroupResponseByWork(&result)
+	}
+
+	// 按导出格式逐条写出
+	if req.Format != export.FormatJSON {
+		c.Header("Content-Type", export.ContentType(req.Format))
+		c.Status(http.StatusOK)
+		if err := export.Write(c.Writer, req.Format, result, export.NewFeedInfo(c.Request, req.Keyword)); err != nil {
+			logger.Warn(ctx, "导出搜索结果失败", "format", req.Format, "error", err)
+		}
+		return
+	}
+
 	// 返回结果
 	response := model.NewSuccessResponse(result)
 	jsonData, _ := jsonutil.Marshal(response)
@@ -40,11 +40,63 @@ type Config struct {
 	AsyncMaxBackgroundTasks   int           // 最大后台任务数量
 	AsyncCacheTTLHours        int           // 异步缓存有效期（小时）
 	AsyncLogEnabled           bool          // 是否启用异步插件详细日志
+	PluginSitesDir            string        // 声明式站点插件定义目录，为空时不加载
+	PluginSitesReloadInterval time.Duration // 站点定义热加载检查周期，0表示不热加载
 	// HTTP服务器配置
 	HTTPReadTimeout  time.Duration // 读取超时
 	HTTPWriteTimeout time.Duration // 写入超时
 	HTTPIdleTimeout  time.Duration // 空闲超时
 	HTTPMaxConns     int           // 最大连接数
+	// 分页相关配置
+	DefaultPageSize int // 默认每页数量
+	MaxPageSize     int // 最大每页数量
+	// 插件熔断相关配置
+	CircuitBreakerEnabled   bool          // 是否启用插件熔断
+	CircuitBreakerThreshold int           // 连续失败多少次后熔断
+	CircuitBreakerCooldown  time.Duration // 熔断后多久放行探测请求
+	// 管理接口配置
+	AdminToken string // 管理接口访问令牌，为空时禁用管理接口
+	
+	// API密钥鉴权配置
+	APIKeys            string   // API密钥列表，格式 key[:每日配额[:每秒请求数]]，逗号分隔
+	APIKeysFile        string   // API密钥JSON文件路径
+	CORSAllowedOrigins []string // 允许跨域访问的来源白名单，为空时允许任意来源
+	
+	// 限流配置
+	RateLimitEnabled          bool     // 是否启用搜索接口限流
+	RateLimitPerMinute        float64  // 每个客户端IP每分钟允许的普通搜索次数
+	RateLimitBurst            int      // 普通搜索允许的突发请求数
+	RefreshRateLimitPerMinute float64  // 每个客户端IP每分钟允许的强制刷新搜索次数
+	RefreshRateLimitBurst     int      // 强制刷新搜索允许的突发请求数
+	RateLimitByKeyword        bool     // 是否同时按关键词限制强制刷新（所有客户端共享）
+	TrustedProxies            []string // 受信任的反向代理，只有来自这些地址的X-Forwarded-For/X-Real-IP才会被采用
+	
+	// 监控配置
+	MetricsEnabled bool // 是否开放/metrics指标接口
+	MCPEnabled     bool // 是否开放/mcp接口（Streamable HTTP方式的MCP服务）
+	
+	// 链接有效性检测配置
+	LinkCheckEnabled     bool          // 是否允许搜索请求通过check_links检测链接有效性
+	LinkCheckTTL         time.Duration // 检测结果缓存时间
+	LinkCheckTimeout     time.Duration // 单个链接的检测超时时间
+	LinkCheckConcurrency int           // 单次请求的并发检测数
+	LinkCheckMaxLinks    int           // 单次请求最多检测的链接数
+	// 后台链接复检配置
+	LinkRevalidateEnabled     bool          // 是否启用后台链接复检
+	LinkRevalidateInterval    time.Duration // 复检周期，判定结果超过该时间的链接会被重新检测
+	LinkRevalidateMaxLinks    int           // 每轮最多复检的链接数
+	LinkRevalidateConcurrency int           // 每种网盘类型的并发检测数
+	// 排序权重配置，权重为0时不计算该分量
+	RankWeightTime    float64 // 发布时间分量权重
+	RankWeightKeyword float64 // 优先关键词分量权重
+	RankWeightPlugin  float64 // 插件等级分量权重
+	RankWeightTitle   float64 // 标题匹配分量权重
+	RankWeightQuality float64 // 画质分量权重
+	
+	// 日志配置
+	LogLevel        string            // 全局日志级别：debug/info/warn/error
+	LogFormat       string            // 日志格式：text或json
+	PluginLogLevels map[string]string // 按插件设置的日志级别，覆盖全局级别
 
 }
 
@@ -86,11 +138,51 @@ func Init() {
 		AsyncMaxBackgroundTasks:   getAsyncMaxBackgroundTasks(),
 		AsyncCacheTTLHours:        getAsyncCacheTTLHours(),
 		AsyncLogEnabled:           getAsyncLogEnabled(),
+		PluginSitesDir:            getPluginSitesDir(),
+		PluginSitesReloadInterval: getPluginSitesReloadInterval(),
 		// HTTP服务器配置
 		HTTPReadTimeout:  getHTTPReadTimeout(),
 		HTTPWriteTimeout: getHTTPWriteTimeout(),
 		HTTPIdleTimeout:  getHTTPIdleTimeout(),
 		HTTPMaxConns:     getHTTPMaxConns(),
+		// 分页相关配置
+		DefaultPageSize: getDefaultPageSize(),
+		MaxPageSize:     getMaxPageSize(),
+		// 插件熔断相关配置
+		CircuitBreakerEnabled:   getCircuitBreakerEnabled(),
+		CircuitBreakerThreshold: getCircuitBreakerThreshold(),
+		CircuitBreakerCooldown:  getCircuitBreakerCooldown(),
+		// 管理接口配置
+		AdminToken: getAdminToken(),
+		APIKeys:            getAPIKeys(),
+		APIKeysFile:        getAPIKeysFile(),
+		CORSAllowedOrigins: getCORSAllowedOrigins(),
+		RateLimitEnabled:          getRateLimitEnabled(),
+		RateLimitPerMinute:        getRateLimitPerMinute("RATE_LIMIT_PER_MINUTE", 60),
+		RateLimitBurst:            getRateLimitBurst("RATE_LIMIT_BURST", 20),
+		RefreshRateLimitPerMinute: getRateLimitPerMinute("REFRESH_RATE_LIMIT_PER_MINUTE", 6),
+		RefreshRateLimitBurst:     getRateLimitBurst("REFRESH_RATE_LIMIT_BURST", 2),
+		RateLimitByKeyword:        getRateLimitByKeyword(),
+		TrustedProxies:            getTrustedProxies(),
+		MetricsEnabled:            getMetricsEnabled(),
+		MCPEnabled:                getMCPEnabled(),
+		LinkCheckEnabled:          getLinkCheckEnabled(),
+		LinkCheckTTL:              getLinkCheckTTL(),
+		LinkCheckTimeout:          getLinkCheckTimeout(),
+		LinkCheckConcurrency:      getLinkCheckConcurrency(),
+		LinkCheckMaxLinks:         getLinkCheckMaxLinks(),
+		LinkRevalidateEnabled:     getLinkRevalidateEnabled(),
+		LinkRevalidateInterval:    getLinkRevalidateInterval(),
+		LinkRevalidateMaxLinks:    getLinkRevalidateMaxLinks(),
+		LinkRevalidateConcurrency: getLinkRevalidateConcurrency(),
+		RankWeightTime:            getRankWeight("RANK_WEIGHT_TIME"),
+		RankWeightKeyword:         getRankWeight("RANK_WEIGHT_KEYWORD"),
+		RankWeightPlugin:          getRankWeight("RANK_WEIGHT_PLUGIN"),
+		RankWeightTitle:           getRankWeight("RANK_WEIGHT_TITLE"),
+		RankWeightQuality:         getRankWeight("RANK_WEIGHT_QUALITY"),
+		LogLevel:                  getLogLevel(),
+		LogFormat:                 getLogFormat(),
+		PluginLogLevels:           getPluginLogLevels(),
 
 	}
 	
@@ -333,6 +425,24 @@ func getEnabledPlugins() []string {
 	return result
 }
 
+// 从环境变量获取声明式站点插件定义目录
+func getPluginSitesDir() string {
+	return strings.TrimSpace(os.Getenv("PLUGIN_SITES_DIR"))
+}
+
+// 从环境变量获取站点定义热加载检查周期（秒），如果未设置则使用默认值，设置为0时不热加载
+func getPluginSitesReloadInterval() time.Duration {
+	intervalEnv := os.Getenv("PLUGIN_SITES_RELOAD_INTERVAL")
+	if intervalEnv == "" {
+		return 30 * time.Second
+	}
+	interval, err := strconv.Atoi(intervalEnv)
+	if err != nil || interval < 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(interval) * time.Second
+}
+
 // 从环境变量获取异步响应超时时间（秒），如果未设置则使用默认值
 func getAsyncResponseTimeout() int {
 	timeoutEnv := os.Getenv("ASYNC_RESPONSE_TIMEOUT")
@@ -506,6 +616,352 @@ func getAsyncLogEnabled() bool {
 	return enabled
 }
 
+// 从环境变量获取默认每页数量，如果未设置则使用默认值
+func getDefaultPageSize() int {
+	sizeEnv := os.Getenv("DEFAULT_PAGE_SIZE")
+	if sizeEnv == "" {
+		return 20 // 默认每页20条
+	}
+	size, err := strconv.Atoi(sizeEnv)
+	if err != nil || size <= 0 {
+		return 20
+	}
+	return size
+}
+
+// 从环境变量获取最大每页数量，如果未设置则使用默认值
+func getMaxPageSize() int {
+	sizeEnv := os.Getenv("MAX_PAGE_SIZE")
+	if sizeEnv == "" {
+		return 100 // 默认最多每页100条
+	}
+	size, err := strconv.Atoi(sizeEnv)
+	if err != nil || size <= 0 {
+		return 100
+	}
+	return size
+}
+
+// 从环境变量获取插件熔断开关，如果未设置则默认启用
+func getCircuitBreakerEnabled() bool {
+	enabledEnv := os.Getenv("CIRCUIT_BREAKER_ENABLED")
+	if enabledEnv == "" {
+		return true
+	}
+	enabled, err := strconv.ParseBool(enabledEnv)
+	if err != nil {
+		return true // 解析失败时默认启用
+	}
+	return enabled
+}
+
+// 从环境变量获取熔断失败阈值，如果未设置则使用默认值
+func getCircuitBreakerThreshold() int {
+	thresholdEnv := os.Getenv("CIRCUIT_BREAKER_THRESHOLD")
+	if thresholdEnv == "" {
+		return 5 // 默认连续失败5次熔断
+	}
+	threshold, err := strconv.Atoi(thresholdEnv)
+	if err != nil || threshold <= 0 {
+		return 5
+	}
+	return threshold
+}
+
+// 从环境变量获取熔断冷却时间，如果未设置则使用默认值
+func getCircuitBreakerCooldown() time.Duration {
+	cooldownEnv := os.Getenv("CIRCUIT_BREAKER_COOLDOWN")
+	if cooldownEnv == "" {
+		return 60 * time.Second // 默认60秒后探测
+	}
+	cooldown, err := strconv.Atoi(cooldownEnv)
+	if err != nil || cooldown <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(cooldown) * time.Second
+}
+
+// 从环境变量获取管理接口访问令牌，未设置时禁用管理接口
+func getAdminToken() string {
+	return strings.TrimSpace(os.Getenv("ADMIN_TOKEN"))
+}
+
+// 从环境变量获取API密钥列表
+func getAPIKeys() string {
+	return strings.TrimSpace(os.Getenv("API_KEYS"))
+}
+
+// 从环境变量获取API密钥文件路径
+func getAPIKeysFile() string {
+	return strings.TrimSpace(os.Getenv("API_KEYS_FILE"))
+}
+
+// 从环境变量获取是否启用限流，默认不启用
+// 部署在非本机反向代理之后时需要先配置TRUSTED_PROXIES，否则所有用户共用代理IP的额度
+func getRateLimitEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("RATE_LIMIT_ENABLED"))
+	if err != nil {
+		return false
+	}
+	return enabled
+}
+
+// 从环境变量获取每分钟允许的请求数，如果未设置则使用默认值
+func getRateLimitPerMinute(name string, defaultValue float64) float64 {
+	rateEnv := os.Getenv(name)
+	if rateEnv == "" {
+		return defaultValue
+	}
+	rate, err := strconv.ParseFloat(rateEnv, 64)
+	if err != nil || rate <= 0 {
+		return defaultValue
+	}
+	return rate
+}
+
+// 从环境变量获取允许的突发请求数，如果未设置则使用默认值
+func getRateLimitBurst(name string, defaultValue int) int {
+	burstEnv := os.Getenv(name)
+	if burstEnv == "" {
+		return defaultValue
+	}
+	burst, err := strconv.Atoi(burstEnv)
+	if err != nil || burst <= 0 {
+		return defaultValue
+	}
+	return burst
+}
+
+// 从环境变量获取是否按关键词限制强制刷新，默认不启用
+func getRateLimitByKeyword() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("RATE_LIMIT_BY_KEYWORD"))
+	if err != nil {
+		return false
+	}
+	return enabled
+}
+
+// 从环境变量获取受信任的反向代理列表，未设置时只信任本机地址
+// 内网中的其他主机同样可能伪造X-Forwarded-For，代理部署在其他主机或容器中时需显式配置
+func getTrustedProxies() []string {
+	proxiesEnv := strings.TrimSpace(os.Getenv("TRUSTED_PROXIES"))
+	if proxiesEnv == "" {
+		return []string{"127.0.0.1", "::1"}
+	}
+	
+	// none表示不信任任何代理，直接使用连接的远端地址
+	if proxiesEnv == "none" {
+		return []string{}
+	}
+	
+	var proxies []string
+	for _, proxy := range strings.Split(proxiesEnv, ",") {
+		if trimmed := strings.TrimSpace(proxy); trimmed != "" {
+			proxies = append(proxies, trimmed)
+		}
+	}
+	return proxies
+}
+
+// 从环境变量获取是否开放指标接口，默认不开放
+// 指标包含插件名称、错误率和缓存统计，接口不经过鉴权，需要时显式开启并限制访问来源
+func getMetricsEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("METRICS_ENABLED"))
+	if err != nil {
+		return false
+	}
+	return enabled
+}
+
+// 从环境变量获取是否开放MCP接口，默认开放
+func getMCPEnabled() bool {
+	enabledEnv := os.Getenv("MCP_ENABLED")
+	if enabledEnv == "" {
+		return true
+	}
+	enabled, err := strconv.ParseBool(enabledEnv)
+	if err != nil {
+		return true // 解析失败时默认开放
+	}
+	return enabled
+}
+
+// 从环境变量获取是否允许检测链接有效性，默认允许
+func getLinkCheckEnabled() bool {
+	enabledEnv := os.Getenv("LINK_CHECK_ENABLED")
+	if enabledEnv == "" {
+		return true
+	}
+	enabled, err := strconv.ParseBool(enabledEnv)
+	if err != nil {
+		return true // 解析失败时默认允许
+	}
+	return enabled
+}
+
+// 从环境变量获取链接检测结果缓存时间（分钟），如果未设置则使用默认值
+func getLinkCheckTTL() time.Duration {
+	ttlEnv := os.Getenv("LINK_CHECK_TTL")
+	if ttlEnv == "" {
+		return 360 * time.Minute // 默认缓存6小时
+	}
+	ttl, err := strconv.Atoi(ttlEnv)
+	if err != nil || ttl <= 0 {
+		return 360 * time.Minute
+	}
+	return time.Duration(ttl) * time.Minute
+}
+
+// 从环境变量获取单个链接的检测超时时间（秒），如果未设置则使用默认值
+func getLinkCheckTimeout() time.Duration {
+	timeoutEnv := os.Getenv("LINK_CHECK_TIMEOUT")
+	if timeoutEnv == "" {
+		return 5 * time.Second
+	}
+	timeout, err := strconv.Atoi(timeoutEnv)
+	if err != nil || timeout <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// 从环境变量获取单次请求的并发检测数，如果未设置则使用默认值
+func getLinkCheckConcurrency() int {
+	concurrencyEnv := os.Getenv("LINK_CHECK_CONCURRENCY")
+	if concurrencyEnv == "" {
+		return 10
+	}
+	concurrency, err := strconv.Atoi(concurrencyEnv)
+	if err != nil || concurrency <= 0 {
+		return 10
+	}
+	return concurrency
+}
+
+// 从环境变量获取单次请求最多检测的链接数，如果未设置则使用默认值
+func getLinkCheckMaxLinks() int {
+	maxEnv := os.Getenv("LINK_CHECK_MAX_LINKS")
+	if maxEnv == "" {
+		return 100
+	}
+	maxLinks, err := strconv.Atoi(maxEnv)
+	if err != nil || maxLinks <= 0 {
+		return 100
+	}
+	return maxLinks
+}
+
+// 从环境变量获取是否启用后台链接复检，默认不启用
+func getLinkRevalidateEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("LINK_REVALIDATE_ENABLED"))
+	if err != nil {
+		return false
+	}
+	return enabled
+}
+
+// 从环境变量获取后台链接复检周期（分钟），如果未设置则使用默认值
+func getLinkRevalidateInterval() time.Duration {
+	intervalEnv := os.Getenv("LINK_REVALIDATE_INTERVAL")
+	if intervalEnv == "" {
+		return 30 * time.Minute
+	}
+	interval, err := strconv.Atoi(intervalEnv)
+	if err != nil || interval <= 0 {
+		return 30 * time.Minute
+	}
+	return time.Duration(interval) * time.Minute
+}
+
+// 从环境变量获取每轮最多复检的链接数，如果未设置则使用默认值
+func getLinkRevalidateMaxLinks() int {
+	maxEnv := os.Getenv("LINK_REVALIDATE_MAX_LINKS")
+	if maxEnv == "" {
+		return 500
+	}
+	maxLinks, err := strconv.Atoi(maxEnv)
+	if err != nil || maxLinks <= 0 {
+		return 500
+	}
+	return maxLinks
+}
+
+// 从环境变量获取后台复检时每种网盘类型的并发检测数，如果未设置则使用默认值
+func getLinkRevalidateConcurrency() int {
+	concurrencyEnv := os.Getenv("LINK_REVALIDATE_CONCURRENCY")
+	if concurrencyEnv == "" {
+		return 2
+	}
+	concurrency, err := strconv.Atoi(concurrencyEnv)
+	if err != nil || concurrency <= 0 {
+		return 2
+	}
+	return concurrency
+}
+
+// 从环境变量获取排序分量权重，如果未设置或无效则使用1
+func getRankWeight(name string) float64 {
+	weightEnv := os.Getenv(name)
+	if weightEnv == "" {
+		return 1
+	}
+	weight, err := strconv.ParseFloat(weightEnv, 64)
+	if err != nil || weight < 0 {
+		return 1
+	}
+	return weight
+}
+
+// 从环境变量获取全局日志级别，如果未设置则使用info
+func getLogLevel() string {
+	level := strings.ToLower(strings.TrimSpace(os.Getenv("LOG_LEVEL")))
+	if level == "" {
+		return "info"
+	}
+	return level
+}
+
+// 从环境变量获取日志格式，只支持text和json
+func getLogFormat() string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("LOG_FORMAT")), "json") {
+		return "json"
+	}
+	return "text"
+}
+
+// 从环境变量获取插件日志级别，格式为 插件名=级别，多个用逗号分隔
+func getPluginLogLevels() map[string]string {
+	levels := make(map[string]string)
+	for _, item := range strings.Split(os.Getenv("PLUGIN_LOG_LEVELS"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		level := strings.ToLower(strings.TrimSpace(parts[1]))
+		if name != "" && level != "" {
+			levels[name] = level
+		}
+	}
+	return levels
+}
+
+// 从环境变量获取跨域来源白名单
+func getCORSAllowedOrigins() []string {
+	originsEnv := os.Getenv("CORS_ALLOWED_ORIGINS")
+	if originsEnv == "" {
+		return nil
+	}
+	
+	var origins []string
+	for _, origin := range strings.Split(originsEnv, ",") {
+		if trimmed := strings.TrimSpace(origin); trimmed != "" {
+			origins = append(origins, trimmed)
+		}
+	}
+	return origins
+}
+
 // 应用GC设置
 func applyGCSettings() {
 	// 设置GC百分比
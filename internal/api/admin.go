@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pansou/model"
+	"pansou/service"
+	jsonutil "pansou/util/json"
+)
+
+// pluginUpdateRequest 插件更新请求，字段为nil表示不修改
+type pluginUpdateRequest struct {
+	Enabled  *bool `json:"enabled"`  // 启用或禁用插件
+	Priority *int  `json:"priority"` // 覆盖优先级(1-4)，0表示恢复插件内置优先级
+}
+
+// ListPluginsHandler 列出所有已编译注册的插件及其启用状态和优先级
+func ListPluginsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, model.NewSuccessResponse(searchService.ListPluginStatuses()))
+}
+
+// UpdatePluginHandler 运行时启用/禁用插件或覆盖插件优先级
+func UpdatePluginHandler(c *gin.Context) {
+	name := c.Param("name")
+
+	data, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "读取请求数据失败: "+err.Error()))
+		return
+	}
+
+	var req pluginUpdateRequest
+	if err := jsonutil.Unmarshal(data, &req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的请求参数: "+err.Error()))
+		return
+	}
+	if req.Enabled == nil && req.Priority == nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "enabled和priority至少指定一个"))
+		return
+	}
+	if req.Priority != nil && (*req.Priority < 0 || *req.Priority > 4) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "priority必须在1-4之间，0表示恢复默认"))
+		return
+	}
+
+	if req.Enabled != nil {
+		err = searchService.SetPluginEnabled(name, *req.Enabled)
+	}
+	if err == nil && req.Priority != nil {
+		err = searchService.SetPluginPriority(name, *req.Priority)
+	}
+	if errors.Is(err, service.ErrPluginNotFound) {
+		c.JSON(http.StatusNotFound, model.NewErrorResponse(404, "插件不存在: "+name))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.NewErrorResponse(500, err.Error()))
+		return
+	}
+
+	status, err := searchService.GetPluginStatus(name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.NewErrorResponse(404, "插件不存在: "+name))
+		return
+	}
+	c.JSON(http.StatusOK, model.NewSuccessResponse(status))
+}
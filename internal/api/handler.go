@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pansou/config"
+	"pansou/model"
+	"pansou/service"
+	"pansou/util/export"
+	jsonutil "pansou/util/json"
+	"pansou/util/logger"
+	"pansou/util/query"
+	"pansou/util/ranking"
+)
+
+// errInvalidSort 排序方式无效时的错误信息
+const errInvalidSort = "无效的sort参数，支持relevance、time、source"
+
+// errInvalidGroup 分组方式无效时的错误信息
+const errInvalidGroup = "无效的group参数，支持type、work"
+
+// errInvalidFormat 响应格式无效时的错误信息
+const errInvalidFormat = "无效的format参数，支持json、csv、jsonl、rss、atom"
+
+// 保存搜索服务的实例
+var searchService *service.SearchService
+
+// SetSearchService 设置搜索服务实例
+func SetSearchService(service *service.SearchService) {
+	searchService = service
+}
+
+// SearchHandler 搜索处理函数
+func SearchHandler(c *gin.Context) {
+	start := time.Now()
+	var req model.SearchRequest
+	var err error
+
+	// 根据请求方法不同处理参数
+	if c.Request.Method == http.MethodGet {
+		// GET方式：从URL参数获取
+		req, err = query.ParseRequest(c.Request.URL.Query())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+			return
+		}
+	} else {
+		// POST方式：从请求体获取
+		data, err := c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "读取请求数据失败: "+err.Error()))
+			return
+		}
+
+		if err := jsonutil.Unmarshal(data, &req); err != nil {
+			c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "无效的请求参数: "+err.Error()))
+			return
+		}
+	}
+
+	// 解析关键词中的查询语法，只保留正向关键词用于搜索
+	if err := query.Apply(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	// 检查关键词
+	if strings.TrimSpace(req.Keyword) == "" {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "关键词不能为空"))
+		return
+	}
+
+	// 检查排序方式
+	if !ranking.IsValidSort(req.Sort) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, errInvalidSort))
+		return
+	}
+
+	// 检查分组方式
+	if !service.IsValidGroup(req.Group) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, errInvalidGroup))
+		return
+	}
+
+	// 检查响应格式，未指定时按Accept头选择
+	if req.Format = strings.ToLower(strings.TrimSpace(req.Format)); req.Format == "" {
+		req.Format = export.Negotiate(c.GetHeader("Accept"))
+	}
+	if !export.IsValidFormat(req.Format) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, errInvalidFormat))
+		return
+	}
+
+	// 检查并设置默认值
+	normalizeSearchRequest(&req)
+
+	// 按插件声明的参数校验ext
+	ext, err := searchService.ValidateExt(req.SourceType, req.Plugins, req.Ext)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+	req.Ext = ext
+	defer observeSearchRequest(req.SourceType, req.ResultType, start)
+
+	// 执行搜索，客户端断开或超过写超时时中断进行中的请求
+	ctx, cancel := searchContext(c)
+	defer cancel()
+	var result model.SearchResponse
+	if isPagedSearch(req) {
+		// 分页搜索，后续页直接从第一页的结果快照中读取
+		result, err = searchService.SearchPage(ctx, req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext, req.ResultOptions(), req.Page, req.PageSize, req.Cursor)
+	} else {
+		result, err = searchService.SearchWithContext(ctx, req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext, req.ResultOptions())
+	}
+
+	if errors.Is(err, service.ErrInvalidCursor) || errors.Is(err, service.ErrInvalidPage) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+	if err != nil {
+		response := model.NewErrorResponse(500, "搜索失败: "+err.Error())
+		jsonData, _ := jsonutil.Marshal(response)
+		c.Data(http.StatusInternalServerError, "application/json", jsonData)
+		return
+	}
+
+	// 检测链接有效性
+	if req.CheckLinks {
+		searchService.CheckLinks(ctx, &result, req.DropDead)
+	}
+
+	// 按作品分组
+	if req.Group == service.GroupByWork {
+		service.GroupResponseByWork(&result)
+	}
+
+	// 按导出格式逐条写出
+	if req.Format != export.FormatJSON {
+		writeExport(c, req, result)
+		return
+	}
+
+	// 返回结果
+	response := model.NewSuccessResponse(result)
+	jsonData, _ := jsonutil.Marshal(response)
+	c.Data(http.StatusOK, "application/json", jsonData)
+}
+
+// writeExport 按请求的导出格式写出搜索结果，写出过程中出错时响应已经开始，只记录日志
+func writeExport(c *gin.Context, req model.SearchRequest, result model.SearchResponse) {
+	c.Header("Content-Type", export.ContentType(req.Format))
+	c.Status(http.StatusOK)
+	if err := export.Write(c.Writer, req.Format, result, export.NewFeedInfo(c.Request, req.Keyword)); err != nil {
+		logger.Warn(c.Request.Context(), "导出搜索结果失败", "format", req.Format, "error", err)
+	}
+}
+
+// isPagedSearch 判断请求是否要求分页
+func isPagedSearch(req model.SearchRequest) bool {
+	return req.Page > 0 || req.PageSize > 0 || req.Cursor != ""
+}
+
+// normalizeSearchRequest 检查并设置搜索请求的默认值
+func normalizeSearchRequest(req *model.SearchRequest) {
+	if len(req.Channels) == 0 {
+		req.Channels = config.AppConfig.DefaultChannels
+	}
+
+	// 如果未指定结果类型，默认返回merge并转换为merged_by_type
+	if req.ResultType == "" {
+		req.ResultType = "merged_by_type"
+	} else if req.ResultType == "merge" {
+		// 将merge转换为merged_by_type，以兼容内部处理
+		req.ResultType = "merged_by_type"
+	}
+
+	// 如果未指定数据来源类型，默认为全部
+	if req.SourceType == "" {
+		req.SourceType = "all"
+	}
+
+	// 参数互斥逻辑：当src=tg时忽略plugins参数，当src=plugin时忽略channels参数
+	if req.SourceType == "tg" {
+		req.Plugins = nil // 忽略plugins参数
+	} else if req.SourceType == "plugin" {
+		req.Channels = nil // 忽略channels参数
+	} else if req.SourceType == "all" {
+		// 对于all类型，如果plugins为空或不存在，统一设为nil
+		if len(req.Plugins) == 0 {
+			req.Plugins = nil
+		}
+	}
+
+	// 确保ext不为nil
+	if req.Ext == nil {
+		req.Ext = make(map[string]interface{})
+	}
+}
+
+// searchContext 基于gin请求上下文创建搜索上下文，超过HTTP写超时后自动取消
+func searchContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	if config.AppConfig.HTTPWriteTimeout > 0 {
+		return context.WithTimeout(c.Request.Context(), config.AppConfig.HTTPWriteTimeout)
+	}
+	return context.WithCancel(c.Request.Context())
+}
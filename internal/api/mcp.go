@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pansou/internal/mcp"
+	"pansou/model"
+	"pansou/util/auth"
+)
+
+// mcpCallerKey 上下文中保存MCP调用方信息的键
+type mcpCallerKey struct{}
+
+// mcpCaller MCP请求的调用方，工具调用时按其限流和扣减配额
+type mcpCaller struct {
+	clientIP string
+	apiKey   string
+}
+
+// mcpAuthMiddleware MCP接口鉴权中间件
+// 一个MCP请求可以包含多条JSON-RPC消息，这里只校验API密钥，不限流也不扣减配额，
+// 限流和配额由mcpToolCallLimiter按每次tools/call扣减
+func mcpAuthMiddleware(store *auth.KeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller := mcpCaller{clientIP: c.ClientIP()}
+		if store.Enabled() && c.Request.Method != http.MethodOptions {
+			caller.apiKey = auth.RequestAPIKey(c)
+			if decision := store.Authenticate(caller.apiKey); !decision.Allowed {
+				c.AbortWithStatusJSON(decision.Status, model.NewErrorResponse(decision.Status, decision.Message))
+				return
+			}
+		}
+
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), mcpCallerKey{}, caller))
+		c.Next()
+	}
+}
+
+// mcpToolCallLimiter 返回MCP工具调用的限流函数，与搜索接口共享按IP限流的令牌桶和API密钥配额
+func mcpToolCallLimiter(limiter *searchRateLimiter, store *auth.KeyStore) mcp.ToolCallLimiter {
+	return func(ctx context.Context, keyword string, forceRefresh bool) error {
+		caller, _ := ctx.Value(mcpCallerKey{}).(mcpCaller)
+
+		if limiter != nil {
+			if allowed, wait, message := limiter.allow(caller.clientIP, normalizeRateLimitKeyword(keyword), forceRefresh); !allowed {
+				return fmt.Errorf("%s（%d秒后可重试）", message, int(math.Ceil(wait.Seconds())))
+			}
+		}
+
+		if store.Enabled() {
+			if decision := store.Check(caller.apiKey); !decision.Allowed {
+				return errors.New(decision.Message)
+			}
+		}
+		return nil
+	}
+}
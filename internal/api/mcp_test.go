@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"pansou/config"
+)
+
+// TestMCPToolCallLimiter MCP工具调用按次限流，强制刷新使用独立额度
+func TestMCPToolCallLimiter(t *testing.T) {
+	config.AppConfig = &config.Config{
+		RateLimitEnabled:          true,
+		RateLimitPerMinute:        1,
+		RateLimitBurst:            1,
+		RefreshRateLimitPerMinute: 1,
+		RefreshRateLimitBurst:     1,
+	}
+	limit := mcpToolCallLimiter(newSearchRateLimiter(), nil)
+	ctx := context.WithValue(context.Background(), mcpCallerKey{}, mcpCaller{clientIP: "192.0.2.1"})
+
+	if err := limit(ctx, "流浪地球", false); err != nil {
+		t.Fatalf("第一次搜索应放行: %v", err)
+	}
+	if err := limit(ctx, "流浪地球", false); err == nil {
+		t.Error("超出额度的搜索应被拒绝")
+	}
+	if err := limit(ctx, "流浪地球", true); err != nil {
+		t.Fatalf("强制刷新使用独立额度，应放行: %v", err)
+	}
+	if err := limit(ctx, "流浪地球", true); err == nil {
+		t.Error("超出额度的强制刷新应被拒绝")
+	}
+
+	other := context.WithValue(context.Background(), mcpCallerKey{}, mcpCaller{clientIP: "192.0.2.2"})
+	if err := limit(other, "流浪地球", false); err != nil {
+		t.Errorf("其他客户端的额度不受影响: %v", err)
+	}
+}
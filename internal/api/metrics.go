@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pansou/util/metrics"
+)
+
+// searchRequestDuration 搜索请求耗时，按来源类型和结果类型区分
+var searchRequestDuration = metrics.NewHistogramVec(
+	"pansou_search_request_duration_seconds",
+	"搜索请求耗时（秒），按来源类型(src)和结果类型(res)区分",
+	metrics.LatencyBuckets,
+	"src", "res",
+)
+
+// 指标标签允许的取值，请求中的其他取值统一记为other，避免任意参数值导致标签数量无限增长
+var (
+	metricSourceTypes = map[string]bool{"all": true, "tg": true, "plugin": true}
+	metricResultTypes = map[string]bool{"all": true, "results": true, "merged_by_type": true, "stream": true}
+)
+
+// observeSearchRequest 记录一次搜索请求的耗时
+func observeSearchRequest(sourceType, resultType string, start time.Time) {
+	searchRequestDuration.Observe(time.Since(start).Seconds(), metricLabel(metricSourceTypes, sourceType), metricLabel(metricResultTypes, resultType))
+}
+
+// metricLabel 取值在允许范围内时原样返回，否则返回other
+func metricLabel(allowed map[string]bool, value string) string {
+	if allowed[value] {
+		return value
+	}
+	return "other"
+}
+
+// MetricsHandler 以Prometheus文本格式输出运行指标
+func MetricsHandler(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	metrics.WriteText(c.Writer)
+}
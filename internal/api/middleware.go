@@ -1,23 +1,61 @@
 package api
 
 import (
-	"fmt"
+	"context"
+	"crypto/subtle"
+	"log"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"pansou/config"
+	"pansou/model"
+	"pansou/util/auth"
+	"pansou/util/logger"
 )
 
-// CORSMiddleware 跨域中间件
+// CORSMiddleware 跨域中间件，根据CORS_ALLOWED_ORIGINS限制允许的来源
 func CORSMiddleware() gin.HandlerFunc {
+	return auth.CORSMiddleware(config.AppConfig.CORSAllowedOrigins)
+}
+
+// APIKeyMiddleware API密钥鉴权中间件，未配置API密钥时直接放行
+func APIKeyMiddleware() gin.HandlerFunc {
+	return auth.APIKeyMiddleware(loadAPIKeyStore())
+}
+
+// loadAPIKeyStore 按配置加载API密钥
+func loadAPIKeyStore() *auth.KeyStore {
+	store, err := auth.LoadKeyStore(config.AppConfig.APIKeys, config.AppConfig.APIKeysFile)
+	if err != nil {
+		// 密钥配置错误时拒绝启动，避免实例在无鉴权状态下对外开放
+		log.Fatalf("加载API密钥失败: %v", err)
+	}
+	if store.Enabled() {
+		logger.Info(context.Background(), "已启用API密钥鉴权", "keys", store.Count())
+	}
+	return store
+}
+
+// AdminAuthMiddleware 管理接口鉴权中间件
+// 支持 Authorization: Bearer <token> 或 X-Admin-Token: <token>，未配置ADMIN_TOKEN时禁用管理接口
+func AdminAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		adminToken := config.AppConfig.AdminToken
+		if adminToken == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, model.NewErrorResponse(403, "管理接口未启用"))
+			return
+		}
 		
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		token := c.GetHeader("X-Admin-Token")
+		if token == "" {
+			token = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, model.NewErrorResponse(401, "管理令牌无效"))
 			return
 		}
 		
@@ -26,22 +64,27 @@ func CORSMiddleware() gin.HandlerFunc {
 }
 
 // LoggerMiddleware 日志中间件
+// 为每个请求分配请求ID（优先使用客户端传入的X-Request-ID），写入请求上下文和响应头，
+// 服务和插件日志通过上下文携带同一个请求ID
 func LoggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 开始时间
 		startTime := time.Now()
 		
+		// 分配请求ID
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > 64 {
+			requestID = logger.NewRequestID()
+		}
+		c.Header("X-Request-ID", requestID)
+		ctx := logger.WithRequestID(c.Request.Context(), requestID)
+		c.Request = c.Request.WithContext(ctx)
+		
 		// 处理请求
 		c.Next()
 		
-		// 结束时间
-		endTime := time.Now()
-		
 		// 执行时间
-		latencyTime := endTime.Sub(startTime)
-		
-		// 请求方式
-		reqMethod := c.Request.Method
+		latencyTime := time.Since(startTime)
 		
 		// 请求路由
 		reqURI := c.Request.RequestURI
@@ -59,15 +102,11 @@ func LoggerMiddleware() gin.HandlerFunc {
 			}
 		}
 		
-		// 状态码
-		statusCode := c.Writer.Status()
-		
-		// 请求IP
-		clientIP := c.ClientIP()
-		
-		// 日志格式
-		gin.DefaultWriter.Write([]byte(
-			fmt.Sprintf("| %s | %s | %s | %d | %s\n", 
-				clientIP, reqMethod, displayURI, statusCode, latencyTime.String())))
+		logger.Info(ctx, "HTTP请求",
+			"client_ip", c.ClientIP(),
+			"method", c.Request.Method,
+			"uri", displayURI,
+			"status", c.Writer.Status(),
+			"latency", latencyTime.String())
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pansou/model"
+)
+
+// PluginsHandler 列出参与搜索的插件及其显示名称、内容分类、网盘类型和可用的ext参数
+func PluginsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, model.NewSuccessResponse(searchService.ListPluginInfos()))
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pansou/config"
+	"pansou/model"
+	jsonutil "pansou/util/json"
+	"pansou/util/ratelimit"
+)
+
+// RateLimitMiddleware 搜索接口限流中间件
+// 普通搜索和强制刷新搜索使用独立的令牌桶，按客户端IP限流；
+// 启用RATE_LIMIT_BY_KEYWORD后，同一关键词的强制刷新在所有客户端之间共享一个令牌桶
+func RateLimitMiddleware() gin.HandlerFunc {
+	return rateLimitMiddleware(newSearchRateLimiter())
+}
+
+// rateLimitMiddleware 使用指定限流器的搜索接口限流中间件，limiter为nil时不限流
+func rateLimitMiddleware(limiter *searchRateLimiter) gin.HandlerFunc {
+	if limiter == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		keyword, forceRefresh := rateLimitTarget(c)
+		if allowed, wait, message := limiter.allow(c.ClientIP(), keyword, forceRefresh); !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, model.NewErrorResponse(429, message))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// searchRateLimiter 搜索限流器，搜索接口和MCP工具调用共享同一组令牌桶
+type searchRateLimiter struct {
+	search  *ratelimit.KeyedLimiter
+	refresh *ratelimit.KeyedLimiter
+	keyword *ratelimit.KeyedLimiter // 未启用RATE_LIMIT_BY_KEYWORD时为nil
+}
+
+// newSearchRateLimiter 按配置创建搜索限流器，未启用限流时返回nil
+func newSearchRateLimiter() *searchRateLimiter {
+	cfg := config.AppConfig
+	if !cfg.RateLimitEnabled {
+		return nil
+	}
+
+	limiter := &searchRateLimiter{
+		search:  ratelimit.NewKeyedLimiter(cfg.RateLimitPerMinute/60, cfg.RateLimitBurst),
+		refresh: ratelimit.NewKeyedLimiter(cfg.RefreshRateLimitPerMinute/60, cfg.RefreshRateLimitBurst),
+	}
+	if cfg.RateLimitByKeyword {
+		limiter.keyword = ratelimit.NewKeyedLimiter(cfg.RefreshRateLimitPerMinute/60, cfg.RefreshRateLimitBurst)
+	}
+	return limiter
+}
+
+// allow 判断一次搜索是否放行，拒绝时返回需要等待的时间和提示信息
+// keyword为规范化后的关键词
+func (l *searchRateLimiter) allow(clientIP string, keyword string, forceRefresh bool) (bool, time.Duration, string) {
+	if !forceRefresh {
+		allowed, wait := l.search.Allow(clientIP)
+		return allowed, wait, "请求过于频繁，请稍后再试"
+	}
+
+	allowed, wait := l.refresh.Allow(clientIP)
+	if allowed && l.keyword != nil && keyword != "" {
+		allowed, wait = l.keyword.Allow(keyword)
+	}
+	return allowed, wait, "强制刷新过于频繁，请稍后再试"
+}
+
+// rateLimitTarget 提取请求的规范化关键词和是否强制刷新
+// POST请求会读取请求体，读取后恢复请求体供后续处理函数使用
+func rateLimitTarget(c *gin.Context) (string, bool) {
+	if c.Request.Method != http.MethodPost {
+		keyword := c.Query("kw")
+		if keyword == "" {
+			keyword = c.Query("keyword")
+		}
+		return normalizeRateLimitKeyword(keyword), c.Query("refresh") == "true"
+	}
+
+	data, err := c.GetRawData()
+	if err != nil {
+		return "", false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	// 请求体无效时按普通搜索限流，交由处理函数返回参数错误
+	var req model.SearchRequest
+	if err := jsonutil.Unmarshal(data, &req); err != nil {
+		return "", false
+	}
+	return normalizeRateLimitKeyword(req.Keyword), req.ForceRefresh
+}
+
+// normalizeRateLimitKeyword 规范化关键词，忽略大小写和多余空白
+func normalizeRateLimitKeyword(keyword string) string {
+	return strings.ToLower(strings.Join(strings.Fields(keyword), " "))
+}
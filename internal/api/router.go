@@ -1,11 +1,15 @@
 package api
 
 import (
+	"log"
 	"strings"
 	"github.com/gin-gonic/gin"
 	"pansou/config"
+	"pansou/internal/mcp"
+	"pansou/plugin"
 	"pansou/service"
 	"pansou/util"
+	"pansou/util/auth"
 )
 
 // SetupRouter 设置路由
@@ -19,6 +23,11 @@ func SetupRouter(searchService *service.SearchService) *gin.Engine {
 	// 创建默认路由
 	r := gin.Default()
 	
+	// 只采用受信任代理传递的客户端IP，避免伪造X-Forwarded-For绕过限流
+	if err := r.SetTrustedProxies(config.AppConfig.TrustedProxies); err != nil {
+		log.Fatalf("无效的TRUSTED_PROXIES配置: %v", err)
+	}
+	
 	// 添加中间件
 	r.Use(CORSMiddleware())
 	r.Use(LoggerMiddleware())
@@ -88,18 +97,33 @@ func SetupRouter(searchService *service.SearchService) *gin.Engine {
 		c.File("./static/404.html")
 	})
 	
+	// 搜索接口按客户端IP限流，并需要API密钥（未配置密钥时不校验）
+	// MCP接口与搜索接口共享限流器和密钥存储，即共享限流额度和密钥配额
+	rateLimiter := newSearchRateLimiter()
+	keyStore := loadAPIKeyStore()
+	searchMiddlewares := []gin.HandlerFunc{rateLimitMiddleware(rateLimiter), auth.APIKeyMiddleware(keyStore)}
+	
 	// 定义API路由组
 	api := r.Group("/api")
 	{
+		search := api.Group("", searchMiddlewares...)
+		
 		// 搜索接口 - 支持POST和GET两种方式
-		api.POST("/search", SearchHandler)
-		api.GET("/search", SearchHandler) // 添加GET方式支持
+		search.POST("/search", SearchHandler)
+		search.GET("/search", SearchHandler) // 添加GET方式支持
+		
+		// 流式搜索接口 - 每个来源完成时通过SSE推送结果
+		search.GET("/search/stream", SearchStreamHandler)
+		
+		// 插件列表接口 - 插件元数据和可用的ext参数
+		api.GET("/plugins", PluginsHandler)
 		
 		// 健康检查接口
 		api.GET("/health", func(c *gin.Context) {
 			// 根据配置决定是否返回插件信息
 			pluginCount := 0
 			pluginNames := []string{}
+			pluginHealth := []plugin.PluginHealthStatus{}
 			pluginsEnabled := config.AppConfig.AsyncPluginEnabled
 			
 			if pluginsEnabled && searchService != nil && searchService.GetPluginManager() != nil {
@@ -108,6 +132,8 @@ func SetupRouter(searchService *service.SearchService) *gin.Engine {
 				for _, p := range plugins {
 					pluginNames = append(pluginNames, p.Name())
 				}
+				// 插件健康状态和熔断状态
+				pluginHealth = searchService.GetPluginManager().PluginHealthStatuses()
 			}
 			
 			// 获取频道信息
@@ -125,12 +151,38 @@ func SetupRouter(searchService *service.SearchService) *gin.Engine {
 			if pluginsEnabled {
 				response["plugin_count"] = pluginCount
 				response["plugins"] = pluginNames
+				response["plugin_health"] = pluginHealth
 			}
 			
 			c.JSON(200, response)
 		})
 	}
 	
+	// 管理接口 - 需要ADMIN_TOKEN鉴权
+	admin := r.Group("/api/admin", AdminAuthMiddleware())
+	{
+		// 插件管理：列出插件、运行时启用/禁用和覆盖优先级
+		admin.GET("/plugins", ListPluginsHandler)
+		admin.PATCH("/plugins/:name", UpdatePluginHandler)
+	}
+	
+	// MCP接口 - Streamable HTTP方式，直接调用搜索服务
+	// 请求级别只校验API密钥，限流和配额按每次工具调用扣减
+	if config.AppConfig.MCPEnabled {
+		mcpServer := mcp.NewServer(searchService)
+		mcpServer.SetToolCallLimiter(mcpToolCallLimiter(rateLimiter, keyStore))
+		mcpHandler := gin.WrapH(mcpServer)
+		mcpGroup := r.Group("/mcp", mcpAuthMiddleware(keyStore))
+		mcpGroup.POST("", mcpHandler)
+		mcpGroup.GET("", mcpHandler)
+		mcpGroup.DELETE("", mcpHandler)
+	}
+	
+	// Prometheus指标接口
+	if config.AppConfig.MetricsEnabled {
+		r.GET("/metrics", MetricsHandler)
+	}
+	
 	// 静态文件服务 - 提供CSS、JS、图片等静态资源
 	r.Static("/static", "./static")
 	
@@ -147,6 +199,10 @@ func SetupRouter(searchService *service.SearchService) *gin.Engine {
 					"GET /api/health",
 					"GET /api/search",
 					"POST /api/search",
+					"GET /api/search/stream",
+					"GET /api/plugins",
+					"GET /api/admin/plugins",
+					"PATCH /api/admin/plugins/:name",
 				},
 			})
 			return
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pansou/model"
+	"pansou/service"
+	jsonutil "pansou/util/json"
+	"pansou/util/query"
+	"pansou/util/ranking"
+)
+
+// SearchStreamHandler 流式搜索处理函数（Server-Sent Events）
+// 每个TG频道或插件完成时推送一个source事件，最后推送merged_by_type事件
+func SearchStreamHandler(c *gin.Context) {
+	start := time.Now()
+	req, err := query.ParseRequest(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	// 解析关键词中的查询语法，只保留正向关键词用于搜索
+	if err := query.Apply(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	// 检查关键词
+	if strings.TrimSpace(req.Keyword) == "" {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, "关键词不能为空"))
+		return
+	}
+
+	// 检查排序方式
+	if !ranking.IsValidSort(req.Sort) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, errInvalidSort))
+		return
+	}
+
+	// 检查分组方式
+	if !service.IsValidGroup(req.Group) {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, errInvalidGroup))
+		return
+	}
+
+	// 检查并设置默认值
+	normalizeSearchRequest(&req)
+
+	// 按插件声明的参数校验ext
+	ext, err := searchService.ValidateExt(req.SourceType, req.Plugins, req.Ext)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(400, err.Error()))
+		return
+	}
+	req.Ext = ext
+	defer observeSearchRequest(req.SourceType, "stream", start)
+
+	// 设置SSE响应头
+	c.Header("Content-Type", "text/event-stream; charset=utf-8")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 禁止Nginx缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	// 每个来源完成时推送事件
+	onSource := func(event model.SearchStreamEvent) {
+		writeStreamEvent(c, "source", event)
+	}
+
+	// 客户端断开时中断进行中的请求
+	ctx, cancel := searchContext(c)
+	defer cancel()
+	result, err := searchService.SearchStream(ctx, req.Keyword, req.Channels, req.Concurrency, req.ForceRefresh, req.ResultType, req.SourceType, req.Plugins, req.CloudTypes, req.Ext, req.ResultOptions(), onSource)
+	if err != nil {
+		// 客户端已断开，无需再推送
+		if c.Request.Context().Err() != nil {
+			return
+		}
+		writeStreamEvent(c, "error", model.NewErrorResponse(500, "搜索失败: "+err.Error()))
+		return
+	}
+
+	// 检测链接有效性
+	if req.CheckLinks {
+		searchService.CheckLinks(ctx, &result, req.DropDead)
+	}
+
+	// 按作品分组
+	if req.Group == service.GroupByWork {
+		service.GroupResponseByWork(&result)
+	}
+
+	// 推送最终合并结果
+	writeStreamEvent(c, "merged_by_type", model.NewSuccessResponse(result))
+}
+
+// writeStreamEvent 写入一个SSE事件并立即刷新
+func writeStreamEvent(c *gin.Context, name string, data interface{}) {
+	jsonData, err := jsonutil.Marshal(data)
+	if err != nil {
+		return
+	}
+	c.SSEvent(name, string(jsonData))
+	c.Writer.Flush()
+}
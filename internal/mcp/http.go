@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+)
+
+// maxHTTPMessageSize HTTP请求体的最大长度
+const maxHTTPMessageSize = 4 * 1024 * 1024
+
+// ServeHTTP 以Streamable HTTP方式提供MCP服务
+// 只支持POST，响应直接以application/json返回；不提供服务端推送流，GET返回405
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHTTPMessageSize))
+	if err != nil {
+		writeHTTPResponse(w, http.StatusBadRequest, marshalResponse(errorResponse(nullID, codeParseError, "读取请求数据失败: "+err.Error())))
+		return
+	}
+
+	resp := s.HandleMessage(r.Context(), body)
+	if resp == nil {
+		// 只包含通知或响应时返回202，无响应体
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeHTTPResponse(w, http.StatusOK, resp)
+}
+
+// writeHTTPResponse 写入JSON-RPC响应
+func writeHTTPResponse(w http.ResponseWriter, status int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
@@ -0,0 +1,111 @@
+// Package mcp 内置的Model Context Protocol服务，直接基于SearchService提供网盘搜索工具和资源
+// 支持stdio（按行分隔的JSON-RPC消息）和Streamable HTTP两种传输方式
+package mcp
+
+import (
+	"encoding/json"
+)
+
+// JSON-RPC 2.0错误码
+const (
+	codeParseError       = -32700
+	codeInvalidRequest   = -32600
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+	codeInternalError    = -32603
+	codeResourceNotFound = -32002
+)
+
+// 服务信息，与TypeScript版MCP服务保持一致
+const (
+	serverName    = "pansou-mcp-server"
+	serverVersion = "1.0.0"
+)
+
+// supportedProtocolVersions 支持的MCP协议版本，第一个为最新版本
+var supportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// request JSON-RPC请求或通知，ID为空时为通知
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// isNotification 判断是否为通知（无需响应）
+func (r *request) isNotification() bool {
+	return len(r.ID) == 0
+}
+
+// response JSON-RPC响应
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError JSON-RPC错误
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error 实现error接口
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// nullID 无法解析请求ID时使用的null
+var nullID = json.RawMessage("null")
+
+// initializeParams initialize请求参数
+type initializeParams struct {
+	ProtocolVersion string `json:"protocolVersion"`
+}
+
+// toolCallParams tools/call请求参数
+type toolCallParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// resourceReadParams resources/read请求参数
+type resourceReadParams struct {
+	URI string `json:"uri"`
+}
+
+// textContent 工具返回的文本内容
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// toolResult tools/call返回结果，工具执行失败时IsError为true
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// tool 工具定义
+type tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// resource 资源定义
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+}
+
+// resourceContent 资源内容
+type resourceContent struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
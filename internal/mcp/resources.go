@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"pansou/config"
+	jsonutil "pansou/util/json"
+	"pansou/util/query"
+)
+
+// supportedCloudTypes 支持的网盘类型
+var supportedCloudTypes = query.CloudTypes
+
+// cloudTypeNames 网盘类型的中文名称
+var cloudTypeNames = map[string]string{
+	"baidu":  "百度网盘",
+	"aliyun": "阿里云盘",
+	"quark":  "夸克网盘",
+	"tianyi": "天翼云盘",
+	"uc":     "UC网盘",
+	"mobile": "移动云盘",
+	"115":    "115网盘",
+	"pikpak": "PikPak",
+	"xunlei": "迅雷网盘",
+	"123":    "123网盘",
+	"magnet": "磁力链接",
+	"ed2k":   "电驴链接",
+	"others": "其他网盘",
+}
+
+// resourceDefinitions 返回资源列表
+func resourceDefinitions() []resource {
+	return []resource{
+		{URI: "pansou://plugins", Name: "可用插件列表", Description: "获取当前可用的搜索插件列表", MimeType: "application/json"},
+		{URI: "pansou://channels", Name: "可用频道列表", Description: "获取当前可用的TG频道列表", MimeType: "application/json"},
+		{URI: "pansou://cloud-types", Name: "支持的网盘类型", Description: "获取支持的网盘类型列表", MimeType: "application/json"},
+	}
+}
+
+// readResource 读取资源内容
+func (s *Server) readResource(uri string) (interface{}, error) {
+	var data interface{}
+	switch uri {
+	case "pansou://plugins":
+		names := s.pluginNames()
+		data = map[string]interface{}{
+			"enabled": config.AppConfig.AsyncPluginEnabled,
+			"count":   len(names),
+			"list":    names,
+		}
+	case "pansou://channels":
+		channels := config.AppConfig.DefaultChannels
+		if channels == nil {
+			channels = []string{}
+		}
+		data = map[string]interface{}{
+			"count": len(channels),
+			"list":  channels,
+		}
+	case "pansou://cloud-types":
+		data = map[string]interface{}{
+			"supported":   supportedCloudTypes,
+			"description": cloudTypeNames,
+		}
+	default:
+		return nil, &rpcError{Code: codeResourceNotFound, Message: "未知资源URI: " + uri}
+	}
+
+	text, err := jsonutil.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: "资源读取失败: " + err.Error()}
+	}
+
+	return map[string]interface{}{
+		"contents": []resourceContent{{URI: uri, MimeType: "application/json", Text: string(text)}},
+	}, nil
+}
@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"pansou/service"
+	jsonutil "pansou/util/json"
+	"pansou/util/logger"
+)
+
+// ToolCallLimiter 工具调用限流函数，每次tools/call执行前调用，返回错误时拒绝本次调用
+// keyword和forceRefresh取自search_netdisk的参数，其他工具为零值
+type ToolCallLimiter func(ctx context.Context, keyword string, forceRefresh bool) error
+
+// Server MCP服务，工具和资源直接调用SearchService，不经过HTTP接口
+type Server struct {
+	searchService *service.SearchService
+	callLimiter   ToolCallLimiter
+}
+
+// NewServer 创建MCP服务
+func NewServer(searchService *service.SearchService) *Server {
+	return &Server{searchService: searchService}
+}
+
+// SetToolCallLimiter 设置工具调用限流函数，未设置时不限流
+// 一个请求可以包含多条消息，限流按tools/call计算，而不是按HTTP请求
+func (s *Server) SetToolCallLimiter(limiter ToolCallLimiter) {
+	s.callLimiter = limiter
+}
+
+// HandleMessage 处理一条JSON-RPC消息（单个请求或批量请求），返回需要写回的响应
+// 消息只包含通知时返回nil
+func (s *Server) HandleMessage(ctx context.Context, data []byte) []byte {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+
+	// 批量请求
+	if data[0] == '[' {
+		var messages []json.RawMessage
+		if err := jsonutil.Unmarshal(data, &messages); err != nil {
+			return marshalResponse(errorResponse(nullID, codeParseError, "无效的JSON: "+err.Error()))
+		}
+		if len(messages) == 0 {
+			return marshalResponse(errorResponse(nullID, codeInvalidRequest, "批量请求不能为空"))
+		}
+
+		responses := make([]*response, 0, len(messages))
+		for _, message := range messages {
+			if resp := s.handleSingle(ctx, message); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return marshalResponse(responses)
+	}
+
+	resp := s.handleSingle(ctx, data)
+	if resp == nil {
+		return nil
+	}
+	return marshalResponse(resp)
+}
+
+// handleSingle 处理单个JSON-RPC消息，通知返回nil
+func (s *Server) handleSingle(ctx context.Context, data []byte) *response {
+	var req request
+	if err := jsonutil.Unmarshal(data, &req); err != nil {
+		return errorResponse(nullID, codeParseError, "无效的JSON: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if req.isNotification() {
+			return nil
+		}
+		return errorResponse(req.ID, codeInvalidRequest, "无效的JSON-RPC请求")
+	}
+
+	result, err := s.dispatch(ctx, &req)
+	if req.isNotification() {
+		return nil
+	}
+	if err != nil {
+		if rpcErr, ok := err.(*rpcError); ok {
+			return errorResponse(req.ID, rpcErr.Code, rpcErr.Message)
+		}
+		return errorResponse(req.ID, codeInternalError, err.Error())
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// dispatch 按方法名分发请求
+func (s *Server) dispatch(ctx context.Context, req *request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		var params initializeParams
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(params), nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": toolDefinitions()}, nil
+	case "tools/call":
+		var params toolCallParams
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.callTool(ctx, params)
+	case "resources/list":
+		return map[string]interface{}{"resources": resourceDefinitions()}, nil
+	case "resources/read":
+		var params resourceReadParams
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.readResource(params.URI)
+	default:
+		// 通知（如notifications/initialized、notifications/cancelled）无需处理
+		if req.isNotification() {
+			return nil, nil
+		}
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "未知方法: " + req.Method}
+	}
+}
+
+// initialize 协商协议版本并返回服务能力
+func (s *Server) initialize(params initializeParams) map[string]interface{} {
+	// 客户端请求的版本受支持时使用该版本，否则返回最新版本由客户端决定是否继续
+	version := supportedProtocolVersions[0]
+	for _, v := range supportedProtocolVersions {
+		if v == params.ProtocolVersion {
+			version = v
+			break
+		}
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools":     map[string]interface{}{"listChanged": false},
+			"resources": map[string]interface{}{"subscribe": false, "listChanged": false},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    serverName,
+			"version": serverVersion,
+		},
+	}
+}
+
+// callTool 执行工具调用，工具执行失败通过isError返回，未知工具返回参数错误
+func (s *Server) callTool(ctx context.Context, params toolCallParams) (interface{}, error) {
+	if params.Name != "search_netdisk" && params.Name != "check_service_health" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "未知工具: " + params.Name}
+	}
+
+	var text string
+	err := s.limitToolCall(ctx, params)
+	if err == nil {
+		switch params.Name {
+		case "search_netdisk":
+			text, err = s.searchNetdisk(ctx, params.Arguments)
+		case "check_service_health":
+			text = s.checkServiceHealth()
+		}
+	}
+
+	if err != nil {
+		logger.Warn(ctx, "MCP工具调用失败", "tool", params.Name, "error", err)
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return toolResult{Content: []textContent{{Type: "text", Text: text}}}, nil
+}
+
+// limitToolCall 对工具调用限流，search_netdisk的强制刷新使用独立的额度
+func (s *Server) limitToolCall(ctx context.Context, params toolCallParams) error {
+	if s.callLimiter == nil {
+		return nil
+	}
+
+	var args struct {
+		Keyword      string `json:"keyword"`
+		ForceRefresh bool   `json:"force_refresh"`
+	}
+	if params.Name == "search_netdisk" && len(params.Arguments) > 0 {
+		// 参数无效时按普通调用限流，由工具返回参数错误
+		_ = jsonutil.Unmarshal(params.Arguments, &args)
+	}
+	return s.callLimiter(ctx, args.Keyword, args.ForceRefresh)
+}
+
+// unmarshalParams 解析请求参数，参数为空时保持零值
+func unmarshalParams(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	if err := jsonutil.Unmarshal(data, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: "无效的参数: " + err.Error()}
+	}
+	return nil
+}
+
+// errorResponse 创建错误响应
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	if len(id) == 0 {
+		id = nullID
+	}
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+// marshalResponse 序列化响应，失败时返回内部错误
+func marshalResponse(v interface{}) []byte {
+	data, err := jsonutil.Marshal(v)
+	if err != nil {
+		return []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":null,"error":{"code":%d,"message":"序列化响应失败"}}`, codeInternalError))
+	}
+	return data
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+)
+
+// maxStdioMessageSize stdio模式下单条消息的最大长度
+const maxStdioMessageSize = 10 * 1024 * 1024
+
+// ServeStdio 以stdio方式提供MCP服务，每行一条JSON-RPC消息
+// 请求并发处理，响应串行写入out；in读取结束或ctx取消后等待进行中的请求完成再返回
+// stdio模式下out专用于协议消息，日志必须写到其他位置（如stderr）
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var writeMutex sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 64*1024), maxStdioMessageSize)
+		for scanner.Scan() {
+			// Scanner会复用缓冲区，需要复制一份
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-scanErr:
+			return err
+		case line := <-lines:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp := s.HandleMessage(ctx, line)
+				if resp == nil {
+					return
+				}
+				writeMutex.Lock()
+				defer writeMutex.Unlock()
+				out.Write(append(resp, '\n'))
+			}()
+		}
+	}
+}
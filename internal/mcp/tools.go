@@ -0,0 +1,431 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"pansou/config"
+	"pansou/model"
+	jsonutil "pansou/util/json"
+	"pansou/util/query"
+	"pansou/util/ranking"
+)
+
+// searchArgs search_netdisk工具参数
+type searchArgs struct {
+	Keyword      string                 `json:"keyword"`
+	Channels     []string               `json:"channels"`
+	Plugins      []string               `json:"plugins"`
+	CloudTypes   []string               `json:"cloud_types"`
+	SourceType   string                 `json:"source_type"`
+	ForceRefresh bool                   `json:"force_refresh"`
+	ResultType   string                 `json:"result_type"`
+	Concurrency  float64                `json:"concurrency"`
+	ExtParams    map[string]interface{} `json:"ext_params"`
+	CheckLinks   bool                   `json:"check_links"`
+	Sort         string                 `json:"sort"`
+}
+
+// toolDefinitions 返回工具列表，与TypeScript版MCP服务的工具定义保持一致
+func toolDefinitions() []tool {
+	return []tool{
+		{
+			Name:        "search_netdisk",
+			Description: "搜索网盘资源，支持多种网盘类型和搜索来源。可以搜索电影、电视剧、软件、文档等各类资源。",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"keyword": map[string]interface{}{
+						"type":        "string",
+						"description": "搜索关键词，如：\"速度与激情\"、\"Python教程\"、\"Office 2021\"等",
+					},
+					"channels": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "TG频道列表，指定要搜索的Telegram频道。不指定则使用默认配置的频道",
+					},
+					"plugins": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "插件列表，指定要使用的搜索插件。不指定则使用所有可用插件",
+					},
+					"cloud_types": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string", "enum": supportedCloudTypes},
+						"description": "网盘类型过滤，只返回指定类型的网盘链接。支持: " + strings.Join(supportedCloudTypes, ", "),
+					},
+					"source_type": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"all", "tg", "plugin"},
+						"default":     "all",
+						"description": "数据来源类型：all(全部来源)、tg(仅Telegram)、plugin(仅插件)",
+					},
+					"force_refresh": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "强制刷新缓存，获取最新数据",
+					},
+					"result_type": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"all", "results", "merge"},
+						"default":     "merge",
+						"description": "结果类型：all(返回所有结果)、results(仅返回results)、merge(仅返回按网盘类型分组的结果)",
+					},
+					"concurrency": map[string]interface{}{
+						"type":        "number",
+						"description": "并发搜索数量，0或不指定则自动计算",
+					},
+					"check_links": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "检测网盘链接是否有效，并移除已失效的链接",
+					},
+					"sort": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{ranking.SortRelevance, ranking.SortTime, ranking.SortSource},
+						"default":     ranking.SortRelevance,
+						"description": "排序方式：relevance(综合相关度)、time(发布时间)、source(来源插件等级)",
+					},
+					"ext_params": map[string]interface{}{
+						"type":        "object",
+						"description": "扩展参数，用于传递给插件的自定义参数，如: {\"title_en\": \"Fast and Furious\", \"is_all\": true}",
+					},
+				},
+				"required": []string{"keyword"},
+			},
+		},
+		{
+			Name:        "check_service_health",
+			Description: "检查PanSou服务的健康状态，获取可用的TG频道和插件信息。",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+	}
+}
+
+// searchNetdisk 执行search_netdisk工具
+func (s *Server) searchNetdisk(ctx context.Context, rawArgs json.RawMessage) (string, error) {
+	var args searchArgs
+	if len(rawArgs) > 0 {
+		if err := jsonutil.Unmarshal(rawArgs, &args); err != nil {
+			return "", fmt.Errorf("参数验证失败: %v", err)
+		}
+	}
+
+	// 解析关键词中的查询语法，type和src条件与参数中的值合并
+	q := query.Parse(args.Keyword)
+	keyword := strings.TrimSpace(q.Keyword)
+	if keyword == "" {
+		return "", fmt.Errorf("参数验证失败: keyword: 搜索关键词不能为空")
+	}
+
+	// 参数校验和默认值，与HTTP接口保持一致
+	sourceType := args.SourceType
+	if q.SourceType != "" {
+		sourceType = q.SourceType
+	}
+	switch sourceType {
+	case "":
+		sourceType = "all"
+	case "all", "tg", "plugin":
+	default:
+		return "", fmt.Errorf("参数验证失败: source_type: 不支持的数据来源类型 %s", sourceType)
+	}
+
+	displayType := args.ResultType
+	switch displayType {
+	case "":
+		displayType = "merge"
+	case "all", "results", "merge":
+	default:
+		return "", fmt.Errorf("参数验证失败: result_type: 不支持的结果类型 %s", displayType)
+	}
+	resultType := displayType
+	if resultType == "merge" {
+		resultType = "merged_by_type"
+	}
+
+	if !ranking.IsValidSort(args.Sort) {
+		return "", fmt.Errorf("参数验证失败: sort: 不支持的排序方式 %s", args.Sort)
+	}
+
+	for _, cloudType := range args.CloudTypes {
+		if !query.IsCloudType(cloudType) {
+			return "", fmt.Errorf("参数验证失败: cloud_types: 不支持的网盘类型 %s", cloudType)
+		}
+	}
+	cloudTypes, err := query.MergeCloudTypes(args.CloudTypes, q.CloudTypes)
+	if err != nil {
+		return "", fmt.Errorf("参数验证失败: keyword: %v", err)
+	}
+
+	channels := args.Channels
+	if len(channels) == 0 {
+		channels = config.AppConfig.DefaultChannels
+	}
+	plugins := args.Plugins
+	if len(plugins) == 0 {
+		plugins = nil
+	}
+	// 参数互斥逻辑：当src=tg时忽略plugins参数，当src=plugin时忽略channels参数
+	if sourceType == "tg" {
+		plugins = nil
+	} else if sourceType == "plugin" {
+		channels = nil
+	}
+
+	ext := args.ExtParams
+	if ext == nil {
+		ext = make(map[string]interface{})
+	}
+	// 按插件声明的参数校验ext，与HTTP接口保持一致
+	ext, err = s.searchService.ValidateExt(sourceType, plugins, ext)
+	if err != nil {
+		return "", fmt.Errorf("参数验证失败: ext_params: %v", err)
+	}
+
+	// 超过HTTP写超时后中断搜索，与HTTP接口保持一致
+	if config.AppConfig.HTTPWriteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.AppConfig.HTTPWriteTimeout)
+		defer cancel()
+	}
+
+	result, err := s.searchService.SearchWithContext(ctx, keyword, channels, int(args.Concurrency), args.ForceRefresh, resultType, sourceType, plugins, cloudTypes, ext, model.ResultOptions{Sort: args.Sort, Filter: q.Filter()})
+	if err != nil {
+		return "", fmt.Errorf("搜索失败: %v", err)
+	}
+	if args.CheckLinks {
+		s.searchService.CheckLinks(ctx, &result, true)
+	}
+
+	return formatSearchResult(result, keyword, displayType), nil
+}
+
+// formatSearchResult 将搜索结果格式化为文本
+func formatSearchResult(result model.SearchResponse, keyword string, resultType string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "搜索关键词: \"%s\"\n", keyword)
+	fmt.Fprintf(&b, "找到 %d 个结果\n\n", result.Total)
+
+	switch resultType {
+	case "merge":
+		writeMergedResults(&b, result.MergedByType)
+	case "results":
+		writeDetailedResults(&b, result.Results)
+	case "all":
+		if len(result.MergedByType) > 0 {
+			b.WriteString("## 按网盘类型分组\n")
+			writeMergedResults(&b, result.MergedByType)
+		}
+		if len(result.Results) > 0 {
+			b.WriteString("\n## 详细结果\n")
+			results := result.Results
+			// 限制显示前10个详细结果
+			if len(results) > 10 {
+				results = results[:10]
+			}
+			writeDetailedResults(&b, results)
+		}
+	}
+
+	return b.String()
+}
+
+// writeMergedResults 格式化按网盘类型分组的结果，每种类型最多显示5个
+func writeMergedResults(b *strings.Builder, merged model.MergedLinks) {
+	for _, cloudType := range sortedCloudTypes(merged) {
+		links := merged[cloudType]
+		if len(links) == 0 {
+			continue
+		}
+
+		typeName := cloudType
+		if name, ok := cloudTypeNames[cloudType]; ok {
+			typeName = name
+		}
+		fmt.Fprintf(b, "### %s (%d个)\n", typeName, len(links))
+
+		for i, link := range links {
+			if i >= 5 {
+				break
+			}
+			note := link.Note
+			if note == "" {
+				note = "未知标题"
+			}
+			fmt.Fprintf(b, "%d. **%s**\n", i+1, note)
+			fmt.Fprintf(b, "   链接: %s\n", link.URL)
+			if link.Password != "" {
+				fmt.Fprintf(b, "   密码: %s\n", link.Password)
+			}
+			if link.Source != "" {
+				fmt.Fprintf(b, "   来源: %s\n", link.Source)
+			}
+			if name, ok := linkStatusNames[link.Status]; ok {
+				fmt.Fprintf(b, "   状态: %s\n", name)
+			}
+			if !link.Datetime.IsZero() {
+				fmt.Fprintf(b, "   时间: %s\n", link.Datetime.Format("2006-01-02 15:04:05"))
+			}
+			b.WriteString("\n")
+		}
+
+		if len(links) > 5 {
+			fmt.Fprintf(b, "   ... 还有 %d 个结果\n\n", len(links)-5)
+		}
+	}
+}
+
+// writeDetailedResults 格式化详细结果
+func writeDetailedResults(b *strings.Builder, results []model.SearchResult) {
+	for i, result := range results {
+		title := result.Title
+		if title == "" {
+			title = "未知标题"
+		}
+		fmt.Fprintf(b, "### %d. %s\n", i+1, title)
+		fmt.Fprintf(b, "频道: %s\n", result.Channel)
+		if !result.Datetime.IsZero() {
+			fmt.Fprintf(b, "时间: %s\n", result.Datetime.Format("2006-01-02 15:04:05"))
+		}
+
+		if result.Content != "" && result.Content != result.Title {
+			content := []rune(result.Content)
+			if len(content) > 200 {
+				fmt.Fprintf(b, "内容: %s...\n", string(content[:200]))
+			} else {
+				fmt.Fprintf(b, "内容: %s\n", result.Content)
+			}
+		}
+
+		if len(result.Tags) > 0 {
+			fmt.Fprintf(b, "标签: %s\n", strings.Join(result.Tags, ", "))
+		}
+
+		if len(result.Links) > 0 {
+			b.WriteString("网盘链接:\n")
+			for j, link := range result.Links {
+				fmt.Fprintf(b, "   %d. [%s] %s", j+1, strings.ToUpper(link.Type), link.URL)
+				if link.Password != "" {
+					fmt.Fprintf(b, " (密码: %s)", link.Password)
+				}
+				b.WriteString("\n")
+			}
+		}
+
+		if len(result.Images) > 0 {
+			fmt.Fprintf(b, "图片: %d张\n", len(result.Images))
+		}
+
+		b.WriteString("\n")
+	}
+}
+
+// linkStatusNames 链接检测状态的中文名称
+var linkStatusNames = map[string]string{
+	"valid":          "有效",
+	"expired":        "已失效",
+	"needs_password": "需要提取码",
+	"unknown":        "未知",
+}
+
+// sortedCloudTypes 按支持列表的顺序排列网盘类型，未知类型按名称排在最后
+func sortedCloudTypes(merged model.MergedLinks) []string {
+	order := make(map[string]int, len(supportedCloudTypes))
+	for i, cloudType := range supportedCloudTypes {
+		order[cloudType] = i
+	}
+
+	types := make([]string, 0, len(merged))
+	for cloudType := range merged {
+		types = append(types, cloudType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		oi, iKnown := order[types[i]]
+		oj, jKnown := order[types[j]]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if iKnown {
+			return oi < oj
+		}
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// checkServiceHealth 执行check_service_health工具
+func (s *Server) checkServiceHealth() string {
+	channels := config.AppConfig.DefaultChannels
+	pluginsEnabled := config.AppConfig.AsyncPluginEnabled
+	pluginNames := s.pluginNames()
+
+	var b strings.Builder
+	b.WriteString("**PanSou服务健康检查**\n\n")
+	b.WriteString("**服务状态**: 正常\n\n")
+
+	b.WriteString("**TG频道信息**\n")
+	fmt.Fprintf(&b, "   频道数量: %d\n", len(channels))
+	if len(channels) > 0 {
+		b.WriteString("   可用频道:\n")
+		for i, channel := range channels {
+			fmt.Fprintf(&b, "      %d. %s\n", i+1, channel)
+		}
+	} else {
+		b.WriteString("   未配置频道\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString("**插件信息**\n")
+	if pluginsEnabled {
+		b.WriteString("   插件功能: 已启用\n")
+		fmt.Fprintf(&b, "   插件数量: %d\n", len(pluginNames))
+		if len(pluginNames) > 0 {
+			b.WriteString("   可用插件:\n")
+			// 每行显示5个插件
+			for i := 0; i < len(pluginNames); i += 5 {
+				end := i + 5
+				if end > len(pluginNames) {
+					end = len(pluginNames)
+				}
+				row := make([]string, 0, end-i)
+				for j := i; j < end; j++ {
+					row = append(row, fmt.Sprintf("%d. %s", j+1, pluginNames[j]))
+				}
+				fmt.Fprintf(&b, "      %s\n", strings.Join(row, "  "))
+			}
+		} else {
+			b.WriteString("   未发现可用插件\n")
+		}
+	} else {
+		b.WriteString("   插件功能: 已禁用\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString("**功能说明**\n")
+	b.WriteString("   支持搜索多种网盘资源\n")
+	b.WriteString("   支持TG频道和插件双重搜索\n")
+	b.WriteString("   支持并发搜索，提升搜索速度\n")
+	b.WriteString("   支持缓存机制，避免重复请求\n")
+	b.WriteString("   支持按网盘类型过滤结果\n")
+
+	return b.String()
+}
+
+// pluginNames 返回已启用的插件名称，插件功能禁用时返回空列表
+func (s *Server) pluginNames() []string {
+	names := []string{}
+	if !config.AppConfig.AsyncPluginEnabled || s.searchService == nil || s.searchService.GetPluginManager() == nil {
+		return names
+	}
+	for _, p := range s.searchService.GetPluginManager().GetPlugins() {
+		names = append(names, p.Name())
+	}
+	return names
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net"
@@ -16,12 +17,15 @@ import (
 
 	"golang.org/x/net/netutil"
 
-	"pansou/api"
+	"pansou/internal/api"
+	"pansou/internal/mcp"
 	"pansou/config"
 	"pansou/plugin"
+	"pansou/plugin/declarative"
 	"pansou/service"
 	"pansou/util"
 	"pansou/util/cache"
+	"pansou/util/logger"
 
 	// 以下是插件的空导入，用于触发各插件的init函数，实现自动注册
 	// 添加新插件时，只需在此处添加对应的导入语句即可
@@ -75,7 +79,26 @@ import (
 // 全局缓存写入管理器
 var globalCacheWriteManager *cache.DelayedBatchWriteManager
 
+// 全局后台链接复检器
+var globalLinkRevalidator *service.LinkRevalidator
+
+// 全局声明式站点插件加载器
+var globalSiteLoader *declarative.Loader
+
 func main() {
+	mcpStdio := flag.Bool("mcp-stdio", false, "以stdio方式运行MCP服务，不启动HTTP服务器")
+	flag.Parse()
+
+	if *mcpStdio {
+		// stdout专用于MCP协议消息，其余输出（日志、fmt打印）全部转到stderr
+		protocolOut := os.Stdout
+		os.Stdout = os.Stderr
+
+		initApp()
+		startMCPStdio(protocolOut)
+		return
+	}
+
 	// 初始化应用
 	initApp()
 
@@ -88,6 +111,9 @@ func initApp() {
 	// 初始化配置
 	config.Init()
 
+	// 初始化日志
+	logger.Init(config.AppConfig.LogLevel, config.AppConfig.LogFormat, config.AppConfig.PluginLogLevels)
+
 	// 初始化HTTP客户端
 	util.InitHTTPClient()
 
@@ -118,11 +144,22 @@ func initApp() {
 	plugin.InitAsyncPluginSystem()
 }
 
-// startServer 启动Web服务器
-func startServer() {
+// newSearchService 创建插件管理器和搜索服务
+func newSearchService() (*plugin.PluginManager, *service.SearchService) {
 	// 初始化插件管理器
 	pluginManager := plugin.NewPluginManager()
 
+	// 加载声明式站点插件，注册后与编译进来的插件一样按配置过滤
+	if config.AppConfig.AsyncPluginEnabled && config.AppConfig.PluginSitesDir != "" {
+		globalSiteLoader = declarative.NewLoader(config.AppConfig.PluginSitesDir, config.AppConfig.EnabledPlugins)
+		names, err := globalSiteLoader.Load()
+		if err != nil {
+			logger.Warn(context.Background(), "加载站点定义目录失败", "dir", config.AppConfig.PluginSitesDir, "error", err)
+		} else if len(names) > 0 {
+			logger.Info(context.Background(), "已加载站点插件", "count", len(names), "plugins", names)
+		}
+	}
+
 	// 注册全局插件（根据配置过滤）
 	if config.AppConfig.AsyncPluginEnabled {
 		pluginManager.RegisterGlobalPluginsWithFilter(config.AppConfig.EnabledPlugins)
@@ -138,6 +175,24 @@ func startServer() {
 	// 初始化搜索服务
 	searchService := service.NewSearchService(pluginManager)
 
+	// 热加载站点定义，启用新插件时需要通过搜索服务注入主缓存
+	if globalSiteLoader != nil {
+		globalSiteLoader.Watch(pluginManager, searchService, config.AppConfig.PluginSitesReloadInterval)
+	}
+
+	// 启动后台链接复检（依赖搜索服务初始化的主缓存）
+	if config.AppConfig.LinkRevalidateEnabled && config.AppConfig.CacheEnabled {
+		globalLinkRevalidator = service.NewLinkRevalidator()
+		globalLinkRevalidator.Start()
+	}
+
+	return pluginManager, searchService
+}
+
+// startServer 启动Web服务器
+func startServer() {
+	pluginManager, searchService := newSearchService()
+
 	// 设置路由
 	router := api.SetupRouter(searchService)
 
@@ -190,8 +245,51 @@ func startServer() {
 	fmt.Println("正在关闭服务器...")
 
 	// 优先保存缓存数据到磁盘（数据安全第一）
+	saveCaches()
+
+	// 设置关闭超时时间
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// 优雅关闭服务器
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("服务器关闭异常: %v", err)
+	}
+
+	fmt.Println("服务器已安全关闭")
+}
+
+// startMCPStdio 以stdio方式运行MCP服务，标准输入关闭或收到中断信号时退出
+func startMCPStdio(out *os.File) {
+	_, searchService := newSearchService()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info(ctx, "MCP服务已启动(stdio)")
+	if err := mcp.NewServer(searchService).ServeStdio(ctx, os.Stdin, out); err != nil && err != context.Canceled {
+		logger.Error(ctx, "MCP服务异常退出", "error", err)
+	}
+
+	saveCaches()
+}
+
+// saveCaches 退出前将缓存数据保存到磁盘
+func saveCaches() {
 	// 增加关闭超时时间，确保数据有足够时间保存
 	shutdownTimeout := 10 * time.Second
+
+	// 停止站点定义热加载
+	if globalSiteLoader != nil {
+		globalSiteLoader.Stop()
+	}
+
+	// 先停止链接复检，保存判定结果后再关闭写入管理器
+	if globalLinkRevalidator != nil {
+		if err := globalLinkRevalidator.Shutdown(shutdownTimeout); err != nil {
+			logger.Warn(context.Background(), "链接复检停止失败", "error", err)
+		}
+	}
 	
 	if globalCacheWriteManager != nil {
 		if err := globalCacheWriteManager.Shutdown(shutdownTimeout); err != nil {
@@ -205,17 +303,6 @@ func startServer() {
 			log.Printf("内存缓存同步失败: %v", err)
 		} 
 	}
-
-	// 设置关闭超时时间
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	// 优雅关闭服务器
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("服务器关闭异常: %v", err)
-	}
-
-	fmt.Println("服务器已安全关闭")
 }
 
 // printServiceInfo 打印服务信息
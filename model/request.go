@@ -11,4 +11,47 @@ type SearchRequest struct {
 	Plugins      []string               `json:"plugins"`                     // 指定搜索的插件列表，不指定则搜索全部插件
 	Ext          map[string]interface{} `json:"ext"`                         // 扩展参数，用于传递给插件的自定义参数
 	CloudTypes   []string               `json:"cloud_types"`                 // 指定返回的网盘类型列表，不指定则返回所有类型
+	Page         int                    `json:"page"`                        // 页码，从1开始，不指定则不分页
+	PageSize     int                    `json:"page_size"`                   // 每页数量，不指定则使用默认值
+	Cursor       string                 `json:"cursor"`                      // 分页游标，来自上一页响应的next_cursor，优先于page
+	CheckLinks   bool                   `json:"check_links"`                 // 是否检测merged_by_type中链接的有效性并标注status
+	DropDead     bool                   `json:"drop_dead"`                   // 检测链接时是否移除已失效的链接，仅check_links=true时生效
+	Sort         string                 `json:"sort"`                        // 排序方式：relevance(默认，综合得分)、time(发布时间)、source(来源插件等级)
+	Explain      bool                   `json:"explain"`                     // 是否在结果中返回排序得分明细，用于调试
+	Group        string                 `json:"group"`                       // 链接分组方式：type(默认，按网盘类型)、work(按作品，返回works)
+	Format       string                 `json:"format"`                      // 响应格式：json(默认)、csv、jsonl、rss、atom，未指定时按Accept头选择
+	Filter       ResultFilter           `json:"-"`                           // 从关键词的查询语法中解析出的结果过滤条件
+}
+
+// ResultFilter 在合并结果前执行的过滤条件，来自关键词中的查询语法
+type ResultFilter struct {
+	Excludes    []string // 标题和内容中不能包含的词
+	Phrases     []string // 标题和内容中必须包含的短语
+	Years       []int    // 标题和内容中必须出现的年份，满足其一即可
+	Resolutions []string // 解析出的分辨率（标准值），满足其一即可
+	Codecs      []string // 解析出的视频编码（标准值），满足其一即可
+	HDR         []string // 解析出的动态范围（标准值），满足其一即可
+	Seasons     []int    // 解析出的季号或季范围需包含其一
+	Subtitles   []string // 解析出的字幕语言（标准值），满足其一即可
+	MinSize     int64    // 解析出的文件大小下限（字节），0表示不限
+	MaxSize     int64    // 解析出的文件大小上限（字节），0表示不限
+}
+
+// IsEmpty 判断是否没有任何过滤条件
+func (f ResultFilter) IsEmpty() bool {
+	return len(f.Excludes) == 0 && len(f.Phrases) == 0 && len(f.Years) == 0 && len(f.Resolutions) == 0 &&
+		len(f.Codecs) == 0 && len(f.HDR) == 0 && len(f.Seasons) == 0 && len(f.Subtitles) == 0 &&
+		f.MinSize == 0 && f.MaxSize == 0
+}
+
+// ResultOptions 搜索结果的处理选项，只影响结果的过滤、排序和展示，不影响搜索缓存
+type ResultOptions struct {
+	Sort    string       // 排序方式：relevance/time/source，为空时使用relevance
+	Explain bool         // 是否返回排序得分明细
+	Filter  ResultFilter // 结果过滤条件
+}
+
+// ResultOptions 获取请求中的结果处理选项
+func (r SearchRequest) ResultOptions() ResultOptions {
+	return ResultOptions{Sort: r.Sort, Explain: r.Explain, Filter: r.Filter}
 } 
@@ -20,6 +20,8 @@ type SearchResult struct {
 	Links     []Link    `json:"links" sonic:"links"`
 	Tags      []string  `json:"tags,omitempty" sonic:"tags,omitempty"`
 	Images    []string  `json:"images,omitempty" sonic:"images,omitempty"` // TG消息中的图片链接
+	Score     *ScoreBreakdown `json:"score,omitempty" sonic:"score,omitempty"` // 排序得分明细，仅explain=true时返回
+	Release   *ReleaseInfo    `json:"release,omitempty" sonic:"release,omitempty"` // 从标题和内容中解析出的资源信息
 }
 
 // MergedLink 合并后的网盘链接
@@ -30,16 +32,61 @@ type MergedLink struct {
 	Datetime time.Time `json:"datetime" sonic:"datetime"`
 	Source   string    `json:"source,omitempty" sonic:"source,omitempty"` // 数据来源：tg:频道名 或 plugin:插件名
 	Images   []string  `json:"images,omitempty" sonic:"images,omitempty"`   // TG消息中的图片链接
+	Status   string    `json:"status,omitempty" sonic:"status,omitempty"`   // 链接检测状态：valid/expired/needs_password/unknown，仅check_links=true时返回
+	Score    *ScoreBreakdown `json:"score,omitempty" sonic:"score,omitempty"` // 所属结果的排序得分明细，仅explain=true时返回
+	Sources   []string  `json:"sources,omitempty" sonic:"sources,omitempty"` // 发布过该资源的所有来源，同一资源的不同链接写法会合并为一条
+	FirstSeen time.Time `json:"first_seen" sonic:"first_seen"`               // 各来源中最早的发布时间
+	LastSeen  time.Time `json:"last_seen" sonic:"last_seen"`                 // 各来源中最晚的发布时间
+	Release   *ReleaseInfo `json:"release,omitempty" sonic:"release,omitempty"` // 从链接标题中解析出的资源信息
+}
+
+// ReleaseInfo 从资源标题中解析出的结构化信息，未识别的字段为空
+type ReleaseInfo struct {
+	Resolution string   `json:"resolution,omitempty" sonic:"resolution,omitempty"`   // 分辨率：4320p/2160p/1440p/1080p/720p/576p/480p
+	HDR        []string `json:"hdr,omitempty" sonic:"hdr,omitempty"`                 // 动态范围：HDR/HDR10/HDR10+/DV/HLG
+	Codec      string   `json:"codec,omitempty" sonic:"codec,omitempty"`             // 视频编码：H.265/H.264/AV1/VP9
+	Audio      []string `json:"audio,omitempty" sonic:"audio,omitempty"`             // 音频格式：Atmos/TrueHD/DTS-HD/DTS:X/DTS/DDP/AC3/AAC/FLAC
+	Season     int      `json:"season,omitempty" sonic:"season,omitempty"`           // 季号，范围时为起始季
+	SeasonEnd  int      `json:"season_end,omitempty" sonic:"season_end,omitempty"`   // 结束季，仅季号为范围时返回
+	Episode    int      `json:"episode,omitempty" sonic:"episode,omitempty"`         // 集号，范围时为起始集
+	EpisodeEnd int      `json:"episode_end,omitempty" sonic:"episode_end,omitempty"` // 结束集，仅集号为范围时返回
+	Complete   bool     `json:"complete,omitempty" sonic:"complete,omitempty"`       // 是否完结或全集
+	Size       string   `json:"size,omitempty" sonic:"size,omitempty"`               // 文件大小，如58.3GB
+	SizeBytes  int64    `json:"size_bytes,omitempty" sonic:"size_bytes,omitempty"`   // 文件大小（字节）
+	Year       int      `json:"year,omitempty" sonic:"year,omitempty"`               // 年份
+	Subtitles  []string `json:"subtitles,omitempty" sonic:"subtitles,omitempty"`     // 字幕语言：zh-hans/zh-hant/zh/en
+}
+
+// ScoreBreakdown 排序得分明细
+type ScoreBreakdown struct {
+	Total      float64            `json:"total" sonic:"total"`           // 综合得分
+	Components map[string]float64 `json:"components" sonic:"components"` // 各分量加权后的得分：time/keyword/plugin/title/quality
 }
 
 // MergedLinks 按网盘类型分组的合并链接
 type MergedLinks map[string][]MergedLink
 
+// WorkGroup 同一作品（同名、同年份、同季）在各网盘类型中的链接
+type WorkGroup struct {
+	Title        string      `json:"title" sonic:"title"`                               // 代表标题，取组内最常见的作品名
+	Year         int         `json:"year,omitempty" sonic:"year,omitempty"`             // 年份
+	Season       int         `json:"season,omitempty" sonic:"season,omitempty"`         // 季号，范围时为起始季
+	SeasonEnd    int         `json:"season_end,omitempty" sonic:"season_end,omitempty"` // 结束季，仅季号为范围时返回
+	Image        string      `json:"image,omitempty" sonic:"image,omitempty"`           // 代表图片，取组内第一个带图片的链接
+	Total        int         `json:"total" sonic:"total"`                               // 组内链接数
+	MergedByType MergedLinks `json:"merged_by_type" sonic:"merged_by_type"`             // 按网盘类型分组的链接，保持原有顺序
+}
+
 // SearchResponse 搜索响应
 type SearchResponse struct {
 	Total        int           `json:"total" sonic:"total"`
 	Results      []SearchResult `json:"results,omitempty" sonic:"results,omitempty"`
 	MergedByType MergedLinks   `json:"merged_by_type,omitempty" sonic:"merged_by_type,omitempty"`
+	Works        []WorkGroup   `json:"works,omitempty" sonic:"works,omitempty"`             // 按作品分组的链接（仅group=work时返回，此时不返回merged_by_type）
+	HasMore      bool          `json:"has_more" sonic:"has_more"`                           // 是否还有下一页
+	Page         int           `json:"page,omitempty" sonic:"page,omitempty"`               // 当前页码（仅分页请求返回）
+	PageSize     int           `json:"page_size,omitempty" sonic:"page_size,omitempty"`     // 每页数量（仅分页请求返回）
+	NextCursor   string        `json:"next_cursor,omitempty" sonic:"next_cursor,omitempty"` // 下一页游标，没有下一页时为空
 }
 
 // Response API通用响应
@@ -64,4 +111,13 @@ func NewErrorResponse(code int, message string) Response {
 		Code:    code,
 		Message: message,
 	}
-}
\ No newline at end of file
+}
+// SearchStreamEvent 流式搜索中单个来源完成时推送的事件
+type SearchStreamEvent struct {
+	Source    string         `json:"source" sonic:"source"`                       // 数据来源：tg:频道名、plugin:插件名，缓存命中时为tg或plugin
+	Count     int            `json:"count" sonic:"count"`                         // 该来源过滤后的结果数
+	ElapsedMs int64          `json:"elapsed_ms" sonic:"elapsed_ms"`               // 自搜索开始的耗时（毫秒）
+	Cached    bool           `json:"cached" sonic:"cached"`                       // 是否来自缓存
+	Error     string         `json:"error,omitempty" sonic:"error,omitempty"`     // 该来源的错误信息
+	Results   []SearchResult `json:"results,omitempty" sonic:"results,omitempty"` // 该来源的结果
+}
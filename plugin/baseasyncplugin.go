@@ -1,15 +1,17 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"pansou/config"
 	"pansou/model"
+	"pansou/util/logger"
 )
 
 // 工作池和统计相关变量
@@ -94,7 +96,7 @@ func cleanupExpiredApiCache() {
 	
 	// 记录清理日志（仅在有清理时输出）
 	if cleanedCount > 0 {
-		fmt.Printf("[Cache] 清理过期缓存: 删除 %d/%d 项，释放内存\n", cleanedCount, totalCount)
+		logger.Debug(context.Background(), "清理过期插件缓存", "removed", cleanedCount, "total", totalCount)
 	}
 }
 
@@ -141,6 +143,7 @@ func acquireWorkerSlot() bool {
 	
 	// 检查总任务数
 	if atomic.LoadInt32(&backgroundTasksCount) >= maxTasks {
+		atomic.AddInt64(&workerSlotRejections, 1)
 		return false
 	}
 	
@@ -150,6 +153,7 @@ func acquireWorkerSlot() bool {
 		atomic.AddInt32(&backgroundTasksCount, 1)
 		return true
 	default:
+		atomic.AddInt64(&workerSlotRejections, 1)
 		return false
 	}
 }
@@ -203,9 +207,7 @@ type BaseAsyncPlugin struct {
 	client             *http.Client  // 用于短超时的客户端
 	backgroundClient   *http.Client  // 用于长超时的客户端
 	cacheTTL           time.Duration // 内存缓存有效期
-	mainCacheUpdater   func(string, []model.SearchResult, time.Duration, bool, string) error // 主缓存更新函数（支持IsFinal参数，接收原始数据，最后参数为关键词）
-	MainCacheKey       string        // 主缓存键，导出字段
-	currentKeyword     string        // 当前搜索的关键词，用于日志显示
+	mainCacheUpdater   MainCacheUpdater // 主缓存更新函数（支持IsFinal参数，接收原始数据，按调用传入关键词和主缓存键）
 	finalUpdateTracker map[string]bool // 追踪已更新的最终结果缓存
 	finalUpdateMutex   sync.RWMutex  // 保护finalUpdateTracker的并发访问
 	skipServiceFilter  bool          // 是否跳过Service层的关键词过滤
@@ -279,18 +281,8 @@ func NewBaseAsyncPluginWithFilter(name string, priority int, skipServiceFilter b
 	}
 }
 
-// SetMainCacheKey 设置主缓存键
-func (p *BaseAsyncPlugin) SetMainCacheKey(key string) {
-	p.MainCacheKey = key
-}
-
-// SetCurrentKeyword 设置当前搜索关键词（用于日志显示）
-func (p *BaseAsyncPlugin) SetCurrentKeyword(keyword string) {
-	p.currentKeyword = keyword
-}
-
-// SetMainCacheUpdater 设置主缓存更新函数（修复后的签名，增加关键词参数）
-func (p *BaseAsyncPlugin) SetMainCacheUpdater(updater func(string, []model.SearchResult, time.Duration, bool, string) error) {
+// SetMainCacheUpdater 设置主缓存更新函数
+func (p *BaseAsyncPlugin) SetMainCacheUpdater(updater MainCacheUpdater) {
 	p.mainCacheUpdater = updater
 }
 
@@ -299,6 +291,11 @@ func (p *BaseAsyncPlugin) Name() string {
 	return p.name
 }
 
+// Logger 返回插件日志器，日志级别可通过PLUGIN_LOG_LEVELS单独设置
+func (p *BaseAsyncPlugin) Logger() *logger.PluginLogger {
+	return logger.ForPlugin(p.name)
+}
+
 // Priority 返回插件优先级
 func (p *BaseAsyncPlugin) Priority() int {
 	return p.priority
@@ -309,22 +306,46 @@ func (p *BaseAsyncPlugin) SkipServiceFilter() bool {
 	return p.skipServiceFilter
 }
 
-// AsyncSearch 异步搜索基础方法
+// AsyncSearch 异步搜索基础方法（兼容方法，使用ext中携带的请求上下文）
 func (p *BaseAsyncPlugin) AsyncSearch(
 	keyword string,
 	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
 	mainCacheKey string,
 	ext map[string]interface{},
 ) ([]model.SearchResult, error) {
-	// 确保ext不为nil
-	if ext == nil {
-		ext = make(map[string]interface{})
+	return p.AsyncSearchCtx(SearchContextFromExt(ext), keyword, searchFunc, mainCacheKey, ext)
+}
+
+// AsyncSearchCtx 支持上下文取消的异步搜索方法
+func (p *BaseAsyncPlugin) AsyncSearchCtx(
+	ctx context.Context,
+	keyword string,
+	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
+	mainCacheKey string,
+	ext map[string]interface{},
+) ([]model.SearchResult, error) {
+	return p.AsyncSearchCall(NewSearchCall(ctx, keyword, mainCacheKey, ext), searchFunc)
+}
+
+// pluginCacheKey 生成插件级缓存键，ext非空时加入ext的哈希，避免带不同ext的请求读到彼此的缓存
+func (p *BaseAsyncPlugin) pluginCacheKey(call *SearchCall) string {
+	if extHash := ExtHash(call.Ext); extHash != "" {
+		return fmt.Sprintf("%s:%s:%s", p.name, call.Keyword, extHash)
 	}
-	
+	return fmt.Sprintf("%s:%s", p.name, call.Keyword)
+}
+
+// AsyncSearchCall 按单次调用状态执行异步搜索
+// 响应前call.Ctx被取消时中断插件正在进行的HTTP请求；响应超时转入后台处理后不再受其影响
+func (p *BaseAsyncPlugin) AsyncSearchCall(
+	call *SearchCall,
+	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
+) ([]model.SearchResult, error) {
+	keyword := call.Keyword
 	now := time.Now()
 	
-	// 修改缓存键，确保包含插件名称
-	pluginSpecificCacheKey := fmt.Sprintf("%s:%s", p.name, keyword)
+	// 修改缓存键，确保包含插件名称和ext
+	pluginSpecificCacheKey := p.pluginCacheKey(call)
 	
 	// 检查缓存
 	if cachedItems, ok := apiResponseCache.Load(pluginSpecificCacheKey); ok {
@@ -334,10 +355,11 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 		if time.Since(cachedResult.Timestamp) < p.cacheTTL && cachedResult.Complete {
 			recordCacheHit()
 			recordCacheAccess(pluginSpecificCacheKey)
+			searchReportFromContext(call.Ctx).markCacheHit()
 			
 			// 如果缓存接近过期（已用时间超过TTL的80%），在后台刷新缓存
 			if time.Since(cachedResult.Timestamp) > (p.cacheTTL * 4 / 5) {
-				go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, mainCacheKey, ext)
+				go p.refreshCacheInBackground(call.detached(), pluginSpecificCacheKey, searchFunc, cachedResult)
 			}
 			
 			return cachedResult.Results, nil
@@ -347,23 +369,38 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 		if len(cachedResult.Results) > 0 {
 			recordCacheHit()
 			recordCacheAccess(pluginSpecificCacheKey)
+			searchReportFromContext(call.Ctx).markCacheHit()
 			
 			// 标记为部分过期
 			if time.Since(cachedResult.Timestamp) >= p.cacheTTL {
 				// 在后台刷新缓存
-				go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, mainCacheKey, ext)
+				go p.refreshCacheInBackground(call.detached(), pluginSpecificCacheKey, searchFunc, cachedResult)
 				
 				// 日志记录
-				fmt.Printf("[%s] 缓存已过期，后台刷新中: %s (已过期: %v)\n", 
-					p.name, pluginSpecificCacheKey, time.Since(cachedResult.Timestamp))
+				p.Logger().Log(call.Ctx, slog.LevelInfo, "缓存已过期，后台刷新中",
+					"cache_key", pluginSpecificCacheKey, "expired_for", time.Since(cachedResult.Timestamp).String())
 			}
 			
 			return cachedResult.Results, nil
 		}
 	}
 	
+	// 请求已取消，不再发起搜索
+	if err := call.Ctx.Err(); err != nil {
+		return nil, err
+	}
+	
 	recordCacheMiss()
 	
+	// 绑定请求上下文：响应前取消则中断插件请求，转入后台处理后不再受影响
+	callCtx, detach := detachableContext(call.Ctx)
+	ext := call.withContext(callCtx).ExtParams()
+	client := contextClient(callCtx, p.client)
+	backgroundClient := contextClient(callCtx, p.backgroundClient)
+	
+	// 跟踪搜索执行情况，响应超时后由后台搜索的结果决定本次调用的成败
+	track := searchReportFromContext(call.Ctx).track()
+	
 	// 创建通道
 	resultChan := make(chan []model.SearchResult, 1)
 	errorChan := make(chan error, 1)
@@ -374,7 +411,8 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 		// 尝试获取工作槽
 		if !acquireWorkerSlot() {
 			// 工作池已满，使用快速响应客户端直接处理
-			results, err := searchFunc(p.client, keyword, ext)
+			results, err := searchFunc(client, keyword, ext)
+			track.done(err)
 			if err != nil {
 				select {
 				case errorChan <- err:
@@ -398,14 +436,15 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 			})
 			
 			// 🔧 工作池满时短超时(默认4秒)内完成，这是完整结果
-			p.updateMainCacheWithFinal(mainCacheKey, results, true)
+			p.updateMainCacheWithFinal(call, results, true)
 			
 			return
 		}
 		defer releaseWorkerSlot()
 		
 		// 执行搜索
-		results, err := searchFunc(p.backgroundClient, keyword, ext)
+		results, err := searchFunc(backgroundClient, keyword, ext)
+		track.done(err)
 		
 		// 检查是否已经响应
 		select {
@@ -455,7 +494,7 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 				recordAsyncCompletion()
 				
 				// 异步插件后台完成时更新主缓存（标记为最终结果）
-				p.updateMainCacheWithFinal(mainCacheKey, results, true)
+				p.updateMainCacheWithFinal(call, results, true)
 				
 				// 异步插件本地缓存系统已移除
 			}
@@ -508,7 +547,7 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 				})
 				
 				// 🔧 短超时(默认4秒)内正常完成，这是完整的最终结果
-				p.updateMainCacheWithFinal(mainCacheKey, results, true)
+				p.updateMainCacheWithFinal(call, results, true)
 				
 				// 异步插件本地缓存系统已移除
 			}
@@ -524,12 +563,22 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 	// 等待响应超时或结果
 	select {
 	case results := <-resultChan:
+		detach()
 		close(doneChan)
 		return results, nil
 	case err := <-errorChan:
+		detach()
 		close(doneChan)
 		return nil, err
+	case <-call.Ctx.Done():
+		// 客户端断开或请求超时，中断插件请求
+		close(doneChan)
+		return nil, call.Ctx.Err()
 	case <-time.After(responseTimeout):
+		// 转入后台继续处理，不再受请求上下文影响
+		detach()
+		track.timeout()
+		
 		// 插件响应超时，后台继续处理（优化完成，日志简化）
 		
 		// 响应超时，返回空结果，后台继续处理
@@ -543,8 +592,8 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 			if len(cachedResult.Results) > 0 {
 				// 有部分缓存可用，记录访问并返回
 				recordCacheAccess(pluginSpecificCacheKey)
-				fmt.Printf("[%s] 响应超时，返回部分缓存: %s (项目数: %d)\n", 
-					p.name, pluginSpecificCacheKey, len(cachedResult.Results))
+				p.Logger().Log(call.Ctx, slog.LevelInfo, "响应超时，返回部分缓存",
+					"cache_key", pluginSpecificCacheKey, "results", len(cachedResult.Results))
 				return cachedResult.Results, nil
 			}
 		}
@@ -559,29 +608,33 @@ func (p *BaseAsyncPlugin) AsyncSearch(
 		})
 		
 		// 🔧 修复：4秒超时时也要更新主缓存，标记为部分结果（空结果）
-		p.updateMainCacheWithFinal(mainCacheKey, []model.SearchResult{}, false)
+		p.updateMainCacheWithFinal(call, []model.SearchResult{}, false)
 		
-		// fmt.Printf("[%s] 响应超时，后台继续处理: %s\n", p.name, pluginSpecificCacheKey)
+		p.Logger().Log(call.Ctx, slog.LevelDebug, "响应超时，后台继续处理", "cache_key", pluginSpecificCacheKey)
 		return []model.SearchResult{}, nil
 	}
 }
 
 // AsyncSearchWithResult 异步搜索方法，返回PluginSearchResult
+// 请求上下文和主缓存键从ext携带的调用状态中获取，插件的Search方法直接透传ext即可
 func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 	keyword string,
 	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
-	mainCacheKey string,
 	ext map[string]interface{},
 ) (model.PluginSearchResult, error) {
-	// 确保ext不为nil
-	if ext == nil {
-		ext = make(map[string]interface{})
-	}
-	
+	return p.AsyncSearchWithResultCall(searchCallForKeyword(keyword, ext), searchFunc)
+}
+
+// AsyncSearchWithResultCall 按单次调用状态执行异步搜索，返回PluginSearchResult
+func (p *BaseAsyncPlugin) AsyncSearchWithResultCall(
+	call *SearchCall,
+	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
+) (model.PluginSearchResult, error) {
+	keyword := call.Keyword
 	now := time.Now()
 	
-	// 修改缓存键，确保包含插件名称
-	pluginSpecificCacheKey := fmt.Sprintf("%s:%s", p.name, keyword)
+	// 修改缓存键，确保包含插件名称和ext
+	pluginSpecificCacheKey := p.pluginCacheKey(call)
 	
 	// 检查缓存
 	if cachedItems, ok := apiResponseCache.Load(pluginSpecificCacheKey); ok {
@@ -591,10 +644,11 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		if time.Since(cachedResult.Timestamp) < p.cacheTTL && cachedResult.Complete {
 			recordCacheHit()
 			recordCacheAccess(pluginSpecificCacheKey)
+			searchReportFromContext(call.Ctx).markCacheHit()
 			
 			// 如果缓存接近过期（已用时间超过TTL的80%），在后台刷新缓存
 			if time.Since(cachedResult.Timestamp) > (p.cacheTTL * 4 / 5) {
-				go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, mainCacheKey, ext)
+				go p.refreshCacheInBackground(call.detached(), pluginSpecificCacheKey, searchFunc, cachedResult)
 			}
 			
 			return model.PluginSearchResult{
@@ -610,11 +664,12 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		if len(cachedResult.Results) > 0 {
 			recordCacheHit()
 			recordCacheAccess(pluginSpecificCacheKey)
+			searchReportFromContext(call.Ctx).markCacheHit()
 			
 			// 标记为部分过期
 			if time.Since(cachedResult.Timestamp) >= p.cacheTTL {
 				// 在后台刷新缓存
-				go p.refreshCacheInBackground(keyword, pluginSpecificCacheKey, searchFunc, cachedResult, mainCacheKey, ext)
+				go p.refreshCacheInBackground(call.detached(), pluginSpecificCacheKey, searchFunc, cachedResult)
 			}
 			
 			return model.PluginSearchResult{
@@ -627,8 +682,19 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		}
 	}
 	
+	// 请求已取消，不再发起搜索
+	if err := call.Ctx.Err(); err != nil {
+		return model.PluginSearchResult{}, err
+	}
+	
 	recordCacheMiss()
 	
+	// 绑定请求上下文：响应前取消则中断插件请求，转入后台处理后不再受影响
+	callCtx, detach := detachableContext(call.Ctx)
+	ext := call.withContext(callCtx).ExtParams()
+	client := contextClient(callCtx, p.client)
+	backgroundClient := contextClient(callCtx, p.backgroundClient)
+	
 	// 创建通道
 	resultChan := make(chan []model.SearchResult, 1)
 	errorChan := make(chan error, 1)
@@ -647,7 +713,7 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		// 尝试获取工作槽
 		if !acquireWorkerSlot() {
 			// 工作池已满，使用快速响应客户端直接处理
-			results, err := searchFunc(p.client, keyword, ext)
+			results, err := searchFunc(client, keyword, ext)
 			if err != nil {
 				select {
 				case errorChan <- err:
@@ -665,7 +731,7 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		defer releaseWorkerSlot()
 		
 		// 使用长超时客户端进行搜索
-		results, err := searchFunc(p.backgroundClient, keyword, ext)
+		results, err := searchFunc(backgroundClient, keyword, ext)
 		if err != nil {
 			select {
 			case errorChan <- err:
@@ -688,6 +754,7 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 	select {
 	case results := <-resultChan:
 		// 不直接关闭，让defer处理
+		detach()
 		
 		// 缓存结果
 		apiResponseCache.Store(pluginSpecificCacheKey, cachedResponse{
@@ -700,10 +767,10 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		
 		// 🔧 恢复主缓存更新：使用统一的GOB序列化
 		// 传递原始数据，由主程序负责序列化
-		if mainCacheKey != "" && p.mainCacheUpdater != nil {
-			err := p.mainCacheUpdater(mainCacheKey, results, p.cacheTTL, true, p.currentKeyword)
+		if call.MainCacheKey != "" && p.mainCacheUpdater != nil {
+			err := p.mainCacheUpdater(call, results, p.cacheTTL, true)
 			if err != nil {
-				fmt.Printf("❌ [%s] 及时完成缓存更新失败: %s | 错误: %v\n", p.name, mainCacheKey, err)
+				p.Logger().Log(call.Ctx, slog.LevelError, "及时完成缓存更新失败", "cache_key", call.MainCacheKey, "error", err)
 			}
 		}
 		
@@ -717,11 +784,19 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 		
 	case err := <-errorChan:
 		// 不直接关闭，让defer处理
+		detach()
 		return model.PluginSearchResult{}, err
 		
+	case <-call.Ctx.Done():
+		// 客户端断开或请求超时，中断插件请求
+		return model.PluginSearchResult{}, call.Ctx.Err()
+		
 	case <-time.After(responseTimeout):
-		// 🔥 超时处理：返回空结果，后台继续处理
-		go p.completeSearchInBackground(keyword, searchFunc, pluginSpecificCacheKey, mainCacheKey, doneChan, ext)
+		// 🔥 超时处理：返回空结果，后台继续处理，不再受请求上下文影响
+		detach()
+		track := searchReportFromContext(call.Ctx).track()
+		track.timeout()
+		go p.completeSearchInBackground(call.detached(), searchFunc, pluginSpecificCacheKey, doneChan, track)
 		
 		// 存储临时缓存（标记为不完整）
 		apiResponseCache.Store(pluginSpecificCacheKey, cachedResponse{
@@ -744,12 +819,11 @@ func (p *BaseAsyncPlugin) AsyncSearchWithResult(
 
 // completeSearchInBackground 后台完成搜索
 func (p *BaseAsyncPlugin) completeSearchInBackground(
-	keyword string,
+	call *SearchCall,
 	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
 	pluginCacheKey string,
-	mainCacheKey string,
 	doneChan chan struct{},
-	ext map[string]interface{},
+	track *searchTrack,
 ) {
 	defer func() {
 		select {
@@ -760,7 +834,8 @@ func (p *BaseAsyncPlugin) completeSearchInBackground(
 	}()
 	
 	// 执行完整搜索
-	results, err := searchFunc(p.backgroundClient, keyword, ext)
+	results, err := searchFunc(p.backgroundClient, call.Keyword, call.ExtParams())
+	track.done(err)
 	if err != nil {
 		return
 	}
@@ -777,28 +852,21 @@ func (p *BaseAsyncPlugin) completeSearchInBackground(
 	
 	// 🔧 恢复主缓存更新：使用统一的GOB序列化
 	// 传递原始数据，由主程序负责序列化
-	if mainCacheKey != "" && p.mainCacheUpdater != nil {
-		err := p.mainCacheUpdater(mainCacheKey, results, p.cacheTTL, true, p.currentKeyword)
+	if call.MainCacheKey != "" && p.mainCacheUpdater != nil {
+		err := p.mainCacheUpdater(call, results, p.cacheTTL, true)
 		if err != nil {
-			fmt.Printf("❌ [%s] 后台完成缓存更新失败: %s | 错误: %v\n", p.name, mainCacheKey, err)
+			p.Logger().Log(call.Ctx, slog.LevelError, "后台完成缓存更新失败", "cache_key", call.MainCacheKey, "error", err)
 		}
 	}
 }
 
 // refreshCacheInBackground 在后台刷新缓存
 func (p *BaseAsyncPlugin) refreshCacheInBackground(
-	keyword string,
+	call *SearchCall,
 	cacheKey string,
 	searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error),
 	oldCache cachedResponse,
-	originalCacheKey string,
-	ext map[string]interface{},
 ) {
-	// 确保ext不为nil
-	if ext == nil {
-		ext = make(map[string]interface{})
-	}
-	
 	// 注意：这里的cacheKey已经是插件特定的了，因为是从AsyncSearch传入的
 	
 	// 检查是否有足够的工作槽
@@ -811,7 +879,7 @@ func (p *BaseAsyncPlugin) refreshCacheInBackground(
 	refreshStart := time.Now()
 	
 	// 执行搜索
-	results, err := searchFunc(p.backgroundClient, keyword, ext)
+	results, err := searchFunc(p.backgroundClient, call.Keyword, call.ExtParams())
 	if err != nil || len(results) == 0 {
 		return
 	}
@@ -843,23 +911,25 @@ func (p *BaseAsyncPlugin) refreshCacheInBackground(
 	})
 	
 	// 🔥 异步插件后台刷新完成时更新主缓存（标记为最终结果）
-	p.updateMainCacheWithFinal(originalCacheKey, mergedResults, true)
+	p.updateMainCacheWithFinal(call, mergedResults, true)
 	
 	// 记录刷新时间
 	refreshTime := time.Since(refreshStart)
-	fmt.Printf("[%s] 后台刷新完成: %s (耗时: %v, 新项目: %d, 合并项目: %d)\n", 
-		p.name, cacheKey, refreshTime, len(results), len(mergedResults))
+	p.Logger().Log(call.Ctx, slog.LevelInfo, "后台刷新完成",
+		"cache_key", cacheKey, "elapsed", refreshTime.String(), "new", len(results), "merged", len(mergedResults))
 	
 	// 异步插件本地缓存系统已移除
 } 
 
 // updateMainCache 更新主缓存系统（兼容性方法，默认IsFinal=true）
-func (p *BaseAsyncPlugin) updateMainCache(cacheKey string, results []model.SearchResult) {
-	p.updateMainCacheWithFinal(cacheKey, results, true)
+func (p *BaseAsyncPlugin) updateMainCache(call *SearchCall, results []model.SearchResult) {
+	p.updateMainCacheWithFinal(call, results, true)
 }
 
 // updateMainCacheWithFinal 更新主缓存系统，支持IsFinal参数
-func (p *BaseAsyncPlugin) updateMainCacheWithFinal(cacheKey string, results []model.SearchResult, isFinal bool) {
+func (p *BaseAsyncPlugin) updateMainCacheWithFinal(call *SearchCall, results []model.SearchResult, isFinal bool) {
+	cacheKey := call.MainCacheKey
+	
 	// 如果主缓存更新函数为空或缓存键为空，直接返回
 	if p.mainCacheUpdater == nil || cacheKey == "" {
 		return
@@ -872,9 +942,9 @@ func (p *BaseAsyncPlugin) updateMainCacheWithFinal(cacheKey string, results []mo
 	
 	// 🔥 增强防重复更新机制 - 使用数据哈希确保真正的去重
 	// 生成结果数据的简单哈希标识
-	dataHash := fmt.Sprintf("%d_%d", len(results), results[0].UniqueID)
+	dataHash := fmt.Sprintf("%d_%s", len(results), results[0].UniqueID)
 	if len(results) > 1 {
-		dataHash += fmt.Sprintf("_%d", results[len(results)-1].UniqueID)
+		dataHash += fmt.Sprintf("_%s", results[len(results)-1].UniqueID)
 	}
 	updateKey := fmt.Sprintf("final_%s_%s_%s_%t", p.name, cacheKey, dataHash, isFinal)
 	
@@ -889,50 +959,17 @@ func (p *BaseAsyncPlugin) updateMainCacheWithFinal(cacheKey string, results []mo
 	// 🔧 恢复异步插件缓存更新，使用修复后的统一序列化
 	// 传递原始数据，由主程序负责GOB序列化
 	if p.mainCacheUpdater != nil {
-		err := p.mainCacheUpdater(cacheKey, results, p.cacheTTL, isFinal, p.currentKeyword)
+		err := p.mainCacheUpdater(call, results, p.cacheTTL, isFinal)
 		if err != nil {
-			fmt.Printf("❌ [%s] 主缓存更新失败: %s | 错误: %v\n", p.name, cacheKey, err)
+			p.Logger().Log(call.Ctx, slog.LevelError, "主缓存更新失败", "cache_key", cacheKey, "error", err)
 		}
 	}
 } 
 
 // FilterResultsByKeyword 根据关键词过滤搜索结果
 func (p *BaseAsyncPlugin) FilterResultsByKeyword(results []model.SearchResult, keyword string) []model.SearchResult {
-	if keyword == "" {
-		return results
-	}
-	
-	// 预估过滤后会保留80%的结果
-	filteredResults := make([]model.SearchResult, 0, len(results)*8/10)
-
-	// 将关键词转为小写，用于不区分大小写的比较
-	lowerKeyword := strings.ToLower(keyword)
-
-	// 将关键词按空格分割，用于支持多关键词搜索
-	keywords := strings.Fields(lowerKeyword)
-
-	for _, result := range results {
-		// 将标题和内容转为小写
-		lowerTitle := strings.ToLower(result.Title)
-		lowerContent := strings.ToLower(result.Content)
-
-		// 检查每个关键词是否在标题或内容中
-		matched := true
-		for _, kw := range keywords {
-			// 对于所有关键词，检查是否在标题或内容中
-			if !strings.Contains(lowerTitle, kw) && !strings.Contains(lowerContent, kw) {
-				matched = false
-				break
-			}
-		}
-
-		if matched {
-			filteredResults = append(filteredResults, result)
-		}
-	}
-
-	return filteredResults
-} 
+	return FilterResultsByKeyword(results, keyword)
+}
 
 // GetClient 返回短超时客户端
 func (p *BaseAsyncPlugin) GetClient() *http.Client {
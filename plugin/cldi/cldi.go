@@ -57,7 +57,7 @@ func (p *CldiPlugin) Search(keyword string, ext map[string]interface{}) ([]model
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *CldiPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实际的搜索实现
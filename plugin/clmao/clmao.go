@@ -79,6 +79,18 @@ func (p *ClmaoPlugin) Description() string {
 	return "磁力猫 - 磁力链接搜索引擎"
 }
 
+// Info 返回插件元数据
+func (p *ClmaoPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		Homepage:   BaseURL,
+		Category:   plugin.CategoryMagnet,
+		CloudTypes: []string{"magnet"},
+		Ext: []plugin.ExtParam{
+			{Key: "search", Type: plugin.ExtString, Description: "过滤结果时使用的关键词，默认使用搜索关键词"},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *ClmaoPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -90,7 +102,7 @@ func (p *ClmaoPlugin) Search(keyword string, ext map[string]interface{}) ([]mode
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *ClmaoPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实际的搜索实现
@@ -150,10 +162,8 @@ func (p *ClmaoPlugin) searchImpl(client *http.Client, keyword string, ext map[st
 	
 	// 3. 关键词过滤
 	searchKeyword := keyword
-	if searchParam, ok := ext["search"]; ok {
-		if searchStr, ok := searchParam.(string); ok && searchStr != "" {
-			searchKeyword = searchStr
-		}
+	if searchParam, ok := plugin.GetString(ext, "search"); ok && searchParam != "" {
+		searchKeyword = searchParam
 	}
 	return plugin.FilterResultsByKeyword(allResults, searchKeyword), nil
 }
@@ -3,7 +3,6 @@ package clxiong
 import (
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -14,6 +13,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -36,13 +36,14 @@ type DetailPageInfo struct {
 // ClxiongPlugin 磁力熊插件
 type ClxiongPlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode bool
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=clxiong=debug 开启
+var pluginLog = logger.ForPlugin("clxiong")
+
 func init() {
 	p := &ClxiongPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPluginWithFilter("clxiong", 2, true), 
-		debugMode:       false, // 开启调试模式检查磁力链接提取问题
 	}
 	plugin.RegisterGlobalPlugin(p)
 }
@@ -58,15 +59,16 @@ func (p *ClxiongPlugin) Search(keyword string, ext map[string]interface{}) ([]mo
 
 // SearchWithResult 搜索并返回详细结果
 func (p *ClxiongPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (*model.PluginSearchResult, error) {
-	if p.debugMode {
-		log.Printf("[CLXIONG] 开始搜索: %s", keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
 	}
 
 	// 第一步：POST搜索获取searchid
 	searchID, err := p.getSearchID(keyword)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[CLXIONG] 获取searchid失败: %v", err)
+		if log.DebugEnabled() {
+			log.Debugf("获取searchid失败: %v", err)
 		}
 		return nil, fmt.Errorf("获取searchid失败: %v", err)
 	}
@@ -74,8 +76,8 @@ func (p *ClxiongPlugin) SearchWithResult(keyword string, ext map[string]interfac
 	// 第二步：GET搜索结果
 	results, err := p.getSearchResults(searchID, keyword)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[CLXIONG] 获取搜索结果失败: %v", err)
+		if log.DebugEnabled() {
+			log.Debugf("获取搜索结果失败: %v", err)
 		}
 		return nil, err
 	}
@@ -83,8 +85,8 @@ func (p *ClxiongPlugin) SearchWithResult(keyword string, ext map[string]interfac
 	// 第三步：同步获取详情页磁力链接
 	results = p.fetchDetailLinksSync(results)
 
-	if p.debugMode {
-		log.Printf("[CLXIONG] 搜索完成，获得 %d 个结果", len(results))
+	if log.DebugEnabled() {
+		log.Debugf("搜索完成，获得 %d 个结果", len(results))
 	}
 
 	// 应用关键词过滤
@@ -101,8 +103,8 @@ func (p *ClxiongPlugin) SearchWithResult(keyword string, ext map[string]interfac
 
 // getSearchID 第一步：POST搜索获取searchid
 func (p *ClxiongPlugin) getSearchID(keyword string) (string, error) {
-	if p.debugMode {
-		log.Printf("[CLXIONG] 正在获取searchid...")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("正在获取searchid...")
 	}
 
 	client := &http.Client{
@@ -169,8 +171,8 @@ func (p *ClxiongPlugin) getSearchID(keyword string) (string, error) {
 		return "", fmt.Errorf("无法从Location中提取searchid: %s", location)
 	}
 
-	if p.debugMode {
-		log.Printf("[CLXIONG] 获取到searchid: %s", searchID)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("获取到searchid: %s", searchID)
 	}
 
 	return searchID, nil
@@ -189,8 +191,8 @@ func (p *ClxiongPlugin) extractSearchIDFromLocation(location string) string {
 
 // getSearchResults 第二步：GET搜索结果
 func (p *ClxiongPlugin) getSearchResults(searchID, keyword string) ([]model.SearchResult, error) {
-	if p.debugMode {
-		log.Printf("[CLXIONG] 正在获取搜索结果，searchid: %s", searchID)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("正在获取搜索结果，searchid: %s", searchID)
 	}
 
 	// 构建结果页URL
@@ -323,8 +325,8 @@ func (p *ClxiongPlugin) parseSearchResults(html string) ([]model.SearchResult, e
 		results = append(results, result)
 	})
 
-	if p.debugMode {
-		log.Printf("[CLXIONG] 解析到 %d 个搜索结果", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析到 %d 个搜索结果", len(results))
 	}
 
 	return results, nil
@@ -347,8 +349,8 @@ func (p *ClxiongPlugin) fetchDetailLinksSync(results []model.SearchResult) []mod
 		return results
 	}
 
-	if p.debugMode {
-		log.Printf("[CLXIONG] 开始同步获取 %d 个详情页的磁力链接", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始同步获取 %d 个详情页的磁力链接", len(results))
 	}
 
 	// 使用WaitGroup确保所有请求完成后再返回
@@ -420,8 +422,8 @@ func (p *ClxiongPlugin) fetchDetailLinksSync(results []model.SearchResult) []mod
 						mu.Unlock()
 					}
 					
-					if p.debugMode {
-						log.Printf("[CLXIONG] 为结果 %d 获取到 %d 个磁力链接，创建了 %d 个搜索结果", index+1, len(detailInfo.MagnetLinks), len(detailInfo.MagnetLinks))
+					if pluginLog.DebugEnabled() {
+						pluginLog.Debugf("为结果 %d 获取到 %d 个磁力链接，创建了 %d 个搜索结果", index+1, len(detailInfo.MagnetLinks), len(detailInfo.MagnetLinks))
 					}
 				}
 			}
@@ -434,12 +436,12 @@ func (p *ClxiongPlugin) fetchDetailLinksSync(results []model.SearchResult) []mod
 	// 合并额外创建的搜索结果
 	results = append(results, additionalResults...)
 	
-	if p.debugMode {
+	if pluginLog.DebugEnabled() {
 		totalLinks := 0
 		for _, result := range results {
 			totalLinks += len(result.Links)
 		}
-		log.Printf("[CLXIONG] 所有磁力链接获取完成，共获得 %d 个磁力链接，总搜索结果 %d 个", totalLinks, len(results))
+		pluginLog.Debugf("所有磁力链接获取完成，共获得 %d 个磁力链接，总搜索结果 %d 个", totalLinks, len(results))
 	}
 	
 	return results
@@ -458,16 +460,16 @@ func (p *ClxiongPlugin) extractDetailURLFromContent(content string) string {
 
 // fetchDetailPageInfo 获取详情页的完整信息
 func (p *ClxiongPlugin) fetchDetailPageInfo(detailURL string, movieTitle string) *DetailPageInfo {
-	if p.debugMode {
-		log.Printf("[CLXIONG] 正在获取详情页信息: %s", detailURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("正在获取详情页信息: %s", detailURL)
 	}
 
 	client := &http.Client{Timeout: 20 * time.Second}
 
 	req, err := http.NewRequest("GET", detailURL, nil)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[CLXIONG] 创建详情页请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("创建详情页请求失败: %v", err)
 		}
 		return nil
 	}
@@ -478,24 +480,24 @@ func (p *ClxiongPlugin) fetchDetailPageInfo(detailURL string, movieTitle string)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[CLXIONG] 详情页请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页请求失败: %v", err)
 		}
 		return nil
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		if p.debugMode {
-			log.Printf("[CLXIONG] 详情页HTTP状态错误: %d", resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页HTTP状态错误: %d", resp.StatusCode)
 		}
 		return nil
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[CLXIONG] 读取详情页响应失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("读取详情页响应失败: %v", err)
 		}
 		return nil
 	}
@@ -507,8 +509,8 @@ func (p *ClxiongPlugin) fetchDetailPageInfo(detailURL string, movieTitle string)
 func (p *ClxiongPlugin) parseDetailPageInfo(html string, movieTitle string) *DetailPageInfo {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[CLXIONG] 解析详情页HTML失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("解析详情页HTML失败: %v", err)
 		}
 		return nil
 	}
@@ -525,8 +527,8 @@ func (p *ClxiongPlugin) parseDetailPageInfo(html string, movieTitle string) *Det
 	detailInfo.MagnetLinks = magnetLinks
 	detailInfo.FileNames = fileNames
 
-	if p.debugMode {
-		log.Printf("[CLXIONG] 详情页解析完成: 磁力链接 %d 个，更新时间: %v", 
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("详情页解析完成: 磁力链接 %d 个，更新时间: %v", 
 			len(detailInfo.MagnetLinks), detailInfo.UpdateTime)
 	}
 
@@ -556,15 +558,15 @@ func (p *ClxiongPlugin) parseUpdateTimeFromDetail(doc *goquery.Document) time.Ti
 			for _, layout := range layouts {
 				if t, err := time.Parse(layout, dateStr); err == nil {
 					updateTime = t
-					if p.debugMode {
-						log.Printf("[CLXIONG] 解析到更新时间: %s -> %v", dateStr, updateTime)
+					if pluginLog.DebugEnabled() {
+						pluginLog.Debugf("解析到更新时间: %s -> %v", dateStr, updateTime)
 					}
 					return
 				}
 			}
 			
-			if p.debugMode {
-				log.Printf("[CLXIONG] 无法解析更新时间: %s", dateStr)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("无法解析更新时间: %s", dateStr)
 			}
 		}
 	})
@@ -577,19 +579,19 @@ func (p *ClxiongPlugin) parseMagnetLinksFromDetailDoc(doc *goquery.Document, mov
 	var links []model.Link
 	var fileNames []string
 
-	if p.debugMode {
+	if pluginLog.DebugEnabled() {
 		// 调试：检查是否找到磁力下载区域
 		mvDown := doc.Find(".mv_down")
-		log.Printf("[CLXIONG] 找到 .mv_down 区域数量: %d", mvDown.Length())
+		pluginLog.Debugf("找到 .mv_down 区域数量: %d", mvDown.Length())
 		
 		// 调试：检查磁力链接数量
 		magnetLinks := doc.Find(".mv_down a[href^='magnet:']")
-		log.Printf("[CLXIONG] 找到磁力链接数量: %d", magnetLinks.Length())
+		pluginLog.Debugf("找到磁力链接数量: %d", magnetLinks.Length())
 		
 		// 如果没找到，尝试其他可能的选择器
 		if magnetLinks.Length() == 0 {
 			allMagnetLinks := doc.Find("a[href^='magnet:']")
-			log.Printf("[CLXIONG] 页面总磁力链接数量: %d", allMagnetLinks.Length())
+			pluginLog.Debugf("页面总磁力链接数量: %d", allMagnetLinks.Length())
 		}
 	}
 
@@ -611,14 +613,14 @@ func (p *ClxiongPlugin) parseMagnetLinksFromDetailDoc(doc *goquery.Document, mov
 			links = append(links, link)
 			fileNames = append(fileNames, fileName)
 
-			if p.debugMode {
-				log.Printf("[CLXIONG] 找到磁力链接: %s", fileName)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("找到磁力链接: %s", fileName)
 			}
 		}
 	})
 
-	if p.debugMode {
-		log.Printf("[CLXIONG] 详情页共找到 %d 个磁力链接", len(links))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("详情页共找到 %d 个磁力链接", len(links))
 	}
 
 	return links, fileNames
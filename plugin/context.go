@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// detachableContext 返回跟随parent取消的上下文，调用detach后不再受parent影响
+// 用于"尽快响应，持续处理"：响应前客户端断开则中断请求，转入后台处理后则继续完成
+func detachableContext(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	stop := context.AfterFunc(parent, cancel)
+	return ctx, func() { stop() }
+}
+
+// contextClient 返回绑定上下文的HTTP客户端副本，上下文取消时中断该客户端上的所有请求
+func contextClient(ctx context.Context, client *http.Client) *http.Client {
+	// 不可取消的上下文无需包装
+	if ctx.Done() == nil {
+		return client
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	clientCopy := *client
+	clientCopy.Transport = &contextTransport{base: base, ctx: ctx}
+	return &clientCopy
+}
+
+// contextTransport 将调用上下文合并到每个请求的上下文中
+type contextTransport struct {
+	base http.RoundTripper
+	ctx  context.Context
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// 请求自身上下文或调用上下文任一取消都会中断请求
+	reqCtx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.ctx, cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+
+	resp, err := t.base.RoundTrip(req.WithContext(reqCtx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// 响应体读取完毕关闭时再释放上下文
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// cancelOnCloseBody 关闭时释放请求上下文的响应体
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close 关闭响应体并释放上下文
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
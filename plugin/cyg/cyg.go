@@ -84,6 +84,21 @@ func init() {
 	plugin.RegisterGlobalPlugin(p)
 }
 
+// Info 返回插件元数据
+func (p *CygPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: "CYG",
+		Homepage:    "https://cyg.app",
+		Category:    plugin.CategoryNetdisk,
+		Ext: []plugin.ExtParam{
+			{Key: "per_page", Type: plugin.ExtInt, Description: "每页结果数", Default: 20},
+			{Key: "page", Type: plugin.ExtInt, Description: "页码", Default: 1},
+			{Key: "order_by", Type: plugin.ExtString, Description: "排序字段", Default: "date"},
+			{Key: "order", Type: plugin.ExtString, Description: "排序方向：asc或desc", Default: "desc"},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *CygPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -95,7 +110,7 @@ func (p *CygPlugin) Search(keyword string, ext map[string]interface{}) ([]model.
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果（推荐方法）
 func (p *CygPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 搜索实现逻辑
@@ -407,19 +422,19 @@ func (p *CygPlugin) parseExtOptions(ext map[string]interface{}) CygSearchOptions
 		return opts
 	}
 
-	if perPage, ok := ext["per_page"].(int); ok && perPage > 0 {
+	if perPage, ok := plugin.GetInt(ext, "per_page"); ok && perPage > 0 {
 		opts.PerPage = perPage
 	}
 
-	if page, ok := ext["page"].(int); ok && page > 0 {
+	if page, ok := plugin.GetInt(ext, "page"); ok && page > 0 {
 		opts.Page = page
 	}
 
-	if orderBy, ok := ext["order_by"].(string); ok && orderBy != "" {
+	if orderBy, ok := plugin.GetString(ext, "order_by"); ok && orderBy != "" {
 		opts.OrderBy = orderBy
 	}
 
-	if order, ok := ext["order"].(string); ok && order != "" {
+	if order, ok := plugin.GetString(ext, "order"); ok && order != "" {
 		opts.Order = order
 	}
 
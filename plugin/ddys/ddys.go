@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -15,6 +14,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -31,11 +31,13 @@ const (
 // DdysPlugin 低端影视插件
 type DdysPlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode    bool
 	detailCache  sync.Map // 缓存详情页结果
 	cacheTTL     time.Duration
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=ddys=debug 开启
+var pluginLog = logger.ForPlugin(PluginName)
+
 // init 注册插件
 func init() {
 	plugin.RegisterGlobalPlugin(NewDdysPlugin())
@@ -43,11 +45,8 @@ func init() {
 
 // NewDdysPlugin 创建新的低端影视插件实例
 func NewDdysPlugin() *DdysPlugin {
-	debugMode := false // 生产环境关闭调试
-
 	p := &DdysPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPlugin(PluginName, 1), // 标准网盘插件，启用Service层过滤
-		debugMode:       debugMode,
 		cacheTTL:        30 * time.Minute, // 详情页缓存30分钟
 	}
 
@@ -76,8 +75,9 @@ func (p *DdysPlugin) Search(keyword string, ext map[string]interface{}) ([]model
 
 // searchImpl 搜索实现
 func (p *DdysPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	if p.debugMode {
-		log.Printf("[DDYS] 开始搜索: %s", keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
 	}
 
 	// 第一步：执行搜索获取结果列表
@@ -86,22 +86,22 @@ func (p *DdysPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 		return nil, fmt.Errorf("[%s] 执行搜索失败: %w", p.Name(), err)
 	}
 
-	if p.debugMode {
-		log.Printf("[DDYS] 搜索获取到 %d 个结果", len(searchResults))
+	if log.DebugEnabled() {
+		log.Debugf("搜索获取到 %d 个结果", len(searchResults))
 	}
 
 	// 第二步：并发获取详情页链接
 	finalResults := p.fetchDetailLinks(client, searchResults, keyword)
 
-	if p.debugMode {
-		log.Printf("[DDYS] 最终获取到 %d 个有效结果", len(finalResults))
+	if log.DebugEnabled() {
+		log.Debugf("最终获取到 %d 个有效结果", len(finalResults))
 	}
 
 	// 第三步：关键词过滤（标准网盘插件需要过滤）
 	filteredResults := plugin.FilterResultsByKeyword(finalResults, keyword)
 	
-	if p.debugMode {
-		log.Printf("[DDYS] 关键词过滤后剩余 %d 个结果", len(filteredResults))
+	if log.DebugEnabled() {
+		log.Debugf("关键词过滤后剩余 %d 个结果", len(filteredResults))
 	}
 
 	return filteredResults, nil
@@ -194,8 +194,8 @@ func (p *DdysPlugin) parseSearchResults(doc *goquery.Document) ([]model.SearchRe
 		}
 	})
 
-	if p.debugMode {
-		log.Printf("[DDYS] 解析到 %d 个原始结果", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析到 %d 个原始结果", len(results))
 	}
 
 	return results, nil
@@ -213,8 +213,8 @@ func (p *DdysPlugin) parseResultItem(s *goquery.Selection, index int) *model.Sea
 	// 提取标题和链接
 	linkEl := s.Find(".post-title a")
 	if linkEl.Length() == 0 {
-		if p.debugMode {
-			log.Printf("[DDYS] 跳过无标题链接的结果")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("跳过无标题链接的结果")
 		}
 		return nil
 	}
@@ -228,8 +228,8 @@ func (p *DdysPlugin) parseResultItem(s *goquery.Selection, index int) *model.Sea
 	// 提取详情页链接
 	detailURL, _ := linkEl.Attr("href")
 	if detailURL == "" {
-		if p.debugMode {
-			log.Printf("[DDYS] 跳过无链接的结果: %s", title)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("跳过无链接的结果: %s", title)
 		}
 		return nil
 	}
@@ -258,8 +258,8 @@ func (p *DdysPlugin) parseResultItem(s *goquery.Selection, index int) *model.Sea
 	// 添加详情页URL到临时字段（用于后续处理）
 	result.Content += fmt.Sprintf("\n详情页: %s", detailURL)
 
-	if p.debugMode {
-		log.Printf("[DDYS] 解析结果: %s (%s)", title, category)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析结果: %s (%s)", title, category)
 	}
 
 	return &result
@@ -340,8 +340,8 @@ func (p *DdysPlugin) fetchDetailLinks(client *http.Client, searchResults []model
 			// 从Content中提取详情页URL
 			detailURL := p.extractDetailURLFromContent(r.Content)
 			if detailURL == "" {
-				if p.debugMode {
-					log.Printf("[DDYS] 跳过无详情页URL的结果: %s", r.Title)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("跳过无详情页URL的结果: %s", r.Title)
 				}
 				return
 			}
@@ -353,8 +353,8 @@ func (p *DdysPlugin) fetchDetailLinks(client *http.Client, searchResults []model
 				// 清理Content中的详情页URL
 				r.Content = p.cleanContent(r.Content)
 				resultsChan <- r
-			} else if p.debugMode {
-				log.Printf("[DDYS] 详情页无有效链接: %s", r.Title)
+			} else if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("详情页无有效链接: %s", r.Title)
 			}
 		}(result)
 	}
@@ -402,8 +402,8 @@ func (p *DdysPlugin) fetchDetailPageLinks(client *http.Client, detailURL string)
 	// 检查缓存
 	if cached, found := p.detailCache.Load(detailURL); found {
 		if links, ok := cached.([]model.Link); ok {
-			if p.debugMode {
-				log.Printf("[DDYS] 使用缓存的详情页链接: %s", detailURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("使用缓存的详情页链接: %s", detailURL)
 			}
 			return links
 		}
@@ -415,8 +415,8 @@ func (p *DdysPlugin) fetchDetailPageLinks(client *http.Client, detailURL string)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[DDYS] 创建详情页请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("创建详情页请求失败: %v", err)
 		}
 		return []model.Link{}
 	}
@@ -429,16 +429,16 @@ func (p *DdysPlugin) fetchDetailPageLinks(client *http.Client, detailURL string)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[DDYS] 详情页请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页请求失败: %v", err)
 		}
 		return []model.Link{}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		if p.debugMode {
-			log.Printf("[DDYS] 详情页HTTP状态错误: %d", resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页HTTP状态错误: %d", resp.StatusCode)
 		}
 		return []model.Link{}
 	}
@@ -446,8 +446,8 @@ func (p *DdysPlugin) fetchDetailPageLinks(client *http.Client, detailURL string)
 	// 读取响应体
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[DDYS] 读取详情页响应失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("读取详情页响应失败: %v", err)
 		}
 		return []model.Link{}
 	}
@@ -460,8 +460,8 @@ func (p *DdysPlugin) fetchDetailPageLinks(client *http.Client, detailURL string)
 		p.detailCache.Store(detailURL, links)
 	}
 
-	if p.debugMode {
-		log.Printf("[DDYS] 从详情页提取到 %d 个链接: %s", len(links), detailURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("从详情页提取到 %d 个链接: %s", len(links), detailURL)
 	}
 
 	return links
@@ -520,8 +520,8 @@ func (p *DdysPlugin) parseNetworkDiskLinks(htmlContent string) []model.Link {
 
 				links = append(links, link)
 
-				if p.debugMode {
-					log.Printf("[DDYS] 找到链接: %s (%s)", url, urlType)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("找到链接: %s (%s)", url, urlType)
 				}
 			}
 		}
@@ -0,0 +1,516 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"pansou/model"
+	"pansou/plugin"
+	"pansou/plugin/sdk"
+	"pansou/util"
+	jsonutil "pansou/util/json"
+)
+
+// defaultUserAgent 站点定义中未设置User-Agent时使用的默认值
+const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+// maxBodySize 单个响应最多读取的字节数
+const maxBodySize = 5 << 20
+
+// 未配置date_formats时尝试的日期格式
+var defaultDateFormats = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"2006.01.02",
+	"2006年01月02日",
+	"2006年1月2日",
+}
+
+// SitePlugin 由站点定义驱动的插件
+type SitePlugin struct {
+	*plugin.BaseAsyncPlugin
+	site *SiteConfig
+}
+
+// NewSitePlugin 根据站点定义创建插件
+func NewSitePlugin(site *SiteConfig) *SitePlugin {
+	return &SitePlugin{
+		BaseAsyncPlugin: plugin.NewBaseAsyncPluginWithFilter(site.Name, site.Priority, site.SkipServiceFilter),
+		site:            site,
+	}
+}
+
+// Site 返回插件的站点定义
+func (p *SitePlugin) Site() *SiteConfig {
+	return p.site
+}
+
+// Info 返回站点定义中的插件元数据
+func (p *SitePlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: p.site.DisplayName,
+		Homepage:    p.site.Homepage,
+		Category:    p.site.Category,
+	}
+}
+
+// Search 执行搜索并返回结果（兼容性方法）
+func (p *SitePlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	result, err := p.SearchWithResult(keyword, ext)
+	if err != nil {
+		return nil, err
+	}
+	return result.Results, nil
+}
+
+// SearchWithResult 执行搜索并返回包含IsFinal标记的结果
+func (p *SitePlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
+}
+
+// item 从列表页解析出的一条结果，detailURL非空时需要进入详情页补充字段
+type item struct {
+	result    model.SearchResult
+	detailURL string
+}
+
+// searchImpl 按分页规则请求搜索页，需要时进入详情页，最后只保留有链接的结果
+func (p *SitePlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	site := p.site
+	var items []item
+	for i := 0; i < site.Pagination.Pages; i++ {
+		page := site.Pagination.Start + i*site.Pagination.Step
+		pageURL := expandTemplate(site.Search.URL, keyword, page)
+		body, err := p.fetch(client, site.Search.Method, pageURL, expandTemplate(site.Search.Body, keyword, page), site.Search.Headers)
+		if err != nil {
+			// 第一页失败时返回错误，后续页失败时返回已有结果
+			if i == 0 {
+				return nil, err
+			}
+			break
+		}
+
+		pageItems, err := p.parseList(body, pageURL)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			break
+		}
+		if len(pageItems) == 0 {
+			break
+		}
+		items = append(items, pageItems...)
+	}
+
+	if site.Detail != nil {
+		p.fetchDetails(client, items)
+	}
+
+	results := make([]model.SearchResult, 0, len(items))
+	for _, it := range items {
+		if len(it.result.Links) > 0 {
+			results = append(results, it.result)
+		}
+	}
+	return results, nil
+}
+
+// fetchDetails 并发请求详情页补充字段，单个详情页失败时保留列表中的字段
+func (p *SitePlugin) fetchDetails(client *http.Client, items []item) {
+	headers := make(map[string]string, len(p.site.Search.Headers)+len(p.site.Detail.Headers))
+	for k, v := range p.site.Search.Headers {
+		headers[k] = v
+	}
+	for k, v := range p.site.Detail.Headers {
+		headers[k] = v
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.site.Detail.Concurrency)
+	for i := range items {
+		if items[i].detailURL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(it *item) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			body, err := p.fetch(client, http.MethodGet, it.detailURL, "", headers)
+			if err != nil {
+				return
+			}
+			p.ParseDetailPage(body, it.detailURL, &it.result)
+		}(&items[i])
+	}
+	wg.Wait()
+}
+
+// fetch 发送请求并读取响应
+func (p *SitePlugin) fetch(client *http.Client, method string, pageURL string, body string, headers map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.site.Search.Timeout)*time.Second)
+	defer cancel()
+
+	var reader io.Reader
+	if method == http.MethodPost {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, pageURL, reader)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] 创建请求失败: %w", p.Name(), err)
+	}
+
+	req.Header.Set("User-Agent", defaultUserAgent)
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] 请求失败: %w", p.Name(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("[%s] 请求返回状态码: %d", p.Name(), resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("[%s] 读取响应失败: %w", p.Name(), err)
+	}
+	return data, nil
+}
+
+// ParseSearchPage 解析搜索页，返回列表中的结果（不请求详情页，包括还没有链接的结果），用于本地HTML/JSON样本测试站点定义
+func (p *SitePlugin) ParseSearchPage(body []byte, pageURL string) ([]model.SearchResult, error) {
+	items, err := p.parseList(body, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]model.SearchResult, len(items))
+	for i, it := range items {
+		results[i] = it.result
+	}
+	return results, nil
+}
+
+// ParseDetailPage 解析详情页并将字段合并到result中，用于本地样本测试和搜索时的详情页处理
+func (p *SitePlugin) ParseDetailPage(body []byte, pageURL string, result *model.SearchResult) error {
+	if p.site.Detail == nil {
+		return fmt.Errorf("[%s] 未配置详情页", p.Name())
+	}
+
+	var root node
+	var err error
+	if p.site.Detail.Response == ResponseJSON {
+		root, err = parseJSONRoot(body)
+	} else {
+		root, err = parseHTMLRoot(body)
+	}
+	if err != nil {
+		return fmt.Errorf("[%s] 解析详情页失败: %w", p.Name(), err)
+	}
+
+	detail := p.extract(root, p.site.Detail.Fields, pageURL, "")
+	if detail.Title != "" {
+		result.Title = detail.Title
+	}
+	if detail.Content != "" {
+		result.Content = detail.Content
+	}
+	if !detail.Datetime.IsZero() {
+		result.Datetime = detail.Datetime
+	}
+	if len(detail.Images) > 0 {
+		result.Images = detail.Images
+	}
+	for _, link := range detail.Links {
+		if !containsLink(result.Links, link.URL) {
+			result.Links = append(result.Links, link)
+		}
+	}
+	return nil
+}
+
+// parseList 解析搜索页中的结果列表
+func (p *SitePlugin) parseList(body []byte, pageURL string) ([]item, error) {
+	var nodes []node
+	if p.site.Response == ResponseJSON {
+		root, err := parseJSONRoot(body)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] 解析JSON失败: %w", p.Name(), err)
+		}
+		for _, v := range lookup(root.json, p.site.List) {
+			// 路径指向数组时展开为每个元素
+			if list, ok := v.([]interface{}); ok {
+				for _, elem := range list {
+					nodes = append(nodes, node{json: elem})
+				}
+			} else {
+				nodes = append(nodes, node{json: v})
+			}
+		}
+	} else {
+		root, err := parseHTMLRoot(body)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] 解析HTML失败: %w", p.Name(), err)
+		}
+		root.html.Find(p.site.List).Each(func(_ int, s *goquery.Selection) {
+			nodes = append(nodes, node{html: s})
+		})
+	}
+
+	items := make([]item, 0, len(nodes))
+	for _, n := range nodes {
+		var it item
+		if p.site.Detail != nil {
+			it.detailURL = resolveURL(pageURL, first(p.values(n, p.site.Fields.Detail)))
+		}
+		it.result = p.extract(n, p.site.Fields, pageURL, it.detailURL)
+		if it.result.Title == "" && len(it.result.Links) == 0 && it.detailURL == "" {
+			continue
+		}
+		items = append(items, it)
+	}
+	return items, nil
+}
+
+// extract 按字段规则从节点中提取一条结果
+func (p *SitePlugin) extract(n node, fields FieldsConfig, pageURL string, detailURL string) model.SearchResult {
+	result := model.SearchResult{
+		Title:   first(p.values(n, fields.Title)),
+		Content: strings.Join(p.values(n, fields.Content), "\n"),
+	}
+	if fields.Date.IsSet() {
+		result.Datetime = p.parseDate(first(p.values(n, fields.Date)))
+	}
+	if image := first(p.values(n, fields.Image)); image != "" {
+		result.Images = []string{resolveURL(pageURL, image)}
+	}
+
+	// 未配置链接规则时从结果文本中自动识别网盘链接
+	var rawLinks []string
+	if fields.Links.IsSet() {
+		rawLinks = p.values(n, fields.Links)
+	} else {
+		rawLinks = util.ExtractNetDiskLinks(n.text())
+	}
+	password := first(p.values(n, fields.Password))
+	for _, raw := range rawLinks {
+		linkURL := resolveURL(pageURL, raw)
+		linkType := sdk.LinkType(linkURL)
+		if linkType == sdk.LinkTypeOthers || containsLink(result.Links, linkURL) {
+			continue
+		}
+		linkPassword := password
+		if linkPassword == "" {
+			linkPassword = sdk.ExtractPassword(result.Content, linkURL)
+		}
+		result.Links = append(result.Links, model.Link{Type: linkType, URL: linkURL, Password: linkPassword})
+	}
+
+	// 唯一ID使用详情页地址或第一个链接，没有时使用标题
+	idSource := detailURL
+	if idSource == "" && len(result.Links) > 0 {
+		idSource = result.Links[0].URL
+	}
+	if idSource == "" {
+		idSource = result.Title
+	}
+	sum := sha1.Sum([]byte(idSource))
+	result.UniqueID = p.Name() + "-" + hex.EncodeToString(sum[:8])
+	return result
+}
+
+// values 按字段规则取值，未配置的字段返回nil
+func (p *SitePlugin) values(n node, rule FieldRule) []string {
+	if !rule.IsSet() {
+		return nil
+	}
+
+	var raw []string
+	if n.html != nil {
+		target := n.html
+		if rule.Selector != "" {
+			target = n.html.Find(rule.Selector)
+		}
+		target.Each(func(_ int, s *goquery.Selection) {
+			if rule.Attr != "" {
+				if v, ok := s.Attr(rule.Attr); ok {
+					raw = append(raw, strings.TrimSpace(v))
+				}
+			} else {
+				raw = append(raw, strings.TrimSpace(s.Text()))
+			}
+		})
+	} else {
+		for _, v := range lookup(n.json, rule.Path) {
+			if s := stringify(v); s != "" {
+				raw = append(raw, s)
+			}
+		}
+	}
+
+	if rule.regex == nil {
+		return nonEmpty(raw)
+	}
+	var matched []string
+	for _, s := range raw {
+		for _, m := range rule.regex.FindAllStringSubmatch(s, -1) {
+			if len(m) > 1 {
+				matched = append(matched, strings.TrimSpace(m[1]))
+			} else {
+				matched = append(matched, strings.TrimSpace(m[0]))
+			}
+		}
+	}
+	return nonEmpty(matched)
+}
+
+// parseDate 按配置的格式解析日期，支持10位和13位时间戳
+func (p *SitePlugin) parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		switch len(s) {
+		case 10:
+			return time.Unix(ts, 0)
+		case 13:
+			return time.UnixMilli(ts)
+		}
+	}
+
+	formats := p.site.DateFormats
+	if len(formats) == 0 {
+		formats = defaultDateFormats
+	}
+	for _, layout := range formats {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// node 列表中的一条结果或详情页的根节点
+type node struct {
+	html *goquery.Selection
+	json interface{}
+}
+
+// text 返回节点的全部文本，用于自动识别网盘链接；HTML中包含链接的href
+func (n node) text() string {
+	if n.html == nil {
+		data, _ := jsonutil.Marshal(n.json)
+		return string(data)
+	}
+
+	var b strings.Builder
+	b.WriteString(n.html.Text())
+	n.html.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		b.WriteString("\n")
+		b.WriteString(href)
+	})
+	return b.String()
+}
+
+// parseHTMLRoot 解析HTML文档
+func parseHTMLRoot(body []byte) (node, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return node{}, err
+	}
+	return node{html: doc.Selection}, nil
+}
+
+// parseJSONRoot 解析JSON文档
+func parseJSONRoot(body []byte) (node, error) {
+	var v interface{}
+	if err := jsonutil.Unmarshal(body, &v); err != nil {
+		return node{}, err
+	}
+	return node{json: v}, nil
+}
+
+// expandTemplate 替换模板中的占位符
+func expandTemplate(tmpl string, keyword string, page int) string {
+	if tmpl == "" {
+		return ""
+	}
+	quoted, _ := jsonutil.Marshal(keyword)
+	return strings.NewReplacer(
+		"{keyword}", url.QueryEscape(keyword),
+		"{keyword_raw}", keyword,
+		"{keyword_json}", string(quoted[1:len(quoted)-1]),
+		"{page}", strconv.Itoa(page),
+	).Replace(tmpl)
+}
+
+// resolveURL 将相对地址转换为绝对地址
+func resolveURL(base string, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// containsLink 判断链接列表中是否已有该地址
+func containsLink(links []model.Link, linkURL string) bool {
+	for _, link := range links {
+		if link.URL == linkURL {
+			return true
+		}
+	}
+	return false
+}
+
+// first 返回第一个值，没有时返回空字符串
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// nonEmpty 去掉空字符串
+func nonEmpty(values []string) []string {
+	result := values[:0]
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
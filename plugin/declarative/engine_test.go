@@ -0,0 +1,259 @@
+package declarative
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pansou/model"
+)
+
+// htmlSite 解析testdata/list.html和testdata/detail.html的站点定义，%s为搜索地址
+const htmlSite = `
+name: %s
+search:
+  url: "%s"
+list: li.item
+fields:
+  title: a.title
+  content: p.desc
+  links: a.pan@href
+  date: span.date
+  image: img@src
+  detail: a.title@href
+detail:
+  fields:
+    title: h1
+    content: div.content
+    links: div.downloads a@href
+pagination:
+  pages: %d
+  start: 0
+  step: 20
+`
+
+// jsonSite 解析testdata/search.json的站点定义
+const jsonSite = `
+name: dtest_json
+response: json
+search:
+  url: "https://example.com/api.php?wd={keyword}"
+list: data.list
+fields:
+  title: vod_name
+  content: tags.*
+  links: downloads.*.url
+  date: vod_time
+`
+
+// newTestSite 按站点定义创建插件
+func newTestSite(t *testing.T, def string) *SitePlugin {
+	t.Helper()
+	site, err := ParseSite([]byte(def))
+	if err != nil {
+		t.Fatalf("解析站点定义失败: %v", err)
+	}
+	return NewSitePlugin(site)
+}
+
+// readFixture 读取testdata中的样本
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// linkURLs 返回结果中的链接地址
+func linkURLs(result model.SearchResult) []string {
+	urls := make([]string, 0, len(result.Links))
+	for _, link := range result.Links {
+		urls = append(urls, link.Type+" "+link.URL)
+	}
+	return urls
+}
+
+func TestParseSearchPageHTML(t *testing.T) {
+	p := newTestSite(t, fmt.Sprintf(htmlSite, "dtest_html", "https://example.com/search?wd={keyword}", 1))
+
+	results, err := p.ParseSearchPage(readFixture(t, "list.html"), "https://example.com/search?wd=test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 没有标题、链接和详情页地址的空条目被跳过
+	if len(results) != 2 {
+		t.Fatalf("解析出%d条结果，期望2条", len(results))
+	}
+
+	first := results[0]
+	if first.Title != "流浪地球2 4K" || first.Content != "提取码: abcd" {
+		t.Errorf("第一条结果的标题和内容 = %q %q", first.Title, first.Content)
+	}
+	if len(first.Links) != 1 || first.Links[0].Type != "baidu" || first.Links[0].Password != "abcd" {
+		t.Errorf("第一条结果的链接 = %+v", first.Links)
+	}
+	if want := time.Date(2023, 4, 1, 0, 0, 0, 0, time.Local); !first.Datetime.Equal(want) {
+		t.Errorf("第一条结果的时间 = %v，期望%v", first.Datetime, want)
+	}
+	if !reflect.DeepEqual(first.Images, []string{"https://example.com/img/1.jpg"}) {
+		t.Errorf("相对地址的图片未转换为绝对地址: %v", first.Images)
+	}
+
+	second := results[1]
+	if len(second.Links) != 0 || second.Datetime.IsZero() {
+		t.Errorf("第二条结果 = %+v", second)
+	}
+	if first.UniqueID == second.UniqueID || !strings.HasPrefix(first.UniqueID, "dtest_html-") {
+		t.Errorf("唯一ID = %q %q", first.UniqueID, second.UniqueID)
+	}
+}
+
+func TestParseDetailPage(t *testing.T) {
+	p := newTestSite(t, fmt.Sprintf(htmlSite, "dtest_html", "https://example.com/search?wd={keyword}", 1))
+
+	result := model.SearchResult{
+		Title: "流浪地球 导演剪辑版",
+		Links: []model.Link{{Type: "quark", URL: "https://pan.quark.cn/s/quark123"}},
+	}
+	if err := p.ParseDetailPage(readFixture(t, "detail.html"), "https://example.com/detail/2.html", &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Title != "流浪地球 导演剪辑版 1080P" || result.Content != "导演剪辑版，含花絮" {
+		t.Errorf("详情页字段未覆盖列表字段: %q %q", result.Title, result.Content)
+	}
+	// 已有的链接不重复添加，站内链接被忽略
+	want := []string{"quark https://pan.quark.cn/s/quark123", "aliyun https://www.alipan.com/s/ali456"}
+	if got := linkURLs(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("详情页链接 = %v，期望%v", got, want)
+	}
+
+	noDetail := newTestSite(t, jsonSite)
+	if err := noDetail.ParseDetailPage(nil, "", &result); err == nil {
+		t.Error("未配置详情页时应返回错误")
+	}
+}
+
+func TestParseSearchPageJSON(t *testing.T) {
+	p := newTestSite(t, jsonSite)
+
+	results, err := p.ParseSearchPage(readFixture(t, "search.json"), "https://example.com/api.php?wd=test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("解析出%d条结果，期望2条", len(results))
+	}
+
+	first := results[0]
+	if first.Title != "三体 全30集" || first.Content != "科幻\n剧集" {
+		t.Errorf("第一条结果的标题和内容 = %q %q", first.Title, first.Content)
+	}
+	want := []string{"quark https://pan.quark.cn/s/santi01", "baidu https://pan.baidu.com/s/1santi02?pwd=st02"}
+	if got := linkURLs(first); !reflect.DeepEqual(got, want) {
+		t.Errorf("链接 = %v，期望%v", got, want)
+	}
+	if first.Links[1].Password != "st02" {
+		t.Errorf("未从链接中识别提取码: %+v", first.Links[1])
+	}
+	if !first.Datetime.Equal(time.Unix(1672531200, 0)) {
+		t.Errorf("时间戳解析错误: %v", first.Datetime)
+	}
+	if want := time.Date(2023, 1, 15, 20, 0, 0, 0, time.Local); !results[1].Datetime.Equal(want) {
+		t.Errorf("日期解析错误: %v", results[1].Datetime)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	root, err := parseJSONRoot(readFixture(t, "search.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"data.list.0.vod_name", []string{"三体 全30集"}},
+		{"data.list.*.vod_name", []string{"三体 全30集", "三体 动画版"}},
+		{"data.list.*.downloads.*.name", []string{"夸克", "百度"}},
+		{"data.list.5.vod_name", nil},
+		{"data.missing.vod_name", nil},
+		{"data.total", []string{"2"}},
+		{"code", []string{"0"}},
+	}
+	for _, tc := range tests {
+		var got []string
+		for _, v := range lookup(root.json, tc.path) {
+			got = append(got, stringify(v))
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("lookup(%q) = %q，期望%q", tc.path, got, tc.want)
+		}
+	}
+}
+
+// TestSearchPaginationAndDetail 按分页规则请求到没有结果的页为止，并进入详情页补充链接
+func TestSearchPaginationAndDetail(t *testing.T) {
+	listPage := readFixture(t, "list.html")
+	detailPage := readFixture(t, "detail.html")
+
+	var mutex sync.Mutex
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			mutex.Lock()
+			pages = append(pages, r.URL.Query().Get("page"))
+			mutex.Unlock()
+			switch r.URL.Query().Get("page") {
+			case "0":
+				w.Write(listPage)
+			case "20":
+				fmt.Fprint(w, `<ul><li class="item"><a class="title" href="/detail/3.html">第二页</a>`+
+					`<a class="pan" href="https://115.com/s/page2">115</a></li></ul>`)
+			default:
+				fmt.Fprint(w, `<ul></ul>`)
+			}
+		case "/detail/2.html":
+			w.Write(detailPage)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p := newTestSite(t, fmt.Sprintf(htmlSite, "dtest_paged", srv.URL+"/search?wd={keyword}&page={page}", 5))
+	results, err := p.searchImpl(srv.Client(), "流浪地球", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"0", "20", "40"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("请求的页 = %v，期望%v", pages, want)
+	}
+
+	got := make(map[string][]string)
+	for _, result := range results {
+		got[result.Title] = linkURLs(result)
+	}
+	want := map[string][]string{
+		// 详情页请求失败时保留列表中的字段
+		"流浪地球2 4K": {"baidu https://pan.baidu.com/s/1abcdefg"},
+		// 列表中没有链接，链接来自详情页
+		"流浪地球 导演剪辑版 1080P": {"quark https://pan.quark.cn/s/quark123", "aliyun https://www.alipan.com/s/ali456"},
+		"第二页":              {"115 https://115.com/s/page2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("搜索结果 = %v，期望%v", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+package declarative
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	jsonutil "pansou/util/json"
+)
+
+// lookup 按点分隔的路径取值：字段名取对象的字段，数字取数组元素，*展开数组的每个元素；
+// 路径为空时返回值本身，返回所有匹配的值
+func lookup(v interface{}, path string) []interface{} {
+	current := []interface{}{v}
+	if path = strings.TrimSpace(path); path == "" {
+		return current
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		var next []interface{}
+		for _, value := range current {
+			switch typed := value.(type) {
+			case map[string]interface{}:
+				if child, ok := typed[segment]; ok {
+					next = append(next, child)
+				}
+			case []interface{}:
+				if segment == "*" {
+					next = append(next, typed...)
+				} else if index, err := strconv.Atoi(segment); err == nil && index >= 0 && index < len(typed) {
+					next = append(next, typed[index])
+				}
+			}
+		}
+		current = next
+		if len(current) == 0 {
+			break
+		}
+	}
+	return current
+}
+
+// stringify 将JSON值转换为字符串，数组和对象序列化为JSON
+func stringify(v interface{}) string {
+	switch typed := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(typed)
+	case json.Number:
+		return typed.String()
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(typed)
+	default:
+		data, err := jsonutil.Marshal(typed)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,271 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pansou/plugin"
+	"pansou/util/logger"
+)
+
+// siteExtensions 站点定义文件的扩展名
+var siteExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// PluginSwitcher 运行时启用或禁用插件，由搜索服务实现
+type PluginSwitcher interface {
+	SetPluginEnabled(name string, enabled bool) error
+}
+
+// siteFile 已加载的站点定义文件
+type siteFile struct {
+	modTime time.Time
+	size    int64
+	name    string // 已注册的插件名，加载失败时为空
+}
+
+// Loader 从目录加载站点定义并注册为插件，可定期检查目录变化热加载
+type Loader struct {
+	dir     string
+	enabled []string // 新增站点是否启用的规则与ENABLED_PLUGINS相同，nil表示全部启用
+
+	mu    sync.Mutex
+	files map[string]siteFile // 文件路径 -> 已加载的文件
+
+	stopOnce sync.Once
+	watching bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewLoader 创建站点定义加载器
+func NewLoader(dir string, enabled []string) *Loader {
+	return &Loader{
+		dir:     dir,
+		enabled: enabled,
+		files:   make(map[string]siteFile),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Load 加载目录中的所有站点定义并注册到全局插件注册表，返回注册成功的插件名；
+// 单个文件无效时记录警告并跳过，需要在按ENABLED_PLUGINS注册插件之前调用
+func (l *Loader) Load() ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, err := l.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, path := range sortedPaths(entries) {
+		state := entries[path]
+		l.files[path] = state
+
+		site, err := LoadSite(path)
+		if err != nil {
+			logger.Warn(context.Background(), "加载站点定义失败", "file", path, "error", err)
+			continue
+		}
+		if err := l.register(site, path); err != nil {
+			logger.Warn(context.Background(), "注册站点插件失败", "file", path, "error", err)
+			continue
+		}
+		state.name = site.Name
+		l.files[path] = state
+		names = append(names, site.Name)
+	}
+	return names, nil
+}
+
+// Watch 按周期检查目录变化：新增的站点按启用规则启用，修改的站点替换为新版本并保持原来的启用状态，
+// 删除的站点被禁用并注销；修改后的定义无效时记录警告并保留旧版本。interval不大于0时不检查
+func (l *Loader) Watch(manager *plugin.PluginManager, switcher PluginSwitcher, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	l.watching = true
+	l.mu.Unlock()
+
+	go func() {
+		defer close(l.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				l.reload(manager, switcher)
+			}
+		}
+	}()
+}
+
+// Stop 停止检查目录变化
+func (l *Loader) Stop() {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+
+	l.mu.Lock()
+	watching := l.watching
+	l.mu.Unlock()
+	if watching {
+		<-l.done
+	}
+}
+
+// reload 对比目录中的文件与已加载的文件，重新加载有变化的站点定义
+func (l *Loader) reload(manager *plugin.PluginManager, switcher PluginSwitcher) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ctx := context.Background()
+	entries, err := l.scan()
+	if err != nil {
+		logger.Warn(ctx, "读取站点定义目录失败", "dir", l.dir, "error", err)
+		return
+	}
+
+	for path, old := range l.files {
+		if _, exists := entries[path]; exists {
+			continue
+		}
+		delete(l.files, path)
+		if old.name != "" {
+			l.remove(old.name, switcher)
+			logger.Info(ctx, "站点插件已卸载", "plugin", old.name, "file", path)
+		}
+	}
+
+	for _, path := range sortedPaths(entries) {
+		state := entries[path]
+		old, exists := l.files[path]
+		if exists && old.modTime.Equal(state.modTime) && old.size == state.size {
+			continue
+		}
+
+		// 先记录文件状态，定义无效时不会每个周期重复报错
+		state.name = old.name
+		l.files[path] = state
+
+		site, err := LoadSite(path)
+		if err != nil {
+			if old.name != "" {
+				logger.Warn(ctx, "重新加载站点定义失败，保留旧版本", "file", path, "error", err)
+			} else {
+				logger.Warn(ctx, "加载站点定义失败", "file", path, "error", err)
+			}
+			continue
+		}
+
+		enable := l.isEnabled(site.Name)
+		if old.name != "" {
+			enable = manager.HasPlugin(old.name)
+			if old.name != site.Name {
+				l.remove(old.name, switcher)
+				state.name = ""
+				l.files[path] = state
+			}
+		}
+
+		if err := l.register(site, path); err != nil {
+			logger.Warn(ctx, "注册站点插件失败", "file", path, "error", err)
+			continue
+		}
+		state.name = site.Name
+		l.files[path] = state
+
+		if enable {
+			// 先禁用再启用，用新实例替换插件管理器中的旧实例并注入主缓存
+			if err := switcher.SetPluginEnabled(site.Name, false); err == nil {
+				err = switcher.SetPluginEnabled(site.Name, true)
+			}
+			if err != nil {
+				logger.Warn(ctx, "启用站点插件失败", "plugin", site.Name, "error", err)
+			}
+		}
+		logger.Info(ctx, "站点插件已加载", "plugin", site.Name, "file", path, "enabled", enable)
+	}
+}
+
+// register 注册站点插件，不能覆盖编译进来的插件或其他文件定义的同名站点
+func (l *Loader) register(site *SiteConfig, path string) error {
+	if existing, exists := plugin.GetPluginByName(site.Name); exists {
+		if _, ok := existing.(*SitePlugin); !ok {
+			return fmt.Errorf("插件%s已存在", site.Name)
+		}
+	}
+	for other, file := range l.files {
+		if other != path && file.name == site.Name {
+			return fmt.Errorf("插件%s已由%s定义", site.Name, other)
+		}
+	}
+
+	plugin.RegisterGlobalPlugin(NewSitePlugin(site))
+	return nil
+}
+
+// remove 禁用并注销站点插件
+func (l *Loader) remove(name string, switcher PluginSwitcher) {
+	if err := switcher.SetPluginEnabled(name, false); err != nil {
+		logger.Warn(context.Background(), "禁用站点插件失败", "plugin", name, "error", err)
+	}
+	plugin.UnregisterGlobalPlugin(name)
+}
+
+// isEnabled 判断新增的站点是否按启用规则启用
+func (l *Loader) isEnabled(name string) bool {
+	if l.enabled == nil {
+		return true
+	}
+	for _, enabled := range l.enabled {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// scan 列出目录中的站点定义文件
+func (l *Loader) scan() (map[string]siteFile, error) {
+	dirEntries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]siteFile, len(dirEntries))
+	for _, entry := range dirEntries {
+		if entry.IsDir() || !siteExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		entries[filepath.Join(l.dir, entry.Name())] = siteFile{modTime: info.ModTime(), size: info.Size()}
+	}
+	return entries, nil
+}
+
+// sortedPaths 按文件名排序，保证同名站点冲突时结果稳定
+func sortedPaths(entries map[string]siteFile) []string {
+	paths := make([]string, 0, len(entries))
+	for path := range entries {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
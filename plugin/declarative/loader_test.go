@@ -0,0 +1,146 @@
+package declarative
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pansou/plugin"
+)
+
+// managerSwitcher 直接启用或禁用插件管理器中的插件
+type managerSwitcher struct {
+	manager *plugin.PluginManager
+}
+
+func (s managerSwitcher) SetPluginEnabled(name string, enabled bool) error {
+	if !enabled {
+		s.manager.DisablePlugin(name)
+		return nil
+	}
+	if !s.manager.EnablePlugin(name) {
+		return fmt.Errorf("插件%s不存在", name)
+	}
+	return nil
+}
+
+// writeSite 写入站点定义文件
+func writeSite(t *testing.T, path string, name string, displayName string) {
+	t.Helper()
+	def := fmt.Sprintf("name: %s\ndisplay_name: %s\nsearch:\n  url: https://example.com/?q={keyword}\nlist: li\n", name, displayName)
+	writeFile(t, path, def)
+}
+
+// writeFile 写入文件，修改时间不晚于原文件时往后调整，避免文件系统时间精度不足导致修改未被检测到
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	var oldModTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		oldModTime = info.ModTime()
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().After(oldModTime) {
+		modTime := oldModTime.Add(time.Second)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// enabledSite 返回插件管理器中启用的站点插件，未启用时返回nil
+func enabledSite(manager *plugin.PluginManager, name string) *SitePlugin {
+	for _, p := range manager.GetPlugins() {
+		if p.Name() == name {
+			site, _ := p.(*SitePlugin)
+			return site
+		}
+	}
+	return nil
+}
+
+// TestLoaderReload 热加载新增、修改、重命名和删除的站点定义，修改后无效时保留旧版本
+func TestLoaderReload(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() {
+		plugin.UnregisterGlobalPlugin("dtest_reload_a")
+		plugin.UnregisterGlobalPlugin("dtest_reload_b")
+	})
+
+	pathA := filepath.Join(dir, "a.yaml")
+	writeSite(t, pathA, "dtest_reload_a", "站点A")
+
+	loader := NewLoader(dir, nil)
+	names, err := loader.Load()
+	if err != nil || len(names) != 1 || names[0] != "dtest_reload_a" {
+		t.Fatalf("Load() = %v, %v", names, err)
+	}
+	manager := plugin.NewPluginManager()
+	manager.RegisterGlobalPluginsWithFilter([]string{"dtest_reload_a"})
+	switcher := managerSwitcher{manager: manager}
+
+	// 新增
+	pathB := filepath.Join(dir, "b.yml")
+	writeSite(t, pathB, "dtest_reload_b", "站点B")
+	loader.reload(manager, switcher)
+	if enabledSite(manager, "dtest_reload_b") == nil {
+		t.Fatal("新增的站点未启用")
+	}
+
+	// 修改
+	writeSite(t, pathA, "dtest_reload_a", "站点A v2")
+	loader.reload(manager, switcher)
+	if site := enabledSite(manager, "dtest_reload_a"); site == nil || site.Site().DisplayName != "站点A v2" {
+		t.Fatalf("修改后的站点未替换旧版本: %+v", site)
+	}
+
+	// 无效的修改保留旧版本
+	writeFile(t, pathA, "name: dtest_reload_a\nsearch: [\n")
+	loader.reload(manager, switcher)
+	if site := enabledSite(manager, "dtest_reload_a"); site == nil || site.Site().DisplayName != "站点A v2" {
+		t.Fatalf("无效的修改替换了旧版本: %+v", site)
+	}
+	if registered, ok := plugin.GetPluginByName("dtest_reload_a"); !ok || registered.(*SitePlugin).Site().DisplayName != "站点A v2" {
+		t.Fatal("无效的修改注销了旧版本")
+	}
+
+	// 重命名文件
+	pathC := filepath.Join(dir, "c.yaml")
+	if err := os.Rename(pathB, pathC); err != nil {
+		t.Fatal(err)
+	}
+	loader.reload(manager, switcher)
+	if enabledSite(manager, "dtest_reload_b") == nil {
+		t.Fatal("重命名文件后站点未重新加载")
+	}
+
+	// 修改站点名称
+	writeSite(t, pathC, "dtest_reload_c", "站点C")
+	t.Cleanup(func() { plugin.UnregisterGlobalPlugin("dtest_reload_c") })
+	loader.reload(manager, switcher)
+	if _, ok := plugin.GetPluginByName("dtest_reload_b"); ok || enabledSite(manager, "dtest_reload_b") != nil {
+		t.Fatal("修改站点名称后旧名称的插件未注销")
+	}
+	if enabledSite(manager, "dtest_reload_c") == nil {
+		t.Fatal("修改站点名称后新名称的插件未启用")
+	}
+
+	// 删除
+	if err := os.Remove(pathC); err != nil {
+		t.Fatal(err)
+	}
+	loader.reload(manager, switcher)
+	if _, ok := plugin.GetPluginByName("dtest_reload_c"); ok || enabledSite(manager, "dtest_reload_c") != nil {
+		t.Fatal("删除文件后站点未注销")
+	}
+	if enabledSite(manager, "dtest_reload_a") == nil {
+		t.Fatal("删除其他文件影响了未变化的站点")
+	}
+}
@@ -0,0 +1,241 @@
+package declarative
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"pansou/plugin"
+)
+
+// 响应类型
+const (
+	ResponseHTML = "html" // 使用CSS选择器解析
+	ResponseJSON = "json" // 使用JSON路径解析
+)
+
+// 默认值
+const (
+	defaultPriority          = 3
+	defaultTimeoutSeconds    = 10
+	defaultDetailConcurrency = 5
+	maxPages                 = 10
+)
+
+// SiteConfig 站点定义，从YAML或JSON文件加载（JSON按YAML解析）
+type SiteConfig struct {
+	Name              string           `yaml:"name"`                // 插件名称，不能与已有插件重名
+	DisplayName       string           `yaml:"display_name"`        // 显示名称，用于插件列表接口
+	Homepage          string           `yaml:"homepage"`            // 站点主页，用于插件列表接口
+	Category          string           `yaml:"category"`            // 内容分类：netdisk、magnet、video、adult
+	Priority          int              `yaml:"priority"`            // 插件等级1-4，默认3
+	SkipServiceFilter bool             `yaml:"skip_service_filter"` // 是否跳过Service层的关键词过滤
+	Search            RequestConfig    `yaml:"search"`              // 搜索请求
+	Response          string           `yaml:"response"`            // 搜索响应类型：html（默认）或json
+	List              string           `yaml:"list"`                // 结果列表：HTML为每条结果的CSS选择器，JSON为结果数组的路径
+	Fields            FieldsConfig     `yaml:"fields"`              // 每条结果中的字段
+	Detail            *DetailConfig    `yaml:"detail"`              // 详情页，需要进入详情页才能拿到链接时配置
+	Pagination        PaginationConfig `yaml:"pagination"`          // 分页规则
+	DateFormats       []string         `yaml:"date_formats"`        // 日期格式（Go时间格式），为空时尝试常见格式
+}
+
+// RequestConfig 请求配置
+type RequestConfig struct {
+	URL     string            `yaml:"url"`     // 地址模板，支持{keyword}（URL编码）、{keyword_raw}、{keyword_json}（JSON字符串转义）和{page}
+	Method  string            `yaml:"method"`  // 请求方法：GET（默认）或POST
+	Body    string            `yaml:"body"`    // POST请求体模板，支持的占位符与URL相同
+	Headers map[string]string `yaml:"headers"` // 请求头，未设置User-Agent时使用默认值
+	Timeout int               `yaml:"timeout"` // 超时时间（秒），默认10
+}
+
+// FieldsConfig 结果字段的提取规则
+type FieldsConfig struct {
+	Title    FieldRule `yaml:"title"`    // 标题
+	Content  FieldRule `yaml:"content"`  // 内容，多个值按行拼接
+	Links    FieldRule `yaml:"links"`    // 网盘链接，可匹配多个；未配置时从结果文本中自动识别网盘链接
+	Password FieldRule `yaml:"password"` // 提取码，未配置时从链接和内容中自动识别
+	Date     FieldRule `yaml:"date"`     // 发布时间
+	Image    FieldRule `yaml:"image"`    // 封面图片
+	Detail   FieldRule `yaml:"detail"`   // 详情页地址，仅配置了detail时使用
+}
+
+// DetailConfig 详情页配置
+type DetailConfig struct {
+	Headers     map[string]string `yaml:"headers"`     // 额外的请求头，与搜索请求头合并
+	Response    string            `yaml:"response"`    // 详情页响应类型，默认与搜索响应相同
+	Fields      FieldsConfig      `yaml:"fields"`      // 详情页中的字段，非空时覆盖列表中的同名字段，链接追加到列表中的链接之后
+	Concurrency int               `yaml:"concurrency"` // 详情页并发请求数，默认5
+}
+
+// PaginationConfig 分页规则，{page}从start开始每页增加step
+type PaginationConfig struct {
+	Pages int `yaml:"pages"` // 最多请求的页数，默认1，最多10；某一页没有结果时停止
+	Start int `yaml:"start"` // 第一页的{page}值，默认1
+	Step  int `yaml:"step"`  // 每页{page}的增量，默认1，按偏移量分页时设为每页条数
+}
+
+// FieldRule 字段提取规则，可以写成字符串简写：HTML中为"选择器"或"选择器@属性"，JSON中为路径
+type FieldRule struct {
+	Selector string `yaml:"selector"` // CSS选择器，相对于当前结果；为空时取当前结果本身
+	Attr     string `yaml:"attr"`     // 取属性值，为空时取文本
+	Path     string `yaml:"path"`     // JSON路径，点分隔，*展开数组，数字为数组下标
+	Regex    string `yaml:"regex"`    // 对取到的值再用正则提取，有分组时取第一个分组，可匹配多次
+
+	regex *regexp.Regexp
+	set   bool
+}
+
+// UnmarshalYAML 支持字符串简写
+func (r *FieldRule) UnmarshalYAML(value *yaml.Node) error {
+	r.set = true
+	if value.Kind == yaml.ScalarNode {
+		s := strings.TrimSpace(value.Value)
+		r.Path = s
+		r.Selector, r.Attr = s, ""
+		if idx := strings.LastIndex(s, "@"); idx >= 0 {
+			r.Selector, r.Attr = strings.TrimSpace(s[:idx]), strings.TrimSpace(s[idx+1:])
+		}
+		return nil
+	}
+
+	type plain FieldRule
+	var p plain
+	if err := value.Decode(&p); err != nil {
+		return err
+	}
+	*r = FieldRule(p)
+	r.set = true
+	return nil
+}
+
+// IsSet 判断是否配置了该字段
+func (r FieldRule) IsSet() bool {
+	return r.set
+}
+
+// compile 编译字段中的正则表达式
+func (f *FieldsConfig) compile() error {
+	for name, rule := range map[string]*FieldRule{
+		"title": &f.Title, "content": &f.Content, "links": &f.Links, "password": &f.Password,
+		"date": &f.Date, "image": &f.Image, "detail": &f.Detail,
+	} {
+		if rule.Regex == "" {
+			continue
+		}
+		re, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return fmt.Errorf("字段%s的正则表达式无效: %w", name, err)
+		}
+		rule.regex = re
+	}
+	return nil
+}
+
+// ParseSite 解析站点定义并校验、填充默认值
+func ParseSite(data []byte) (*SiteConfig, error) {
+	var site SiteConfig
+	if err := yaml.Unmarshal(data, &site); err != nil {
+		return nil, fmt.Errorf("解析站点定义失败: %w", err)
+	}
+	if err := site.normalize(); err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// LoadSite 从文件加载站点定义
+func LoadSite(path string) (*SiteConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	site, err := ParseSite(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return site, nil
+}
+
+// normalize 校验站点定义并填充默认值
+func (s *SiteConfig) normalize() error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return fmt.Errorf("缺少name")
+	}
+	if s.Search.URL == "" {
+		return fmt.Errorf("站点%s缺少search.url", s.Name)
+	}
+	if s.List == "" {
+		return fmt.Errorf("站点%s缺少list", s.Name)
+	}
+	switch s.Category = strings.ToLower(strings.TrimSpace(s.Category)); s.Category {
+	case "", plugin.CategoryNetdisk, plugin.CategoryMagnet, plugin.CategoryVideo, plugin.CategoryAdult:
+	default:
+		return fmt.Errorf("站点%s的category只支持netdisk、magnet、video和adult", s.Name)
+	}
+	if s.Priority < 1 || s.Priority > 4 {
+		s.Priority = defaultPriority
+	}
+
+	s.Search.Method = strings.ToUpper(s.Search.Method)
+	switch s.Search.Method {
+	case "":
+		s.Search.Method = "GET"
+	case "GET", "POST":
+	default:
+		return fmt.Errorf("站点%s的search.method只支持GET和POST", s.Name)
+	}
+	if s.Search.Timeout <= 0 {
+		s.Search.Timeout = defaultTimeoutSeconds
+	}
+
+	var err error
+	if s.Response, err = normalizeResponse(s.Name, s.Response, ResponseHTML); err != nil {
+		return err
+	}
+	if err := s.Fields.compile(); err != nil {
+		return fmt.Errorf("站点%s: %w", s.Name, err)
+	}
+
+	if s.Detail != nil {
+		if !s.Fields.Detail.IsSet() {
+			return fmt.Errorf("站点%s配置了detail但缺少fields.detail", s.Name)
+		}
+		if s.Detail.Response, err = normalizeResponse(s.Name, s.Detail.Response, s.Response); err != nil {
+			return err
+		}
+		if s.Detail.Concurrency <= 0 {
+			s.Detail.Concurrency = defaultDetailConcurrency
+		}
+		if err := s.Detail.Fields.compile(); err != nil {
+			return fmt.Errorf("站点%s的详情页: %w", s.Name, err)
+		}
+	}
+
+	if s.Pagination.Pages <= 0 {
+		s.Pagination.Pages = 1
+	}
+	if s.Pagination.Pages > maxPages {
+		s.Pagination.Pages = maxPages
+	}
+	if s.Pagination.Start == 0 && s.Pagination.Step == 0 {
+		s.Pagination.Start = 1
+	}
+	if s.Pagination.Step <= 0 {
+		s.Pagination.Step = 1
+	}
+	return nil
+}
+
+// normalizeResponse 校验响应类型，为空时使用默认值
+func normalizeResponse(name string, response string, fallback string) (string, error) {
+	switch response = strings.ToLower(strings.TrimSpace(response)); response {
+	case "":
+		return fallback, nil
+	case ResponseHTML, ResponseJSON:
+		return response, nil
+	}
+	return "", fmt.Errorf("站点%s的response只支持html和json", name)
+}
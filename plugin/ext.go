@@ -0,0 +1,202 @@
+package plugin
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ExtPluginsKey ext中按插件划分的参数，如{"plugins": {"sdso": {"pages_per_type": 3}}}
+const ExtPluginsKey = "plugins"
+
+// PluginExt 返回传给指定插件的ext：全局参数加上ext.plugins中该插件的参数，同名时插件参数优先；
+// 插件名不区分大小写，返回的ext不含plugins键，没有plugins键时直接返回原ext
+func PluginExt(ext map[string]interface{}, name string) map[string]interface{} {
+	if _, ok := ext[ExtPluginsKey]; !ok {
+		return ext
+	}
+
+	merged := make(map[string]interface{}, len(ext))
+	for key, value := range ext {
+		if key != ExtPluginsKey {
+			merged[key] = value
+		}
+	}
+	sections, _ := ext[ExtPluginsKey].(map[string]interface{})
+	for sectionName, section := range sections {
+		if !strings.EqualFold(sectionName, name) {
+			continue
+		}
+		if values, ok := section.(map[string]interface{}); ok {
+			for key, value := range values {
+				merged[key] = value
+			}
+		}
+	}
+	return merged
+}
+
+// ExtHash 生成ext的稳定哈希，用于缓存键：ext参数不同时插件返回的结果可能不同；
+// JSON序列化时map按键排序，键的顺序不影响哈希，ext为空时返回空字符串
+func ExtHash(ext map[string]interface{}) string {
+	ext = withoutSearchCall(ext)
+	if len(ext) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(ext)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%v", ext))
+	}
+	hash := md5.Sum(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// GetString 获取字符串参数，数字和布尔值转换为字符串
+func GetString(ext map[string]interface{}, key string) (string, bool) {
+	value, ok := ext[key]
+	if !ok {
+		return "", false
+	}
+	return toString(value)
+}
+
+// GetInt 获取整数参数，兼容JSON解析出的float64和GET查询参数中的字符串
+func GetInt(ext map[string]interface{}, key string) (int, bool) {
+	value, ok := ext[key]
+	if !ok {
+		return 0, false
+	}
+	return toInt(value)
+}
+
+// GetBool 获取布尔参数，兼容"true"、"1"等字符串和数字0、1
+func GetBool(ext map[string]interface{}, key string) (bool, bool) {
+	value, ok := ext[key]
+	if !ok {
+		return false, false
+	}
+	return toBool(value)
+}
+
+// GetStringSlice 获取字符串列表参数，兼容JSON数组、重复的GET查询参数和逗号分隔的字符串，空项被忽略
+func GetStringSlice(ext map[string]interface{}, key string) ([]string, bool) {
+	value, ok := ext[key]
+	if !ok {
+		return nil, false
+	}
+	return toStringSlice(value)
+}
+
+// toString 把值转换为字符串
+func toString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []string:
+		if len(v) > 0 {
+			return v[len(v)-1], true
+		}
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case json.Number:
+		return v.String(), true
+	case int, int64:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// toInt 把值转换为整数，带小数的数字不转换；
+// JSON数字（float64或json.Number）和查询参数中的字符串按同样的规则转换，"3.0"和3.0都转换为3
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return integralFloat(v)
+	case json.Number:
+		return parseInt(v.String())
+	case string, []string:
+		s, _ := toString(v)
+		return parseInt(s)
+	}
+	return 0, false
+}
+
+// parseInt 解析整数字符串，也接受小数部分为0的写法如"3.0"
+func parseInt(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return integralFloat(f)
+}
+
+// integralFloat 把没有小数部分的浮点数转换为整数
+func integralFloat(f float64) (int, bool) {
+	if f == math.Trunc(f) && math.Abs(f) <= math.MaxInt32 {
+		return int(f), true
+	}
+	return 0, false
+}
+
+// toBool 把值转换为布尔值
+func toBool(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string, []string:
+		s, _ := toString(v)
+		if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+			return b, true
+		}
+	default:
+		if n, ok := toInt(v); ok && (n == 0 || n == 1) {
+			return n == 1, true
+		}
+	}
+	return false, false
+}
+
+// toStringSlice 把值转换为字符串列表
+func toStringSlice(value interface{}) ([]string, bool) {
+	var items []string
+	switch v := value.(type) {
+	case []string:
+		items = v
+	case []interface{}:
+		for _, item := range v {
+			s, ok := toString(item)
+			if !ok {
+				return nil, false
+			}
+			items = append(items, s)
+		}
+	case string:
+		items = []string{v}
+	default:
+		return nil, false
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		for _, part := range strings.Split(item, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result, true
+}
@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestGetIntIntegralValues JSON数字、json.Number和查询参数中的字符串按同样的规则转换为整数
+func TestGetIntIntegralValues(t *testing.T) {
+	tests := []struct {
+		value  interface{}
+		want   int
+		wantOK bool
+	}{
+		{3, 3, true},
+		{float64(3), 3, true},
+		{3.0, 3, true},
+		{json.Number("3"), 3, true},
+		{json.Number("3.0"), 3, true},
+		{"3", 3, true},
+		{" 3.0 ", 3, true},
+		{[]string{"1", "3.0"}, 3, true},
+		{3.5, 0, false},
+		{json.Number("3.5"), 0, false},
+		{"3.5", 0, false},
+		{"abc", 0, false},
+		{json.Number("1e20"), 0, false},
+	}
+	for _, tc := range tests {
+		got, ok := GetInt(map[string]interface{}{"n": tc.value}, "n")
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("GetInt(%#v) = %d, %v，期望%d, %v", tc.value, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+// infoPlugin 声明ext参数的测试插件
+type infoPlugin struct {
+	stubPlugin
+	params []ExtParam
+}
+
+func (p *infoPlugin) Info() PluginMetadata {
+	return PluginMetadata{Ext: p.params}
+}
+
+// TestValidateExtReturnsCopy ValidateExt返回转换后的副本，不修改调用方的ext
+func TestValidateExtReturnsCopy(t *testing.T) {
+	p := &infoPlugin{
+		stubPlugin: stubPlugin{NewBaseAsyncPlugin("ext_info", 3)},
+		params:     []ExtParam{{Key: "pages", Type: ExtInt}},
+	}
+	ext := map[string]interface{}{
+		"pages":       json.Number("2.0"),
+		"other":       "x",
+		ExtPluginsKey: map[string]interface{}{"ext_info": map[string]interface{}{"pages": "3"}},
+	}
+
+	converted, err := ValidateExt([]AsyncSearchPlugin{p}, ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"pages":       2,
+		"other":       "x",
+		ExtPluginsKey: map[string]interface{}{"ext_info": map[string]interface{}{"pages": 3}},
+	}
+	if !reflect.DeepEqual(converted, want) {
+		t.Errorf("ValidateExt() = %#v，期望%#v", converted, want)
+	}
+
+	original := map[string]interface{}{
+		"pages":       json.Number("2.0"),
+		"other":       "x",
+		ExtPluginsKey: map[string]interface{}{"ext_info": map[string]interface{}{"pages": "3"}},
+	}
+	if !reflect.DeepEqual(ext, original) {
+		t.Errorf("ValidateExt修改了传入的ext: %#v", ext)
+	}
+
+	if _, err := ValidateExt([]AsyncSearchPlugin{p}, map[string]interface{}{"pages": "2.5"}); err == nil {
+		t.Error("带小数的值应校验失败")
+	}
+}
+
+// TestPluginCacheKeyIncludesExt 插件级缓存键包含ext，调用状态不影响缓存键
+func TestPluginCacheKeyIncludesExt(t *testing.T) {
+	p := NewBaseAsyncPlugin("ext_cache", 3)
+	key := func(ext map[string]interface{}) string {
+		return p.pluginCacheKey(NewSearchCall(context.Background(), "流浪地球", "", ext))
+	}
+
+	base := key(nil)
+	if base != "ext_cache:流浪地球" || key(map[string]interface{}{}) != base {
+		t.Errorf("没有ext时的缓存键 = %q", base)
+	}
+	withPages := key(map[string]interface{}{"pages_per_type": 5})
+	if withPages == base || withPages == key(map[string]interface{}{"pages_per_type": 3}) {
+		t.Error("ext不同时缓存键应不同")
+	}
+
+	call := NewSearchCall(context.Background(), "流浪地球", "main", map[string]interface{}{"pages_per_type": 5})
+	if ExtHash(call.ExtParams()) != ExtHash(map[string]interface{}{"pages_per_type": 5}) {
+		t.Error("ext中的调用状态不应影响哈希")
+	}
+}
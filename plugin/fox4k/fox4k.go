@@ -20,6 +20,7 @@ import (
 	"golang.org/x/net/proxy"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 // 常量定义
@@ -45,9 +46,6 @@ const (
 	DefaultHTTPProxy  = "http://154.219.110.34:51422"
 	DefaultSocks5Proxy = "socks5://154.219.110.34:51423"
 	
-	// 调试开关 - 默认关闭
-	DebugMode = false
-	
 	// 代理开关 - 默认关闭
 	ProxyEnabled = false
 	
@@ -157,7 +155,7 @@ func createProxyTransport(proxyURL string) (*http.Transport, error) {
 			return nil, fmt.Errorf("创建SOCKS5代理失败: %w", err)
 		}
 		transport.Dial = dialer.Dial
-		debugPrintf("🔧 [Fox4k DEBUG] 使用SOCKS5代理: %s\n", proxyURL)
+		debugPrintf("🔧 使用SOCKS5代理: %s", proxyURL)
 	} else {
 		// HTTP代理
 		parsedURL, err := url.Parse(proxyURL)
@@ -165,7 +163,7 @@ func createProxyTransport(proxyURL string) (*http.Transport, error) {
 			return nil, fmt.Errorf("解析代理URL失败: %w", err)
 		}
 		transport.Proxy = http.ProxyURL(parsedURL)
-		debugPrintf("🔧 [Fox4k DEBUG] 使用HTTP代理: %s\n", proxyURL)
+		debugPrintf("🔧 使用HTTP代理: %s", proxyURL)
 	}
 
 	return transport, nil
@@ -182,17 +180,17 @@ func createOptimizedHTTPClient() *http.Client {
 	} else {
 		// 代理未启用，使用直连
 		selectedProxy = ""
-		debugPrintf("🔧 [Fox4k DEBUG] 代理功能已禁用，使用直连模式\n")
+		debugPrintf("🔧 代理功能已禁用，使用直连模式")
 	}
 	
 	transport, err := createProxyTransport(selectedProxy)
 	if err != nil {
-		debugPrintf("❌ [Fox4k DEBUG] 创建代理传输层失败: %v，使用直连\n", err)
+		debugPrintf("❌ 创建代理传输层失败: %v，使用直连", err)
 		transport, _ = createProxyTransport("")
 	}
 	
 	if selectedProxy == "" && ProxyEnabled {
-		debugPrintf("🔧 [Fox4k DEBUG] 使用直连模式\n")
+		debugPrintf("🔧 使用直连模式")
 	}
 	
 	return &http.Client{
@@ -211,11 +209,14 @@ func NewFox4kPlugin() *Fox4kPlugin {
 
 // debugPrintf 调试输出函数
 func debugPrintf(format string, args ...interface{}) {
-	if DebugMode {
-		fmt.Printf(format, args...)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf(format, args...)
 	}
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=fox4k=debug 开启
+var pluginLog = logger.ForPlugin("fox4k")
+
 // 初始化插件
 func init() {
 	plugin.RegisterGlobalPlugin(NewFox4kPlugin())
@@ -247,18 +248,18 @@ func (p *Fox4kPlugin) Search(keyword string, ext map[string]interface{}) ([]mode
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *Fox4kPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	debugPrintf("🔧 [Fox4k DEBUG] SearchWithResult 开始 - keyword: %s, MainCacheKey: '%s'\n", keyword, p.MainCacheKey)
+	debugPrintf("🔧 SearchWithResult 开始 - keyword: %s", keyword)
 	
-	result, err := p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	result, err := p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 	
-	debugPrintf("🔧 [Fox4k DEBUG] SearchWithResult 完成 - 结果数: %d, IsFinal: %v, 错误: %v\n", 
+	debugPrintf("🔧 SearchWithResult 完成 - 结果数: %d, IsFinal: %v, 错误: %v", 
 		len(result.Results), result.IsFinal, err)
 	
 	if len(result.Results) > 0 {
-		debugPrintf("🔧 [Fox4k DEBUG] 前3个结果示例:\n")
+		debugPrintf("🔧 前3个结果示例:")
 		for i, r := range result.Results {
 			if i >= 3 { break }
-			debugPrintf("  %d. 标题: %s, 链接数: %d\n", i+1, r.Title, len(r.Links))
+			debugPrintf("  %d. 标题: %s, 链接数: %d", i+1, r.Title, len(r.Links))
 		}
 	}
 	
@@ -267,7 +268,7 @@ func (p *Fox4kPlugin) SearchWithResult(keyword string, ext map[string]interface{
 
 // searchImpl 实现具体的搜索逻辑（支持分页）
 func (p *Fox4kPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	debugPrintf("🔧 [Fox4k DEBUG] searchImpl 开始执行 - keyword: %s\n", keyword)
+	debugPrintf("🔧 searchImpl 开始执行 - keyword: %s", keyword)
 	startTime := time.Now()
 	atomic.AddInt64(&searchRequests, 1)
 	
@@ -329,7 +330,7 @@ func (p *Fox4kPlugin) searchImpl(client *http.Client, keyword string, ext map[st
 	searchDuration := time.Since(startTime)
 	atomic.AddInt64(&totalSearchTime, int64(searchDuration))
 	
-	debugPrintf("🔧 [Fox4k DEBUG] searchImpl 完成 - 原始结果: %d, 过滤后结果: %d, 耗时: %v\n", 
+	debugPrintf("🔧 searchImpl 完成 - 原始结果: %d, 过滤后结果: %d, 耗时: %v", 
 		len(allResults), len(results), searchDuration)
 	
 	return results, nil
@@ -339,7 +340,7 @@ func (p *Fox4kPlugin) searchImpl(client *http.Client, keyword string, ext map[st
 
 // searchPage 搜索指定页面
 func (p *Fox4kPlugin) searchPage(client *http.Client, encodedKeyword string, page int) ([]model.SearchResult, int, error) {
-	debugPrintf("🔧 [Fox4k DEBUG] searchPage 开始 - 第%d页, keyword: %s\n", page, encodedKeyword)
+	debugPrintf("🔧 searchPage 开始 - 第%d页, keyword: %s", page, encodedKeyword)
 	
 	// 1. 构建搜索URL
 	var searchURL string
@@ -349,7 +350,7 @@ func (p *Fox4kPlugin) searchPage(client *http.Client, encodedKeyword string, pag
 		searchURL = fmt.Sprintf(SearchPageURL, encodedKeyword, page)
 	}
 	
-	debugPrintf("🔧 [Fox4k DEBUG] 构建的URL: %s\n", searchURL)
+	debugPrintf("🔧 构建的URL: %s", searchURL)
 	
 	// 2. 创建带超时的上下文
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
@@ -376,16 +377,16 @@ func (p *Fox4kPlugin) searchPage(client *http.Client, encodedKeyword string, pag
 	req.Header.Set("X-Real-IP", randomIP)
 	req.Header.Set("sec-ch-ua-platform", "macOS")
 	
-	debugPrintf("🔧 [Fox4k DEBUG] 使用随机UA: %s\n", randomUA)
-	debugPrintf("🔧 [Fox4k DEBUG] 使用随机IP: %s\n", randomIP)
+	debugPrintf("🔧 使用随机UA: %s", randomUA)
+	debugPrintf("🔧 使用随机IP: %s", randomIP)
 	
 	// 5. 发送HTTP请求
-	debugPrintf("🔧 [Fox4k DEBUG] 开始发送HTTP请求到: %s\n", searchURL)
-	debugPrintf("🔧 [Fox4k DEBUG] 请求头信息:\n")
-	if DebugMode {
+	debugPrintf("🔧 开始发送HTTP请求到: %s", searchURL)
+	debugPrintf("🔧 请求头信息:")
+	if pluginLog.DebugEnabled() {
 		for key, values := range req.Header {
 			for _, value := range values {
-				debugPrintf("    %s: %s\n", key, value)
+				debugPrintf("    %s: %s", key, value)
 			}
 		}
 	}
@@ -395,27 +396,27 @@ func (p *Fox4kPlugin) searchPage(client *http.Client, encodedKeyword string, pag
 	requestDuration := time.Since(startTime)
 	
 	if err != nil {
-		debugPrintf("❌ [Fox4k DEBUG] HTTP请求失败 (耗时: %v): %v\n", requestDuration, err)
-		debugPrintf("❌ [Fox4k DEBUG] 错误类型分析:\n")
+		debugPrintf("❌ HTTP请求失败 (耗时: %v): %v", requestDuration, err)
+		debugPrintf("❌ 错误类型分析:")
 		if netErr, ok := err.(*url.Error); ok {
-			fmt.Printf("    URL错误: %v\n", netErr.Err)
+			debugPrintf("    URL错误: %v", netErr.Err)
 			if netErr.Timeout() {
-				fmt.Printf("    -> 这是超时错误\n")
+				debugPrintf("    -> 这是超时错误")
 			}
 			if netErr.Temporary() {
-				fmt.Printf("    -> 这是临时错误\n")
+				debugPrintf("    -> 这是临时错误")
 			}
 		}
 		return nil, 0, fmt.Errorf("[%s] 第%d页搜索请求失败: %w", p.Name(), page, err)
 	}
 	defer resp.Body.Close()
 	
-	debugPrintf("✅ [Fox4k DEBUG] HTTP请求成功 (耗时: %v)\n", requestDuration)
+	debugPrintf("✅ HTTP请求成功 (耗时: %v)", requestDuration)
 	
 	// 6. 检查状态码
-	debugPrintf("🔧 [Fox4k DEBUG] HTTP响应状态码: %d\n", resp.StatusCode)
+	debugPrintf("🔧 HTTP响应状态码: %d", resp.StatusCode)
 	if resp.StatusCode != 200 {
-		debugPrintf("❌ [Fox4k DEBUG] 状态码异常: %d\n", resp.StatusCode)
+		debugPrintf("❌ 状态码异常: %d", resp.StatusCode)
 		return nil, 0, fmt.Errorf("[%s] 第%d页请求返回状态码: %d", p.Name(), page, resp.StatusCode)
 	}
 	
@@ -426,10 +427,10 @@ func (p *Fox4kPlugin) searchPage(client *http.Client, encodedKeyword string, pag
 	}
 	
 	htmlContent := string(htmlBytes)
-	debugPrintf("🔧 [Fox4k DEBUG] 第%d页 HTML长度: %d bytes\n", page, len(htmlContent))
+	debugPrintf("🔧 第%d页 HTML长度: %d bytes", page, len(htmlContent))
 	
 	// 保存HTML到文件（仅在调试模式下）
-	if DebugMode {
+	if pluginLog.DebugEnabled() {
 		htmlDir := "./html"
 		os.MkdirAll(htmlDir, 0755)
 		
@@ -438,9 +439,9 @@ func (p *Fox4kPlugin) searchPage(client *http.Client, encodedKeyword string, pag
 		
 		err = os.WriteFile(filepath, htmlBytes, 0644)
 		if err != nil {
-			debugPrintf("❌ [Fox4k DEBUG] 保存HTML文件失败: %v\n", err)
+			debugPrintf("❌ 保存HTML文件失败: %v", err)
 		} else {
-			debugPrintf("✅ [Fox4k DEBUG] HTML已保存到: %s\n", filepath)
+			debugPrintf("✅ HTML已保存到: %s", filepath)
 		}
 	}
 	
@@ -992,15 +993,15 @@ func (p *Fox4kPlugin) doRequestWithRetry(req *http.Request, client *http.Client)
 	maxRetries := 3
 	var lastErr error
 	
-	debugPrintf("🔄 [Fox4k DEBUG] 开始重试机制 - 最大重试次数: %d\n", maxRetries)
+	debugPrintf("🔄 开始重试机制 - 最大重试次数: %d", maxRetries)
 	
 	for i := 0; i < maxRetries; i++ {
-		debugPrintf("🔄 [Fox4k DEBUG] 第 %d/%d 次尝试\n", i+1, maxRetries)
+		debugPrintf("🔄 第 %d/%d 次尝试", i+1, maxRetries)
 		
 		if i > 0 {
 			// 指数退避重试
 			backoff := time.Duration(1<<uint(i-1)) * 200 * time.Millisecond
-			debugPrintf("⏳ [Fox4k DEBUG] 等待 %v 后重试\n", backoff)
+			debugPrintf("⏳ 等待 %v 后重试", backoff)
 			time.Sleep(backoff)
 		}
 		
@@ -1011,22 +1012,22 @@ func (p *Fox4kPlugin) doRequestWithRetry(req *http.Request, client *http.Client)
 		resp, err := client.Do(reqClone)
 		attemptDuration := time.Since(attemptStart)
 		
-		debugPrintf("🔧 [Fox4k DEBUG] 第 %d 次尝试耗时: %v\n", i+1, attemptDuration)
+		debugPrintf("🔧 第 %d 次尝试耗时: %v", i+1, attemptDuration)
 		
 		if err != nil {
-			debugPrintf("❌ [Fox4k DEBUG] 第 %d 次尝试失败: %v\n", i+1, err)
+			debugPrintf("❌ 第 %d 次尝试失败: %v", i+1, err)
 			lastErr = err
 			continue
 		}
 		
-		debugPrintf("🔧 [Fox4k DEBUG] 第 %d 次尝试获得响应 - 状态码: %d\n", i+1, resp.StatusCode)
+		debugPrintf("🔧 第 %d 次尝试获得响应 - 状态码: %d", i+1, resp.StatusCode)
 		
 		if resp.StatusCode == 200 {
-			debugPrintf("✅ [Fox4k DEBUG] 第 %d 次尝试成功!\n", i+1)
+			debugPrintf("✅ 第 %d 次尝试成功!", i+1)
 			return resp, nil
 		}
 		
-		debugPrintf("❌ [Fox4k DEBUG] 第 %d 次尝试状态码异常: %d\n", i+1, resp.StatusCode)
+		debugPrintf("❌ 第 %d 次尝试状态码异常: %d", i+1, resp.StatusCode)
 		
 		// 读取响应体以便调试
 		if resp.Body != nil {
@@ -1037,14 +1038,14 @@ func (p *Fox4kPlugin) doRequestWithRetry(req *http.Request, client *http.Client)
 				if len(bodyPreview) > 200 {
 					bodyPreview = bodyPreview[:200] + "..."
 				}
-				debugPrintf("🔧 [Fox4k DEBUG] 响应体预览: %s\n", bodyPreview)
+				debugPrintf("🔧 响应体预览: %s", bodyPreview)
 			}
 		}
 		
 		lastErr = fmt.Errorf("状态码 %d", resp.StatusCode)
 	}
 	
-	debugPrintf("❌ [Fox4k DEBUG] 所有重试都失败了!\n")
+	debugPrintf("❌ 所有重试都失败了!")
 	return nil, fmt.Errorf("重试 %d 次后仍然失败: %w", maxRetries, lastErr)
 }
 
@@ -14,11 +14,10 @@ import (
 	"pansou/model"
 	"pansou/plugin"
 	"pansou/util/json"
+	"pansou/util/logger"
 )
 
 const (
-	// 调试日志开关
-	DebugLog = false
 	// 默认每种网盘类型获取页数
 	DefaultPagesPerType = 2
 	// 最大允许每种网盘类型页数（防止过度请求）
@@ -82,6 +81,9 @@ type LinkResult struct {
 	err      error
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=haisou=debug 开启
+var pluginLog = logger.ForPlugin("haisou")
+
 func init() {
 	p := &HaisouPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPlugin("haisou", 3), 
@@ -89,6 +91,19 @@ func init() {
 	plugin.RegisterGlobalPlugin(p)
 }
 
+// Info 返回插件元数据
+func (p *HaisouPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: "海搜",
+		Homepage:    "https://haisou.cc",
+		Category:    plugin.CategoryNetdisk,
+		CloudTypes:  []string{"aliyun", "baidu", "quark", "xunlei", "tianyi"},
+		Ext: []plugin.ExtParam{
+			{Key: "pages_per_type", Type: plugin.ExtInt, Description: "每种网盘类型请求的页数，最多3页", Default: DefaultPagesPerType},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *HaisouPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -100,38 +115,33 @@ func (p *HaisouPlugin) Search(keyword string, ext map[string]interface{}) ([]mod
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果（推荐方法）
 func (p *HaisouPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实际的搜索实现
 func (p *HaisouPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	if DebugLog {
-		fmt.Printf("[%s] 开始搜索，关键词: %s\n", p.Name(), keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索，关键词: %s", keyword)
 	}
 
 	// 1. 从扩展参数中获取每种网盘类型的页数配置
 	pagesPerType := DefaultPagesPerType
 	if ext != nil {
-		if pages, ok := ext["pages_per_type"].(int); ok && pages > 0 {
+		if pages, ok := plugin.GetInt(ext, "pages_per_type"); ok && pages > 0 {
 			pagesPerType = pages
 			if pagesPerType > MaxAllowedPagesPerType {
 				pagesPerType = MaxAllowedPagesPerType
-				if DebugLog {
-					fmt.Printf("[%s] 每种网盘类型页数限制在最大值: %d\n", p.Name(), MaxAllowedPagesPerType)
+				if log.DebugEnabled() {
+					log.Debugf("每种网盘类型页数限制在最大值: %d", MaxAllowedPagesPerType)
 				}
 			}
-		} else if pagesFloat, ok := ext["pages_per_type"].(float64); ok && pagesFloat > 0 {
-			pagesPerType = int(pagesFloat)
-			if pagesPerType > MaxAllowedPagesPerType {
-				pagesPerType = MaxAllowedPagesPerType
-			}
 		}
 	}
 
 	totalTasks := len(SupportedCloudTypes) * pagesPerType
-	if DebugLog {
-		fmt.Printf("[%s] 将分别搜索 %d 种网盘类型，每种 %d 页，总计 %d 个并发任务\n",
-			p.Name(), len(SupportedCloudTypes), pagesPerType, totalTasks)
+	if log.DebugEnabled() {
+		log.Debugf("将分别搜索 %d 种网盘类型，每种 %d 页，总计 %d 个并发任务", len(SupportedCloudTypes), pagesPerType, totalTasks)
 	}
 
 	// 2. 第一阶段：并发搜索获取所有hsid
@@ -171,8 +181,8 @@ func (p *HaisouPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 	for pageResult := range shareItemsChan {
 		if pageResult.err != nil {
 			errorTasks++
-			if DebugLog {
-				fmt.Printf("[%s] %s网盘第%d页搜索失败: %v\n", p.Name(), pageResult.cloudType, pageResult.pageNo, pageResult.err)
+			if log.DebugEnabled() {
+				log.Debugf("%s网盘第%d页搜索失败: %v", pageResult.cloudType, pageResult.pageNo, pageResult.err)
 			}
 			continue
 		}
@@ -180,16 +190,15 @@ func (p *HaisouPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 		successTasks++
 		allShareItems = append(allShareItems, pageResult.shareItems...)
 		resultsByType[pageResult.cloudType] += len(pageResult.shareItems)
-		if DebugLog {
-			fmt.Printf("[%s] %s网盘第%d页成功获取 %d 个结果\n", p.Name(), pageResult.cloudType, pageResult.pageNo, len(pageResult.shareItems))
+		if log.DebugEnabled() {
+			log.Debugf("%s网盘第%d页成功获取 %d 个结果", pageResult.cloudType, pageResult.pageNo, len(pageResult.shareItems))
 		}
 	}
 
-	if DebugLog {
-		fmt.Printf("[%s] 搜索阶段完成: 成功%d任务, 失败%d任务, 总hsid%d个\n",
-			p.Name(), successTasks, errorTasks, len(allShareItems))
+	if log.DebugEnabled() {
+		log.Debugf("搜索阶段完成: 成功%d任务, 失败%d任务, 总hsid%d个", successTasks, errorTasks, len(allShareItems))
 		for cloudType, count := range resultsByType {
-			fmt.Printf("[%s]   - %s网盘: %d个结果\n", p.Name(), cloudType, count)
+			log.Debugf("- %s网盘: %d个结果", cloudType, count)
 		}
 	}
 
@@ -199,8 +208,8 @@ func (p *HaisouPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 	}
 
 	// 5. 第二阶段：并发获取所有链接
-	if DebugLog {
-		fmt.Printf("[%s] 开始第二阶段：并发获取 %d 个链接\n", p.Name(), len(allShareItems))
+	if log.DebugEnabled() {
+		log.Debugf("开始第二阶段：并发获取 %d 个链接", len(allShareItems))
 	}
 
 	linkResultsChan := make(chan LinkResult, len(allShareItems))
@@ -236,8 +245,8 @@ func (p *HaisouPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 	for linkResult := range linkResultsChan {
 		if linkResult.err != nil {
 			linkErrorCount++
-			if DebugLog {
-				fmt.Printf("[%s] 获取链接失败 hsid=%s: %v\n", p.Name(), linkResult.hsid, linkResult.err)
+			if log.DebugEnabled() {
+				log.Debugf("获取链接失败 hsid=%s: %v", linkResult.hsid, linkResult.err)
 			}
 			continue
 		}
@@ -246,8 +255,8 @@ func (p *HaisouPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 		hsidToLink[linkResult.hsid] = linkResult
 	}
 
-	if DebugLog {
-		fmt.Printf("[%s] 链接获取阶段完成: 成功%d个, 失败%d个\n", p.Name(), linkSuccessCount, linkErrorCount)
+	if log.DebugEnabled() {
+		log.Debugf("链接获取阶段完成: 成功%d个, 失败%d个", linkSuccessCount, linkErrorCount)
 	}
 
 	// 7. 组合搜索结果和链接信息
@@ -291,18 +300,16 @@ func (p *HaisouPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 		processedCount++
 	}
 
-	if DebugLog {
-		fmt.Printf("[%s] 结果组合完成: 处理%d项 -> 有效%d项 -> 跳过%d项\n",
-			p.Name(), len(allShareItems), processedCount, skippedCount)
+	if log.DebugEnabled() {
+		log.Debugf("结果组合完成: 处理%d项 -> 有效%d项 -> 跳过%d项", len(allShareItems), processedCount, skippedCount)
 	}
 
 	// 8. 关键词过滤
 	beforeFilterCount := len(results)
 	filteredResults := plugin.FilterResultsByKeyword(results, keyword)
 
-	if DebugLog {
-		fmt.Printf("[%s] 关键词过滤: 过滤前%d项 -> 过滤后%d项\n",
-			p.Name(), beforeFilterCount, len(filteredResults))
+	if log.DebugEnabled() {
+		log.Debugf("关键词过滤: 过滤前%d项 -> 过滤后%d项", beforeFilterCount, len(filteredResults))
 	}
 
 	return filteredResults, nil
@@ -314,8 +321,8 @@ func (p *HaisouPlugin) fetchSearchPage(client *http.Client, keyword string, page
 	searchURL := fmt.Sprintf("https://haisou.cc/api/pan/share/search?query=%s&scope=title&pan=%s&page=%d&filter_valid=true&filter_has_files=false",
 		url.QueryEscape(keyword), panType, pageNo)
 
-	if DebugLog {
-		fmt.Printf("[%s] 请求%s网盘第%d页: %s\n", p.Name(), panType, pageNo, searchURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("请求%s网盘第%d页: %s", panType, pageNo, searchURL)
 	}
 
 	// 创建带超时的上下文
@@ -364,8 +371,8 @@ func (p *HaisouPlugin) fetchSearchPage(client *http.Client, keyword string, page
 		return nil, fmt.Errorf("[%s] %s网盘第%d页API错误: %s", p.Name(), panType, pageNo, apiResp.Msg)
 	}
 
-	if DebugLog {
-		fmt.Printf("[%s] %s网盘第%d页获取到 %d 个搜索结果\n", p.Name(), panType, pageNo, len(apiResp.Data.List))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("%s网盘第%d页获取到 %d 个搜索结果", panType, pageNo, len(apiResp.Data.List))
 	}
 
 	return apiResp.Data.List, nil
@@ -376,8 +383,8 @@ func (p *HaisouPlugin) fetchShareLink(client *http.Client, hsid string, platform
 	// 构建获取链接的URL
 	fetchURL := fmt.Sprintf("https://haisou.cc/api/pan/share/%s/fetch", hsid)
 
-	if DebugLog {
-		fmt.Printf("[%s] 获取链接 hsid=%s platform=%s: %s\n", p.Name(), hsid, platform, fetchURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("获取链接 hsid=%s platform=%s: %s", hsid, platform, fetchURL)
 	}
 
 	// 创建带超时的上下文
@@ -438,8 +445,8 @@ func (p *HaisouPlugin) fetchShareLink(client *http.Client, hsid string, platform
 		password = *apiResp.Data.SharePwd
 	}
 
-	if DebugLog {
-		fmt.Printf("[%s] hsid=%s成功获取链接: %s password=%s\n", p.Name(), hsid, shareURL, password)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("hsid=%s成功获取链接: %s password=%s", hsid, shareURL, password)
 	}
 
 	return shareURL, password, nil
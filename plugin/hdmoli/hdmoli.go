@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -15,6 +14,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -31,11 +31,13 @@ const (
 // HdmoliPlugin HDmoli插件
 type HdmoliPlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode   bool
 	detailCache sync.Map // 缓存详情页结果
 	cacheTTL    time.Duration
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=hdmoli=debug 开启
+var pluginLog = logger.ForPlugin(PluginName)
+
 // init 注册插件
 func init() {
 	plugin.RegisterGlobalPlugin(NewHdmoliPlugin())
@@ -43,11 +45,8 @@ func init() {
 
 // NewHdmoliPlugin 创建新的HDmoli插件实例
 func NewHdmoliPlugin() *HdmoliPlugin {
-	debugMode := false // 生产环境关闭调试
-
 	p := &HdmoliPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPlugin(PluginName, 2), // 标准网盘插件，启用Service层过滤
-		debugMode:       debugMode,
 		cacheTTL:        30 * time.Minute, // 详情页缓存30分钟
 	}
 
@@ -76,8 +75,9 @@ func (p *HdmoliPlugin) Search(keyword string, ext map[string]interface{}) ([]mod
 
 // searchImpl 搜索实现
 func (p *HdmoliPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	if p.debugMode {
-		log.Printf("[HDMOLI] 开始搜索: %s", keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
 	}
 
 	// 第一步：执行搜索获取结果列表
@@ -86,22 +86,22 @@ func (p *HdmoliPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 		return nil, fmt.Errorf("[%s] 执行搜索失败: %w", p.Name(), err)
 	}
 
-	if p.debugMode {
-		log.Printf("[HDMOLI] 搜索获取到 %d 个结果", len(searchResults))
+	if log.DebugEnabled() {
+		log.Debugf("搜索获取到 %d 个结果", len(searchResults))
 	}
 
 	// 第二步：并发获取详情页链接
 	finalResults := p.fetchDetailLinks(client, searchResults, keyword)
 
-	if p.debugMode {
-		log.Printf("[HDMOLI] 最终获取到 %d 个有效结果", len(finalResults))
+	if log.DebugEnabled() {
+		log.Debugf("最终获取到 %d 个有效结果", len(finalResults))
 	}
 
 	// 第三步：关键词过滤（标准网盘插件需要过滤）
 	filteredResults := plugin.FilterResultsByKeyword(finalResults, keyword)
 	
-	if p.debugMode {
-		log.Printf("[HDMOLI] 关键词过滤后剩余 %d 个结果", len(filteredResults))
+	if log.DebugEnabled() {
+		log.Debugf("关键词过滤后剩余 %d 个结果", len(filteredResults))
 	}
 
 	return filteredResults, nil
@@ -194,8 +194,8 @@ func (p *HdmoliPlugin) parseSearchResults(doc *goquery.Document) ([]model.Search
 		}
 	})
 
-	if p.debugMode {
-		log.Printf("[HDMOLI] 解析到 %d 个原始结果", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析到 %d 个原始结果", len(results))
 	}
 
 	return results, nil
@@ -206,8 +206,8 @@ func (p *HdmoliPlugin) parseResultItem(s *goquery.Selection, index int) *model.S
 	// 提取标题和链接
 	titleEl := s.Find(".detail h4.title a")
 	if titleEl.Length() == 0 {
-		if p.debugMode {
-			log.Printf("[HDMOLI] 跳过无标题链接的结果")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("跳过无标题链接的结果")
 		}
 		return nil
 	}
@@ -229,8 +229,8 @@ func (p *HdmoliPlugin) parseResultItem(s *goquery.Selection, index int) *model.S
 	}
 
 	if detailURL == "" {
-		if p.debugMode {
-			log.Printf("[HDMOLI] 跳过无链接的结果: %s", title)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("跳过无链接的结果: %s", title)
 		}
 		return nil
 	}
@@ -318,8 +318,8 @@ func (p *HdmoliPlugin) parseResultItem(s *goquery.Selection, index int) *model.S
 	// 添加详情页URL到临时字段（用于后续处理）
 	result.Content += fmt.Sprintf("\n详情页URL: %s", detailURL)
 
-	if p.debugMode {
-		log.Printf("[HDMOLI] 解析结果: %s (%s)", title, category)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析结果: %s (%s)", title, category)
 	}
 
 	return &result
@@ -463,8 +463,8 @@ func (p *HdmoliPlugin) fetchDetailLinks(client *http.Client, searchResults []mod
 			// 从Content中提取详情页URL
 			detailURL := p.extractDetailURLFromContent(r.Content)
 			if detailURL == "" {
-				if p.debugMode {
-					log.Printf("[HDMOLI] 跳过无详情页URL的结果: %s", r.Title)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("跳过无详情页URL的结果: %s", r.Title)
 				}
 				return
 			}
@@ -476,8 +476,8 @@ func (p *HdmoliPlugin) fetchDetailLinks(client *http.Client, searchResults []mod
 				// 清理Content中的详情页URL
 				r.Content = p.cleanContent(r.Content)
 				resultsChan <- r
-			} else if p.debugMode {
-				log.Printf("[HDMOLI] 详情页无有效链接: %s", r.Title)
+			} else if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("详情页无有效链接: %s", r.Title)
 			}
 		}(result)
 	}
@@ -525,8 +525,8 @@ func (p *HdmoliPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 	// 检查缓存
 	if cached, found := p.detailCache.Load(detailURL); found {
 		if links, ok := cached.([]model.Link); ok {
-			if p.debugMode {
-				log.Printf("[HDMOLI] 使用缓存的详情页链接: %s", detailURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("使用缓存的详情页链接: %s", detailURL)
 			}
 			return links
 		}
@@ -538,8 +538,8 @@ func (p *HdmoliPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[HDMOLI] 创建详情页请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("创建详情页请求失败: %v", err)
 		}
 		return []model.Link{}
 	}
@@ -552,16 +552,16 @@ func (p *HdmoliPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 
 	resp, err := client.Do(req)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[HDMOLI] 详情页请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页请求失败: %v", err)
 		}
 		return []model.Link{}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		if p.debugMode {
-			log.Printf("[HDMOLI] 详情页HTTP状态错误: %d", resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页HTTP状态错误: %d", resp.StatusCode)
 		}
 		return []model.Link{}
 	}
@@ -569,8 +569,8 @@ func (p *HdmoliPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 	// 读取响应体
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[HDMOLI] 读取详情页响应失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("读取详情页响应失败: %v", err)
 		}
 		return []model.Link{}
 	}
@@ -583,8 +583,8 @@ func (p *HdmoliPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 		p.detailCache.Store(detailURL, links)
 	}
 
-	if p.debugMode {
-		log.Printf("[HDMOLI] 从详情页提取到 %d 个链接: %s", len(links), detailURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("从详情页提取到 %d 个链接: %s", len(links), detailURL)
 	}
 
 	return links
@@ -597,8 +597,8 @@ func (p *HdmoliPlugin) parseNetworkDiskLinks(htmlContent string) []model.Link {
 	// 解析HTML文档以便更精确的提取
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[HDMOLI] 解析详情页HTML失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("解析详情页HTML失败: %v", err)
 		}
 		// 如果解析失败，使用正则表达式作为备选
 		return p.parseNetworkDiskLinksWithRegex(htmlContent)
@@ -620,8 +620,8 @@ func (p *HdmoliPlugin) parseNetworkDiskLinks(htmlContent string) []model.Link {
 							Password: p.extractPasswordFromQuarkURL(href),
 						}
 						links = append(links, link)
-						if p.debugMode {
-							log.Printf("[HDMOLI] 找到夸克链接: %s", href)
+						if pluginLog.DebugEnabled() {
+							pluginLog.Debugf("找到夸克链接: %s", href)
 						}
 					}
 				})
@@ -639,8 +639,8 @@ func (p *HdmoliPlugin) parseNetworkDiskLinks(htmlContent string) []model.Link {
 							Password: password,
 						}
 						links = append(links, link)
-						if p.debugMode {
-							log.Printf("[HDMOLI] 找到百度链接: %s (密码: %s)", href, password)
+						if pluginLog.DebugEnabled() {
+							pluginLog.Debugf("找到百度链接: %s (密码: %s)", href, password)
 						}
 					}
 				})
@@ -106,6 +106,18 @@ func NewHdr4kAsyncPlugin() *Hdr4kAsyncPlugin {
 	}
 }
 
+// Info 返回插件元数据
+func (p *Hdr4kAsyncPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: "4KHDR",
+		Homepage:    "https://www.4khdr.cn",
+		Category:    plugin.CategoryVideo,
+		Ext: []plugin.ExtParam{
+			{Key: "title_en", Type: plugin.ExtString, Description: "英文标题，设置后代替关键词搜索"},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *Hdr4kAsyncPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -117,7 +129,7 @@ func (p *Hdr4kAsyncPlugin) Search(keyword string, ext map[string]interface{}) ([
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *Hdr4kAsyncPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.doSearch, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.doSearch, ext)
 }
 
 // doSearch 实际的搜索实现
@@ -126,7 +138,7 @@ func (p *Hdr4kAsyncPlugin) doSearch(client *http.Client, keyword string, ext map
 	searchKeyword := keyword
 	if ext != nil {
 		// 使用类型断言安全地获取参数
-		if titleEn, ok := ext["title_en"].(string); ok && titleEn != "" {
+		if titleEn, ok := plugin.GetString(ext, "title_en"); ok && titleEn != "" {
 			// 使用英文标题替换关键词
 			searchKeyword = titleEn
 		}
@@ -0,0 +1,200 @@
+package plugin
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"pansou/config"
+)
+
+// 熔断器状态
+const (
+	CircuitClosed   = "closed"    // 正常，插件参与搜索
+	CircuitOpen     = "open"      // 熔断，插件被跳过
+	CircuitHalfOpen = "half_open" // 冷却结束，放行一次探测请求
+)
+
+// 熔断器默认参数（配置未初始化时使用）
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitCooldown         = 60 * time.Second
+)
+
+// PluginHealthStatus 插件健康状态快照，用于健康检查接口展示
+type PluginHealthStatus struct {
+	Name                string     `json:"name"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	TotalSuccesses      int64      `json:"total_successes"`
+	TotalFailures       int64      `json:"total_failures"`
+	LastLatencyMs       int64      `json:"last_latency_ms"`
+	AvgLatencyMs        int64      `json:"avg_latency_ms"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"` // 熔断状态下下次探测的时间
+}
+
+// pluginHealth 单个插件的健康统计和熔断状态
+type pluginHealth struct {
+	mutex               sync.Mutex
+	state               string
+	consecutiveFailures int
+	totalSuccesses      int64
+	totalFailures       int64
+	lastLatency         time.Duration
+	avgLatency          time.Duration // 指数加权平均延迟
+	lastError           string
+	lastSuccessAt       time.Time
+	lastFailureAt       time.Time
+	openedAt            time.Time
+	probing             bool      // 半开状态下是否已有探测请求在进行
+	probeStartedAt      time.Time // 探测请求开始时间，探测未能完成时超过冷却时间后重新放行
+}
+
+// circuitSettings 返回熔断器是否启用、失败阈值和冷却时间
+func circuitSettings() (bool, int, time.Duration) {
+	if config.AppConfig == nil {
+		return true, defaultCircuitFailureThreshold, defaultCircuitCooldown
+	}
+	return config.AppConfig.CircuitBreakerEnabled, config.AppConfig.CircuitBreakerThreshold, config.AppConfig.CircuitBreakerCooldown
+}
+
+// healthOf 获取插件的健康记录，不存在时创建
+func (pm *PluginManager) healthOf(name string) *pluginHealth {
+	pm.healthMutex.RLock()
+	health, ok := pm.health[name]
+	pm.healthMutex.RUnlock()
+	if ok {
+		return health
+	}
+
+	pm.healthMutex.Lock()
+	defer pm.healthMutex.Unlock()
+	if health, ok = pm.health[name]; !ok {
+		health = &pluginHealth{state: CircuitClosed}
+		pm.health[name] = health
+	}
+	return health
+}
+
+// AllowPlugin 判断插件本次是否可以参与搜索
+// 熔断状态下冷却时间结束后转为半开状态，只放行一个探测请求
+func (pm *PluginManager) AllowPlugin(name string) bool {
+	enabled, _, cooldown := circuitSettings()
+	if !enabled {
+		return true
+	}
+
+	health := pm.healthOf(name)
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+
+	switch health.state {
+	case CircuitOpen:
+		if time.Since(health.openedAt) < cooldown {
+			return false
+		}
+		health.state = CircuitHalfOpen
+		health.probing = true
+		health.probeStartedAt = time.Now()
+		return true
+	case CircuitHalfOpen:
+		if health.probing && time.Since(health.probeStartedAt) < cooldown {
+			return false
+		}
+		health.probing = true
+		health.probeStartedAt = time.Now()
+		return true
+	default:
+		return true
+	}
+}
+
+// RecordPluginResult 记录插件一次搜索的结果和耗时，err不为nil表示失败或超时
+func (pm *PluginManager) RecordPluginResult(name string, latency time.Duration, err error) {
+	_, threshold, _ := circuitSettings()
+
+	health := pm.healthOf(name)
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+
+	now := time.Now()
+	health.lastLatency = latency
+	if health.avgLatency == 0 {
+		health.avgLatency = latency
+	} else {
+		health.avgLatency = (health.avgLatency*4 + latency) / 5
+	}
+	health.probing = false
+
+	if err == nil {
+		health.totalSuccesses++
+		health.consecutiveFailures = 0
+		health.lastSuccessAt = now
+		health.state = CircuitClosed
+		return
+	}
+
+	health.totalFailures++
+	health.consecutiveFailures++
+	health.lastError = err.Error()
+	health.lastFailureAt = now
+
+	// 半开探测失败或连续失败达到阈值，打开熔断
+	if health.state == CircuitHalfOpen || health.consecutiveFailures >= threshold {
+		health.state = CircuitOpen
+		health.openedAt = now
+	}
+}
+
+// ReleasePluginProbe 放弃半开状态下的本次探测，不改变熔断状态
+// 用于未能反映插件是否可用的调用（如命中插件级缓存），下一个请求可以立即重新探测
+func (pm *PluginManager) ReleasePluginProbe(name string) {
+	health := pm.healthOf(name)
+	health.mutex.Lock()
+	health.probing = false
+	health.mutex.Unlock()
+}
+
+// PluginHealthStatuses 返回已注册插件的健康状态，按插件名称排序
+func (pm *PluginManager) PluginHealthStatuses() []PluginHealthStatus {
+	_, _, cooldown := circuitSettings()
+
+	plugins := pm.GetPlugins()
+	statuses := make([]PluginHealthStatus, 0, len(plugins))
+	for _, p := range plugins {
+		health := pm.healthOf(p.Name())
+		health.mutex.Lock()
+		status := PluginHealthStatus{
+			Name:                p.Name(),
+			State:               health.state,
+			ConsecutiveFailures: health.consecutiveFailures,
+			TotalSuccesses:      health.totalSuccesses,
+			TotalFailures:       health.totalFailures,
+			LastLatencyMs:       health.lastLatency.Milliseconds(),
+			AvgLatencyMs:        health.avgLatency.Milliseconds(),
+			LastError:           health.lastError,
+		}
+		if !health.lastSuccessAt.IsZero() {
+			lastSuccessAt := health.lastSuccessAt
+			status.LastSuccessAt = &lastSuccessAt
+		}
+		if !health.lastFailureAt.IsZero() {
+			lastFailureAt := health.lastFailureAt
+			status.LastFailureAt = &lastFailureAt
+		}
+		if health.state == CircuitOpen {
+			retryAt := health.openedAt.Add(cooldown)
+			status.RetryAt = &retryAt
+		}
+		health.mutex.Unlock()
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
@@ -53,7 +53,7 @@ func (p *HunhepanAsyncPlugin) Search(keyword string, ext map[string]interface{})
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *HunhepanAsyncPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.doSearch, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.doSearch, ext)
 }
 
 // doSearch 实际的搜索实现
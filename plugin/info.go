@@ -0,0 +1,180 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 插件内容分类
+const (
+	CategoryNetdisk = "netdisk" // 网盘资源
+	CategoryMagnet  = "magnet"  // 磁力和电驴链接
+	CategoryVideo   = "video"   // 影视资源站
+	CategoryAdult   = "adult"   // 成人内容
+)
+
+// ext参数类型
+const (
+	ExtString      = "string"
+	ExtInt         = "int"
+	ExtBool        = "bool"
+	ExtStringSlice = "string_list" // JSON数组或逗号分隔的字符串
+)
+
+// ExtParam 插件接受的ext参数
+type ExtParam struct {
+	Key         string      `json:"key"`
+	Type        string      `json:"type"` // string、int、bool或string_list
+	Description string      `json:"description"`
+	Default     interface{} `json:"default,omitempty"`
+}
+
+// PluginMetadata 插件元数据，用于插件列表接口展示和ext参数校验
+type PluginMetadata struct {
+	DisplayName string     `json:"display_name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Homepage    string     `json:"homepage,omitempty"`
+	Category    string     `json:"category,omitempty"`    // 内容分类：netdisk、magnet、video、adult
+	CloudTypes  []string   `json:"cloud_types,omitempty"` // 可能返回的网盘类型
+	Ext         []ExtParam `json:"ext,omitempty"`         // 接受的ext参数
+}
+
+// PluginInfo 插件元数据接口，插件可选实现
+type PluginInfo interface {
+	// Info 返回插件元数据
+	Info() PluginMetadata
+}
+
+// GetPluginMetadata 获取插件元数据；未实现PluginInfo的插件使用已有的DisplayName和Description方法
+func GetPluginMetadata(p AsyncSearchPlugin) PluginMetadata {
+	var meta PluginMetadata
+	if info, ok := p.(PluginInfo); ok {
+		meta = info.Info()
+	}
+	if named, ok := p.(interface{ DisplayName() string }); ok && meta.DisplayName == "" {
+		meta.DisplayName = named.DisplayName()
+	}
+	if described, ok := p.(interface{ Description() string }); ok && meta.Description == "" {
+		meta.Description = described.Description()
+	}
+	if meta.DisplayName == "" {
+		meta.DisplayName = p.Name()
+	}
+	return meta
+}
+
+// ValidateExt 按插件声明的ext参数校验ext，返回把值转换为声明的类型（如JSON中的数字转换为int）后的副本，不修改传入的ext。
+// 全局参数按所有插件的声明校验，多个插件声明同一参数时，值符合其中任一声明即可；
+// ext.plugins中的参数只按对应插件的声明校验；没有插件声明的参数原样保留
+func ValidateExt(plugins []AsyncSearchPlugin, ext map[string]interface{}) (map[string]interface{}, error) {
+	if len(ext) == 0 {
+		return ext, nil
+	}
+
+	declared := make(map[string][]ExtParam)
+	pluginParams := make(map[string]map[string][]ExtParam)
+	for _, p := range plugins {
+		info, ok := p.(PluginInfo)
+		if !ok {
+			continue
+		}
+		params := make(map[string][]ExtParam)
+		for _, param := range info.Info().Ext {
+			declared[param.Key] = append(declared[param.Key], param)
+			params[param.Key] = append(params[param.Key], param)
+		}
+		pluginParams[strings.ToLower(p.Name())] = params
+	}
+
+	converted := copyExt(ext)
+	if err := convertExtValues(converted, declared, ""); err != nil {
+		return nil, err
+	}
+
+	sections, ok := ext[ExtPluginsKey]
+	if !ok {
+		return converted, nil
+	}
+	sectionMap, ok := sections.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ext参数%s必须是以插件名为键的对象", ExtPluginsKey)
+	}
+	convertedSections := make(map[string]interface{}, len(sectionMap))
+	for name, section := range sectionMap {
+		values, ok := section.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("ext参数%s.%s必须是对象", ExtPluginsKey, name)
+		}
+		values = copyExt(values)
+		if err := convertExtValues(values, pluginParams[strings.ToLower(name)], ExtPluginsKey+"."+name+"."); err != nil {
+			return nil, err
+		}
+		convertedSections[name] = values
+	}
+	converted[ExtPluginsKey] = convertedSections
+	return converted, nil
+}
+
+// copyExt 复制ext的第一层
+func copyExt(ext map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(ext))
+	for key, value := range ext {
+		copied[key] = value
+	}
+	return copied
+}
+
+// convertExtValues 把values中已声明的参数转换为声明的类型，prefix用于错误信息中的参数名
+func convertExtValues(values map[string]interface{}, declared map[string][]ExtParam, prefix string) error {
+	for key, value := range values {
+		params := declared[key]
+		if key == ExtPluginsKey || len(params) == 0 {
+			continue
+		}
+		var lastErr error
+		converted := false
+		for _, param := range params {
+			v, err := param.convert(value)
+			if err == nil {
+				values[key] = v
+				converted = true
+				break
+			}
+			lastErr = err
+		}
+		if !converted {
+			return fmt.Errorf("ext参数%s%s无效: %v", prefix, key, lastErr)
+		}
+	}
+	return nil
+}
+
+// convert 把值转换为参数声明的类型
+func (p ExtParam) convert(value interface{}) (interface{}, error) {
+	switch p.Type {
+	case ExtString:
+		if s, ok := toString(value); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("需要字符串")
+
+	case ExtInt:
+		if n, ok := toInt(value); ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("需要整数")
+
+	case ExtBool:
+		if b, ok := toBool(value); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("需要布尔值")
+
+	case ExtStringSlice:
+		if items, ok := toStringSlice(value); ok {
+			return items, nil
+		}
+		return nil, fmt.Errorf("需要字符串列表")
+	}
+	return value, nil
+}
@@ -5,7 +5,6 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
-	"log"
 	"math/rand"
 	"net/http"
 	"net/url"
@@ -18,6 +17,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -39,13 +39,15 @@ const (
 // JavdbPlugin JavDB插件
 type JavdbPlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode      bool
 	detailCache    sync.Map // 缓存详情页结果
 	cacheTTL       time.Duration
 	rateLimited    int32  // 429限流标志位，使用atomic操作
 	rateLimitCount int32  // 429错误计数
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=javdb=debug 开启
+var pluginLog = logger.ForPlugin(PluginName)
+
 // init 注册插件
 func init() {
 	plugin.RegisterGlobalPlugin(NewJavdbPlugin())
@@ -53,14 +55,11 @@ func init() {
 
 // NewJavdbPlugin 创建新的JavDB插件实例
 func NewJavdbPlugin() *JavdbPlugin {
-	debugMode := false 
-	
 	// 初始化随机种子
 	rand.Seed(time.Now().UnixNano())
 
 	p := &JavdbPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPluginWithFilter(PluginName, 5, true), 
-		debugMode:       debugMode,
 		cacheTTL:        30 * time.Minute, // 详情页缓存30分钟
 	}
 
@@ -82,6 +81,15 @@ func (p *JavdbPlugin) Description() string {
 	return Description
 }
 
+// Info 返回插件元数据
+func (p *JavdbPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		Homepage:   BaseURL,
+		Category:   plugin.CategoryAdult,
+		CloudTypes: []string{"magnet"},
+	}
+}
+
 // SkipServiceFilter 磁力搜索插件，跳过Service层过滤
 func (p *JavdbPlugin) SkipServiceFilter() bool {
 	return true // 磁力搜索，跳过网盘服务过滤
@@ -98,17 +106,18 @@ func (p *JavdbPlugin) Search(keyword string, ext map[string]interface{}) ([]mode
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *JavdbPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 搜索实现
 func (p *JavdbPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	if p.debugMode {
-		log.Printf("[JAVDB] 开始搜索: %s", keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
 	}
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 开始搜索，客户端超时: %v", client.Timeout)
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索，客户端超时: %v", client.Timeout)
 	}
 
 	// 第一步：执行搜索获取结果列表
@@ -117,18 +126,18 @@ func (p *JavdbPlugin) searchImpl(client *http.Client, keyword string, ext map[st
 		return nil, fmt.Errorf("[%s] 执行搜索失败: %w", p.Name(), err)
 	}
 
-	if p.debugMode {
+	if log.DebugEnabled() {
 		if isRateLimited {
-			log.Printf("[JAVDB] ⚡ 遇到429限流，但继续处理已获取的 %d 个结果", len(searchResults))
+			log.Debugf("⚡ 遇到429限流，但继续处理已获取的 %d 个结果", len(searchResults))
 		} else {
-			log.Printf("[JAVDB] 搜索获取到 %d 个结果", len(searchResults))
+			log.Debugf("搜索获取到 %d 个结果", len(searchResults))
 		}
 	}
 
 	// 如果没有搜索结果，直接返回
 	if len(searchResults) == 0 {
-		if p.debugMode {
-			log.Printf("[JAVDB] 无搜索结果，直接返回")
+		if log.DebugEnabled() {
+			log.Debugf("无搜索结果，直接返回")
 		}
 		return []model.SearchResult{}, nil
 	}
@@ -136,10 +145,10 @@ func (p *JavdbPlugin) searchImpl(client *http.Client, keyword string, ext map[st
 	// 第二步：并发获取详情页磁力链接（设定合理超时）
 	finalResults := p.fetchDetailMagnetLinks(client, searchResults, keyword)
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 最终获取到 %d 个有效结果", len(finalResults))
+	if log.DebugEnabled() {
+		log.Debugf("最终获取到 %d 个有效结果", len(finalResults))
 		if isRateLimited {
-			log.Printf("[JAVDB] ⚡ 由于429限流，结果可能不完整，系统将在后台继续获取")
+			log.Debugf("⚡ 由于429限流，结果可能不完整，系统将在后台继续获取")
 		}
 	}
 
@@ -154,17 +163,17 @@ func (p *JavdbPlugin) executeSearchWithRateLimit(client *http.Client, keyword st
 	// 构建搜索URL
 	searchURL := fmt.Sprintf("%s%s", BaseURL, fmt.Sprintf(SearchPath, url.QueryEscape(keyword)))
 	
-	if p.debugMode {
-		log.Printf("[JAVDB] 搜索URL: %s", searchURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("搜索URL: %s", searchURL)
 		// 显示重试配置信息
 		if MaxRetryOnRateLimit > 0 {
-			log.Printf("[JAVDB] 429重试配置: 最大%d次，延迟%d-%d秒", MaxRetryOnRateLimit, MinRetryDelay, MaxRetryDelay)
+			pluginLog.Debugf("429重试配置: 最大%d次，延迟%d-%d秒", MaxRetryOnRateLimit, MinRetryDelay, MaxRetryDelay)
 		} else {
-			log.Printf("[JAVDB] 429重试配置: 禁用重试")
+			pluginLog.Debugf("429重试配置: 禁用重试")
 		}
 		// 如果之前有限流，显示统计信息
 		if count := atomic.LoadInt32(&p.rateLimitCount); count > 0 {
-			log.Printf("[JAVDB] 历史429限流次数: %d", count)
+			pluginLog.Debugf("历史429限流次数: %d", count)
 		}
 	}
 
@@ -186,8 +195,8 @@ func (p *JavdbPlugin) executeSearchWithRateLimit(client *http.Client, keyword st
 	req.Header.Set("Cache-Control", "max-age=0")
 	req.Header.Set("Referer", BaseURL+"/")
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 发送搜索请求...")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("发送搜索请求...")
 	}
 
 	resp, err := client.Do(req)
@@ -196,16 +205,16 @@ func (p *JavdbPlugin) executeSearchWithRateLimit(client *http.Client, keyword st
 	}
 	defer resp.Body.Close()
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 搜索请求响应状态: %d", resp.StatusCode)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("搜索请求响应状态: %d", resp.StatusCode)
 	}
 
 	// 检测429限流 - 立即返回，不延迟
 	if resp.StatusCode == 429 {
 		atomic.StoreInt32(&p.rateLimited, 1)
 		atomic.AddInt32(&p.rateLimitCount, 1)
-		if p.debugMode {
-			log.Printf("[JAVDB] ⚡ 检测到429限流，立即返回空结果")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("⚡ 检测到429限流，立即返回空结果")
 		}
 		return []model.SearchResult{}, nil, true // 返回空结果和限流标志
 	}
@@ -220,14 +229,14 @@ func (p *JavdbPlugin) executeSearchWithRateLimit(client *http.Client, keyword st
 		return nil, fmt.Errorf("[%s] 读取搜索结果失败: %w", p.Name(), err), false
 	}
 
-	if p.debugMode {
+	if pluginLog.DebugEnabled() {
 		bodyStr := string(bodyBytes)
-		log.Printf("[JAVDB] 响应体长度: %d", len(bodyStr))
+		pluginLog.Debugf("响应体长度: %d", len(bodyStr))
 		// 输出前500个字符用于调试
 		if len(bodyStr) > 500 {
-			log.Printf("[JAVDB] 响应体前500字符: %s", bodyStr[:500])
+			pluginLog.Debugf("响应体前500字符: %s", bodyStr[:500])
 		} else {
-			log.Printf("[JAVDB] 完整响应体: %s", bodyStr)
+			pluginLog.Debugf("完整响应体: %s", bodyStr)
 		}
 	}
 
@@ -279,8 +288,8 @@ func (p *JavdbPlugin) doRequestWithRateLimitRetry(req *http.Request, client *htt
 		if attempt > 0 {
 			// 随机延迟，避免同时重试造成更大压力
 			delaySeconds := rand.Intn(MaxRetryDelay-MinRetryDelay+1) + MinRetryDelay
-			if p.debugMode {
-				log.Printf("[JAVDB] 429重试 %d/%d，随机延迟 %d 秒", attempt, MaxRetryOnRateLimit, delaySeconds)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("429重试 %d/%d，随机延迟 %d 秒", attempt, MaxRetryOnRateLimit, delaySeconds)
 			}
 			time.Sleep(time.Duration(delaySeconds) * time.Second)
 		}
@@ -304,8 +313,8 @@ func (p *JavdbPlugin) doRequestWithRateLimitRetry(req *http.Request, client *htt
 		
 		// 遇到429
 		atomic.AddInt32(&p.rateLimitCount, 1)
-		if p.debugMode {
-			log.Printf("[JAVDB] 遇到429限流，尝试 %d/%d", attempt+1, MaxRetryOnRateLimit+1)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("遇到429限流，尝试 %d/%d", attempt+1, MaxRetryOnRateLimit+1)
 		}
 		
 		// 如果不允许重试或已达到最大重试次数
@@ -332,18 +341,18 @@ func (p *JavdbPlugin) doRequestWithRateLimitRetry(req *http.Request, client *htt
 func (p *JavdbPlugin) parseSearchResults(doc *goquery.Document) ([]model.SearchResult, error) {
 	var results []model.SearchResult
 
-	if p.debugMode {
+	if pluginLog.DebugEnabled() {
 		// 检查是否找到了.movie-list元素
 		movieListEl := doc.Find(".movie-list")
-		log.Printf("[JAVDB] 找到.movie-list元素数量: %d", movieListEl.Length())
+		pluginLog.Debugf("找到.movie-list元素数量: %d", movieListEl.Length())
 		
 		// 检查是否找到了.item元素
 		itemEls := doc.Find(".movie-list .item")
-		log.Printf("[JAVDB] 找到.movie-list .item元素数量: %d", itemEls.Length())
+		pluginLog.Debugf("找到.movie-list .item元素数量: %d", itemEls.Length())
 		
 		// 如果没有找到预期元素，尝试其他可能的选择器
 		if itemEls.Length() == 0 {
-			log.Printf("[JAVDB] 尝试查找其他可能的结果元素...")
+			pluginLog.Debugf("尝试查找其他可能的结果元素...")
 			
 			// 尝试其他可能的选择器
 			altSelectors := []string{
@@ -357,14 +366,14 @@ func (p *JavdbPlugin) parseSearchResults(doc *goquery.Document) ([]model.SearchR
 			for _, selector := range altSelectors {
 				altEls := doc.Find(selector)
 				if altEls.Length() > 0 {
-					log.Printf("[JAVDB] 找到替代选择器 '%s' 的元素数量: %d", selector, altEls.Length())
+					pluginLog.Debugf("找到替代选择器 '%s' 的元素数量: %d", selector, altEls.Length())
 				}
 			}
 			
 			// 输出页面的主要结构用于调试
 			doc.Find("div[class*='movie'], div[class*='video'], div[class*='search'], div[class*='result']").Each(func(i int, s *goquery.Selection) {
 				className, _ := s.Attr("class")
-				log.Printf("[JAVDB] 找到可能相关的div元素: class='%s'", className)
+				pluginLog.Debugf("找到可能相关的div元素: class='%s'", className)
 			})
 		}
 	}
@@ -375,23 +384,23 @@ func (p *JavdbPlugin) parseSearchResults(doc *goquery.Document) ([]model.SearchR
 			return
 		}
 
-		if p.debugMode {
-			log.Printf("[JAVDB] 开始解析第 %d 个结果项", i+1)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("开始解析第 %d 个结果项", i+1)
 		}
 
 		result := p.parseResultItem(s, i+1)
 		if result != nil {
 			results = append(results, *result)
-			if p.debugMode {
-				log.Printf("[JAVDB] 成功解析第 %d 个结果项: %s", i+1, result.Title)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("成功解析第 %d 个结果项: %s", i+1, result.Title)
 			}
-		} else if p.debugMode {
-			log.Printf("[JAVDB] 第 %d 个结果项解析失败", i+1)
+		} else if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("第 %d 个结果项解析失败", i+1)
 		}
 	})
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 解析到 %d 个原始结果", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析到 %d 个原始结果", len(results))
 	}
 
 	return results, nil
@@ -399,20 +408,20 @@ func (p *JavdbPlugin) parseSearchResults(doc *goquery.Document) ([]model.SearchR
 
 // parseResultItem 解析单个搜索结果项
 func (p *JavdbPlugin) parseResultItem(s *goquery.Selection, index int) *model.SearchResult {
-	if p.debugMode {
+	if pluginLog.DebugEnabled() {
 		// 输出当前结果项的HTML结构用于调试
 		itemHTML, _ := s.Html()
 		if len(itemHTML) > 300 {
-			log.Printf("[JAVDB] 结果项 %d HTML前300字符: %s", index, itemHTML[:300])
+			pluginLog.Debugf("结果项 %d HTML前300字符: %s", index, itemHTML[:300])
 		} else {
-			log.Printf("[JAVDB] 结果项 %d 完整HTML: %s", index, itemHTML)
+			pluginLog.Debugf("结果项 %d 完整HTML: %s", index, itemHTML)
 		}
 	}
 
 	// 提取详情页链接
 	linkEl := s.Find("a.box")
-	if p.debugMode {
-		log.Printf("[JAVDB] 结果项 %d 找到a.box元素数量: %d", index, linkEl.Length())
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("结果项 %d 找到a.box元素数量: %d", index, linkEl.Length())
 		
 		// 如果没有找到a.box，尝试其他可能的链接选择器
 		if linkEl.Length() == 0 {
@@ -420,15 +429,15 @@ func (p *JavdbPlugin) parseResultItem(s *goquery.Selection, index int) *model.Se
 			for _, selector := range altLinkSelectors {
 				altLinks := s.Find(selector)
 				if altLinks.Length() > 0 {
-					log.Printf("[JAVDB] 结果项 %d 找到替代链接选择器 '%s' 的元素数量: %d", index, selector, altLinks.Length())
+					pluginLog.Debugf("结果项 %d 找到替代链接选择器 '%s' 的元素数量: %d", index, selector, altLinks.Length())
 				}
 			}
 		}
 	}
 
 	if linkEl.Length() == 0 {
-		if p.debugMode {
-			log.Printf("[JAVDB] 跳过无链接的结果")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("跳过无链接的结果")
 		}
 		return nil
 	}
@@ -436,14 +445,14 @@ func (p *JavdbPlugin) parseResultItem(s *goquery.Selection, index int) *model.Se
 	detailURL, _ := linkEl.Attr("href")
 	title, _ := linkEl.Attr("title")
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 结果项 %d 详情页URL: %s", index, detailURL)
-		log.Printf("[JAVDB] 结果项 %d 标题: %s", index, title)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("结果项 %d 详情页URL: %s", index, detailURL)
+		pluginLog.Debugf("结果项 %d 标题: %s", index, title)
 	}
 
 	if detailURL == "" || title == "" {
-		if p.debugMode {
-			log.Printf("[JAVDB] 跳过无效链接或标题的结果")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("跳过无效链接或标题的结果")
 		}
 		return nil
 	}
@@ -500,8 +509,8 @@ func (p *JavdbPlugin) parseResultItem(s *goquery.Selection, index int) *model.Se
 	// 添加详情页URL到临时字段（用于后续处理）
 	result.Content += fmt.Sprintf("\n详情页URL: %s", detailURL)
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 解析结果: %s (%s)", title, videoNumber)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析结果: %s (%s)", title, videoNumber)
 	}
 
 	return &result
@@ -595,14 +604,14 @@ func (p *JavdbPlugin) parseTime(dateStr string) time.Time {
 // fetchDetailMagnetLinks 并发获取详情页磁力链接
 func (p *JavdbPlugin) fetchDetailMagnetLinks(client *http.Client, searchResults []model.SearchResult, keyword string) []model.SearchResult {
 	if len(searchResults) == 0 {
-		if p.debugMode {
-			log.Printf("[JAVDB] 无搜索结果需要获取详情页")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("无搜索结果需要获取详情页")
 		}
 		return []model.SearchResult{}
 	}
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 开始获取 %d 个搜索结果的详情页磁力链接", len(searchResults))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始获取 %d 个搜索结果的详情页磁力链接", len(searchResults))
 	}
 
 	// 使用通道控制并发数
@@ -617,8 +626,8 @@ func (p *JavdbPlugin) fetchDetailMagnetLinks(client *http.Client, searchResults
 	for i, result := range searchResults {
 		// 检查是否已经被限流，如果是则停止启动新的goroutine
 		if atomic.LoadInt32(&p.rateLimited) == 1 {
-			if p.debugMode {
-				log.Printf("[JAVDB] 检测到限流状态，停止启动新的详情页请求")
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("检测到限流状态，停止启动新的详情页请求")
 			}
 			break
 		}
@@ -631,34 +640,34 @@ func (p *JavdbPlugin) fetchDetailMagnetLinks(client *http.Client, searchResults
 
 			// 在goroutine内部再次检查限流状态
 			if atomic.LoadInt32(&p.rateLimited) == 1 {
-				if p.debugMode {
-					log.Printf("[JAVDB] goroutine内检测到限流状态，跳过详情页请求: %s", r.Title)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("goroutine内检测到限流状态，跳过详情页请求: %s", r.Title)
 				}
 				return
 			}
 
-			if p.debugMode {
-				log.Printf("[JAVDB] 开始处理第 %d 个搜索结果: %s", index+1, r.Title)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("开始处理第 %d 个搜索结果: %s", index+1, r.Title)
 			}
 
 			// 从Content中提取详情页URL
 			detailURL := p.extractDetailURLFromContent(r.Content)
 			if detailURL == "" {
-				if p.debugMode {
-					log.Printf("[JAVDB] 跳过无详情页URL的结果: %s", r.Title)
-					log.Printf("[JAVDB] Content内容: %s", r.Content)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("跳过无详情页URL的结果: %s", r.Title)
+					pluginLog.Debugf("Content内容: %s", r.Content)
 				}
 				return
 			}
 
-			if p.debugMode {
-				log.Printf("[JAVDB] 第 %d 个结果详情页URL: %s", index+1, detailURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("第 %d 个结果详情页URL: %s", index+1, detailURL)
 			}
 
 			// 获取详情页磁力链接
 			magnetLinks := p.fetchDetailPageMagnetLinks(client, detailURL)
-			if p.debugMode {
-				log.Printf("[JAVDB] 第 %d 个结果获取到 %d 个磁力链接", index+1, len(magnetLinks))
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("第 %d 个结果获取到 %d 个磁力链接", index+1, len(magnetLinks))
 			}
 
 			if len(magnetLinks) > 0 {
@@ -678,11 +687,11 @@ func (p *JavdbPlugin) fetchDetailMagnetLinks(client *http.Client, searchResults
 					results = append(results, newResult)
 				}
 				resultsChan <- results
-				if p.debugMode {
-					log.Printf("[JAVDB] 第 %d 个结果成功创建 %d 个最终结果", index+1, len(results))
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("第 %d 个结果成功创建 %d 个最终结果", index+1, len(results))
 				}
-			} else if p.debugMode {
-				log.Printf("[JAVDB] 详情页无磁力链接: %s", r.Title)
+			} else if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("详情页无磁力链接: %s", r.Title)
 			}
 		}(result, i)
 	}
@@ -707,17 +716,17 @@ func (p *JavdbPlugin) fetchDetailMagnetLinks(client *http.Client, searchResults
 					break collectLoop
 				}
 				finalResults = append(finalResults, results...)
-				if p.debugMode {
-					log.Printf("[JAVDB] 收集到一批结果，数量: %d，总数: %d", len(results), len(finalResults))
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("收集到一批结果，数量: %d，总数: %d", len(results), len(finalResults))
 				}
 			case <-timeout:
-				if p.debugMode {
-					log.Printf("[JAVDB] ⏰ 4秒超时，返回已获取的 %d 个结果", len(finalResults))
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("⏰ 4秒超时，返回已获取的 %d 个结果", len(finalResults))
 				}
 				break collectLoop
 			case <-done:
-				if p.debugMode {
-					log.Printf("[JAVDB] 所有详情页请求完成")
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("所有详情页请求完成")
 				}
 				break collectLoop
 			}
@@ -731,12 +740,12 @@ func (p *JavdbPlugin) fetchDetailMagnetLinks(client *http.Client, searchResults
 					goto finished
 				}
 				finalResults = append(finalResults, results...)
-				if p.debugMode {
-					log.Printf("[JAVDB] 收集到一批结果，数量: %d，总数: %d", len(results), len(finalResults))
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("收集到一批结果，数量: %d，总数: %d", len(results), len(finalResults))
 				}
 			case <-done:
-				if p.debugMode {
-					log.Printf("[JAVDB] 所有详情页请求完成")
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("所有详情页请求完成")
 				}
 				goto finished
 			}
@@ -744,11 +753,11 @@ func (p *JavdbPlugin) fetchDetailMagnetLinks(client *http.Client, searchResults
 	finished:
 	}
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 最终收集到 %d 个结果", len(finalResults))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("最终收集到 %d 个结果", len(finalResults))
 		// 如果遇到了限流，提示用户
 		if atomic.LoadInt32(&p.rateLimited) == 1 {
-			log.Printf("[JAVDB] 本次搜索遇到429限流，结果可能不完整")
+			pluginLog.Debugf("本次搜索遇到429限流，结果可能不完整")
 		}
 	}
 
@@ -782,15 +791,15 @@ func (p *JavdbPlugin) cleanContent(content string) string {
 
 // fetchDetailPageMagnetLinks 获取详情页的磁力链接
 func (p *JavdbPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL string) []model.Link {
-	if p.debugMode {
-		log.Printf("[JAVDB] 开始获取详情页磁力链接: %s", detailURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始获取详情页磁力链接: %s", detailURL)
 	}
 
 	// 检查缓存
 	if cached, found := p.detailCache.Load(detailURL); found {
 		if links, ok := cached.([]model.Link); ok {
-			if p.debugMode {
-				log.Printf("[JAVDB] 使用缓存的详情页链接: %s", detailURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("使用缓存的详情页链接: %s", detailURL)
 			}
 			return links
 		}
@@ -802,8 +811,8 @@ func (p *JavdbPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL
 
 	req, err := http.NewRequestWithContext(ctx, "GET", detailURL, nil)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[JAVDB] 创建详情页请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("创建详情页请求失败: %v", err)
 		}
 		return []model.Link{}
 	}
@@ -814,26 +823,26 @@ func (p *JavdbPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL
 	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
 	req.Header.Set("Referer", BaseURL+"/")
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 发送详情页请求...")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("发送详情页请求...")
 	}
 
 	resp, err := p.doRequestWithRateLimitRetry(req, client)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[JAVDB] 详情页请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页请求失败: %v", err)
 		}
 		return []model.Link{}
 	}
 	defer resp.Body.Close()
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 详情页请求响应状态: %d", resp.StatusCode)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("详情页请求响应状态: %d", resp.StatusCode)
 	}
 
 	if resp.StatusCode != 200 {
-		if p.debugMode {
-			log.Printf("[JAVDB] 详情页HTTP状态错误: %d", resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页HTTP状态错误: %d", resp.StatusCode)
 		}
 		return []model.Link{}
 	}
@@ -841,34 +850,34 @@ func (p *JavdbPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL
 	// 读取响应体
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[JAVDB] 读取详情页响应失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("读取详情页响应失败: %v", err)
 		}
 		return []model.Link{}
 	}
 
-	if p.debugMode {
+	if pluginLog.DebugEnabled() {
 		bodyStr := string(body)
-		log.Printf("[JAVDB] 详情页响应体长度: %d", len(bodyStr))
+		pluginLog.Debugf("详情页响应体长度: %d", len(bodyStr))
 		// 检查页面是否包含磁力链接相关内容
 		if strings.Contains(bodyStr, "magnet:") {
 			magnetCount := strings.Count(bodyStr, "magnet:")
-			log.Printf("[JAVDB] 详情页包含 %d 个magnet字符串", magnetCount)
+			pluginLog.Debugf("详情页包含 %d 个magnet字符串", magnetCount)
 		} else {
-			log.Printf("[JAVDB] 详情页不包含magnet字符串")
+			pluginLog.Debugf("详情页不包含magnet字符串")
 		}
 		
 		// 检查是否包含预期的磁力链接容器元素
 		if strings.Contains(bodyStr, "magnets-content") {
-			log.Printf("[JAVDB] 找到magnets-content容器")
+			pluginLog.Debugf("找到magnets-content容器")
 		} else {
-			log.Printf("[JAVDB] 未找到magnets-content容器")
+			pluginLog.Debugf("未找到magnets-content容器")
 		}
 		
 		if strings.Contains(bodyStr, "magnet-links") {
-			log.Printf("[JAVDB] 找到magnet-links容器")
+			pluginLog.Debugf("找到magnet-links容器")
 		} else {
-			log.Printf("[JAVDB] 未找到magnet-links容器")
+			pluginLog.Debugf("未找到magnet-links容器")
 		}
 	}
 
@@ -880,8 +889,8 @@ func (p *JavdbPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL
 		p.detailCache.Store(detailURL, links)
 	}
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 从详情页提取到 %d 个磁力链接: %s", len(links), detailURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("从详情页提取到 %d 个磁力链接: %s", len(links), detailURL)
 	}
 
 	return links
@@ -891,33 +900,33 @@ func (p *JavdbPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL
 func (p *JavdbPlugin) parseMagnetLinks(htmlContent string) []model.Link {
 	var links []model.Link
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 开始解析磁力链接")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始解析磁力链接")
 	}
 
 	// 使用goquery解析HTML
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[JAVDB] 解析详情页HTML失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("解析详情页HTML失败: %v", err)
 		}
 		return links
 	}
 
-	if p.debugMode {
+	if pluginLog.DebugEnabled() {
 		// 检查关键容器元素
 		magnetsContentEl := doc.Find("#magnets-content")
-		log.Printf("[JAVDB] 找到#magnets-content元素数量: %d", magnetsContentEl.Length())
+		pluginLog.Debugf("找到#magnets-content元素数量: %d", magnetsContentEl.Length())
 		
 		magnetLinksEl := doc.Find("#magnets-content .magnet-links")
-		log.Printf("[JAVDB] 找到#magnets-content .magnet-links元素数量: %d", magnetLinksEl.Length())
+		pluginLog.Debugf("找到#magnets-content .magnet-links元素数量: %d", magnetLinksEl.Length())
 		
 		magnetItemsEl := doc.Find("#magnets-content .magnet-links .item")
-		log.Printf("[JAVDB] 找到#magnets-content .magnet-links .item元素数量: %d", magnetItemsEl.Length())
+		pluginLog.Debugf("找到#magnets-content .magnet-links .item元素数量: %d", magnetItemsEl.Length())
 		
 		// 如果没有找到预期元素，尝试其他可能的选择器
 		if magnetItemsEl.Length() == 0 {
-			log.Printf("[JAVDB] 尝试其他可能的磁力链接选择器...")
+			pluginLog.Debugf("尝试其他可能的磁力链接选择器...")
 			
 			altSelectors := []string{
 				".magnet-links .item",
@@ -929,7 +938,7 @@ func (p *JavdbPlugin) parseMagnetLinks(htmlContent string) []model.Link {
 			for _, selector := range altSelectors {
 				altEls := doc.Find(selector)
 				if altEls.Length() > 0 {
-					log.Printf("[JAVDB] 找到替代选择器 '%s' 的元素数量: %d", selector, altEls.Length())
+					pluginLog.Debugf("找到替代选择器 '%s' 的元素数量: %d", selector, altEls.Length())
 				}
 			}
 		}
@@ -937,48 +946,48 @@ func (p *JavdbPlugin) parseMagnetLinks(htmlContent string) []model.Link {
 
 	// 查找磁力链接区域: .magnet-links .item (因为#magnets-content本身就有magnet-links类)
 	doc.Find(".magnet-links .item").Each(func(i int, s *goquery.Selection) {
-		if p.debugMode {
-			log.Printf("[JAVDB] 开始解析第 %d 个磁力链接项", i+1)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("开始解析第 %d 个磁力链接项", i+1)
 		}
 
 		// 提取磁力链接URL - 从.magnet-name下的a标签获取href
 		magnetEl := s.Find(".magnet-name a")
-		if p.debugMode {
-			log.Printf("[JAVDB] 第 %d 个项找到.magnet-name a元素数量: %d", i+1, magnetEl.Length())
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("第 %d 个项找到.magnet-name a元素数量: %d", i+1, magnetEl.Length())
 		}
 
 		if magnetEl.Length() == 0 {
-			if p.debugMode {
-				log.Printf("[JAVDB] 第 %d 个项无.magnet-name a元素，跳过", i+1)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("第 %d 个项无.magnet-name a元素，跳过", i+1)
 			}
 			return
 		}
 
 		magnetURL, _ := magnetEl.Attr("href")
 		if magnetURL == "" {
-			if p.debugMode {
-				log.Printf("[JAVDB] 第 %d 个项磁力链接URL为空，跳过", i+1)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("第 %d 个项磁力链接URL为空，跳过", i+1)
 			}
 			return
 		}
 
 		// 验证是否为磁力链接
 		if !strings.HasPrefix(magnetURL, "magnet:") {
-			if p.debugMode {
-				log.Printf("[JAVDB] 第 %d 个项不是磁力链接: %s，跳过", i+1, magnetURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("第 %d 个项不是磁力链接: %s，跳过", i+1, magnetURL)
 			}
 			return
 		}
 
-		if p.debugMode {
-			log.Printf("[JAVDB] 第 %d 个项原始磁力URL: %s", i+1, magnetURL)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("第 %d 个项原始磁力URL: %s", i+1, magnetURL)
 		}
 
 		// 解码HTML实体
 		magnetURL = strings.ReplaceAll(magnetURL, "&amp;", "&")
 
-		if p.debugMode {
-			log.Printf("[JAVDB] 第 %d 个项解码后磁力URL: %s", i+1, magnetURL)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("第 %d 个项解码后磁力URL: %s", i+1, magnetURL)
 		}
 
 		link := model.Link{
@@ -989,19 +998,19 @@ func (p *JavdbPlugin) parseMagnetLinks(htmlContent string) []model.Link {
 
 		links = append(links, link)
 
-		if p.debugMode {
+		if pluginLog.DebugEnabled() {
 			// 提取资源名称用于调试日志
 			nameEl := s.Find(".magnet-name .name")
 			resourceName := strings.TrimSpace(nameEl.Text())
 			// 提取文件信息用于调试日志
 			metaEl := s.Find(".magnet-name .meta")
 			fileInfo := strings.TrimSpace(metaEl.Text())
-			log.Printf("[JAVDB] 成功提取第 %d 个磁力链接: %s (%s)", i+1, resourceName, fileInfo)
+			pluginLog.Debugf("成功提取第 %d 个磁力链接: %s (%s)", i+1, resourceName, fileInfo)
 		}
 	})
 
-	if p.debugMode {
-		log.Printf("[JAVDB] 磁力链接解析完成，共找到 %d 个链接", len(links))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("磁力链接解析完成，共找到 %d 个链接", len(links))
 	}
 
 	return links
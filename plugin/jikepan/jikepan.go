@@ -35,6 +35,18 @@ func NewJikepanAsyncV2Plugin() *JikepanAsyncV2Plugin {
 	}
 }
 
+// Info 返回插件元数据
+func (p *JikepanAsyncV2Plugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: "即刻盘",
+		Homepage:    "https://jikepan.xyz",
+		Category:    plugin.CategoryNetdisk,
+		Ext: []plugin.ExtParam{
+			{Key: "is_all", Type: plugin.ExtBool, Description: "全量搜索，结果更多但耗时约10秒", Default: false},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *JikepanAsyncV2Plugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -46,7 +58,7 @@ func (p *JikepanAsyncV2Plugin) Search(keyword string, ext map[string]interface{}
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *JikepanAsyncV2Plugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.doSearch, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.doSearch, ext)
 }
 
 // doSearch 实际的搜索实现
@@ -59,7 +71,7 @@ func (p *JikepanAsyncV2Plugin) doSearch(client *http.Client, keyword string, ext
 	
 	// 检查ext中是否包含自定义参数，如果有则使用它
 	if ext != nil {
-		if isAll, ok := ext["is_all"].(bool); ok && isAll {
+		if isAll, ok := plugin.GetBool(ext, "is_all"); ok && isAll {
 			// 使用全量搜索，时间大约10秒
 			reqBody["is_all"] = true
 		}
@@ -38,7 +38,7 @@ func (p *JutoushePlugin) Search(keyword string, ext map[string]interface{}) ([]m
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果（推荐方法）
 func (p *JutoushePlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实现搜索逻辑
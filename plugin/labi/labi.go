@@ -112,7 +112,7 @@ func (p *LabiAsyncPlugin) Search(keyword string, ext map[string]interface{}) ([]
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *LabiAsyncPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实现具体的搜索逻辑
@@ -4,7 +4,6 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -15,6 +14,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -28,19 +28,14 @@ const (
 // LeijingPlugin 雷鲸小站插件
 type LeijingPlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode    bool
 	detailCache  sync.Map // 缓存详情页结果
 	cacheTTL     time.Duration
 }
 
 // NewLeijingPlugin 创建新的雷鲸小站插件实例
 func NewLeijingPlugin() *LeijingPlugin {
-	// 检查调试模式
-	debugMode := false // 默认关闭调试
-	
 	p := &LeijingPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPlugin("leijing", 2),
-		debugMode:       debugMode,
 		cacheTTL:        30 * time.Minute,
 	}
 	
@@ -73,7 +68,7 @@ func (p *LeijingPlugin) Search(keyword string, ext map[string]interface{}) ([]mo
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *LeijingPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // setRequestHeaders 设置请求头
@@ -98,20 +93,20 @@ func (p *LeijingPlugin) doRequest(client *http.Client, url string, referer strin
 	
 	p.setRequestHeaders(req, referer)
 	
-	if p.debugMode {
-		log.Printf("[Leijing] 发送请求: %s", url)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("发送请求: %s", url)
 	}
 	
 	resp, err := client.Do(req)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Leijing] 请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("请求失败: %v", err)
 		}
 		return nil, err
 	}
 	
-	if p.debugMode {
-		log.Printf("[Leijing] 响应状态: %d", resp.StatusCode)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("响应状态: %d", resp.StatusCode)
 	}
 	
 	return resp, nil
@@ -119,11 +114,12 @@ func (p *LeijingPlugin) doRequest(client *http.Client, url string, referer strin
 
 // searchImpl 实际的搜索实现
 func (p *LeijingPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
 	searchURL := fmt.Sprintf("%s%s?keyword=%s", BaseURL, SearchPath, url.QueryEscape(keyword))
 	
-	if p.debugMode {
-		log.Printf("[Leijing] 开始搜索: %s", keyword)
-		log.Printf("[Leijing] 搜索URL: %s", searchURL)
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
+		log.Debugf("搜索URL: %s", searchURL)
 	}
 	
 	// 发送搜索请求
@@ -152,8 +148,8 @@ func (p *LeijingPlugin) searchImpl(client *http.Client, keyword string, ext map[
 	// 提取搜索结果
 	results := p.extractSearchResults(doc, keyword)
 	
-	if p.debugMode {
-		log.Printf("[Leijing] 找到 %d 个搜索结果", len(results))
+	if log.DebugEnabled() {
+		log.Debugf("找到 %d 个搜索结果", len(results))
 	}
 	
 	// 对于没有直接提取到链接的结果，访问详情页获取链接
@@ -162,8 +158,8 @@ func (p *LeijingPlugin) searchImpl(client *http.Client, keyword string, ext map[
 	// 过滤结果（去掉没有链接的）
 	filteredResults := p.filterValidResults(results)
 	
-	if p.debugMode {
-		log.Printf("[Leijing] 过滤后剩余 %d 个有效结果", len(filteredResults))
+	if log.DebugEnabled() {
+		log.Debugf("过滤后剩余 %d 个有效结果", len(filteredResults))
 	}
 	
 	return filteredResults, nil
@@ -175,8 +171,8 @@ func (p *LeijingPlugin) getResponseReader(resp *http.Response) (io.Reader, error
 	
 	// 检查Content-Encoding
 	contentEncoding := resp.Header.Get("Content-Encoding")
-	if p.debugMode {
-		log.Printf("[Leijing] Content-Encoding: %s", contentEncoding)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("Content-Encoding: %s", contentEncoding)
 	}
 	
 	// 如果是gzip压缩，手动解压
@@ -225,15 +221,15 @@ func (p *LeijingPlugin) extractSearchResults(doc *goquery.Document, keyword stri
 			resourceID = fmt.Sprintf("%d", time.Now().UnixNano())
 		}
 		
-		if p.debugMode {
-			log.Printf("[Leijing] 提取结果 %d: %s, URL: %s", i+1, title, detailURL)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("提取结果 %d: %s, URL: %s", i+1, title, detailURL)
 		}
 		
 		// 尝试从摘要中提取天翼云盘链接
 		links := p.extractTianyiLinks(summary)
 		
-		if p.debugMode {
-			log.Printf("[Leijing] 从摘要中提取到 %d 个链接", len(links))
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("从摘要中提取到 %d 个链接", len(links))
 		}
 		
 		// 解析时间
@@ -295,8 +291,8 @@ func (p *LeijingPlugin) extractTianyiLinks(text string) []model.Link {
 
 // enrichWithDetailLinks 并发获取详情页的下载链接
 func (p *LeijingPlugin) enrichWithDetailLinks(client *http.Client, results []model.SearchResult, keyword string) []model.SearchResult {
-	if p.debugMode {
-		log.Printf("[Leijing] 开始获取详情页链接")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始获取详情页链接")
 	}
 	
 	var wg sync.WaitGroup
@@ -334,8 +330,8 @@ func (p *LeijingPlugin) enrichWithDetailLinks(client *http.Client, results []mod
 			results[idx].Tags = nil
 			mu.Unlock()
 			
-			if p.debugMode {
-				log.Printf("[Leijing] 详情页 %d/%d 获取到 %d 个链接", idx+1, len(results), len(links))
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("详情页 %d/%d 获取到 %d 个链接", idx+1, len(results), len(links))
 			}
 		}(i)
 	}
@@ -350,8 +346,8 @@ func (p *LeijingPlugin) fetchDetailPageLinks(client *http.Client, detailURL stri
 	// 检查缓存
 	if cached, ok := p.detailCache.Load(detailURL); ok {
 		if links, ok := cached.([]model.Link); ok {
-			if p.debugMode {
-				log.Printf("[Leijing] 使用缓存的详情页结果: %s", detailURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("使用缓存的详情页结果: %s", detailURL)
 			}
 			return links
 		}
@@ -360,16 +356,16 @@ func (p *LeijingPlugin) fetchDetailPageLinks(client *http.Client, detailURL stri
 	// 访问详情页
 	resp, err := p.doRequest(client, detailURL, BaseURL)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Leijing] 获取详情页失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("获取详情页失败: %v", err)
 		}
 		return nil
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
-		if p.debugMode {
-			log.Printf("[Leijing] 详情页响应状态码异常: %d", resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页响应状态码异常: %d", resp.StatusCode)
 		}
 		return nil
 	}
@@ -383,8 +379,8 @@ func (p *LeijingPlugin) fetchDetailPageLinks(client *http.Client, detailURL stri
 	// 解析HTML
 	doc, err := goquery.NewDocumentFromReader(reader)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Leijing] 解析详情页HTML失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("解析详情页HTML失败: %v", err)
 		}
 		return nil
 	}
@@ -429,8 +425,8 @@ func (p *LeijingPlugin) extractDetailPageLinks(doc *goquery.Document) []model.Li
 			Type: "tianyi",
 		})
 		
-		if p.debugMode {
-			log.Printf("[Leijing] 提取到天翼云盘链接: %s", href)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("提取到天翼云盘链接: %s", href)
 		}
 	})
 	
@@ -450,14 +446,17 @@ func (p *LeijingPlugin) filterValidResults(results []model.SearchResult) []model
 	for _, result := range results {
 		if len(result.Links) > 0 {
 			validResults = append(validResults, result)
-		} else if p.debugMode {
-			log.Printf("[Leijing] 忽略无链接结果: %s", result.Title)
+		} else if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("忽略无链接结果: %s", result.Title)
 		}
 	}
 	
 	return validResults
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=leijing=debug 开启
+var pluginLog = logger.ForPlugin("leijing")
+
 func init() {
 	plugin.RegisterGlobalPlugin(NewLeijingPlugin())
 }
\ No newline at end of file
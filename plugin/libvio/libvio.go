@@ -4,7 +4,6 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -16,6 +15,7 @@ import (
 	"pansou/model"
 	"pansou/plugin"
 	"pansou/util/json"
+	"pansou/util/logger"
 )
 
 const (
@@ -29,7 +29,6 @@ const (
 // LibvioPlugin LIBVIO插件
 type LibvioPlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode    bool
 	detailCache  sync.Map // 缓存详情页结果
 	playCache    sync.Map // 缓存播放页结果
 	cacheTTL     time.Duration
@@ -37,12 +36,8 @@ type LibvioPlugin struct {
 
 // NewLibvioPlugin 创建新的LIBVIO插件实例
 func NewLibvioPlugin() *LibvioPlugin {
-	// 检查调试模式
-	debugMode := false // 开启调试模式
-	
 	p := &LibvioPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPluginWithFilter("libvio", 1, true ),	
-		debugMode:       debugMode,
 		cacheTTL:        30 * time.Minute,
 	}
 	
@@ -75,7 +70,7 @@ func (p *LibvioPlugin) Search(keyword string, ext map[string]interface{}) ([]mod
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *LibvioPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // setRequestHeaders 设置请求头
@@ -100,20 +95,20 @@ func (p *LibvioPlugin) doRequest(client *http.Client, url string, referer string
 	
 	p.setRequestHeaders(req, referer)
 	
-	if p.debugMode {
-		log.Printf("[Libvio] 发送请求: %s", url)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("发送请求: %s", url)
 	}
 	
 	resp, err := client.Do(req)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Libvio] 请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("请求失败: %v", err)
 		}
 		return nil, err
 	}
 	
-	if p.debugMode {
-		log.Printf("[Libvio] 响应状态: %d", resp.StatusCode)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("响应状态: %d", resp.StatusCode)
 	}
 	
 	return resp, nil
@@ -121,11 +116,12 @@ func (p *LibvioPlugin) doRequest(client *http.Client, url string, referer string
 
 // searchImpl 实际的搜索实现
 func (p *LibvioPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
 	searchURL := fmt.Sprintf("%s%s?wd=%s&submit=", BaseURL, SearchPath, url.QueryEscape(keyword))
 	
-	if p.debugMode {
-		log.Printf("[Libvio] 开始搜索: %s", keyword)
-		log.Printf("[Libvio] 搜索URL: %s", searchURL)
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
+		log.Debugf("搜索URL: %s", searchURL)
 	}
 	
 	// 发送搜索请求
@@ -154,28 +150,28 @@ func (p *LibvioPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 	// 提取搜索结果
 	results := p.extractSearchResults(doc, keyword)
 	
-	if p.debugMode {
-		log.Printf("[Libvio] 找到 %d 个搜索结果", len(results))
+	if log.DebugEnabled() {
+		log.Debugf("找到 %d 个搜索结果", len(results))
 	}
 	
 	// 并发获取详情页的下载链接
 	results = p.enrichWithDetailLinks(client, results, keyword)
 	
-	if p.debugMode {
+	if log.DebugEnabled() {
 		// 统计链接数量
 		totalLinks := 0
 		for i, r := range results {
-			log.Printf("[Libvio] 结果 %d: %s, 链接数: %d", i+1, r.Title, len(r.Links))
+			log.Debugf("结果 %d: %s, 链接数: %d", i+1, r.Title, len(r.Links))
 			totalLinks += len(r.Links)
 		}
-		log.Printf("[Libvio] 总计: %d 个结果，%d 个链接", len(results), totalLinks)
+		log.Debugf("总计: %d 个结果，%d 个链接", len(results), totalLinks)
 	}
 	
 	// 过滤结果
 	filteredResults := plugin.FilterResultsByKeyword(results, keyword)
 	
-	if p.debugMode {
-		log.Printf("[Libvio] 过滤后剩余 %d 个结果", len(filteredResults))
+	if log.DebugEnabled() {
+		log.Debugf("过滤后剩余 %d 个结果", len(filteredResults))
 	}
 	
 	return filteredResults, nil
@@ -187,8 +183,8 @@ func (p *LibvioPlugin) getResponseReader(resp *http.Response) (io.Reader, error)
 	
 	// 检查Content-Encoding
 	contentEncoding := resp.Header.Get("Content-Encoding")
-	if p.debugMode {
-		log.Printf("[Libvio] Content-Encoding: %s", contentEncoding)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("Content-Encoding: %s", contentEncoding)
 	}
 	
 	// 如果是gzip压缩，手动解压
@@ -244,8 +240,8 @@ func (p *LibvioPlugin) extractSearchResults(doc *goquery.Document, keyword strin
 			resourceID = fmt.Sprintf("%d", time.Now().UnixNano())
 		}
 		
-		if p.debugMode {
-			log.Printf("[Libvio] 提取结果 %d: %s, URL: %s", i+1, title, detailURL)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("提取结果 %d: %s, URL: %s", i+1, title, detailURL)
 		}
 		
 		// 构建内容描述
@@ -285,8 +281,8 @@ func (p *LibvioPlugin) enrichWithDetailLinks(client *http.Client, results []mode
 		return results
 	}
 	
-	if p.debugMode {
-		log.Printf("[Libvio] 开始获取 %d 个详情页的下载链接", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始获取 %d 个详情页的下载链接", len(results))
 	}
 	
 	var wg sync.WaitGroup
@@ -314,8 +310,8 @@ func (p *LibvioPlugin) enrichWithDetailLinks(client *http.Client, results []mode
 				results[idx].Tags = nil
 				mu.Unlock()
 				
-				if p.debugMode {
-					log.Printf("[Libvio] 详情页 %d/%d 获取到 %d 个链接", idx+1, len(results), len(links))
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("详情页 %d/%d 获取到 %d 个链接", idx+1, len(results), len(links))
 				}
 			}
 		}(i)
@@ -328,15 +324,15 @@ func (p *LibvioPlugin) enrichWithDetailLinks(client *http.Client, results []mode
 
 // fetchDetailPageLinks 获取详情页的下载链接
 func (p *LibvioPlugin) fetchDetailPageLinks(client *http.Client, detailURL string, keyword string) []model.Link {
-	if p.debugMode {
-		log.Printf("[Libvio] 开始获取详情页: %s", detailURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始获取详情页: %s", detailURL)
 	}
 	
 	// 检查缓存
 	if cached, ok := p.detailCache.Load(detailURL); ok {
 		if links, ok := cached.([]model.Link); ok {
-			if p.debugMode {
-				log.Printf("[Libvio] 使用缓存的详情页结果: %s, 链接数: %d", detailURL, len(links))
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("使用缓存的详情页结果: %s, 链接数: %d", detailURL, len(links))
 			}
 			return links
 		}
@@ -345,16 +341,16 @@ func (p *LibvioPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 	// 访问详情页
 	resp, err := p.doRequest(client, detailURL, BaseURL)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Libvio] 获取详情页失败: %s, 错误: %v", detailURL, err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("获取详情页失败: %s, 错误: %v", detailURL, err)
 		}
 		return nil
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
-		if p.debugMode {
-			log.Printf("[Libvio] 详情页响应状态码异常: %s, 状态码: %d", detailURL, resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页响应状态码异常: %s, 状态码: %d", detailURL, resp.StatusCode)
 		}
 		return nil
 	}
@@ -368,8 +364,8 @@ func (p *LibvioPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 	// 解析HTML
 	doc, err := goquery.NewDocumentFromReader(reader)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Libvio] 解析详情页HTML失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("解析详情页HTML失败: %v", err)
 		}
 		return nil
 	}
@@ -377,13 +373,13 @@ func (p *LibvioPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 	// 提取下载播放页链接（只提取包含"下载"的）
 	playLinks := p.extractDownloadPlayLinks(doc)
 	
-	if p.debugMode {
-		log.Printf("[Libvio] 找到 %d 个下载播放页链接", len(playLinks))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("找到 %d 个下载播放页链接", len(playLinks))
 	}
 	
 	if len(playLinks) == 0 {
-		if p.debugMode {
-			log.Printf("[Libvio] 未找到下载链接")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("未找到下载链接")
 		}
 		return nil
 	}
@@ -391,19 +387,19 @@ func (p *LibvioPlugin) fetchDetailPageLinks(client *http.Client, detailURL strin
 	// 获取网盘链接
 	var links []model.Link
 	for _, playLink := range playLinks {
-		if p.debugMode {
-			log.Printf("[Libvio] 获取网盘链接: %s", playLink.URL)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("获取网盘链接: %s", playLink.URL)
 		}
 		panLink := p.fetchPanLink(client, playLink.URL, detailURL)
 		if panLink != nil {
 			links = append(links, *panLink)
-		} else if p.debugMode {
-			log.Printf("[Libvio] 未能获取网盘链接: %s", playLink.URL)
+		} else if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("未能获取网盘链接: %s", playLink.URL)
 		}
 	}
 	
-	if p.debugMode {
-		log.Printf("[Libvio] 详情页 %s 最终获取到 %d 个网盘链接", detailURL, len(links))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("详情页 %s 最终获取到 %d 个网盘链接", detailURL, len(links))
 	}
 	
 	// 缓存结果
@@ -430,22 +426,22 @@ func (p *LibvioPlugin) extractDownloadPlayLinks(doc *goquery.Document) []PlayLin
 	
 	// 查找所有播放源
 	allHeads := doc.Find(".stui-vodlist__head")
-	if p.debugMode {
-		log.Printf("[Libvio] 找到 %d 个播放源头部", allHeads.Length())
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("找到 %d 个播放源头部", allHeads.Length())
 	}
 	
 	allHeads.Each(func(i int, s *goquery.Selection) {
 		// 获取标题
 		title := strings.TrimSpace(s.Find("h3").Text())
 		
-		if p.debugMode {
-			log.Printf("[Libvio] 播放源 %d 标题: %s", i+1, title)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("播放源 %d 标题: %s", i+1, title)
 		}
 		
 		// 只处理包含"下载"的源
 		if !strings.Contains(title, "下载") {
-			if p.debugMode {
-				log.Printf("[Libvio] 跳过非下载源: %s", title)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("跳过非下载源: %s", title)
 			}
 			return
 		}
@@ -462,8 +458,8 @@ func (p *LibvioPlugin) extractDownloadPlayLinks(doc *goquery.Document) []PlayLin
 		
 		// 提取播放页链接
 		playlistLinks := s.Find(".stui-content__playlist li a")
-		if p.debugMode {
-			log.Printf("[Libvio] 播放列表中有 %d 个链接", playlistLinks.Length())
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("播放列表中有 %d 个链接", playlistLinks.Length())
 		}
 		
 		// 通常只取第一个链接（合集）
@@ -479,9 +475,9 @@ func (p *LibvioPlugin) extractDownloadPlayLinks(doc *goquery.Document) []PlayLin
 					PanType: panType,
 				})
 				
-				if p.debugMode {
+				if pluginLog.DebugEnabled() {
 					linkText := strings.TrimSpace(firstLink.Text())
-					log.Printf("[Libvio] 找到下载链接: %s (%s) [%s]", playURL, panType, linkText)
+					pluginLog.Debugf("找到下载链接: %s (%s) [%s]", playURL, panType, linkText)
 				}
 			}
 		}
@@ -495,8 +491,8 @@ func (p *LibvioPlugin) fetchPanLink(client *http.Client, playURL string, referer
 	// 检查缓存
 	if cached, ok := p.playCache.Load(playURL); ok {
 		if link, ok := cached.(*model.Link); ok {
-			if p.debugMode {
-				log.Printf("[Libvio] 使用缓存的播放页结果: %s", playURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("使用缓存的播放页结果: %s", playURL)
 			}
 			return link
 		}
@@ -505,16 +501,16 @@ func (p *LibvioPlugin) fetchPanLink(client *http.Client, playURL string, referer
 	// 访问播放页
 	resp, err := p.doRequest(client, playURL, referer)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Libvio] 获取播放页失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("获取播放页失败: %v", err)
 		}
 		return nil
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
-		if p.debugMode {
-			log.Printf("[Libvio] 播放页响应状态码异常: %d", resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("播放页响应状态码异常: %d", resp.StatusCode)
 		}
 		return nil
 	}
@@ -536,14 +532,14 @@ func (p *LibvioPlugin) fetchPanLink(client *http.Client, playURL string, referer
 	matches := playerDataRegex.FindStringSubmatch(string(body))
 	
 	if len(matches) < 2 {
-		if p.debugMode {
-			log.Printf("[Libvio] 未找到player_aaaa对象")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("未找到player_aaaa对象")
 			// 输出部分body内容用于调试
 			bodyStr := string(body)
 			if len(bodyStr) > 500 {
-				log.Printf("[Libvio] 页面内容前500字符: %s", bodyStr[:500])
+				pluginLog.Debugf("页面内容前500字符: %s", bodyStr[:500])
 			} else {
-				log.Printf("[Libvio] 页面内容: %s", bodyStr)
+				pluginLog.Debugf("页面内容: %s", bodyStr)
 			}
 		}
 		return nil
@@ -551,8 +547,8 @@ func (p *LibvioPlugin) fetchPanLink(client *http.Client, playURL string, referer
 	
 	// 解析JSON
 	playerJSON := matches[1]
-	if p.debugMode {
-		log.Printf("[Libvio] 找到player_aaaa: %s", playerJSON)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("找到player_aaaa: %s", playerJSON)
 	}
 	
 	// 处理转义字符
@@ -560,8 +556,8 @@ func (p *LibvioPlugin) fetchPanLink(client *http.Client, playURL string, referer
 	
 	var playerData map[string]interface{}
 	if err := json.Unmarshal([]byte(playerJSON), &playerData); err != nil {
-		if p.debugMode {
-			log.Printf("[Libvio] 解析player_aaaa失败: %v, JSON: %s", err, playerJSON)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("解析player_aaaa失败: %v, JSON: %s", err, playerJSON)
 		}
 		return nil
 	}
@@ -569,8 +565,8 @@ func (p *LibvioPlugin) fetchPanLink(client *http.Client, playURL string, referer
 	// 提取URL
 	panURL, ok := playerData["url"].(string)
 	if !ok || panURL == "" {
-		if p.debugMode {
-			log.Printf("[Libvio] player_aaaa中没有url字段")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("player_aaaa中没有url字段")
 		}
 		return nil
 	}
@@ -584,8 +580,8 @@ func (p *LibvioPlugin) fetchPanLink(client *http.Client, playURL string, referer
 		Type: linkType,
 	}
 	
-	if p.debugMode {
-		log.Printf("[Libvio] 提取到网盘链接: %s (from=%s, type=%s)", panURL, from, linkType)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("提取到网盘链接: %s (from=%s, type=%s)", panURL, from, linkType)
 	}
 	
 	// 缓存结果
@@ -644,6 +640,9 @@ func (p *LibvioPlugin) mapPanType(from string, url string) string {
 	return "others"
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=libvio=debug 开启
+var pluginLog = logger.ForPlugin("libvio")
+
 func init() {
 	plugin.RegisterGlobalPlugin(NewLibvioPlugin())
 }
\ No newline at end of file
@@ -0,0 +1,207 @@
+package maccms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pansou/model"
+	"pansou/plugin"
+	"pansou/plugin/sdk"
+)
+
+const (
+	// DefaultTimeout 单个API请求的超时时间
+	DefaultTimeout = 8 * time.Second
+
+	// maxRetries 单个API请求失败后的重试次数，JSON接口只做一次快速重试
+	maxRetries = 1
+
+	// retryBackoff 重试前的等待时间
+	retryBackoff = 100 * time.Millisecond
+
+	// maxPages 最多请求的页数
+	maxPages = 10
+)
+
+// Config MacCMS站点配置
+type Config struct {
+	Name        string // 插件名称
+	DisplayName string // 插件显示名称，为空时使用插件名称
+	Priority    int    // 插件等级1-4
+
+	// APIURLs 采集接口地址（到api.php/provide/vod为止），多个地址时按顺序主备切换
+	APIURLs []string
+
+	// CloudTypes 站点特有的vod_down_from来源标识到网盘类型的映射，在默认映射基础上追加或覆盖，标识不区分大小写
+	CloudTypes map[string]string
+
+	// Pages 最多请求的页数（pg参数），默认1，最多10；达到接口返回的总页数时停止
+	Pages int
+
+	// Categories 只保留这些分类的结果，按type_id或type_name匹配，为空时不过滤
+	Categories []string
+	// ExcludeCategories 排除这些分类的结果，按type_id或type_name匹配
+	ExcludeCategories []string
+}
+
+// Plugin 基于MacCMS采集接口（api.php/provide/vod）的异步插件
+type Plugin struct {
+	*plugin.BaseAsyncPlugin
+	config     Config
+	cloudTypes map[string]string
+}
+
+// NewPlugin 根据站点配置创建MacCMS插件
+func NewPlugin(config Config) *Plugin {
+	if config.Pages <= 0 {
+		config.Pages = 1
+	}
+	if config.Pages > maxPages {
+		config.Pages = maxPages
+	}
+
+	cloudTypes := make(map[string]string, len(defaultCloudTypes)+len(config.CloudTypes))
+	for from, linkType := range defaultCloudTypes {
+		cloudTypes[from] = linkType
+	}
+	for from, linkType := range config.CloudTypes {
+		cloudTypes[strings.ToUpper(from)] = linkType
+	}
+
+	return &Plugin{
+		BaseAsyncPlugin: plugin.NewBaseAsyncPlugin(config.Name, config.Priority),
+		config:          config,
+		cloudTypes:      cloudTypes,
+	}
+}
+
+// Info 返回插件元数据，主页为第一个采集接口的站点地址，网盘类型为来源标识映射中的全部类型
+func (p *Plugin) Info() plugin.PluginMetadata {
+	info := plugin.PluginMetadata{
+		DisplayName: p.config.DisplayName,
+		Category:    plugin.CategoryVideo,
+	}
+	if len(p.config.APIURLs) > 0 {
+		if u, err := url.Parse(p.config.APIURLs[0]); err == nil && u.Host != "" {
+			info.Homepage = u.Scheme + "://" + u.Host
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, linkType := range p.cloudTypes {
+		if !seen[linkType] {
+			seen[linkType] = true
+			info.CloudTypes = append(info.CloudTypes, linkType)
+		}
+	}
+	sort.Strings(info.CloudTypes)
+	return info
+}
+
+// Search 同步搜索接口
+func (p *Plugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	result, err := p.SearchWithResult(keyword, ext)
+	if err != nil {
+		return nil, err
+	}
+	return result.Results, nil
+}
+
+// SearchWithResult 带结果统计的搜索接口
+func (p *Plugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
+}
+
+// searchImpl 搜索实现：第一页按顺序尝试各个接口地址，后续页使用成功的地址并发请求；
+// 使用BaseAsyncPlugin传入的客户端，请求随搜索调用取消，转入后台后继续完成
+func (p *Plugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	ctx := plugin.SearchContextFromExt(ext)
+	if len(p.config.APIURLs) == 0 {
+		return nil, fmt.Errorf("[%s] 未配置接口地址", p.Name())
+	}
+
+	var apiURL string
+	var first *APIResponse
+	var lastErr error
+	for _, candidate := range p.config.APIURLs {
+		first, lastErr = p.fetchPage(ctx, client, candidate, keyword, 1)
+		if lastErr == nil {
+			apiURL = candidate
+			break
+		}
+	}
+	if first == nil {
+		return nil, lastErr
+	}
+
+	pages := []*APIResponse{first}
+	if pageCount := min(p.config.Pages, int(first.PageCount)); pageCount > 1 {
+		rest := make([]*APIResponse, pageCount-1)
+		var wg sync.WaitGroup
+		for pg := 2; pg <= pageCount; pg++ {
+			wg.Add(1)
+			go func(pg int) {
+				defer wg.Done()
+				// 后续页失败时只丢弃该页
+				if resp, err := p.fetchPage(ctx, client, apiURL, keyword, pg); err == nil {
+					rest[pg-2] = resp
+				}
+			}(pg)
+		}
+		wg.Wait()
+		pages = append(pages, rest...)
+	}
+
+	var results []model.SearchResult
+	seen := make(map[string]bool)
+	for _, resp := range pages {
+		if resp == nil {
+			continue
+		}
+		for _, result := range p.parseItems(resp.List) {
+			if !seen[result.UniqueID] {
+				seen[result.UniqueID] = true
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
+
+// fetchPage 请求并解析一页搜索结果
+func (p *Plugin) fetchPage(ctx context.Context, client *http.Client, apiURL string, keyword string, pg int) (*APIResponse, error) {
+	searchURL := fmt.Sprintf("%s?ac=detail&wd=%s", apiURL, url.QueryEscape(keyword))
+	if pg > 1 {
+		searchURL += "&pg=" + strconv.Itoa(pg)
+	}
+
+	req := sdk.Get(searchURL).
+		Context(ctx).
+		Header("Accept", "application/json, text/plain, */*").
+		Header("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8").
+		Header("Cache-Control", "no-cache").
+		Timeout(DefaultTimeout).
+		Retries(maxRetries).
+		Backoff(retryBackoff, retryBackoff)
+	if u, err := url.Parse(apiURL); err == nil && u.Host != "" {
+		req.Header("Referer", u.Scheme+"://"+u.Host+"/")
+	}
+
+	body, err := req.Bytes(client)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %w", p.Name(), err)
+	}
+
+	apiResponse, err := ParseResponse(body)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %w", p.Name(), err)
+	}
+	return apiResponse, nil
+}
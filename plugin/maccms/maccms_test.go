@@ -0,0 +1,229 @@
+package maccms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"pansou/model"
+	"pansou/plugin"
+)
+
+// vodServer 按pg参数返回testdata中录制的采集接口响应，记录请求的页
+type vodServer struct {
+	*httptest.Server
+	mutex sync.Mutex
+	pages []string
+}
+
+func newVodServer(t *testing.T) *vodServer {
+	t.Helper()
+	s := &vodServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api.php/provide/vod" || r.URL.Query().Get("ac") != "detail" || r.URL.Query().Get("wd") == "" {
+			http.NotFound(w, r)
+			return
+		}
+		pg := r.URL.Query().Get("pg")
+		if pg == "" {
+			pg = "1"
+		}
+		s.mutex.Lock()
+		s.pages = append(s.pages, pg)
+		s.mutex.Unlock()
+
+		data, err := os.ReadFile(filepath.Join("testdata", "page"+pg+".json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// requestedPages 返回按页码排序的请求记录
+func (s *vodServer) requestedPages() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pages := append([]string(nil), s.pages...)
+	sort.Strings(pages)
+	return pages
+}
+
+// describeLinks 将结果转换为 唯一ID -> "类型 地址 提取码" 列表，便于比较
+func describeLinks(results []model.SearchResult) map[string][]string {
+	described := make(map[string][]string, len(results))
+	for _, result := range results {
+		links := make([]string, 0, len(result.Links))
+		for _, link := range result.Links {
+			links = append(links, link.Type+" "+link.URL+" "+link.Password)
+		}
+		described[result.UniqueID] = links
+	}
+	return described
+}
+
+// 各条录制数据解析出的链接
+var (
+	links101 = []string{
+		"quark https://pan.quark.cn/s/kg101a ",
+		"quark https://pan.quark.cn/s/kg101b ",
+		"baidu https://pan.baidu.com/s/1bd101?pwd=x1y2 x1y2",
+	}
+	links102 = []string{
+		"aliyun https://www.alipan.com/s/aly102 ",
+		"115 https://115cdn.com/s/sw102?password=p115 p115",
+	}
+	links103 = []string{"quark https://pan.quark-mirror.example/s/qk103 "}
+	links201 = []string{"uc https://drive.uc.cn/s/uc201 "}
+	links301 = []string{"magnet magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567 "}
+)
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		wantPages []string
+		want      map[string][]string
+	}{
+		{
+			name:      "first_page",
+			config:    Config{},
+			wantPages: []string{"1"},
+			want:      map[string][]string{"test-101": links101, "test-102": links102, "test-103": links103},
+		},
+		{
+			// 请求的页数不超过接口返回的总页数，重复的数据项只保留一次
+			name:      "pagination",
+			config:    Config{Pages: 5},
+			wantPages: []string{"1", "2", "3"},
+			want: map[string][]string{
+				"test-101": links101, "test-102": links102, "test-103": links103,
+				"test-201": links201, "test-301": links301,
+			},
+		},
+		{
+			name:      "pages_limit",
+			config:    Config{Pages: 2},
+			wantPages: []string{"1", "2"},
+			want: map[string][]string{
+				"test-101": links101, "test-102": links102, "test-103": links103, "test-201": links201,
+			},
+		},
+		{
+			// 分类按type_name或type_id匹配
+			name:      "include_categories",
+			config:    Config{Pages: 3, Categories: []string{"电影", "25"}},
+			wantPages: []string{"1", "2", "3"},
+			want: map[string][]string{
+				"test-101": links101, "test-103": links103, "test-201": links201, "test-301": links301,
+			},
+		},
+		{
+			name:      "exclude_categories",
+			config:    Config{Pages: 3, ExcludeCategories: []string{"20", "综艺"}},
+			wantPages: []string{"1", "2", "3"},
+			want:      map[string][]string{"test-101": links101, "test-103": links103, "test-301": links301},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newVodServer(t)
+			config := tc.config
+			config.Name = "test"
+			config.APIURLs = []string{srv.URL + "/api.php/provide/vod"}
+			// 站点特有的来源标识，地址无法识别时按来源标识判断网盘类型
+			config.CloudTypes = map[string]string{"kuake": "quark"}
+			p := NewPlugin(config)
+
+			results, err := p.searchImpl(srv.Client(), "流浪地球", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := srv.requestedPages(); !reflect.DeepEqual(got, tc.wantPages) {
+				t.Errorf("请求的页 = %v，期望%v", got, tc.wantPages)
+			}
+			if got := describeLinks(results); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("搜索结果 = %v\n期望%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseResults(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "page1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := NewPlugin(Config{Name: "test"}).ParseResults(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("解析出%d条结果，期望2条（未配置KUAKE映射的103和没有链接的104被丢弃）", len(results))
+	}
+
+	first := results[0]
+	if first.Title != "流浪地球2" || first.Content != "主演: 吴京,刘德华 | 导演: 郭帆 | 地区: 大陆 | 语言: 国语 | 年份: 2023 | 状态: HD" {
+		t.Errorf("标题和描述 = %q %q", first.Title, first.Content)
+	}
+	if !first.Datetime.Equal(time.Date(2023, 4, 1, 12, 0, 0, 0, time.Local)) {
+		t.Errorf("时间 = %v", first.Datetime)
+	}
+	if !reflect.DeepEqual(first.Images, []string{"https://img.example.com/101.jpg"}) || len(results[1].Images) != 0 {
+		t.Errorf("图片 = %v %v", first.Images, results[1].Images)
+	}
+
+	errorData, err := os.ReadFile(filepath.Join("testdata", "error.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPlugin(Config{Name: "test"}).ParseResults(errorData); err == nil {
+		t.Error("code不为1时应返回错误")
+	}
+}
+
+// TestSearchFailover 第一个接口地址失败时使用下一个地址
+func TestSearchFailover(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+	srv := newVodServer(t)
+
+	p := NewPlugin(Config{Name: "test", APIURLs: []string{broken.URL + "/api.php/provide/vod", srv.URL + "/api.php/provide/vod"}})
+	results, err := p.searchImpl(srv.Client(), "流浪地球", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Errorf("备用地址返回%d条结果，期望2条", len(results))
+	}
+}
+
+// TestSearchHonorsCallContext 搜索调用取消后不再请求接口
+func TestSearchHonorsCallContext(t *testing.T) {
+	srv := newVodServer(t)
+	p := NewPlugin(Config{Name: "test", APIURLs: []string{srv.URL + "/api.php/provide/vod"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	call := plugin.NewSearchCall(ctx, "流浪地球", "", nil)
+	if _, err := p.searchImpl(srv.Client(), "流浪地球", call.ExtParams()); err == nil {
+		t.Fatal("调用已取消时应返回错误")
+	}
+	if pages := srv.requestedPages(); len(pages) != 0 {
+		t.Errorf("调用已取消后仍请求了接口: %v", pages)
+	}
+}
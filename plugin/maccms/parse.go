@@ -0,0 +1,283 @@
+package maccms
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"pansou/model"
+	"pansou/util/json"
+)
+
+// 分隔符：$$$分隔多个下载组，#分隔组内的多条链接，$分隔每条链接的名称和地址
+const (
+	groupSeparator   = "$$$"
+	episodeSeparator = "#"
+	nameSeparator    = "$"
+)
+
+// defaultCloudTypes 常见的vod_down_from来源标识到网盘类型的映射（标识统一为大写），站点特有的标识在Config.CloudTypes中配置
+var defaultCloudTypes = map[string]string{
+	"BD":  "baidu",
+	"KG":  "quark",
+	"UC":  "uc",
+	"ALY": "aliyun",
+	"XL":  "xunlei",
+	"TY":  "tianyi",
+	"115": "115",
+	"MB":  "mobile",
+	"123": "123",
+	"PK":  "pikpak",
+	"WY":  "weiyun",
+	"LZ":  "lanzou",
+	"JGY": "jianguoyun",
+}
+
+// 预编译的正则表达式
+var (
+	// 密码提取正则表达式，百度等使用pwd参数，115使用password参数
+	passwordRegex = regexp.MustCompile(`[?&](?:pwd|password)=([0-9a-zA-Z]+)`)
+
+	// 按顺序匹配的网盘链接类型
+	linkTypePatterns = []struct {
+		linkType string
+		pattern  *regexp.Regexp
+	}{
+		{"quark", regexp.MustCompile(`https?://pan\.quark\.cn/s/[0-9a-zA-Z]+`)},
+		{"uc", regexp.MustCompile(`https?://drive\.uc\.cn/s/[0-9a-zA-Z]+`)},
+		{"baidu", regexp.MustCompile(`https?://pan\.baidu\.com/s/[0-9a-zA-Z_\-]+`)},
+		{"aliyun", regexp.MustCompile(`https?://(www\.)?(aliyundrive\.com|alipan\.com)/s/[0-9a-zA-Z]+`)},
+		{"xunlei", regexp.MustCompile(`https?://pan\.xunlei\.com/s/[0-9a-zA-Z_\-]+`)},
+		{"tianyi", regexp.MustCompile(`https?://cloud\.189\.cn/t/[0-9a-zA-Z]+`)},
+		{"115", regexp.MustCompile(`https?://(115\.com|115cdn\.com)/s/[0-9a-zA-Z]+`)},
+		{"mobile", regexp.MustCompile(`https?://(caiyun\.feixin\.10086\.cn|caiyun\.139\.com)/[0-9a-zA-Z]+`)},
+		{"weiyun", regexp.MustCompile(`https?://share\.weiyun\.com/[0-9a-zA-Z]+`)},
+		{"lanzou", regexp.MustCompile(`https?://(www\.)?(lanzou[uixys]*|lan[zs]o[ux])\.(com|net|org)/[0-9a-zA-Z]+`)},
+		{"jianguoyun", regexp.MustCompile(`https?://(www\.)?jianguoyun\.com/p/[0-9a-zA-Z]+`)},
+		{"123", regexp.MustCompile(`https?://(www\.)?(123pan\.com|123pan\.cn|123912\.com|123865\.com|123684\.com|123685\.com|123592\.com)/s/[0-9a-zA-Z_\-]+`)},
+		{"pikpak", regexp.MustCompile(`https?://mypikpak\.com/s/[0-9a-zA-Z]+`)},
+		{"magnet", regexp.MustCompile(`magnet:\?xt=urn:btih:[0-9a-zA-Z]{32,40}`)},
+		{"ed2k", regexp.MustCompile(`ed2k://\|file\|.+\|\d+\|[0-9a-fA-F]{32}\|/`)},
+	}
+)
+
+// APIResponse 采集接口响应
+type APIResponse struct {
+	Code      int       `json:"code"`
+	Msg       string    `json:"msg"`
+	Page      flexInt   `json:"page"`
+	PageCount flexInt   `json:"pagecount"`
+	Limit     flexInt   `json:"limit"` // 部分站点返回字符串
+	Total     flexInt   `json:"total"`
+	List      []APIItem `json:"list"`
+}
+
+// APIItem 采集接口数据项
+type APIItem struct {
+	VodID       flexInt `json:"vod_id"`
+	VodName     string  `json:"vod_name"`
+	TypeID      flexInt `json:"type_id"`
+	TypeName    string  `json:"type_name"`
+	VodPic      string  `json:"vod_pic"`
+	VodActor    string  `json:"vod_actor"`
+	VodDirector string  `json:"vod_director"`
+	VodArea     string  `json:"vod_area"`
+	VodLang     string  `json:"vod_lang"`
+	VodYear     string  `json:"vod_year"`
+	VodRemarks  string  `json:"vod_remarks"`
+	VodTime     string  `json:"vod_time"`
+	VodDownFrom string  `json:"vod_down_from"`
+	VodDownURL  string  `json:"vod_down_url"`
+}
+
+// flexInt 兼容数字和数字字符串的整数
+type flexInt int
+
+// UnmarshalJSON 解析数字或数字字符串，无法解析时为0
+func (n *flexInt) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(bytes.TrimSpace(data), `"`)
+	value, err := strconv.Atoi(string(data))
+	if err != nil {
+		*n = 0
+		return nil
+	}
+	*n = flexInt(value)
+	return nil
+}
+
+// ParseResponse 解析采集接口的响应，code不为1时返回错误
+func ParseResponse(body []byte) (*APIResponse, error) {
+	var apiResponse APIResponse
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return nil, fmt.Errorf("解析JSON响应失败: %w", err)
+	}
+	if apiResponse.Code != 1 {
+		return nil, fmt.Errorf("API返回错误: %s", apiResponse.Msg)
+	}
+	return &apiResponse, nil
+}
+
+// ParseResults 解析采集接口的响应并转换为搜索结果，用于测试已录制的接口响应
+func (p *Plugin) ParseResults(body []byte) ([]model.SearchResult, error) {
+	apiResponse, err := ParseResponse(body)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseItems(apiResponse.List), nil
+}
+
+// parseItems 按分类过滤数据项并转换为搜索结果，只保留有网盘链接的结果
+func (p *Plugin) parseItems(items []APIItem) []model.SearchResult {
+	var results []model.SearchResult
+	for _, item := range items {
+		if !p.matchCategory(item) {
+			continue
+		}
+		if result := p.parseItem(item); result.Title != "" && len(result.Links) > 0 {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// matchCategory 判断数据项的分类是否符合配置
+func (p *Plugin) matchCategory(item APIItem) bool {
+	typeID := strconv.Itoa(int(item.TypeID))
+	typeName := strings.TrimSpace(item.TypeName)
+	matches := func(categories []string) bool {
+		for _, category := range categories {
+			if category == typeID || (typeName != "" && category == typeName) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(p.config.Categories) > 0 && !matches(p.config.Categories) {
+		return false
+	}
+	return !matches(p.config.ExcludeCategories)
+}
+
+// parseItem 解析单个数据项
+func (p *Plugin) parseItem(item APIItem) model.SearchResult {
+	title := strings.TrimSpace(item.VodName)
+	if title == "" {
+		return model.SearchResult{}
+	}
+
+	// 构建描述，部分站点的演员和导演前后带逗号
+	var contentParts []string
+	fields := []struct{ label, value string }{
+		{"主演", item.VodActor},
+		{"导演", item.VodDirector},
+		{"地区", item.VodArea},
+		{"语言", item.VodLang},
+		{"年份", item.VodYear},
+		{"状态", item.VodRemarks},
+	}
+	for _, field := range fields {
+		if value := strings.TrimSpace(strings.Trim(field.value, ",")); value != "" {
+			contentParts = append(contentParts, fmt.Sprintf("%s: %s", field.label, value))
+		}
+	}
+
+	var tags []string
+	if item.VodYear != "" {
+		tags = append(tags, item.VodYear)
+	}
+	if item.VodArea != "" {
+		tags = append(tags, item.VodArea)
+	}
+
+	var images []string
+	if pic := strings.TrimSpace(item.VodPic); strings.HasPrefix(pic, "http") {
+		images = append(images, pic)
+	}
+
+	// vod_time为资源更新时间，解析失败时使用零值
+	datetime, _ := time.ParseInLocation("2006-01-02 15:04:05", strings.TrimSpace(item.VodTime), time.Local)
+
+	return model.SearchResult{
+		UniqueID: fmt.Sprintf("%s-%d", p.Name(), item.VodID),
+		Title:    title,
+		Content:  strings.Join(contentParts, " | "),
+		Links:    p.ParseDownloadLinks(item.VodDownFrom, item.VodDownURL),
+		Tags:     tags,
+		Images:   images,
+		Channel:  "", // 插件搜索结果Channel为空
+		Datetime: datetime,
+	}
+}
+
+// ParseDownloadLinks 解析vod_down_from和vod_down_url：两者都用$$$分隔多个下载组并按位置对应，
+// 组内用#分隔多条链接，每条链接可以是"名称$地址"或直接是地址；重复的链接只保留一次
+func (p *Plugin) ParseDownloadLinks(downFrom, downURL string) []model.Link {
+	if downURL == "" {
+		return nil
+	}
+
+	fromParts := strings.Split(downFrom, groupSeparator)
+	var links []model.Link
+	seen := make(map[string]bool)
+	for i, group := range strings.Split(downURL, groupSeparator) {
+		var from string
+		if i < len(fromParts) {
+			from = strings.TrimSpace(fromParts[i])
+		}
+
+		for _, episode := range strings.Split(group, episodeSeparator) {
+			linkURL := episode
+			if idx := strings.LastIndex(episode, nameSeparator); idx >= 0 {
+				linkURL = episode[idx+len(nameSeparator):]
+			}
+			linkURL = strings.TrimSpace(linkURL)
+			if linkURL == "" || seen[linkURL] {
+				continue
+			}
+
+			linkType := p.linkType(from, linkURL)
+			if linkType == "" {
+				continue
+			}
+			seen[linkURL] = true
+			links = append(links, model.Link{
+				Type:     linkType,
+				URL:      linkURL,
+				Password: extractPassword(linkURL),
+			})
+		}
+	}
+	return links
+}
+
+// linkType 确定链接类型：优先根据地址识别，地址无法识别时按下载组的来源标识判断，都无法识别时返回空字符串
+func (p *Plugin) linkType(from string, linkURL string) string {
+	if strings.Contains(linkURL, "javascript:") ||
+		(!strings.HasPrefix(linkURL, "http") && !strings.HasPrefix(linkURL, "magnet:") && !strings.HasPrefix(linkURL, "ed2k:")) {
+		return ""
+	}
+
+	for _, candidate := range linkTypePatterns {
+		if candidate.pattern.MatchString(linkURL) {
+			return candidate.linkType
+		}
+	}
+
+	// 网盘域名变化时地址无法识别，来源标识是网盘时仍然收录
+	if strings.HasPrefix(linkURL, "http") {
+		return p.cloudTypes[strings.ToUpper(from)]
+	}
+	return ""
+}
+
+// extractPassword 从链接参数中提取密码
+func extractPassword(linkURL string) string {
+	if matches := passwordRegex.FindStringSubmatch(linkURL); len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
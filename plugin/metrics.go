@@ -0,0 +1,25 @@
+package plugin
+
+import (
+	"sync/atomic"
+
+	"pansou/util/metrics"
+)
+
+// workerSlotRejections 后台工作槽已满导致任务被拒绝的次数
+var workerSlotRejections int64
+
+func init() {
+	metrics.NewGaugeFunc("pansou_async_background_tasks", "正在运行的异步插件后台任务数", func() float64 {
+		return float64(atomic.LoadInt32(&backgroundTasksCount))
+	})
+	metrics.NewGaugeFunc("pansou_async_worker_slots_in_use", "已占用的异步插件后台工作槽数", func() float64 {
+		return float64(len(backgroundWorkerPool))
+	})
+	metrics.NewGaugeFunc("pansou_async_worker_slots_capacity", "异步插件后台工作槽总数", func() float64 {
+		return float64(cap(backgroundWorkerPool))
+	})
+	metrics.NewCounterFunc("pansou_async_worker_slot_rejections_total", "后台工作槽已满导致任务被拒绝的次数", func() float64 {
+		return float64(atomic.LoadInt64(&workerSlotRejections))
+	})
+}
@@ -64,6 +64,17 @@ func NewMiaosouPlugin() *MiaosouPlugin {
 	}
 }
 
+// Info 返回插件元数据
+func (p *MiaosouPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		Homepage: "https://miaosou.fun",
+		Category: plugin.CategoryNetdisk,
+		Ext: []plugin.ExtParam{
+			{Key: "title_en", Type: plugin.ExtString, Description: "英文标题，设置后代替关键词搜索"},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *MiaosouPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -75,7 +86,7 @@ func (p *MiaosouPlugin) Search(keyword string, ext map[string]interface{}) ([]mo
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *MiaosouPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实际的搜索实现
@@ -83,10 +94,8 @@ func (p *MiaosouPlugin) searchImpl(client *http.Client, keyword string, ext map[
 	// 处理扩展参数
 	searchKeyword := keyword
 	if ext != nil {
-		if titleEn, exists := ext["title_en"]; exists {
-			if titleEnStr, ok := titleEn.(string); ok && titleEnStr != "" {
-				searchKeyword = titleEnStr
-			}
+		if titleEn, ok := plugin.GetString(ext, "title_en"); ok && titleEn != "" {
+			searchKeyword = titleEn
 		}
 	}
 	
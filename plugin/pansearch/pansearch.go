@@ -15,6 +15,7 @@ import (
 	"pansou/plugin"
 	"pansou/util/json"
 	"sync/atomic"
+	"pansou/util/logger"
 )
 
 // 预编译正则表达式
@@ -31,6 +32,9 @@ var (
 	cacheTTL = 1 * time.Hour
 )
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=pansearch=debug 开启
+var pluginLog = logger.ForPlugin("pansearch")
+
 // 在init函数中注册插件
 func init() {
 	// 使用全局超时时间创建插件实例并注册
@@ -157,7 +161,7 @@ func (wp *WorkerPool) Start(ctx context.Context, handler func(ctx context.Contex
 							// 成功发送错误
 						default:
 							// 通道可能已关闭，忽略错误
-							fmt.Printf("无法发送错误: %v\n", err)
+							pluginLog.Warnf("无法发送错误: %v", err)
 						}
 					} else {
 						select {
@@ -165,7 +169,7 @@ func (wp *WorkerPool) Start(ctx context.Context, handler func(ctx context.Contex
 							// 成功发送结果
 						default:
 							// 通道可能已关闭，忽略结果
-							fmt.Printf("无法发送结果\n")
+							pluginLog.Warnf("无法发送结果")
 						}
 					}
 
@@ -298,7 +302,7 @@ func (p *PanSearchAsyncPlugin) updateBuildId() {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		fmt.Printf("获取buildId时服务器返回非200状态码: %d\n", resp.StatusCode)
+		pluginLog.Warnf("获取buildId时服务器返回非200状态码: %d", resp.StatusCode)
 		return
 	}
 
@@ -314,7 +318,7 @@ func (p *PanSearchAsyncPlugin) updateBuildId() {
 	// 尝试提取 buildId
 	newBuildId := extractBuildId(body)
 	if newBuildId == "" {
-		fmt.Println("未能从响应中提取 buildId")
+		pluginLog.Warnf("未能从响应中提取 buildId")
 		return
 	}
 
@@ -326,7 +330,7 @@ func (p *PanSearchAsyncPlugin) updateBuildId() {
 	if newBuildId != "" && newBuildId != buildIdCache {
 		buildIdCache = newBuildId
 		buildIdCacheTime = time.Now()
-		fmt.Printf("成功更新 buildId: %s\n", newBuildId)
+		pluginLog.Infof("成功更新 buildId: %s", newBuildId)
 	}
 }
 
@@ -440,7 +444,7 @@ func (p *PanSearchAsyncPlugin) getBuildId() (string, error) {
 	if resp.StatusCode != 200 {
 		// 如果状态码不是200，但有旧的缓存，使用旧的缓存（优雅降级）
 		if buildIdCache != "" {
-			fmt.Printf("获取buildId时服务器返回非200状态码: %d，使用旧的buildId\n", resp.StatusCode)
+			pluginLog.Warnf("获取buildId时服务器返回非200状态码: %d，使用旧的buildId", resp.StatusCode)
 			return buildIdCache, nil
 		}
 		return "", fmt.Errorf("获取buildId时服务器返回非200状态码: %d", resp.StatusCode)
@@ -499,11 +503,13 @@ func (p *PanSearchAsyncPlugin) Search(keyword string, ext map[string]interface{}
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *PanSearchAsyncPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.doSearch, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.doSearch, ext)
 }
 
 // doSearch 执行具体的搜索逻辑
 func (p *PanSearchAsyncPlugin) doSearch(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+
 	// 获取API基础URL
 	baseURL, err := p.getBaseURL(client)
 	if err != nil {
@@ -515,7 +521,7 @@ func (p *PanSearchAsyncPlugin) doSearch(client *http.Client, keyword string, ext
 	if err != nil {
 		// 如果返回404错误，可能是buildId过期，尝试强制刷新buildId
 		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not Found") {
-			fmt.Println("检测到404错误，buildId可能已过期，尝试强制刷新")
+			log.Infof("检测到404错误，buildId可能已过期，尝试强制刷新")
 
 			// 强制刷新buildId
 			buildIdMutex.Lock()
@@ -621,7 +627,7 @@ func (p *PanSearchAsyncPlugin) doSearch(client *http.Client, keyword string, ext
 						if err == nil && newBuildId != "" {
 							// 更新baseURL
 							task.baseURL = fmt.Sprintf(BaseURLTemplate, newBuildId)
-							fmt.Printf("成功刷新buildId: %s\n", newBuildId)
+							log.Infof("成功刷新buildId: %s", newBuildId)
 						}
 
 						// 重置标志
@@ -699,7 +705,7 @@ func (p *PanSearchAsyncPlugin) doSearch(client *http.Client, keyword string, ext
 
 				// 尝试提交任务，如果失败则跳出循环
 				if !p.workerPool.Submit(task) {
-					fmt.Printf("无法提交任务，工作池可能已关闭\n")
+					log.Warnf("无法提交任务，工作池可能已关闭")
 					goto CollectResults
 				}
 
@@ -1,12 +1,12 @@
 package panta
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/plugin/sdk"
 	"regexp"
 	"strings"
 	"sync"
@@ -75,33 +75,22 @@ var (
 		"mypikpak.com",
 	}
 	
-	// 缓存相关
-	isNetDiskLinkCache     = sync.Map{} // 缓存URL是否为网盘链接的结果
-	determineLinkTypeCache = sync.Map{} // 缓存URL的链接类型
-	extractPasswordCache   = sync.Map{} // 缓存提取码提取结果
+	// 缓存已解析的topicId
+	topicIDCache = sdk.NewTTLCache(cacheTTL)
 	
-	// 新增缓存，用于存储已解析的topicId
-	topicIDCache = sync.Map{}
+	// 缓存已解析的发布时间
+	postTimeCache = sdk.NewTTLCache(cacheTTL)
 	
-	// 新增缓存，用于存储已解析的发布时间
-	postTimeCache = sync.Map{}
-	
-	// 新增缓存，用于存储已解析的年份
-	yearCache = sync.Map{}
+	// 缓存已解析的年份
+	yearCache = sdk.NewTTLCache(cacheTTL)
 	
 	// 链接提取结果缓存
-	linkExtractCache = sync.Map{} // 缓存从文本中提取的链接结果
+	linkExtractCache = sdk.NewTTLCache(cacheTTL) // 缓存从文本中提取的链接结果
 	
 	// 线程链接缓存
-	threadLinksCache = sync.Map{} // 缓存帖子详情页中的链接
+	threadLinksCache = sdk.NewTTLCache(cacheTTL) // 缓存帖子详情页中的链接
 )
 
-// 缓存键结构，用于extractPassword函数
-type passwordCacheKey struct {
-	content string
-	url     string
-}
-
 // 常量定义
 const (
 	// 插件名称
@@ -145,8 +134,21 @@ const (
 	
 	// 最大退避时间（毫秒）
 	maxBackoff = 5000
+	
+	// 缓存有效期
+	cacheTTL = 1 * time.Hour
 )
 
+// requestHeaders 请求搜索页和帖子页时使用的请求头，User-Agent由sdk随机轮换
+var requestHeaders = map[string]string{
+	"Referer":                   "https://www.91panta.cn/index",
+	"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
+	"Accept-Language":           "zh-CN,zh;q=0.9,en;q=0.8",
+	"Connection":                "keep-alive",
+	"Upgrade-Insecure-Requests": "1",
+	"Cache-Control":             "max-age=0",
+}
+
 // PantaAsyncPlugin 是PanTa网站的异步搜索插件实现
 type PantaAsyncPlugin struct {
 	*plugin.BaseAsyncPlugin
@@ -172,9 +174,6 @@ func init() {
 
 // NewPantaAsyncPlugin 创建一个新的PanTa异步插件实例
 func NewPantaAsyncPlugin() *PantaAsyncPlugin {
-	// 启动定期清理缓存的goroutine
-	go startCacheCleaner()
-	
 	// 创建插件实例
 	p := &PantaAsyncPlugin{
 		BaseAsyncPlugin:    plugin.NewBaseAsyncPlugin("panta", defaultPriority),
@@ -187,25 +186,6 @@ func NewPantaAsyncPlugin() *PantaAsyncPlugin {
 	return p
 }
 
-// startCacheCleaner 启动一个定期清理缓存的goroutine
-func startCacheCleaner() {
-	// 每小时清理一次缓存
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
-	
-	for range ticker.C {
-		// 清空所有缓存
-		isNetDiskLinkCache = sync.Map{}
-		determineLinkTypeCache = sync.Map{}
-		extractPasswordCache = sync.Map{}
-		topicIDCache = sync.Map{}
-		postTimeCache = sync.Map{}
-		yearCache = sync.Map{}
-		linkExtractCache = sync.Map{}
-		threadLinksCache = sync.Map{}
-	}
-}
-
 // Name 返回插件名称
 func (p *PantaAsyncPlugin) Name() string {
 	return pluginName
@@ -227,7 +207,7 @@ func (p *PantaAsyncPlugin) Search(keyword string, ext map[string]interface{}) ([
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *PantaAsyncPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.doSearch, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.doSearch, ext)
 }
 
 // doSearch 执行具体的搜索逻辑
@@ -238,42 +218,11 @@ func (p *PantaAsyncPlugin) doSearch(client *http.Client, keyword string, ext map
 	// 构建搜索URL
 	searchURL := fmt.Sprintf(searchURLTemplate, encodedKeyword)
 	
-	// 创建一个带有超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(defaultTimeout)*time.Second)
-	defer cancel()
-	
-	// 创建请求
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %v", err)
-	}
-	
-	// 设置User-Agent和Referer
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	req.Header.Set("Referer", "https://www.91panta.cn/index")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-	req.Header.Set("Cache-Control", "max-age=0")
-	
-	// 使用带重试的请求方法发送HTTP请求
-	resp, err := p.doRequestWithRetry(req, client)
+	// 请求并解析搜索页
+	doc, err := p.fetchPage(client, searchURL)
 	if err != nil {
 		return nil, fmt.Errorf("请求PanTa搜索页面失败: %v", err)
 	}
-	defer resp.Body.Close()
-	
-	// 检查状态码
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("请求PanTa搜索页面失败，状态码: %d", resp.StatusCode)
-	}
-	
-	// 使用goquery解析HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("解析HTML失败: %v", err)
-	}
 	
 	// 解析搜索结果
 	results, err := p.parseSearchResults(doc, client)
@@ -339,7 +288,7 @@ func (p *PantaAsyncPlugin) parseSearchResults(doc *goquery.Document, client *htt
 			
 			// 从href中提取topicId - 使用缓存
 			var topicID string
-			if cachedID, ok := topicIDCache.Load(href); ok {
+			if cachedID, ok := topicIDCache.Get(href); ok {
 				topicID = cachedID.(string)
 			} else {
 				match := topicIDRegex.FindStringSubmatch(href)
@@ -347,7 +296,7 @@ func (p *PantaAsyncPlugin) parseSearchResults(doc *goquery.Document, client *htt
 					return
 				}
 				topicID = match[1]
-				topicIDCache.Store(href, topicID)
+				topicIDCache.Set(href, topicID)
 			}
 			
 			// 提取标题
@@ -361,7 +310,7 @@ func (p *PantaAsyncPlugin) parseSearchResults(doc *goquery.Document, client *htt
 			var postTime time.Time
 			
 			// 使用缓存提取发布时间
-			if cachedTime, ok := postTimeCache.Load(postTimeText); ok {
+			if cachedTime, ok := postTimeCache.Get(postTimeText); ok {
 				postTime = cachedTime.(time.Time)
 			} else {
 				timeMatch := postTimeRegex.FindStringSubmatch(postTimeText)
@@ -376,19 +325,19 @@ func (p *PantaAsyncPlugin) parseSearchResults(doc *goquery.Document, client *htt
 				} else {
 					postTime = time.Now()
 				}
-				postTimeCache.Store(postTimeText, postTime)
+				postTimeCache.Set(postTimeText, postTime)
 			}
 			
 			// 从标题中提取年份作为可能的提取码
 			var yearFromTitle string
-			if cachedYear, ok := yearCache.Load(title); ok {
+			if cachedYear, ok := yearCache.Get(title); ok {
 				yearFromTitle = cachedYear.(string)
 			} else {
 				yearMatch := yearRegex.FindStringSubmatch(title)
 				if len(yearMatch) >= 2 {
 					yearFromTitle = yearMatch[1]
 				}
-				yearCache.Store(title, yearFromTitle)
+				yearCache.Set(title, yearFromTitle)
 			}
 			
 			// 尝试从摘要中提取链接
@@ -478,7 +427,7 @@ func (p *PantaAsyncPlugin) extractLinksFromElement(s *goquery.Selection, yearFro
 	cacheKey := fmt.Sprintf("%s_%s", html, yearFromTitle)
 	
 	// 检查缓存中是否已有结果
-	if cachedLinks, ok := linkExtractCache.Load(cacheKey); ok {
+	if cachedLinks, ok := linkExtractCache.Get(cacheKey); ok {
 		return cachedLinks.([]model.Link)
 	}
 	
@@ -496,15 +445,7 @@ func (p *PantaAsyncPlugin) extractLinksFromElement(s *goquery.Selection, yearFro
 		}
 		
 		// 快速过滤非网盘链接
-		isNetDisk := false
-		for _, domain := range netDiskDomains {
-			if strings.Contains(strings.ToLower(href), domain) {
-				isNetDisk = true
-				break
-			}
-		}
-		
-		if isNetDisk {
+		if sdk.IsNetDiskLink(href) {
 			allHrefs = append(allHrefs, href)
 			
 			// 获取周围文本，用于检查提取码相关信息
@@ -531,10 +472,10 @@ func (p *PantaAsyncPlugin) extractLinksFromElement(s *goquery.Selection, yearFro
 		}
 		
 		// 确定链接类型
-		linkType := determineLinkType(href)
+		linkType := sdk.LinkType(href)
 		
 		// 提取密码
-		password := extractPassword(surroundingText, href)
+		password := sdk.ExtractPassword(surroundingText, href)
 		
 		// 根据链接类型进行特殊处理
 		switch linkType {
@@ -583,7 +524,7 @@ func (p *PantaAsyncPlugin) extractLinksFromElement(s *goquery.Selection, yearFro
 	}
 	
 	// 缓存结果
-	linkExtractCache.Store(cacheKey, links)
+	linkExtractCache.Set(cacheKey, links)
 	
 	return links
 }
@@ -591,49 +532,18 @@ func (p *PantaAsyncPlugin) extractLinksFromElement(s *goquery.Selection, yearFro
 // fetchThreadLinks 获取帖子详情页中的链接
 func (p *PantaAsyncPlugin) fetchThreadLinks(topicID string, client *http.Client) ([]model.Link, error) {
 	// 检查缓存中是否已有结果
-	if cachedLinks, ok := threadLinksCache.Load(topicID); ok {
+	if cachedLinks, ok := threadLinksCache.Get(topicID); ok {
 		return cachedLinks.([]model.Link), nil
 	}
 	
 	// 构建帖子URL
 	threadURL := fmt.Sprintf(threadURLTemplate, topicID)
 	
-	// 创建一个带有超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(defaultTimeout)*time.Second)
-	defer cancel()
-	
-	// 创建请求
-	req, err := http.NewRequestWithContext(ctx, "GET", threadURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %v", err)
-	}
-	
-	// 设置User-Agent和Referer
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	req.Header.Set("Referer", "https://www.91panta.cn/index")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-	req.Header.Set("Cache-Control", "max-age=0")
-	
-	// 使用带重试的请求方法发送HTTP请求
-	resp, err := p.doRequestWithRetry(req, client)
+	// 请求并解析帖子详情页
+	doc, err := p.fetchPage(client, threadURL)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	
-	// 检查状态码
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("请求帖子详情页失败，状态码: %d", resp.StatusCode)
-	}
-	
-	// 使用goquery解析HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("解析HTML失败: %v", err)
-	}
 	
 	// 提取标题
 	title := strings.TrimSpace(doc.Find("div.title").Text())
@@ -659,7 +569,7 @@ func (p *PantaAsyncPlugin) fetchThreadLinks(topicID string, client *http.Client)
 			}
 			
 			// 检查是否为网盘链接
-			if isNetDiskLink(href) {
+			if sdk.IsNetDiskLink(href) {
 				// 如果链接已存在，跳过
 				if foundURLs[href] {
 					return
@@ -676,10 +586,10 @@ func (p *PantaAsyncPlugin) fetchThreadLinks(topicID string, client *http.Client)
 				}
 				
 				// 确定链接类型
-				linkType := determineLinkType(href)
+				linkType := sdk.LinkType(href)
 				
 				// 提取密码
-				password := extractPassword(surroundingText, href)
+				password := sdk.ExtractPassword(surroundingText, href)
 				
 				// 根据链接类型进行特殊处理
 				switch linkType {
@@ -740,7 +650,7 @@ func (p *PantaAsyncPlugin) fetchThreadLinks(topicID string, client *http.Client)
 	})
 	
 	// 缓存结果
-	threadLinksCache.Store(topicID, links)
+	threadLinksCache.Set(topicID, links)
 	
 	return links, nil
 }
@@ -843,7 +753,7 @@ func extractTextLinks(text string, yearFromTitle string) []model.Link {
 						baseURL = strings.TrimRight(baseURL, "#")
 						
 						// 确定链接类型
-						linkType := determineLinkType(baseURL)
+						linkType := sdk.LinkType(baseURL)
 						
 						// 添加到链接列表
 						foundLinks = append(foundLinks, linkInfo{
@@ -1081,120 +991,6 @@ func extractTextLinks(text string, yearFromTitle string) []model.Link {
 	return links
 }
 
-// extractPassword 从文本中提取密码
-func extractPassword(content string, url string) string {
-	// 创建缓存键
-	key := passwordCacheKey{
-		content: content,
-		url:     url,
-	}
-	
-	// 检查缓存中是否已有结果
-	if result, ok := extractPasswordCache.Load(key); ok {
-		return result.(string)
-	}
-	
-	// 如果URL已经包含密码参数，直接提取
-	pwdMatch := pwdParamRegex.FindStringSubmatch(url)
-	if len(pwdMatch) >= 2 {
-		// 缓存结果
-		extractPasswordCache.Store(key, pwdMatch[1])
-		return pwdMatch[1]
-	}
-	
-	// 只有当内容中可能包含提取码时才进行提取
-	hasPasswordKeyword := false
-	for _, keyword := range pwdKeywords {
-		if strings.Contains(content, keyword) {
-			hasPasswordKeyword = true
-			break
-		}
-	}
-	
-	// 如果内容中没有提取码相关关键词，直接返回空
-	if !hasPasswordKeyword {
-		// 缓存结果
-		extractPasswordCache.Store(key, "")
-		return ""
-	}
-	
-	// 尝试从文本中提取密码
-	for _, pattern := range pwdPatterns {
-		matches := pattern.FindStringSubmatch(content)
-		
-		if len(matches) >= 2 {
-			// 缓存结果
-			extractPasswordCache.Store(key, matches[1])
-			return matches[1]
-		}
-	}
-	
-	// 缓存结果
-	extractPasswordCache.Store(key, "")
-	return ""
-}
-
-// determineLinkType 根据URL确定链接类型
-func determineLinkType(url string) string {
-	// 检查缓存中是否已有结果
-	if result, ok := determineLinkTypeCache.Load(url); ok {
-		return result.(string)
-	}
-	
-	lowerURL := strings.ToLower(url)
-	var linkType string
-	
-	switch {
-	case strings.Contains(lowerURL, "pan.baidu.com"):
-		linkType = "baidu"
-	case strings.Contains(lowerURL, "pan.quark.cn"):
-		linkType = "quark"
-	case strings.Contains(lowerURL, "alipan.com") || strings.Contains(lowerURL, "aliyundrive.com"):
-		linkType = "aliyun"
-	case strings.Contains(lowerURL, "cloud.189.cn"):
-		linkType = "tianyi"
-	case strings.Contains(lowerURL, "caiyun.139.com"):
-		linkType = "mobile"  // 修改为mobile而不是caiyun
-	case strings.Contains(lowerURL, "115.com"):
-		linkType = "115"
-	case strings.Contains(lowerURL, "pan.xunlei.com"):
-		linkType = "xunlei"
-	case strings.Contains(lowerURL, "mypikpak.com"):
-		linkType = "pikpak"
-	case strings.Contains(lowerURL, "123"):
-		linkType = "123"
-	default:
-		linkType = "others"
-	}
-	
-	// 缓存结果
-	determineLinkTypeCache.Store(url, linkType)
-	return linkType
-}
-
-// isNetDiskLink 检查链接是否为网盘链接
-func isNetDiskLink(url string) bool {
-	// 检查缓存中是否已有结果
-	if result, ok := isNetDiskLinkCache.Load(url); ok {
-		return result.(bool)
-	}
-	
-	lowerURL := strings.ToLower(url)
-	
-	// 使用预定义的网盘域名列表进行快速检查
-	for _, domain := range netDiskDomains {
-		if strings.Contains(lowerURL, domain) {
-			// 缓存结果
-			isNetDiskLinkCache.Store(url, true)
-			return true
-		}
-	}
-	
-	// 缓存结果
-	isNetDiskLinkCache.Store(url, false)
-	return false
-} 
-
 // startConcurrencyAdjuster 启动一个定期调整并发数的goroutine
 func (p *PantaAsyncPlugin) startConcurrencyAdjuster() {
 	ticker := time.NewTicker(concurrencyAdjustInterval * time.Second)
@@ -1249,47 +1045,25 @@ func (p *PantaAsyncPlugin) recordResponseTime(d time.Duration) {
 	p.responseTimes = append(p.responseTimes, d)
 }
 
-// doRequestWithRetry 发送HTTP请求，带重试机制
-func (p *PantaAsyncPlugin) doRequestWithRetry(req *http.Request, client *http.Client) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-	var startTime time.Time
-	
-	// 重试循环
-	for retry := 0; retry <= maxRetries; retry++ {
-		// 如果不是第一次尝试，则等待一段时间
-		if retry > 0 {
-			// 使用指数退避算法计算等待时间
-			backoffTime := time.Duration(min(backoffBase*1<<uint(retry-1), maxBackoff)) * time.Millisecond
-			time.Sleep(backoffTime)
-			
-			// 创建新的请求，因为原请求可能已经被关闭
-			newReq := req.Clone(req.Context())
-			req = newReq
-		}
-		
-		// 记录开始时间
-		startTime = time.Now()
-		
-		// 发送请求
-		resp, err = client.Do(req)
-		
-		// 记录响应时间
-		responseTime := time.Since(startTime)
-		p.recordResponseTime(responseTime)
-		
-		// 如果请求成功，或者是不可重试的错误，则退出重试循环
-		if err == nil && resp.StatusCode < 500 {
-			break
-		}
-		
-		// 如果请求失败，但响应不为nil，则关闭响应体
-		if resp != nil {
-			resp.Body.Close()
-		}
+// fetchPage 请求页面并解析HTML，失败时按指数退避重试，并记录响应时间用于调整并发数
+func (p *PantaAsyncPlugin) fetchPage(client *http.Client, pageURL string) (*goquery.Document, error) {
+	startTime := time.Now()
+	body, err := sdk.Get(pageURL).
+		Headers(requestHeaders).
+		Timeout(time.Duration(defaultTimeout) * time.Second).
+		Retries(maxRetries).
+		Backoff(backoffBase*time.Millisecond, maxBackoff*time.Millisecond).
+		Bytes(client)
+	p.recordResponseTime(time.Since(startTime))
+	if err != nil {
+		return nil, err
 	}
 	
-	return resp, err
+	doc, err := sdk.ParseHTML(body)
+	if err != nil {
+		return nil, fmt.Errorf("解析HTML失败: %v", err)
+	}
+	return doc, nil
 }
 
 // max 返回两个整数中的较大值
@@ -2,7 +2,6 @@ package panwiki
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -14,6 +13,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -30,25 +30,19 @@ type PanwikiPlugin struct {
 	*plugin.BaseAsyncPlugin
 	detailCache sync.Map // 详情页缓存
 	cacheTTL    time.Duration
-	debugMode   bool     // debug模式开关
 	currentBaseURL string // 当前使用的域名
 }
 
 // NewPanwikiPlugin 创建Panwiki插件实例
 func NewPanwikiPlugin() *PanwikiPlugin {
-	
-	// 检查调试模式
-	debugMode := false
-	
 	p := &PanwikiPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPluginWithFilter("panwiki", 3, true),
 		cacheTTL:       30 * time.Minute,
-		debugMode:      debugMode,
 		currentBaseURL: PrimaryBaseURL, // 默认使用主域名
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] Debug模式已启用")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("Debug模式已启用")
 	}
 	
 	return p
@@ -69,14 +63,16 @@ func (p *PanwikiPlugin) getSearchURL(keyword string, page int) string {
 func (p *PanwikiPlugin) switchToBackupDomain() {
 	if p.currentBaseURL == PrimaryBaseURL {
 		p.currentBaseURL = BackupBaseURL
-		if p.debugMode {
-			log.Printf("[Panwiki] 切换到备用域名: %s", p.currentBaseURL)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("切换到备用域名: %s", p.currentBaseURL)
 		}
 	}
 }
 
 // searchImpl 实现搜索逻辑
 func (p *PanwikiPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+
 	// 第一页搜索
 	firstPageResults, err := p.searchPage(client, keyword, 1)
 	if err != nil {
@@ -122,33 +118,33 @@ func (p *PanwikiPlugin) searchImpl(client *http.Client, keyword string, ext map[
 	}
 
 	// 获取详情页链接
-	if p.debugMode {
-		log.Printf("[Panwiki] 开始获取详情页链接前，结果数: %d", len(allResults))
+	if log.DebugEnabled() {
+		log.Debugf("开始获取详情页链接前，结果数: %d", len(allResults))
 	}
 	
 	p.enrichWithDetailLinks(client, allResults, keyword)
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 获取详情页链接后，结果数: %d", len(allResults))
+	if log.DebugEnabled() {
+		log.Debugf("获取详情页链接后，结果数: %d", len(allResults))
 		for i, result := range allResults {
-			log.Printf("[Panwiki] 返回前检查 - 结果#%d: 标题=%s, 链接数=%d", i+1, result.Title, len(result.Links))
-			log.Printf("[Panwiki] 返回前检查 - 结果#%d: 链接=%s", i+1, result.Links)
+			log.Debugf("返回前检查 - 结果#%d: 标题=%s, 链接数=%d", i+1, result.Title, len(result.Links))
+			log.Debugf("返回前检查 - 结果#%d: 链接=%s", i+1, result.Links)
 		}
 	}
 
 	// 进行关键词过滤
-	if p.debugMode {
-		log.Printf("[Panwiki] 开始关键词过滤，关键词: %s", keyword)
+	if log.DebugEnabled() {
+		log.Debugf("开始关键词过滤，关键词: %s", keyword)
 	}
 	
 	filteredResults := plugin.FilterResultsByKeyword(allResults, keyword)
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 关键词过滤完成，过滤前: %d，过滤后: %d", len(allResults), len(filteredResults))
+	if log.DebugEnabled() {
+		log.Debugf("关键词过滤完成，过滤前: %d，过滤后: %d", len(allResults), len(filteredResults))
 		for i, result := range filteredResults {
-			log.Printf("[Panwiki] 最终结果%d: MessageID=%s, UniqueID=%s, 标题=%s, 链接数=%d", i+1, result.MessageID, result.UniqueID, result.Title, len(result.Links))
+			log.Debugf("最终结果%d: MessageID=%s, UniqueID=%s, 标题=%s, 链接数=%d", i+1, result.MessageID, result.UniqueID, result.Title, len(result.Links))
 		}
-		log.Printf("[Panwiki] 🚀 插件返回结果总数: %d", len(filteredResults))
+		log.Debugf("🚀 插件返回结果总数: %d", len(filteredResults))
 	}
 
 	return filteredResults, nil
@@ -175,8 +171,8 @@ func (p *PanwikiPlugin) searchPage(client *http.Client, keyword string, page int
 	if err != nil {
 		// 如果主域名失败，尝试切换到备用域名
 		if p.currentBaseURL == PrimaryBaseURL {
-			if p.debugMode {
-				log.Printf("[Panwiki] 主域名请求失败，尝试备用域名: %v", err)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("主域名请求失败，尝试备用域名: %v", err)
 			}
 			p.switchToBackupDomain()
 			
@@ -273,18 +269,18 @@ func (p *PanwikiPlugin) extractSearchResults(doc *goquery.Document) []model.Sear
 		result := p.parseSearchResult(s)
 		if result.Title != "" {
 			results = append(results, result)
-			if p.debugMode {
-				log.Printf("[Panwiki] 解析到结果 #%d: 标题=%s", i+1, result.Title)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("解析到结果 #%d: 标题=%s", i+1, result.Title)
 			}
 		} else {
-			if p.debugMode {
-				log.Printf("[Panwiki] 第%d项解析失败，标题为空", i+1)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("第%d项解析失败，标题为空", i+1)
 			}
 		}
 	})
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 共解析出 %d 个有效搜索结果", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("共解析出 %d 个有效搜索结果", len(results))
 	}
 	
 	return results
@@ -388,14 +384,14 @@ func (p *PanwikiPlugin) cleanTitle(title string) string {
 // enrichWithDetailLinks 并发获取详情页链接
 func (p *PanwikiPlugin) enrichWithDetailLinks(client *http.Client, results []model.SearchResult, keyword string) {
 	if len(results) == 0 {
-		if p.debugMode {
-			log.Printf("[Panwiki] 没有结果需要获取详情页链接")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("没有结果需要获取详情页链接")
 		}
 		return
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 开始为 %d 个结果获取详情页链接", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始为 %d 个结果获取详情页链接", len(results))
 	}
 	
 	var wg sync.WaitGroup
@@ -414,23 +410,23 @@ func (p *PanwikiPlugin) enrichWithDetailLinks(client *http.Client, results []mod
 			// 从Content中提取详情页URL
 			detailURL := p.extractDetailURLFromContent(results[index].Content)
 			if detailURL != "" {
-				if p.debugMode {
-					log.Printf("[Panwiki] 结果#%d 提取到详情页URL: %s", index+1, detailURL)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("结果#%d 提取到详情页URL: %s", index+1, detailURL)
 				}
 				links := p.fetchDetailPageLinksWithKeyword(client, detailURL, keyword)
 				if len(links) > 0 {
 					results[index].Links = append(results[index].Links, links...)
-					if p.debugMode {
-						log.Printf("[Panwiki] 结果#%d 从详情页获取到 %d 个链接", index+1, len(links))
+					if pluginLog.DebugEnabled() {
+						pluginLog.Debugf("结果#%d 从详情页获取到 %d 个链接", index+1, len(links))
 					}
 				} else {
-					if p.debugMode {
-						log.Printf("[Panwiki] 结果#%d 详情页未获取到有效链接", index+1)
+					if pluginLog.DebugEnabled() {
+						pluginLog.Debugf("结果#%d 详情页未获取到有效链接", index+1)
 					}
 				}
 			} else {
-				if p.debugMode {
-					log.Printf("[Panwiki] 结果#%d 未找到详情页URL", index+1)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("结果#%d 未找到详情页URL", index+1)
 				}
 			}
 		}(i)
@@ -438,27 +434,27 @@ func (p *PanwikiPlugin) enrichWithDetailLinks(client *http.Client, results []mod
 	
 	wg.Wait()
 	
-	if p.debugMode {
+	if pluginLog.DebugEnabled() {
 		totalLinks := 0
 		for i, result := range results {
 			totalLinks += len(result.Links)
-			log.Printf("[Panwiki] 结果#%d 最终链接数: %d", i+1, len(result.Links))
+			pluginLog.Debugf("结果#%d 最终链接数: %d", i+1, len(result.Links))
 		}
-		log.Printf("[Panwiki] 详情页链接获取完成，总计获得 %d 个链接", totalLinks)
+		pluginLog.Debugf("详情页链接获取完成，总计获得 %d 个链接", totalLinks)
 	}
 }
 
 // fetchDetailPageLinksWithKeyword 获取详情页中的网盘链接（带关键词过滤）
 func (p *PanwikiPlugin) fetchDetailPageLinksWithKeyword(client *http.Client, detailURL string, keyword string) []model.Link {
 	if detailURL == "" {
-		if p.debugMode {
-			log.Printf("[Panwiki] 详情页URL为空，跳过获取链接")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页URL为空，跳过获取链接")
 		}
 		return []model.Link{}
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 开始获取详情页链接: %s", detailURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始获取详情页链接: %s", detailURL)
 	}
 	
 	// 检查缓存
@@ -489,8 +485,8 @@ func (p *PanwikiPlugin) fetchDetailPageLinksWithKeyword(client *http.Client, det
 	
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Panwiki] 解析详情页HTML失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("解析详情页HTML失败: %v", err)
 		}
 		return []model.Link{}
 	}
@@ -510,9 +506,9 @@ func (p *PanwikiPlugin) fetchDetailPageLinksWithKeyword(client *http.Client, det
 func (p *PanwikiPlugin) extractDetailPageLinksWithFilter(doc *goquery.Document, keyword string) []model.Link {
 	var allLinks []model.Link
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] ==================== 开始智能过滤详情页链接 ====================")
-		log.Printf("[Panwiki] 关键词: %s", keyword)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("==================== 开始智能过滤详情页链接 ====================")
+		pluginLog.Debugf("关键词: %s", keyword)
 	}
 	
 	// 查找主要内容区域
@@ -528,21 +524,21 @@ func (p *PanwikiPlugin) extractDetailPageLinksWithFilter(doc *goquery.Document,
 	// 先直接提取所有链接，看有多少个
 	allFoundLinks := p.extractAllLinksDirectly(contentArea)
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 提取到链接总数: %d", len(allFoundLinks))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("提取到链接总数: %d", len(allFoundLinks))
 	}
 	
 	// 核心策略：4个或以下链接直接返回，超过4个才进行内容匹配
 	if len(allFoundLinks) <= 4 {
-		if p.debugMode {
-			log.Printf("[Panwiki] 链接数≤4，直接返回（帖子标题就是资源标题）")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("链接数≤4，直接返回（帖子标题就是资源标题）")
 		}
 		return allFoundLinks
 	}
 	
 	// 超过4个链接，需要精确匹配
-	if p.debugMode {
-		log.Printf("[Panwiki] 链接数>4，需要精确匹配")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("链接数>4，需要精确匹配")
 	}
 	
 	// 获取HTML内容进行分析
@@ -551,15 +547,15 @@ func (p *PanwikiPlugin) extractDetailPageLinksWithFilter(doc *goquery.Document,
 	
 	// 检查是否是单行格式
 	if p.isSingleLineFormat(lines, keyword) {
-		if p.debugMode {
-			log.Printf("[Panwiki] 检测到单行格式，使用精确匹配")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("检测到单行格式，使用精确匹配")
 		}
 		return p.extractLinksFromSingleLineFormat(lines, keyword)
 	}
 	
 	// 非单行格式，使用分组逻辑
-	if p.debugMode {
-		log.Printf("[Panwiki] 非单行格式，使用分组逻辑")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("非单行格式，使用分组逻辑")
 	}
 	return p.extractLinksWithGrouping(htmlContent, keyword)
 }
@@ -574,8 +570,8 @@ func (p *PanwikiPlugin) filterLinksByContext(links []model.Link, htmlContent, ke
 	cleanContent := p.cleanHtmlText(htmlContent)
 	lines := strings.Split(cleanContent, "\n")
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 开始上下文过滤，输入链接数: %d", len(links))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始上下文过滤，输入链接数: %d", len(links))
 	}
 	
 	for _, link := range links {
@@ -585,8 +581,8 @@ func (p *PanwikiPlugin) filterLinksByContext(links []model.Link, htmlContent, ke
 			if strings.Contains(line, link.URL) {
 				// 提取这个链接对应的作品名
 				workName = p.extractWorkNameForLinkInLine(line, link.URL)
-				if p.debugMode {
-					log.Printf("[Panwiki] 链接 %s 对应作品: '%s'", link.URL, workName)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("链接 %s 对应作品: '%s'", link.URL, workName)
 				}
 				break
 			}
@@ -595,16 +591,16 @@ func (p *PanwikiPlugin) filterLinksByContext(links []model.Link, htmlContent, ke
 		// 检查作品名是否与关键词相关
 		if workName != "" && p.isWorkTitleRelevant(workName, keyword) {
 			filtered = append(filtered, link)
-			if p.debugMode {
-				log.Printf("[Panwiki] ✅ 保留相关链接: %s", link.URL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("✅ 保留相关链接: %s", link.URL)
 			}
-		} else if p.debugMode {
-			log.Printf("[Panwiki] ❌ 过滤不相关链接: %s", link.URL)
+		} else if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("❌ 过滤不相关链接: %s", link.URL)
 		}
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 上下文过滤完成，输出链接数: %d", len(filtered))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("上下文过滤完成，输出链接数: %d", len(filtered))
 	}
 	
 	return filtered
@@ -636,8 +632,8 @@ func (p *PanwikiPlugin) isSimpleCase(htmlContent, keyword string) bool {
 	
 	// 如果是单行格式，不应该作为简单情况处理
 	if p.isSingleLineFormat(lines, keyword) {
-		if p.debugMode {
-			log.Printf("[Panwiki] 检测到单行格式，不作为简单情况处理")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("检测到单行格式，不作为简单情况处理")
 		}
 		return false
 	}
@@ -675,8 +671,8 @@ func (p *PanwikiPlugin) isSimpleCase(htmlContent, keyword string) bool {
 	// 注：搜索结果本身就是相关的，不需要再次严格过滤
 	isSimple := titleCount <= 2 || (linkCount <= 3 && titleCount <= 1)
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 简单情况判断: 标题数=%d, 链接数=%d, 有相关标题=%v, 内容相关=%v, 结果=%v", 
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("简单情况判断: 标题数=%d, 链接数=%d, 有相关标题=%v, 内容相关=%v, 结果=%v", 
 			titleCount, linkCount, hasRelevantTitle, hasRelevantContent, isSimple)
 	}
 	
@@ -689,13 +685,13 @@ func (p *PanwikiPlugin) pageContentRelevant(htmlContent, keyword string) bool {
 	normalizedText := strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(text, " ", ""), ".", ""))
 	normalizedKeyword := strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(keyword, " ", ""), ".", ""))
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 内容相关性检查 - 原文本长度: %d", len(text))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("内容相关性检查 - 原文本长度: %d", len(text))
 		if len(text) < 300 {
-			log.Printf("[Panwiki] 原文本: %s", text)
+			pluginLog.Debugf("原文本: %s", text)
 		}
-		log.Printf("[Panwiki] 标准化文本: %s", normalizedText)
-		log.Printf("[Panwiki] 标准化关键词: %s", normalizedKeyword)
+		pluginLog.Debugf("标准化文本: %s", normalizedText)
+		pluginLog.Debugf("标准化关键词: %s", normalizedKeyword)
 	}
 	
 	// 基本匹配
@@ -714,8 +710,8 @@ func (p *PanwikiPlugin) pageContentRelevant(htmlContent, keyword string) bool {
 			normalizedVariant := strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(variant, " ", ""), ".", ""))
 			if strings.Contains(normalizedText, normalizedVariant) {
 				keywordMatch = true
-				if p.debugMode {
-					log.Printf("[Panwiki] 匹配到变体: %s", variant)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("匹配到变体: %s", variant)
 				}
 				break
 			}
@@ -723,8 +719,8 @@ func (p *PanwikiPlugin) pageContentRelevant(htmlContent, keyword string) bool {
 	}
 	
 	result := basicMatch || keywordMatch
-	if p.debugMode {
-		log.Printf("[Panwiki] 内容相关性结果: 基本匹配=%v, 关键词匹配=%v, 最终结果=%v", basicMatch, keywordMatch, result)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("内容相关性结果: 基本匹配=%v, 关键词匹配=%v, 最终结果=%v", basicMatch, keywordMatch, result)
 	}
 	
 	return result
@@ -734,8 +730,8 @@ func (p *PanwikiPlugin) pageContentRelevant(htmlContent, keyword string) bool {
 func (p *PanwikiPlugin) extractAllLinksDirectly(contentArea *goquery.Selection) []model.Link {
 	var links []model.Link
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 开始直接提取链接（简单情况）")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始直接提取链接（简单情况）")
 	}
 	
 	// 提取直接的链接
@@ -745,8 +741,8 @@ func (p *PanwikiPlugin) extractAllLinksDirectly(contentArea *goquery.Selection)
 			return
 		}
 		
-		if p.debugMode {
-			log.Printf("[Panwiki] 找到a标签链接: %s", href)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("找到a标签链接: %s", href)
 		}
 		
 		linkType := p.determineLinkType(href)
@@ -758,32 +754,32 @@ func (p *PanwikiPlugin) extractAllLinksDirectly(contentArea *goquery.Selection)
 				Type:     linkType,
 				Password: password,
 			})
-			if p.debugMode {
-				log.Printf("[Panwiki] 识别为网盘链接: %s (类型: %s)", href, linkType)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("识别为网盘链接: %s (类型: %s)", href, linkType)
 			}
-		} else if p.debugMode {
-			log.Printf("[Panwiki] 不是支持的网盘链接: %s", href)
+		} else if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("不是支持的网盘链接: %s", href)
 		}
 	})
 	
 	// 提取文本中的链接
 	contentText := contentArea.Text()
-	if p.debugMode {
-		log.Printf("[Panwiki] 内容文本长度: %d", len(contentText))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("内容文本长度: %d", len(contentText))
 		if len(contentText) < 500 {
-			log.Printf("[Panwiki] 内容文本: %s", contentText)
+			pluginLog.Debugf("内容文本: %s", contentText)
 		}
 	}
 	
 	textLinks := p.extractLinksFromText(contentText)
-	if p.debugMode {
-		log.Printf("[Panwiki] 从文本提取到 %d 个链接", len(textLinks))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("从文本提取到 %d 个链接", len(textLinks))
 	}
 	links = append(links, textLinks...)
 	
 	deduplicatedLinks := p.deduplicateLinks(links)
-	if p.debugMode {
-		log.Printf("[Panwiki] 直接提取完成: 原始 %d 个, 去重后 %d 个", len(links), len(deduplicatedLinks))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("直接提取完成: 原始 %d 个, 去重后 %d 个", len(links), len(deduplicatedLinks))
 	}
 	
 	return deduplicatedLinks
@@ -811,16 +807,16 @@ func (p *PanwikiPlugin) extractLinksWithGrouping(htmlContent, keyword string) []
 		
 		// 检查是否是新的作品标题行
 		isTitle := p.isNewWorkTitle(cleanLine)
-		if p.debugMode {
-			log.Printf("[Panwiki] 检查标题: '%s' -> 是否为标题: %v", cleanLine, isTitle)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("检查标题: '%s' -> 是否为标题: %v", cleanLine, isTitle)
 		}
 		if isTitle {
 			// 处理之前的组
 			if len(currentGroup) > 0 && isRelevantGroup {
 				groupLinks := p.extractLinksFromGroup(currentGroup)
 				allLinks = append(allLinks, groupLinks...)
-				if p.debugMode {
-					log.Printf("[Panwiki] 从相关组提取到 %d 个链接", len(groupLinks))
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("从相关组提取到 %d 个链接", len(groupLinks))
 				}
 			}
 			
@@ -828,15 +824,15 @@ func (p *PanwikiPlugin) extractLinksWithGrouping(htmlContent, keyword string) []
 			currentGroup = []string{line}
 			isRelevantGroup = p.isWorkTitleRelevant(cleanLine, keyword)
 			
-			if p.debugMode {
-				log.Printf("[Panwiki] 新作品组: %s, 相关性: %v, 关键词: %s", cleanLine, isRelevantGroup, keyword)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("新作品组: %s, 相关性: %v, 关键词: %s", cleanLine, isRelevantGroup, keyword)
 			}
 		} else {
 			// 添加到当前组
 			if len(currentGroup) > 0 {
 				currentGroup = append(currentGroup, line)
-				if p.debugMode && strings.Contains(line, "http") {
-					log.Printf("[Panwiki] 添加链接行到当前组: %s", cleanLine)
+				if pluginLog.DebugEnabled() && strings.Contains(line, "http") {
+					pluginLog.Debugf("添加链接行到当前组: %s", cleanLine)
 				}
 			}
 		}
@@ -846,13 +842,13 @@ func (p *PanwikiPlugin) extractLinksWithGrouping(htmlContent, keyword string) []
 	if len(currentGroup) > 0 && isRelevantGroup {
 		groupLinks := p.extractLinksFromGroup(currentGroup)
 		allLinks = append(allLinks, groupLinks...)
-		if p.debugMode {
-			log.Printf("[Panwiki] 从最后相关组提取到 %d 个链接", len(groupLinks))
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("从最后相关组提取到 %d 个链接", len(groupLinks))
 		}
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 分组过滤完成，共提取 %d 个相关链接", len(allLinks))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("分组过滤完成，共提取 %d 个相关链接", len(allLinks))
 	}
 	
 	return p.deduplicateLinks(allLinks)
@@ -884,8 +880,8 @@ func (p *PanwikiPlugin) isSingleLineFormat(lines []string, keyword string) bool
 				matchingLineCount++
 			}
 			
-			if p.debugMode {
-				log.Printf("[Panwiki] 单行格式检查: '%s', 相关性: %v", cleanLine, p.isLineTitleRelevant(cleanLine, keyword))
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("单行格式检查: '%s', 相关性: %v", cleanLine, p.isLineTitleRelevant(cleanLine, keyword))
 			}
 		}
 	}
@@ -893,8 +889,8 @@ func (p *PanwikiPlugin) isSingleLineFormat(lines []string, keyword string) bool
 	// 如果有至少2行符合单行格式，且有匹配的行，就认为是单行格式
 	isMatch := validLineCount >= 2 && matchingLineCount > 0
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 单行格式判断: 有效行=%d, 匹配行=%d, 结果=%v", validLineCount, matchingLineCount, isMatch)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("单行格式判断: 有效行=%d, 匹配行=%d, 结果=%v", validLineCount, matchingLineCount, isMatch)
 	}
 	
 	return isMatch
@@ -912,8 +908,8 @@ func (p *PanwikiPlugin) extractLinksFromSingleLineFormat(lines []string, keyword
 		
 		// 检查是否包含"丨"和"："的单行格式
 		if strings.Contains(cleanLine, "丨") && strings.Contains(cleanLine, "：") {
-			if p.debugMode {
-				log.Printf("[Panwiki] 处理单行格式: %s", cleanLine)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("处理单行格式: %s", cleanLine)
 			}
 			
 			// 精确提取相关作品的链接
@@ -922,8 +918,8 @@ func (p *PanwikiPlugin) extractLinksFromSingleLineFormat(lines []string, keyword
 		}
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 单行格式处理完成，共提取 %d 个链接", len(allLinks))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("单行格式处理完成，共提取 %d 个链接", len(allLinks))
 	}
 	
 	return p.deduplicateLinks(allLinks)
@@ -937,8 +933,8 @@ func (p *PanwikiPlugin) extractLinksFromSingleLine(line, keyword string) []model
 	pattern := regexp.MustCompile(`([^丨]+)丨([^：]+)：(https?://[a-zA-Z0-9\.\-\_\?\=\&\/]+)`)
 	matches := pattern.FindAllStringSubmatch(line, -1)
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 单行匹配到 %d 个模式", len(matches))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("单行匹配到 %d 个模式", len(matches))
 	}
 	
 	for _, match := range matches {
@@ -947,8 +943,8 @@ func (p *PanwikiPlugin) extractLinksFromSingleLine(line, keyword string) []model
 			netdisk := strings.TrimSpace(match[2])
 			url := strings.TrimSpace(match[3])
 			
-			if p.debugMode {
-				log.Printf("[Panwiki] 作品: '%s', 网盘: '%s', 链接: '%s'", workName, netdisk, url)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("作品: '%s', 网盘: '%s', 链接: '%s'", workName, netdisk, url)
 			}
 			
 			if p.isWorkTitleRelevant(workName, keyword) {
@@ -962,12 +958,12 @@ func (p *PanwikiPlugin) extractLinksFromSingleLine(line, keyword string) []model
 						Password: password,
 					})
 					
-					if p.debugMode {
-						log.Printf("[Panwiki] ✅ 相关作品链接: %s -> %s", workName, url)
+					if pluginLog.DebugEnabled() {
+						pluginLog.Debugf("✅ 相关作品链接: %s -> %s", workName, url)
 					}
 				}
-			} else if p.debugMode {
-				log.Printf("[Panwiki] ❌ 不相关作品: %s", workName)
+			} else if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("❌ 不相关作品: %s", workName)
 			}
 		}
 	}
@@ -982,27 +978,27 @@ func (p *PanwikiPlugin) isLineTitleRelevant(line, keyword string) bool {
 	workPattern := regexp.MustCompile(`([^丨]+)丨[^：]+：`)
 	matches := workPattern.FindAllStringSubmatch(line, -1)
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 单行标题相关性检查: 原行='%s', 关键词='%s'", line, keyword)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("单行标题相关性检查: 原行='%s', 关键词='%s'", line, keyword)
 	}
 	
 	for _, match := range matches {
 		if len(match) > 1 {
 			workTitle := strings.TrimSpace(match[1])
-			if p.debugMode {
-				log.Printf("[Panwiki] 检查作品标题: '%s'", workTitle)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("检查作品标题: '%s'", workTitle)
 			}
 			if p.isWorkTitleRelevant(workTitle, keyword) {
-				if p.debugMode {
-					log.Printf("[Panwiki] ✅ 找到相关作品: '%s'", workTitle)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("✅ 找到相关作品: '%s'", workTitle)
 				}
 				return true
 			}
 		}
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 单行标题相关性结果: false")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("单行标题相关性结果: false")
 	}
 	
 	return false
@@ -1041,24 +1037,24 @@ func (p *PanwikiPlugin) isNewWorkTitle(text string) bool {
 	
 	// 如果文本太短，不太可能是标题
 	if len(text) < 3 {
-		if p.debugMode {
-			log.Printf("[Panwiki] 标题检查 '%s': 太短，不是标题", text)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("标题检查 '%s': 太短，不是标题", text)
 		}
 		return false
 	}
 	
 	// 1. 包含年份 (2025)
 	if matched, _ := regexp.MatchString(`\(\d{4}\)`, text); matched {
-		if p.debugMode {
-			log.Printf("[Panwiki] 标题检查 '%s': 匹配年份格式", text)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("标题检查 '%s': 匹配年份格式", text)
 		}
 		return true
 	}
 	
 	// 2. 包含分类标签 [剧情]、[古装]等 或 【作品名】格式
 	if matched, _ := regexp.MatchString(`\[[^\]]*\]|【[^\]]*】`, text); matched {
-		if p.debugMode {
-			log.Printf("[Panwiki] 标题检查 '%s': 匹配标签格式", text)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("标题检查 '%s': 匹配标签格式", text)
 		}
 		return true
 	}
@@ -1070,8 +1066,8 @@ func (p *PanwikiPlugin) isNewWorkTitle(text string) bool {
 	}
 	for _, indicator := range indicators {
 		if strings.Contains(text, indicator) {
-			if p.debugMode {
-				log.Printf("[Panwiki] 标题检查 '%s': 匹配指示词 '%s'", text, indicator)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("标题检查 '%s': 匹配指示词 '%s'", text, indicator)
 			}
 			return true
 		}
@@ -1079,8 +1075,8 @@ func (p *PanwikiPlugin) isNewWorkTitle(text string) bool {
 	
 	// 4. 检查集数格式：【全30集】、【40全】、[全36集]等
 	if matched, _ := regexp.MatchString(`【[全\d]+[集\d]*】|【\d+[全集]】|\[\d+[全集]\]|【完结】`, text); matched {
-		if p.debugMode {
-			log.Printf("[Panwiki] 标题检查 '%s': 匹配集数格式", text)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("标题检查 '%s': 匹配集数格式", text)
 		}
 		return true
 	}
@@ -1093,8 +1089,8 @@ func (p *PanwikiPlugin) isNewWorkTitle(text string) bool {
 	}
 	for _, prefix := range nonTitlePrefixes {
 		if strings.HasPrefix(text, prefix) {
-			if p.debugMode {
-				log.Printf("[Panwiki] 标题检查 '%s': 排除非标题内容", text)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("标题检查 '%s': 排除非标题内容", text)
 			}
 			return false
 		}
@@ -1117,14 +1113,14 @@ func (p *PanwikiPlugin) isNewWorkTitle(text string) bool {
 			}
 			chineseRatio := float64(chineseCount) / float64(textLength)
 			
-			if p.debugMode {
-				log.Printf("[Panwiki] 标题检查 '%s': 短标题检查 - 长度=%d, 中文字符数=%d, 中文比例=%.1f%%", text, textLength, chineseCount, chineseRatio*100)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("标题检查 '%s': 短标题检查 - 长度=%d, 中文字符数=%d, 中文比例=%.1f%%", text, textLength, chineseCount, chineseRatio*100)
 			}
 			
 			// 如果主要是中文字符，认为是短标题
 			if chineseRatio >= 0.8 { // 至少80%是中文
-				if p.debugMode {
-					log.Printf("[Panwiki] 标题检查 '%s': 匹配短中文标题", text)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("标题检查 '%s': 匹配短中文标题", text)
 				}
 				return true
 			}
@@ -1132,8 +1128,8 @@ func (p *PanwikiPlugin) isNewWorkTitle(text string) bool {
 		
 		// 检查是否包含常见的作品名称特征
 		if matched, _ := regexp.MatchString(`^[A-Za-z]*[^\s]*(?:传|剧|版|之|的|与|和|：|丨|\s)+`, text); matched {
-			if p.debugMode {
-				log.Printf("[Panwiki] 标题检查 '%s': 匹配作品名称特征", text)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("标题检查 '%s': 匹配作品名称特征", text)
 			}
 			return true
 		}
@@ -1141,16 +1137,16 @@ func (p *PanwikiPlugin) isNewWorkTitle(text string) bool {
 		// 长标题检查（7-50个字符）
 		if textLength >= 7 && textLength <= 50 {
 			if matched, _ := regexp.MatchString(`^[\u4e00-\u9fff\w\s\-\(\)（）]+$`, text); matched {
-				if p.debugMode {
-					log.Printf("[Panwiki] 标题检查 '%s': 匹配长标题", text)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("标题检查 '%s': 匹配长标题", text)
 				}
 				return true
 			}
 		}
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 标题检查 '%s': 不符合任何标题规则", text)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("标题检查 '%s': 不符合任何标题规则", text)
 	}
 	return false
 }
@@ -1161,9 +1157,9 @@ func (p *PanwikiPlugin) isWorkTitleRelevant(title, keyword string) bool {
 	normalizedTitle := strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(title, " ", ""), ".", ""))
 	normalizedKeyword := strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(keyword, " ", ""), ".", ""))
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 相关性检查 - 原标题: %s, 原关键词: %s", title, keyword)
-		log.Printf("[Panwiki] 相关性检查 - 标准化标题: %s, 标准化关键词: %s", normalizedTitle, normalizedKeyword)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("相关性检查 - 原标题: %s, 原关键词: %s", title, keyword)
+		pluginLog.Debugf("相关性检查 - 标准化标题: %s, 标准化关键词: %s", normalizedTitle, normalizedKeyword)
 	}
 	
 	// 针对"凡人修仙传"的严格检查
@@ -1177,29 +1173,29 @@ func (p *PanwikiPlugin) isWorkTitleRelevant(title, keyword string) bool {
 		for _, pattern := range relevantPatterns {
 			normalizedPattern := strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(pattern, " ", ""), ".", ""))
 			if strings.Contains(normalizedTitle, normalizedPattern) {
-				if p.debugMode {
-					log.Printf("[Panwiki] 匹配到相关模式: %s", pattern)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("匹配到相关模式: %s", pattern)
 				}
 				return true
 			}
 		}
 		
-		if p.debugMode {
-			log.Printf("[Panwiki] 凡人修仙传检查：不相关")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("凡人修仙传检查：不相关")
 		}
 		return false
 	}
 	
 	// 对于其他关键词，进行精确匹配
 	if strings.Contains(normalizedTitle, normalizedKeyword) {
-		if p.debugMode {
-			log.Printf("[Panwiki] 其他关键词精确匹配成功")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("其他关键词精确匹配成功")
 		}
 		return true
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 不相关")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("不相关")
 	}
 	
 	return false
@@ -1343,8 +1339,8 @@ func (p *PanwikiPlugin) deduplicateLinks(links []model.Link) []model.Link {
 		result = append(result, link)
 	}
 	
-	if p.debugMode {
-		log.Printf("[Panwiki] 去重前: %d 个链接, 去重后: %d 个链接", len(links), len(result))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("去重前: %d 个链接, 去重后: %d 个链接", len(links), len(result))
 	}
 	
 	return result
@@ -1450,7 +1446,7 @@ func (p *PanwikiPlugin) Search(keyword string, ext map[string]interface{}) ([]mo
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *PanwikiPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // extractPasswordFromContent 从内容文本中提取指定链接的密码
@@ -1485,8 +1481,8 @@ func (p *PanwikiPlugin) extractPasswordFromContent(content, linkURL string) stri
 		re := regexp.MustCompile(pattern)
 		matches := re.FindStringSubmatch(surroundingText)
 		if len(matches) > 1 {
-			if p.debugMode {
-				log.Printf("[Panwiki] 为链接 %s 找到密码: %s", linkURL, matches[1])
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("为链接 %s 找到密码: %s", linkURL, matches[1])
 			}
 			return matches[1]
 		}
@@ -1497,6 +1493,9 @@ func (p *PanwikiPlugin) extractPasswordFromContent(content, linkURL string) stri
 	return urlPassword
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=panwiki=debug 开启
+var pluginLog = logger.ForPlugin("panwiki")
+
 func init() {
 	p := NewPanwikiPlugin()
 	plugin.RegisterGlobalPlugin(p)
@@ -18,6 +18,7 @@ import (
 
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 // 常量定义
@@ -28,8 +29,6 @@ const (
 	MaxConcurrency = 100
 	// 默认请求重试次数
 	MaxRetries = 0 // 重试
-	// 是否开启调试日志
-	DebugLog = false // 修改为true，使得调试信息可见
 	// 配置文件名
 	ConfigFileName = "panyq_config.json"
 	// 基础URL
@@ -128,10 +127,23 @@ func NewPanyqPlugin() *PanyqPlugin {
 	}
 }
 
+// Info 返回插件元数据
+func (p *PanyqPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: "盘友圈",
+		Homepage:    BaseURL,
+		Category:    plugin.CategoryNetdisk,
+		Ext: []plugin.ExtParam{
+			{Key: "referer", Type: plugin.ExtString, Description: "请求来源，开启来源检查时必须在允许列表中"},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果
 func (p *PanyqPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	if DebugLog {
-		fmt.Println("panyq: ext 参数内容:", ext)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("ext 参数内容: %v", ext)
 	}
 
 	// 检查搜索结果缓存
@@ -139,8 +151,8 @@ func (p *PanyqPlugin) Search(keyword string, ext map[string]interface{}) ([]mode
 	searchResultCacheLock.RLock()
 	if cachedResults, ok := searchResultCache[cacheKey]; ok {
 		searchResultCacheLock.RUnlock()
-		if DebugLog {
-			fmt.Printf("panyq: 缓存命中搜索结果: %s\n", keyword)
+		if log.DebugEnabled() {
+			log.Debugf("缓存命中搜索结果: %s", keyword)
 		}
 		return cachedResults, nil
 	}
@@ -148,17 +160,14 @@ func (p *PanyqPlugin) Search(keyword string, ext map[string]interface{}) ([]mode
 
 	// 请求来源检查
 	if EnableRefererCheck && ext != nil {
-		referer := ""
-		if refererVal, ok := ext["referer"].(string); ok {
-			referer = refererVal
-		}
+		referer, _ := plugin.GetString(ext, "referer")
 		
 		// 检查referer是否在允许列表中
 		allowed := false
 		for _, allowedReferer := range AllowedReferers {
 			if strings.HasPrefix(referer, allowedReferer) {
-				if DebugLog {
-					fmt.Printf("panyq: 允许来自 %s 的请求\n", referer)
+				if log.DebugEnabled() {
+					log.Debugf("允许来自 %s 的请求", referer)
 				}
 				allowed = true
 				break
@@ -166,15 +175,15 @@ func (p *PanyqPlugin) Search(keyword string, ext map[string]interface{}) ([]mode
 		}
 		
 		if !allowed {
-			if DebugLog {
-				fmt.Printf("panyq: 拒绝来自 %s 的请求\n", referer)
+			if log.DebugEnabled() {
+				log.Debugf("拒绝来自 %s 的请求", referer)
 			}
 			return nil, fmt.Errorf("请求来源不被允许")
 		}
 	}
 	
 	// 使用新的异步搜索方法
-	result, err := p.AsyncSearchWithResult(keyword, p.doSearch, p.MainCacheKey, ext)
+	result, err := p.AsyncSearchWithResult(keyword, p.doSearch, ext)
 	if err != nil {
 		return nil, err
 	}
@@ -192,13 +201,14 @@ func (p *PanyqPlugin) Search(keyword string, ext map[string]interface{}) ([]mode
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *PanyqPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.doSearch, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.doSearch, ext)
 }
 
 // doSearch 实际的搜索实现
 func (p *PanyqPlugin) doSearch(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	if DebugLog {
-		fmt.Println("panyq: searching for", keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("searching for %v", keyword)
 	}
 
 	// 尝试获取或发现 Action ID
@@ -231,16 +241,16 @@ func (p *PanyqPlugin) doSearch(client *http.Client, keyword string, ext map[stri
 	}
 
 	if len(hits) == 0 {
-		if DebugLog {
-			fmt.Println("panyq: no results found for", keyword)
+		if log.DebugEnabled() {
+			log.Debugf("no results found for %v", keyword)
 		}
 		return []model.SearchResult{}, nil
 	}
 	
 	// 如果有多页结果，并发获取其他页的数据
 	if maxPageNum > 1 {
-		if DebugLog {
-			fmt.Printf("panyq: found %d pages, fetching additional pages...\n", maxPageNum)
+		if log.DebugEnabled() {
+			log.Debugf("found %d pages, fetching additional pages...", maxPageNum)
 		}
 		if maxPageNum >= 3 {
 			maxPageNum = 3
@@ -255,8 +265,8 @@ func (p *PanyqPlugin) doSearch(client *http.Client, keyword string, ext map[stri
 			go func(pageNum int) {
 				defer wg.Done()
 				
-				if DebugLog {
-					fmt.Printf("panyq: fetching page %d...\n", pageNum)
+				if log.DebugEnabled() {
+					log.Debugf("fetching page %d...", pageNum)
 				}
 				
 				pageHits, _, err := p.getSearchResults(credentials.Sign, pageNum, client)
@@ -280,8 +290,8 @@ func (p *PanyqPlugin) doSearch(client *http.Client, keyword string, ext map[stri
 			hits = append(hits, pageHits...)
 		}
 		
-		if DebugLog {
-			fmt.Printf("panyq: total %d results from all pages\n", len(hits))
+		if log.DebugEnabled() {
+			log.Debugf("total %d results from all pages", len(hits))
 		}
 	}
 
@@ -364,8 +374,8 @@ func (p *PanyqPlugin) doSearch(client *http.Client, keyword string, ext map[stri
 	// 使用关键词过滤结果
 	filteredResults := p.FilterResultsByKeyword(results, keyword)
 
-	if DebugLog {
-		fmt.Println("panyq: returning", len(filteredResults), "filtered results")
+	if log.DebugEnabled() {
+		log.Debugf("returning %v %v", len(filteredResults), "filtered results")
 	}
 
 	return filteredResults, nil
@@ -396,15 +406,15 @@ func (p *PanyqPlugin) getOrDiscoverActionIDs() (map[string]string, error) {
 
 // discoverActionIDs 发现Action ID
 func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
-	if DebugLog {
-		fmt.Println("panyq: discovering Action IDs...")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("discovering Action IDs...")
 	}
 	
 	// 尝试从缓存文件加载
 	finalIDs, err := p.loadActionIDsFromFile()
 	if err == nil && len(finalIDs) == len(ActionIDKeys) {
-		if DebugLog {
-			fmt.Println("panyq: loaded Action IDs from file cache")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("loaded Action IDs from file cache")
 		}
 		
 		// 保存到内存缓存
@@ -427,18 +437,18 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 		return nil, fmt.Errorf("未找到潜在的Action ID")
 	}
 	
-	if DebugLog {
+	if pluginLog.DebugEnabled() {
 		// fmt.Printf("panyq: 找到 %d 个潜在的 Action ID\n", len(potentialIDs))
 		if len(potentialIDs) > 0 {
-			fmt.Printf("panyq: 样例ID: %s\n", potentialIDs[0])
+			pluginLog.Debugf("样例ID: %s", potentialIDs[0])
 		}
 	}
 	
 	finalIDs = make(map[string]string)
 	
 	// 1. 验证credential_action_id - 并发验证
-	if DebugLog {
-		fmt.Println("panyq: validating credential_action_id...")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("validating credential_action_id...")
 	}
 	
 	// 使用通道存储验证成功的ID
@@ -450,12 +460,12 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 		wg.Add(1)
 		go func(index int, actionID string) {
 			defer wg.Done()
-			if DebugLog {
-				fmt.Printf("panyq: 并发尝试第 %d 个ID作为credential_action_id: %.10s...\n", index+1, actionID)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("并发尝试第 %d 个ID作为credential_action_id: %.10s...", index+1, actionID)
 			}
 			if p.validateCredentialID(actionID) {
-				if DebugLog {
-					fmt.Printf("panyq: 找到有效的credential_action_id: %s\n", actionID)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("找到有效的credential_action_id: %s", actionID)
 				}
 				credIDChan <- actionID
 			}
@@ -484,8 +494,8 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 		return nil, fmt.Errorf("获取测试凭证失败: %w", err)
 	}
 	
-	if DebugLog {
-		fmt.Printf("panyq: 获取到测试凭证: sign=%.10s..., hash=%.10s..., sha=%.10s...\n", 
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("获取到测试凭证: sign=%.10s..., hash=%.10s..., sha=%.10s...", 
 			testCreds.Sign, testCreds.Hash, testCreds.Sha)
 	}
 	
@@ -498,8 +508,8 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 	}
 	
 	// 2. 验证intermediate_action_id - 从后向前验证
-	if DebugLog {
-		fmt.Printf("panyq: validating intermediate_action_id (%d candidates)...\n", len(remainingIDs))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("validating intermediate_action_id (%d candidates)...", len(remainingIDs))
 	}
 	
 	var intermediateIDFound bool
@@ -507,14 +517,14 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 	// 从后向前验证
 	for i := len(remainingIDs) - 1; i >= 0; i-- {
 		id := remainingIDs[i]
-		if DebugLog {
-			fmt.Printf("panyq: 尝试第 %d 个剩余ID作为intermediate_action_id: %.10s...\n", i+1, id)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("尝试第 %d 个剩余ID作为intermediate_action_id: %.10s...", i+1, id)
 		}
 		if p.validateIntermediateID(id, testCreds.Hash, testCreds.Sha) {
 			finalIDs[ActionIDKeys[1]] = id
 			intermediateIDFound = true
-			if DebugLog {
-				fmt.Printf("panyq: 找到有效的intermediate_action_id: %s\n", id)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("找到有效的intermediate_action_id: %s", id)
 			}
 			break
 		}
@@ -536,8 +546,8 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 	
 	testEID := testHits[0].EID
 	
-	if DebugLog {
-		fmt.Printf("panyq: 获取到测试EID: %s\n", testEID)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("获取到测试EID: %s", testEID)
 	}
 	
 	// 从剩余ID中排除已使用的ID
@@ -550,33 +560,33 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 	remainingIDs = newRemainingIDs
 	
 	// 3. 验证final_link_action_id
-	if DebugLog {
-		fmt.Printf("panyq: validating final_link_action_id (%d candidates)...\n", len(remainingIDs))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("validating final_link_action_id (%d candidates)...", len(remainingIDs))
 	}
 	
 	var finalLinkIDFound bool
 	for i, id := range remainingIDs {
 		// 针对每个候选ID都执行一次中间步骤
-		if DebugLog {
-			fmt.Printf("panyq: 尝试第 %d 个ID作为final_link_action_id: %.10s...\n", i+1, id)
-			fmt.Println("panyq: 执行中间步骤...")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("尝试第 %d 个ID作为final_link_action_id: %.10s...", i+1, id)
+			pluginLog.Debugf("执行中间步骤...")
 		}
 		
 		err = p.performIntermediateStep(finalIDs[ActionIDKeys[1]], testCreds.Hash, testCreds.Sha, testEID, p.client)
 		if err != nil {
-			fmt.Printf("panyq: 中间步骤执行失败, 继续尝试下一个ID: %v\n", err)
+			pluginLog.Warnf("中间步骤执行失败, 继续尝试下一个ID: %v", err)
 			continue
 		}
 		
-		if DebugLog {
-			fmt.Println("panyq: 验证final_link_action_id...")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("验证final_link_action_id...")
 		}
 		
 		if p.validateFinalLinkID(id, testEID) {
 			finalIDs[ActionIDKeys[2]] = id
 			finalLinkIDFound = true
-			if DebugLog {
-				fmt.Printf("panyq: 找到有效的final_link_action_id: %s\n", id)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("找到有效的final_link_action_id: %s", id)
 			}
 			break
 		}
@@ -585,8 +595,8 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 	if !finalLinkIDFound {
 		// 如果只剩下一个ID且验证失败，尝试交换intermediate_action_id和final_link_action_id
 		if len(remainingIDs) == 1 && len(potentialIDs) == 3 {
-			if DebugLog {
-				fmt.Println("panyq: final_link_action_id验证失败，尝试交换intermediate_action_id和final_link_action_id...")
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("final_link_action_id验证失败，尝试交换intermediate_action_id和final_link_action_id...")
 			}
 			
 			// 保存当前的intermediate_action_id
@@ -601,15 +611,15 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 			// 执行中间步骤
 			err = p.performIntermediateStep(finalIDs[ActionIDKeys[1]], testCreds.Hash, testCreds.Sha, testEID, p.client)
 			if err != nil {
-				if DebugLog {
-					fmt.Printf("panyq: 交换后中间步骤执行失败: %v\n", err)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Warnf("交换后中间步骤执行失败: %v", err)
 				}
 			} else {
 				// 验证final_link_action_id
 				if p.validateFinalLinkID(finalIDs[ActionIDKeys[2]], testEID) {
 					finalLinkIDFound = true
-					if DebugLog {
-						fmt.Println("panyq: 交换ID后验证成功!")
+					if pluginLog.DebugEnabled() {
+						pluginLog.Debugf("交换ID后验证成功!")
 					}
 				}
 			}
@@ -629,14 +639,14 @@ func (p *PanyqPlugin) discoverActionIDs() (map[string]string, error) {
 	
 	// 保存到文件缓存
 	if err := p.saveActionIDsToFile(finalIDs); err != nil {
-		fmt.Printf("panyq: 保存Action IDs到文件失败: %v\n", err)
+		pluginLog.Warnf("保存Action IDs到文件失败: %v", err)
 		// 继续执行，不返回错误
 	}
 	
-	if DebugLog {
-		fmt.Println("panyq: all Action IDs validated successfully:")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("all Action IDs validated successfully:")
 		for _, key := range ActionIDKeys {
-			fmt.Printf("panyq:   %s = %s\n", key, finalIDs[key])
+			pluginLog.Debugf("  %s = %s", key, finalIDs[key])
 		}
 	}
 	
@@ -731,8 +741,8 @@ func (p *PanyqPlugin) findPotentialActionIDs(client *http.Client) ([]string, err
 		ids = append(ids, id)
 	}
 	
-	if DebugLog {
-		fmt.Println("panyq: found", len(ids), "potential Action IDs")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("found %v %v", len(ids), "potential Action IDs")
 	}
 	
 	return ids, nil
@@ -755,7 +765,7 @@ func (p *PanyqPlugin) validateFinalLinkID(actionID, testEID string) bool {
 	responseText, err := p.getRawFinalLinkResponse(actionID, testEID, p.client)
 	if err != nil {
 		// 记录错误但继续尝试验证，因为Python版本在出现请求异常时返回None，但仍然会尝试验证
-		fmt.Println("panyq: 获取响应失败，但仍尝试验证:", err)
+		pluginLog.Warnf("获取响应失败，但仍尝试验证: %v", err)
 		// 即使出错，responseText可能包含部分响应内容
 		if responseText == "" {
 			return false
@@ -766,8 +776,8 @@ func (p *PanyqPlugin) validateFinalLinkID(actionID, testEID string) bool {
 	keywords := []string{"http", "magnet", "aliyundrive", `"url"`}
 	for _, kw := range keywords {
 		if strings.Contains(responseText, kw) {
-			if DebugLog {
-				fmt.Println("panyq: found keyword in response:", kw)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("found keyword in response: %v", kw)
 			}
 			return true
 		}
@@ -791,8 +801,8 @@ func (p *PanyqPlugin) doRequestWithRetry(client *http.Client, req *http.Request,
 			}
 			time.Sleep(backoff)
 			
-			if DebugLog {
-				fmt.Printf("panyq: 重试请求 #%d，等待 %v\n", i, backoff)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("重试请求 #%d，等待 %v", i, backoff)
 			}
 		}
 		
@@ -836,8 +846,8 @@ func (p *PanyqPlugin) getRawFinalLinkResponse(actionID, eid string, client *http
 	cacheKey := fmt.Sprintf("%s:%s", actionID, eid)
 	if cachedResponse, ok := finalLinkCache[cacheKey]; ok {
 		finalLinkCacheLock.RUnlock()
-		if DebugLog {
-			fmt.Printf("panyq: 缓存命中 raw final link: %s\n", eid)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("缓存命中 raw final link: %s", eid)
 		}
 		return cachedResponse, nil
 	}
@@ -902,8 +912,8 @@ func (p *PanyqPlugin) getRawFinalLinkResponse(actionID, eid string, client *http
 	resp, err := p.doRequestWithRetry(client, req, MaxRetries)
 	if err != nil {
 		// 网络错误等情况下返回空字符串和错误
-		if DebugLog {
-			fmt.Println("panyq: network error:", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("network error: %v", err)
 		}
 		return "", err
 	}
@@ -911,8 +921,8 @@ func (p *PanyqPlugin) getRawFinalLinkResponse(actionID, eid string, client *http
 	
 	// 检查状态码
 	if resp.StatusCode != http.StatusOK {
-		if DebugLog {
-			fmt.Println("panyq: bad status code:", resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("bad status code: %v", resp.StatusCode)
 		}
 		return "", fmt.Errorf("HTTP status code: %d", resp.StatusCode)
 	}
@@ -921,8 +931,8 @@ func (p *PanyqPlugin) getRawFinalLinkResponse(actionID, eid string, client *http
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		// 读取错误时返回空字符串和错误
-		if DebugLog {
-			fmt.Println("panyq: read error:", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("read error: %v", err)
 		}
 		return "", err
 	}
@@ -1106,8 +1116,8 @@ func (p *PanyqPlugin) getFinalLink(actionID, eid string, client *http.Client) (s
 	linkCacheKey := fmt.Sprintf("link:%s:%s", actionID, eid)
 	if cachedLink, ok := finalLinkCache[linkCacheKey]; ok {
 		finalLinkCacheLock.RUnlock()
-		if DebugLog {
-			fmt.Printf("panyq: 缓存命中最终链接: %s\n", eid)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("缓存命中最终链接: %s", eid)
 		}
 		return cachedLink, nil
 	}
@@ -1327,6 +1337,9 @@ func (p *PanyqPlugin) extractTitle(desc string) string {
 	return strings.TrimSpace(cleanDesc)
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=panyq=debug 开启
+var pluginLog = logger.ForPlugin("panyq")
+
 // 在init函数中注册插件
 func init() {
 	plugin.RegisterGlobalPlugin(NewPanyqPlugin())
@@ -1341,8 +1354,8 @@ func startCacheCleaner() {
 	defer ticker.Stop()
 	
 	for range ticker.C {
-		if DebugLog {
-			fmt.Println("panyq: 开始清理缓存")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("开始清理缓存")
 		}
 		
 		// 清理finalLinkCache
@@ -1355,8 +1368,8 @@ func startCacheCleaner() {
 		searchResultCache = make(map[string][]model.SearchResult)
 		searchResultCacheLock.Unlock()
 		
-		if DebugLog {
-			fmt.Println("panyq: 缓存清理完成")
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("缓存清理完成")
 		}
 	}
 }
@@ -93,6 +93,18 @@ func NewPiankuPlugin() *PiankuPlugin {
 	}
 }
 
+// Info 返回插件元数据
+func (p *PiankuPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: "片库",
+		Homepage:    BaseURL,
+		Category:    plugin.CategoryVideo,
+		Ext: []plugin.ExtParam{
+			{Key: "title_en", Type: plugin.ExtString, Description: "英文标题，设置后代替关键词搜索"},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *PiankuPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -104,7 +116,7 @@ func (p *PiankuPlugin) Search(keyword string, ext map[string]interface{}) ([]mod
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *PiankuPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实际的搜索实现
@@ -112,10 +124,8 @@ func (p *PiankuPlugin) searchImpl(client *http.Client, keyword string, ext map[s
 	// 处理扩展参数
 	searchKeyword := keyword
 	if ext != nil {
-		if titleEn, exists := ext["title_en"]; exists {
-			if titleEnStr, ok := titleEn.(string); ok && titleEnStr != "" {
-				searchKeyword = titleEnStr
-			}
+		if titleEn, ok := plugin.GetString(ext, "title_en"); ok && titleEn != "" {
+			searchKeyword = titleEn
 		}
 	}
 	
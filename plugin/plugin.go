@@ -1,17 +1,22 @@
 package plugin
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"sync"
 
 	"pansou/model"
+	"pansou/util/textnorm"
 )
 
 // 全局异步插件注册表
 var (
 	globalRegistry     = make(map[string]AsyncSearchPlugin)
 	globalRegistryLock sync.RWMutex
+	
+	// 运行时优先级覆盖（插件名 -> 优先级），由管理接口设置
+	priorityOverrides sync.Map
 )
 
 // AsyncSearchPlugin 异步搜索插件接口
@@ -25,11 +30,8 @@ type AsyncSearchPlugin interface {
 	// AsyncSearch 异步搜索方法
 	AsyncSearch(keyword string, searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error), mainCacheKey string, ext map[string]interface{}) ([]model.SearchResult, error)
 	
-	// SetMainCacheKey 设置主缓存键
-	SetMainCacheKey(key string)
-	
-	// SetCurrentKeyword 设置当前搜索关键词（用于日志显示）
-	SetCurrentKeyword(keyword string)
+	// AsyncSearchCtx 支持上下文取消的异步搜索方法，ctx取消时中断插件请求
+	AsyncSearchCtx(ctx context.Context, keyword string, searchFunc func(*http.Client, string, map[string]interface{}) ([]model.SearchResult, error), mainCacheKey string, ext map[string]interface{}) ([]model.SearchResult, error)
 	
 	// Search 兼容性方法（内部调用AsyncSearch）
 	Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error)
@@ -56,6 +58,14 @@ func RegisterGlobalPlugin(plugin AsyncSearchPlugin) {
 	globalRegistry[name] = plugin
 }
 
+// UnregisterGlobalPlugin 从全局注册表中移除插件，用于卸载运行时加载的插件
+func UnregisterGlobalPlugin(name string) {
+	globalRegistryLock.Lock()
+	defer globalRegistryLock.Unlock()
+	
+	delete(globalRegistry, name)
+}
+
 // GetRegisteredPlugins 获取所有已注册的异步插件
 func GetRegisteredPlugins() []AsyncSearchPlugin {
 	globalRegistryLock.RLock()
@@ -78,21 +88,104 @@ func GetPluginByName(name string) (AsyncSearchPlugin, bool) {
 	return plugin, exists
 }
 
+// SetPriorityOverride 运行时覆盖插件优先级
+func SetPriorityOverride(name string, priority int) {
+	priorityOverrides.Store(name, priority)
+}
+
+// ClearPriorityOverride 清除插件的运行时优先级覆盖，恢复插件默认优先级
+func ClearPriorityOverride(name string) {
+	priorityOverrides.Delete(name)
+}
+
+// GetPriorityOverride 获取插件的运行时优先级覆盖
+func GetPriorityOverride(name string) (int, bool) {
+	if priority, ok := priorityOverrides.Load(name); ok {
+		return priority.(int), true
+	}
+	return 0, false
+}
+
+// GetPluginPriority 获取插件生效的优先级，存在运行时覆盖时使用覆盖值
+func GetPluginPriority(plugin AsyncSearchPlugin) int {
+	if priority, ok := GetPriorityOverride(plugin.Name()); ok {
+		return priority
+	}
+	return plugin.Priority()
+}
+
 // PluginManager 异步插件管理器
 type PluginManager struct {
-	plugins []AsyncSearchPlugin
+	plugins      []AsyncSearchPlugin
+	pluginsMutex sync.RWMutex // 保护plugins，插件可在运行时启用/禁用
+	health       map[string]*pluginHealth // 插件健康统计和熔断状态
+	healthMutex  sync.RWMutex
 }
 
 // NewPluginManager 创建新的异步插件管理器
 func NewPluginManager() *PluginManager {
 	return &PluginManager{
 		plugins: make([]AsyncSearchPlugin, 0),
+		health:  make(map[string]*pluginHealth),
 	}
 }
 
 // RegisterPlugin 注册异步插件
 func (pm *PluginManager) RegisterPlugin(plugin AsyncSearchPlugin) {
-	pm.plugins = append(pm.plugins, plugin)
+	pm.pluginsMutex.Lock()
+	defer pm.pluginsMutex.Unlock()
+	pm.appendPlugin(plugin)
+}
+
+// appendPlugin 追加插件，调用方需持有pluginsMutex写锁
+func (pm *PluginManager) appendPlugin(plugin AsyncSearchPlugin) {
+	// 写时复制，GetPlugins返回的切片不会被后续修改影响
+	plugins := make([]AsyncSearchPlugin, 0, len(pm.plugins)+1)
+	plugins = append(plugins, pm.plugins...)
+	pm.plugins = append(plugins, plugin)
+}
+
+// HasPlugin 判断插件是否已启用
+func (pm *PluginManager) HasPlugin(name string) bool {
+	for _, p := range pm.GetPlugins() {
+		if p.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EnablePlugin 运行时启用已编译注册的插件，插件不存在时返回false
+func (pm *PluginManager) EnablePlugin(name string) bool {
+	plugin, exists := GetPluginByName(name)
+	if !exists {
+		return false
+	}
+	
+	// 检查和追加在同一把锁内完成，并发启用时不会重复注册
+	pm.pluginsMutex.Lock()
+	defer pm.pluginsMutex.Unlock()
+	for _, p := range pm.plugins {
+		if p.Name() == name {
+			return true
+		}
+	}
+	pm.appendPlugin(plugin)
+	return true
+}
+
+// DisablePlugin 运行时禁用插件，插件未启用时不做处理
+func (pm *PluginManager) DisablePlugin(name string) {
+	pm.pluginsMutex.Lock()
+	defer pm.pluginsMutex.Unlock()
+	
+	plugins := make([]AsyncSearchPlugin, 0, len(pm.plugins))
+	for _, p := range pm.plugins {
+		if p.Name() != name {
+			plugins = append(plugins, p)
+		}
+	}
+	pm.plugins = plugins
 }
 
 // RegisterAllGlobalPlugins 注册所有全局异步插件
@@ -137,6 +230,8 @@ func (pm *PluginManager) RegisterGlobalPluginsWithFilter(enabledPlugins []string
 
 // GetPlugins 获取所有注册的异步插件
 func (pm *PluginManager) GetPlugins() []AsyncSearchPlugin {
+	pm.pluginsMutex.RLock()
+	defer pm.pluginsMutex.RUnlock()
 	return pm.plugins
 }
 
@@ -149,22 +244,25 @@ func FilterResultsByKeyword(results []model.SearchResult, keyword string) []mode
 	// 预估过滤后会保留80%的结果
 	filteredResults := make([]model.SearchResult, 0, len(results)*8/10)
 
-	// 将关键词转为小写，用于不区分大小写的比较
-	lowerKeyword := strings.ToLower(keyword)
-
-	// 将关键词按空格分割，用于支持多关键词搜索
-	keywords := strings.Fields(lowerKeyword)
+	// 将关键词按空格分割并标准化，用于支持多关键词搜索以及忽略全半角、繁简体和数字写法的差异
+	keywords := textnorm.Tokens(keyword)
+	// 标准化会去掉C++、C#等词中的符号，这些词还需按原有写法匹配
+	symbolTerms := textnorm.SymbolTerms(keyword)
 
 	for _, result := range results {
-		// 将标题和内容转为小写
-		lowerTitle := strings.ToLower(result.Title)
-		lowerContent := strings.ToLower(result.Content)
+		if len(symbolTerms) > 0 && !textnorm.ContainsAll(textnorm.Fold(result.Title+"\n"+result.Content), symbolTerms) {
+			continue
+		}
+
+		// 将标题和内容标准化
+		normalizedTitle := textnorm.Normalize(result.Title)
+		normalizedContent := textnorm.Normalize(result.Content)
 
 		// 检查每个关键词是否在标题或内容中
 		matched := true
 		for _, kw := range keywords {
 			// 对于所有关键词，检查是否在标题或内容中
-			if !strings.Contains(lowerTitle, kw) && !strings.Contains(lowerContent, kw) {
+			if !strings.Contains(normalizedTitle, kw) && !strings.Contains(normalizedContent, kw) {
 				matched = false
 				break
 			}
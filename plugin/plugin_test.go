@@ -0,0 +1,71 @@
+package plugin
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"pansou/model"
+)
+
+// stubPlugin 不发起请求的测试插件
+type stubPlugin struct {
+	*BaseAsyncPlugin
+}
+
+func (p *stubPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	return nil, nil
+}
+
+// TestEnablePluginConcurrent 并发启用同一插件只注册一次
+func TestEnablePluginConcurrent(t *testing.T) {
+	RegisterGlobalPlugin(&stubPlugin{NewBaseAsyncPlugin("stub_enable", 3)})
+	t.Cleanup(func() { UnregisterGlobalPlugin("stub_enable") })
+
+	pm := NewPluginManager()
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !pm.EnablePlugin("stub_enable") {
+				t.Error("EnablePlugin返回false")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(pm.GetPlugins()); n != 1 {
+		t.Fatalf("插件注册了%d次，期望1次", n)
+	}
+	if pm.EnablePlugin("stub_missing") {
+		t.Error("未注册的插件不应启用成功")
+	}
+}
+
+// TestFilterResultsByKeywordSymbols C++、C#等带符号的关键词不匹配只含C的结果
+func TestFilterResultsByKeywordSymbols(t *testing.T) {
+	results := []model.SearchResult{
+		{Title: "C++ Primer 第五版"},
+		{Title: "Ｃ＃ Primer 中文版"},
+		{Title: "C Primer Plus"},
+	}
+
+	tests := []struct {
+		keyword string
+		want    []string
+	}{
+		{"C++ Primer", []string{"C++ Primer 第五版"}},
+		{"c# primer", []string{"Ｃ＃ Primer 中文版"}},
+		{"C Primer", []string{"C++ Primer 第五版", "Ｃ＃ Primer 中文版", "C Primer Plus"}},
+	}
+	for _, tc := range tests {
+		var got []string
+		for _, result := range FilterResultsByKeyword(results, tc.keyword) {
+			got = append(got, result.Title)
+		}
+		if strings.Join(got, "|") != strings.Join(tc.want, "|") {
+			t.Errorf("FilterResultsByKeyword(%q) = %q，期望%q", tc.keyword, got, tc.want)
+		}
+	}
+}
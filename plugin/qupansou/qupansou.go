@@ -99,7 +99,7 @@ func (p *QuPanSouAsyncPlugin) Search(keyword string, ext map[string]interface{})
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *QuPanSouAsyncPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.doSearch, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.doSearch, ext)
 }
 
 // doSearch 执行具体的搜索逻辑
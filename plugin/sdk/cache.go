@@ -0,0 +1,105 @@
+package sdk
+
+import (
+	"sync"
+	"time"
+)
+
+// minCleanupInterval 后台清理过期条目的最短间隔
+const minCleanupInterval = time.Minute
+
+// TTLCache 带有效期的内存缓存，用于缓存详情页、接口响应等；过期条目读取时视为不存在，并由后台定期清理
+type TTLCache struct {
+	ttl     time.Duration
+	entries sync.Map
+}
+
+// cacheEntry 缓存条目
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewTTLCache 创建缓存并启动后台清理，缓存通常作为插件的包级变量长期存在
+func NewTTLCache(ttl time.Duration) *TTLCache {
+	c := &TTLCache{ttl: ttl}
+
+	interval := ttl
+	if interval < minCleanupInterval {
+		interval = minCleanupInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.cleanup()
+		}
+	}()
+	return c
+}
+
+// Get 获取未过期的缓存值
+func (c *TTLCache) Get(key string) (interface{}, bool) {
+	value, ok := c.entries.Load(key)
+	if !ok {
+		return nil, false
+	}
+	entry := value.(cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.entries.Delete(key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set 设置缓存值
+func (c *TTLCache) Set(key string, value interface{}) {
+	c.entries.Store(key, cacheEntry{value: value, expires: time.Now().Add(c.ttl)})
+}
+
+// GetOrLoad 获取缓存值，不存在时调用load加载并缓存，load返回错误时不缓存
+func (c *TTLCache) GetOrLoad(key string, load func() (interface{}, error)) (interface{}, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.Set(key, value)
+	return value, nil
+}
+
+// Delete 删除缓存值
+func (c *TTLCache) Delete(key string) {
+	c.entries.Delete(key)
+}
+
+// Clear 清空缓存
+func (c *TTLCache) Clear() {
+	c.entries.Range(func(key, _ interface{}) bool {
+		c.entries.Delete(key)
+		return true
+	})
+}
+
+// Len 返回缓存条目数（包括还未清理的过期条目）
+func (c *TTLCache) Len() int {
+	count := 0
+	c.entries.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// cleanup 清理过期条目
+func (c *TTLCache) cleanup() {
+	now := time.Now()
+	c.entries.Range(func(key, value interface{}) bool {
+		if now.After(value.(cacheEntry).expires) {
+			c.entries.Delete(key)
+		}
+		return true
+	})
+}
@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"pansou/model"
+)
+
+// ParseHTML 解析HTML文档
+func ParseHTML(body []byte) (*goquery.Document, error) {
+	return goquery.NewDocumentFromReader(bytes.NewReader(body))
+}
+
+// Text 返回节点的文本，连续的空白合并为一个空格
+func Text(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// Attr 返回节点属性值，去掉首尾空白，属性不存在时返回空字符串
+func Attr(s *goquery.Selection, name string) string {
+	value, _ := s.Attr(name)
+	return strings.TrimSpace(value)
+}
+
+// ResolveURL 将相对地址解析为基于base的绝对地址，无法解析时原样返回
+func ResolveURL(base string, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// LinksFromSelection 提取节点中的网盘链接：包括a标签的href和文本中出现的链接，
+// 提取码从节点文本中识别，重复的链接只保留一次
+func LinksFromSelection(s *goquery.Selection) []model.Link {
+	text := s.Text()
+
+	var links []model.Link
+	seen := make(map[string]bool)
+	add := func(href string) {
+		if href == "" || seen[href] {
+			return
+		}
+		seen[href] = true
+		if link, ok := NewLink(href, text); ok {
+			links = append(links, link)
+		}
+	}
+
+	s.Find("a[href]").AddSelection(s.Filter("a[href]")).Each(func(_ int, a *goquery.Selection) {
+		add(Attr(a, "href"))
+	})
+	for _, link := range ExtractLinks(text) {
+		add(link.URL)
+	}
+	return links
+}
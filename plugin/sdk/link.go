@@ -0,0 +1,91 @@
+// Package sdk 插件开发的公共工具：网盘链接分类和提取码提取、带重试和UA轮换的HTTP请求、带有效期的页面缓存以及HTML解析辅助函数
+package sdk
+
+import (
+	"strings"
+
+	"pansou/model"
+	"pansou/util"
+)
+
+// LinkTypeOthers 无法识别的链接类型
+const LinkTypeOthers = "others"
+
+// nameHints 网盘名称关键词到链接类型的映射，按顺序匹配，用于地址无法识别时根据按钮文字或来源名称判断
+var nameHints = []struct {
+	keywords []string
+	linkType string
+}{
+	{[]string{"百度"}, "baidu"},
+	{[]string{"阿里"}, "aliyun"},
+	{[]string{"迅雷"}, "xunlei"},
+	{[]string{"夸克"}, "quark"},
+	{[]string{"天翼"}, "tianyi"},
+	{[]string{"115"}, "115"},
+	{[]string{"uc"}, "uc"},
+	{[]string{"移动", "彩云"}, "mobile"},
+	{[]string{"123"}, "123"},
+	{[]string{"pikpak"}, "pikpak"},
+	{[]string{"磁力"}, "magnet"},
+	{[]string{"电驴"}, "ed2k"},
+}
+
+// LinkType 判断链接类型，与util.GetLinkType的规则一致；地址无法识别时依次按names中的网盘名称（如按钮文字）判断，
+// 都无法识别时返回others
+func LinkType(url string, names ...string) string {
+	if linkType := util.GetLinkType(url); linkType != LinkTypeOthers {
+		return linkType
+	}
+
+	for _, name := range names {
+		lowerName := strings.ToLower(name)
+		for _, hint := range nameHints {
+			for _, keyword := range hint.keywords {
+				if strings.Contains(lowerName, keyword) {
+					return hint.linkType
+				}
+			}
+		}
+	}
+	return LinkTypeOthers
+}
+
+// IsNetDiskLink 判断是否是可识别的网盘、磁力或电驴链接
+func IsNetDiskLink(url string) bool {
+	return util.GetLinkType(url) != LinkTypeOthers
+}
+
+// ExtractPassword 提取链接的提取码，先从链接参数中提取，再从附近的文本中提取，与util.ExtractPassword的规则一致
+func ExtractPassword(content string, url string) string {
+	return util.ExtractPassword(content, url)
+}
+
+// NewLink 根据地址和附近的文本创建链接，自动识别类型和提取码；无法识别的链接返回false
+func NewLink(url string, content string) (model.Link, bool) {
+	url = strings.TrimSpace(url)
+	linkType := LinkType(url)
+	if url == "" || linkType == LinkTypeOthers {
+		return model.Link{}, false
+	}
+	return model.Link{
+		Type:     linkType,
+		URL:      url,
+		Password: ExtractPassword(content, url),
+	}, true
+}
+
+// ExtractLinks 从文本中提取所有可识别的网盘链接，并从文本中提取对应的提取码，重复的链接只保留一次
+func ExtractLinks(text string) []model.Link {
+	var links []model.Link
+	seen := make(map[string]bool)
+	for _, url := range util.ExtractNetDiskLinks(text) {
+		if seen[url] {
+			continue
+		}
+		seen[url] = true
+		if link, ok := NewLink(url, text); ok {
+			links = append(links, link)
+		}
+	}
+	return links
+}
@@ -0,0 +1,270 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"pansou/util/json"
+)
+
+// 请求默认值
+const (
+	DefaultTimeout     = 10 * time.Second
+	DefaultRetries     = 2
+	DefaultBackoffBase = 200 * time.Millisecond
+	DefaultBackoffMax  = 5 * time.Second
+
+	// maxResponseSize Bytes和JSON最多读取的响应字节数
+	maxResponseSize = 10 << 20
+)
+
+// userAgents 请求轮换使用的User-Agent
+var userAgents = []string{
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:120.0) Gecko/20100101 Firefox/120.0",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36",
+}
+
+// RandomUserAgent 随机返回一个常见浏览器的User-Agent
+func RandomUserAgent() string {
+	return userAgents[rand.Intn(len(userAgents))]
+}
+
+// Request 可重试的HTTP请求，通过链式方法设置参数：
+//
+//	body, err := sdk.Get(searchURL).Header("Referer", baseURL).Retries(3).Bytes(client)
+type Request struct {
+	method      string
+	url         string
+	body        string
+	headers     map[string]string
+	ctx         context.Context
+	timeout     time.Duration
+	retries     int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	rotateUA    bool
+}
+
+// NewRequest 创建请求，默认超时10秒、失败后重试2次、每次尝试随机选择User-Agent
+func NewRequest(method string, url string) *Request {
+	return &Request{
+		method:      method,
+		url:         url,
+		headers:     make(map[string]string),
+		ctx:         context.Background(),
+		timeout:     DefaultTimeout,
+		retries:     DefaultRetries,
+		backoffBase: DefaultBackoffBase,
+		backoffMax:  DefaultBackoffMax,
+		rotateUA:    true,
+	}
+}
+
+// Get 创建GET请求
+func Get(url string) *Request {
+	return NewRequest(http.MethodGet, url)
+}
+
+// Post 创建POST请求
+func Post(url string, contentType string, body string) *Request {
+	return NewRequest(http.MethodPost, url).Header("Content-Type", contentType).Body(body)
+}
+
+// Header 设置请求头，设置User-Agent后不再轮换
+func (r *Request) Header(key string, value string) *Request {
+	if strings.EqualFold(key, "User-Agent") {
+		r.rotateUA = false
+	}
+	r.headers[key] = value
+	return r
+}
+
+// Headers 批量设置请求头
+func (r *Request) Headers(headers map[string]string) *Request {
+	for key, value := range headers {
+		r.Header(key, value)
+	}
+	return r
+}
+
+// Body 设置请求体
+func (r *Request) Body(body string) *Request {
+	r.body = body
+	return r
+}
+
+// Context 设置请求的上下文，上下文取消时停止重试
+func (r *Request) Context(ctx context.Context) *Request {
+	r.ctx = ctx
+	return r
+}
+
+// Timeout 设置每次尝试的超时时间，0表示只使用客户端的超时时间
+func (r *Request) Timeout(timeout time.Duration) *Request {
+	r.timeout = timeout
+	return r
+}
+
+// Retries 设置失败后的重试次数，0表示不重试
+func (r *Request) Retries(retries int) *Request {
+	r.retries = retries
+	return r
+}
+
+// Backoff 设置重试的指数退避：第n次重试前等待base*2^(n-1)，最多等待max
+func (r *Request) Backoff(base time.Duration, max time.Duration) *Request {
+	r.backoffBase, r.backoffMax = base, max
+	return r
+}
+
+// Do 发送请求，网络错误、429和5xx响应按退避规则重试；返回的响应由调用方关闭，状态码可能不是200
+func (r *Request) Do(client *http.Client) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-r.ctx.Done():
+				return nil, r.ctx.Err()
+			case <-time.After(r.backoff(attempt)):
+			}
+		}
+
+		resp, err := r.attempt(client)
+		if err == nil && !isRetriableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if err == nil {
+			// 最后一次尝试时把响应交给调用方处理
+			if attempt == r.retries {
+				return resp, nil
+			}
+			resp.Body.Close()
+			lastErr = fmt.Errorf("HTTP状态码: %d", resp.StatusCode)
+			continue
+		}
+
+		lastErr = err
+		if !IsRetriableError(err) {
+			break
+		}
+	}
+	return nil, fmt.Errorf("请求%s失败: %w", r.url, lastErr)
+}
+
+// Bytes 发送请求并读取响应，状态码不是200时返回错误
+func (r *Request) Bytes(client *http.Client) ([]byte, error) {
+	resp, err := r.Do(client)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("请求%s返回状态码: %d", r.url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	return body, nil
+}
+
+// JSON 发送请求并将响应解析到v中
+func (r *Request) JSON(client *http.Client, v interface{}) error {
+	body, err := r.Bytes(client)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("解析JSON响应失败: %w", err)
+	}
+	return nil
+}
+
+// attempt 发送一次请求
+func (r *Request) attempt(client *http.Client) (*http.Response, error) {
+	ctx := r.ctx
+	cancel := context.CancelFunc(func() {})
+	if r.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+	}
+
+	var body io.Reader
+	if r.body != "" {
+		body = strings.NewReader(r.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url, body)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	for key, value := range r.headers {
+		req.Header.Set(key, value)
+	}
+	if r.rotateUA {
+		req.Header.Set("User-Agent", RandomUserAgent())
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// 读完响应体后再取消超时
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff 计算第attempt次重试前的等待时间
+func (r *Request) backoff(attempt int) time.Duration {
+	wait := r.backoffBase << uint(attempt-1)
+	if wait <= 0 || wait > r.backoffMax {
+		wait = r.backoffMax
+	}
+	return wait
+}
+
+// cancelOnClose 关闭响应体时取消请求的超时上下文
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close 关闭响应体并取消上下文
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// isRetriableStatus 判断状态码是否值得重试
+func isRetriableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// IsRetriableError 判断请求错误是否值得重试：超时、连接失败和连接中断可以重试，上下文取消和请求本身的错误不重试
+func IsRetriableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
@@ -16,11 +16,10 @@ import (
 
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
-	// 调试日志开关
-	DebugLog = false
 	// 默认每种网盘类型获取页数
 	DefaultPagesPerType = 2
 	// 最大允许每种网盘类型页数（防止过度请求）
@@ -81,6 +80,9 @@ type PageResult struct {
 	err      error
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=sdso=debug 开启
+var pluginLog = logger.ForPlugin("sdso")
+
 func init() {
 	p := &SDSOPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPlugin("sdso", 3), // 优先级3 = 普通质量数据源
@@ -88,6 +90,20 @@ func init() {
 	plugin.RegisterGlobalPlugin(p)
 }
 
+// Info 返回插件元数据
+func (p *SDSOPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: "SDSO",
+		Homepage:    "https://sdso.top",
+		Category:    plugin.CategoryNetdisk,
+		CloudTypes:  []string{"baidu", "quark", "xunlei", "aliyun"},
+		Ext: []plugin.ExtParam{
+			{Key: "pages_per_type", Type: plugin.ExtInt, Description: "每种网盘类型请求的页数，最多5页", Default: DefaultPagesPerType},
+			{Key: "pages", Type: plugin.ExtInt, Description: "总页数，平均分配给各网盘类型（兼容旧参数）"},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *SDSOPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -99,34 +115,30 @@ func (p *SDSOPlugin) Search(keyword string, ext map[string]interface{}) ([]model
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果（推荐方法）
 func (p *SDSOPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实际的搜索实现
 func (p *SDSOPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	if DebugLog {
-		fmt.Printf("[%s] 开始搜索，关键词: %s\n", p.Name(), keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索，关键词: %s", keyword)
 	}
 
 	// 1. 从扩展参数中获取每种网盘类型的页数配置
 	pagesPerType := DefaultPagesPerType
 	if ext != nil {
-		if pages, ok := ext["pages_per_type"].(int); ok && pages > 0 {
+		if pages, ok := plugin.GetInt(ext, "pages_per_type"); ok && pages > 0 {
 			pagesPerType = pages
 			if pagesPerType > MaxAllowedPagesPerType {
 				pagesPerType = MaxAllowedPagesPerType
-				if DebugLog {
-					fmt.Printf("[%s] 每种网盘类型页数限制在最大值: %d\n", p.Name(), MaxAllowedPagesPerType)
+				if log.DebugEnabled() {
+					log.Debugf("每种网盘类型页数限制在最大值: %d", MaxAllowedPagesPerType)
 				}
 			}
-		} else if pagesFloat, ok := ext["pages_per_type"].(float64); ok && pagesFloat > 0 {
-			pagesPerType = int(pagesFloat)
-			if pagesPerType > MaxAllowedPagesPerType {
-				pagesPerType = MaxAllowedPagesPerType
-			}
 		}
 		// 保持向后兼容：如果设置了 pages 参数，则平均分配给各网盘类型
-		if pages, ok := ext["pages"].(int); ok && pages > 0 {
+		if pages, ok := plugin.GetInt(ext, "pages"); ok && pages > 0 {
 			pagesPerType = pages / len(SupportedCloudTypes)
 			if pagesPerType == 0 {
 				pagesPerType = 1
@@ -138,9 +150,8 @@ func (p *SDSOPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 	}
 
 	totalTasks := len(SupportedCloudTypes) * pagesPerType
-	if DebugLog {
-		fmt.Printf("[%s] 将分别搜索 %d 种网盘类型，每种 %d 页，总计 %d 个并发任务\n", 
-			p.Name(), len(SupportedCloudTypes), pagesPerType, totalTasks)
+	if log.DebugEnabled() {
+		log.Debugf("将分别搜索 %d 种网盘类型，每种 %d 页，总计 %d 个并发任务", len(SupportedCloudTypes), pagesPerType, totalTasks)
 	}
 
 	// 2. 并发请求多个网盘类型的多页数据
@@ -180,8 +191,8 @@ func (p *SDSOPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 	for pageResult := range resultsChan {
 		if pageResult.err != nil {
 			errorTasks++
-			if DebugLog {
-				fmt.Printf("[%s] %s网盘第%d页请求失败: %v\n", p.Name(), pageResult.fromType, pageResult.pageNo, pageResult.err)
+			if log.DebugEnabled() {
+				log.Debugf("%s网盘第%d页请求失败: %v", pageResult.fromType, pageResult.pageNo, pageResult.err)
 			}
 			continue
 		}
@@ -189,16 +200,15 @@ func (p *SDSOPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 		successTasks++
 		allResults = append(allResults, pageResult.results...)
 		resultsByType[pageResult.fromType] += len(pageResult.results)
-		if DebugLog {
-			fmt.Printf("[%s] %s网盘第%d页成功获取 %d 个结果\n", p.Name(), pageResult.fromType, pageResult.pageNo, len(pageResult.results))
+		if log.DebugEnabled() {
+			log.Debugf("%s网盘第%d页成功获取 %d 个结果", pageResult.fromType, pageResult.pageNo, len(pageResult.results))
 		}
 	}
 
-	if DebugLog {
-		fmt.Printf("[%s] 分类搜索完成: 成功%d任务, 失败%d任务, 总结果%d个\n", 
-			p.Name(), successTasks, errorTasks, len(allResults))
+	if log.DebugEnabled() {
+		log.Debugf("分类搜索完成: 成功%d任务, 失败%d任务, 总结果%d个", successTasks, errorTasks, len(allResults))
 		for cloudType, count := range resultsByType {
-			fmt.Printf("[%s]   - %s网盘: %d个结果\n", p.Name(), cloudType, count)
+			log.Debugf("- %s网盘: %d个结果", cloudType, count)
 		}
 	}
 
@@ -211,9 +221,8 @@ func (p *SDSOPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 	beforeFilterCount := len(allResults)
 	filteredResults := plugin.FilterResultsByKeyword(allResults, keyword)
 	
-	if DebugLog {
-		fmt.Printf("[%s] 关键词过滤: 过滤前%d项 -> 过滤后%d项\n", 
-			p.Name(), beforeFilterCount, len(filteredResults))
+	if log.DebugEnabled() {
+		log.Debugf("关键词过滤: 过滤前%d项 -> 过滤后%d项", beforeFilterCount, len(filteredResults))
 	}
 
 	return filteredResults, nil
@@ -224,8 +233,8 @@ func (p *SDSOPlugin) fetchSinglePageWithType(client *http.Client, keyword string
 	// 1. 构建搜索URL，添加from参数指定网盘类型
 	searchURL := fmt.Sprintf("https://sdso.top/api/sd/search?name=%s&pageNo=%d&from=%s", 
 		url.QueryEscape(keyword), pageNo, fromType)
-	if DebugLog {
-		fmt.Printf("[%s] 请求%s网盘第%d页: %s\n", p.Name(), fromType, pageNo, searchURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("请求%s网盘第%d页: %s", fromType, pageNo, searchURL)
 	}
 
 	// 2. 创建带超时的上下文
@@ -268,8 +277,8 @@ func (p *SDSOPlugin) fetchSinglePageWithType(client *http.Client, keyword string
 		return nil, fmt.Errorf("[%s] %s网盘第%d页API错误: %s", p.Name(), fromType, pageNo, apiResp.Msg)
 	}
 
-	if DebugLog {
-		fmt.Printf("[%s] %s网盘第%d页获取到 %d 个原始结果\n", p.Name(), fromType, pageNo, len(apiResp.Data.List))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("%s网盘第%d页获取到 %d 个原始结果", fromType, pageNo, len(apiResp.Data.List))
 	}
 
 	// 9. 转换为标准格式
@@ -281,8 +290,8 @@ func (p *SDSOPlugin) fetchSinglePageWithType(client *http.Client, keyword string
 		// 解密网盘链接
 		decryptedURL, err := DecryptURL(item.URL)
 		if err != nil {
-			if DebugLog {
-				fmt.Printf("[%s] %s网盘第%d页第%d项解密失败: %v\n", p.Name(), fromType, pageNo, i+1, err)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("%s网盘第%d页第%d项解密失败: %v", fromType, pageNo, i+1, err)
 			}
 			skippedCount++
 			continue
@@ -290,8 +299,8 @@ func (p *SDSOPlugin) fetchSinglePageWithType(client *http.Client, keyword string
 
 		// 验证是否为有效的网盘链接
 		if !isValidPanURL(decryptedURL) {
-			if DebugLog {
-				fmt.Printf("[%s] %s网盘第%d页第%d项无效链接: %s\n", p.Name(), fromType, pageNo, i+1, decryptedURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("%s网盘第%d页第%d项无效链接: %s", fromType, pageNo, i+1, decryptedURL)
 			}
 			skippedCount++
 			continue
@@ -335,9 +344,8 @@ func (p *SDSOPlugin) fetchSinglePageWithType(client *http.Client, keyword string
 		processedCount++
 	}
 
-	if DebugLog {
-		fmt.Printf("[%s] %s网盘第%d页处理完成: 原始%d项 -> 有效%d项 -> 跳过%d项\n", 
-			p.Name(), fromType, pageNo, len(apiResp.Data.List), processedCount, skippedCount)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("%s网盘第%d页处理完成: 原始%d项 -> 有效%d项 -> 跳过%d项", fromType, pageNo, len(apiResp.Data.List), processedCount, skippedCount)
 	}
 
 	return results, nil
@@ -0,0 +1,105 @@
+package plugin
+
+import (
+	"context"
+	"time"
+
+	"pansou/model"
+)
+
+// extSearchCallKey ext中保存搜索调用状态的保留键
+// 插件的Search(keyword, ext)签名不含调用状态，借助ext将其传递到AsyncSearchWithResult
+const extSearchCallKey = "__search_call"
+
+// SearchCall 单次搜索调用的状态
+// 插件是全局单例，关键词、主缓存键等必须按调用传递，保存在插件字段上会被并发搜索互相覆盖
+type SearchCall struct {
+	Ctx          context.Context        // 请求上下文，取消时中断插件请求
+	Keyword      string                 // 搜索关键词
+	MainCacheKey string                 // 主缓存键，插件结果写入该键
+	Ext          map[string]interface{} // 扩展参数（不含保留键）
+	Deadline     time.Time              // 请求截止时间，零值表示无截止时间
+}
+
+// MainCacheUpdater 主缓存更新函数，参数为调用状态、原始结果、缓存有效期和是否为最终结果
+type MainCacheUpdater func(call *SearchCall, results []model.SearchResult, ttl time.Duration, isFinal bool) error
+
+// NewSearchCall 创建搜索调用状态
+func NewSearchCall(ctx context.Context, keyword string, mainCacheKey string, ext map[string]interface{}) *SearchCall {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	deadline, _ := ctx.Deadline()
+	return &SearchCall{
+		Ctx:          ctx,
+		Keyword:      keyword,
+		MainCacheKey: mainCacheKey,
+		Ext:          withoutSearchCall(ext),
+		Deadline:     deadline,
+	}
+}
+
+// SearchCallFromExt 从ext中取出搜索调用状态，不存在时返回nil
+func SearchCallFromExt(ext map[string]interface{}) *SearchCall {
+	if call, ok := ext[extSearchCallKey].(*SearchCall); ok {
+		return call
+	}
+	return nil
+}
+
+// SearchContextFromExt 从ext中取出请求上下文，不存在时返回context.Background()
+func SearchContextFromExt(ext map[string]interface{}) context.Context {
+	if call := SearchCallFromExt(ext); call != nil {
+		return call.Ctx
+	}
+	return context.Background()
+}
+
+// ExtParams 返回携带本次调用状态的ext副本，传给插件的搜索函数
+func (c *SearchCall) ExtParams() map[string]interface{} {
+	ext := make(map[string]interface{}, len(c.Ext)+1)
+	for k, v := range c.Ext {
+		ext[k] = v
+	}
+	ext[extSearchCallKey] = c
+	return ext
+}
+
+// withContext 返回使用新上下文的调用状态副本
+func (c *SearchCall) withContext(ctx context.Context) *SearchCall {
+	callCopy := *c
+	callCopy.Ctx = ctx
+	callCopy.Deadline, _ = ctx.Deadline()
+	return &callCopy
+}
+
+// detached 返回不随请求取消的调用状态副本，用于后台刷新等任务
+func (c *SearchCall) detached() *SearchCall {
+	return c.withContext(context.WithoutCancel(c.Ctx))
+}
+
+// searchCallForKeyword 根据ext中的调用状态创建本次调用，沿用请求上下文和主缓存键
+// ext中没有调用状态时（如插件被直接调用），不写入主缓存
+func searchCallForKeyword(keyword string, ext map[string]interface{}) *SearchCall {
+	if call := SearchCallFromExt(ext); call != nil {
+		return NewSearchCall(call.Ctx, keyword, call.MainCacheKey, ext)
+	}
+	return NewSearchCall(context.Background(), keyword, "", ext)
+}
+
+// withoutSearchCall 返回去掉保留键的ext，ext为nil时返回空map
+func withoutSearchCall(ext map[string]interface{}) map[string]interface{} {
+	if ext == nil {
+		return make(map[string]interface{})
+	}
+	if _, ok := ext[extSearchCallKey]; !ok {
+		return ext
+	}
+	extWithoutCall := make(map[string]interface{}, len(ext))
+	for k, v := range ext {
+		if k != extSearchCallKey {
+			extWithoutCall[k] = v
+		}
+	}
+	return extWithoutCall
+}
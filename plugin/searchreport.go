@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"context"
+	"sync"
+)
+
+// searchReportKey 上下文中保存插件调用执行情况的键
+type searchReportKey struct{}
+
+// SearchReport 单次插件调用的执行情况，由服务层通过上下文传入，用于插件健康统计
+// 命中插件级缓存的调用没有访问插件站点，不能说明插件是否可用；
+// 响应超时转入后台的搜索要等后台完成后才知道成败
+type SearchReport struct {
+	mutex    sync.Mutex
+	cacheHit bool
+	pending  int   // 前台调用和转入后台的搜索中尚未完成的数量
+	err      error // 第一个错误
+	onDone   func(cacheHit bool, err error)
+}
+
+// WithSearchReport 返回携带执行情况记录的上下文
+// 调用方在插件调用返回后调用Done，onDone在前台调用和转入后台的搜索全部完成后调用一次
+func WithSearchReport(ctx context.Context, onDone func(cacheHit bool, err error)) (context.Context, *SearchReport) {
+	report := &SearchReport{pending: 1, onDone: onDone}
+	return context.WithValue(ctx, searchReportKey{}, report), report
+}
+
+// searchReportFromContext 从上下文中取出执行情况记录，不存在时返回nil
+func searchReportFromContext(ctx context.Context) *SearchReport {
+	report, _ := ctx.Value(searchReportKey{}).(*SearchReport)
+	return report
+}
+
+// Done 记录前台调用完成，err为插件调用返回的错误
+func (r *SearchReport) Done(err error) {
+	r.finish(err)
+}
+
+// markCacheHit 记录本次调用命中插件级缓存
+func (r *SearchReport) markCacheHit() {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	r.cacheHit = true
+	r.mutex.Unlock()
+}
+
+// track 返回跟踪一次搜索函数执行的记录器
+func (r *SearchReport) track() *searchTrack {
+	if r == nil {
+		return nil
+	}
+	return &searchTrack{report: r}
+}
+
+// finish 记录一项调用完成，全部完成时调用onDone
+func (r *SearchReport) finish(err error) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	if r.err == nil {
+		r.err = err
+	}
+	r.pending--
+	done := r.pending == 0
+	cacheHit, finalErr := r.cacheHit, r.err
+	r.mutex.Unlock()
+
+	if done && r.onDone != nil {
+		r.onDone(cacheHit, finalErr)
+	}
+}
+
+// searchTrack 跟踪一次搜索函数的执行，timeout和done的调用顺序不限
+type searchTrack struct {
+	report   *SearchReport
+	mutex    sync.Mutex
+	timedOut bool
+	finished bool
+	err      error
+}
+
+// timeout 响应超时，搜索转入后台继续；搜索已完成时直接计入其结果
+func (t *searchTrack) timeout() {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.timedOut {
+		return
+	}
+	t.timedOut = true
+
+	t.report.mutex.Lock()
+	if t.finished {
+		if t.report.err == nil {
+			t.report.err = t.err
+		}
+	} else {
+		t.report.pending++
+	}
+	t.report.mutex.Unlock()
+}
+
+// done 搜索函数返回，已转入后台时计入后台搜索的结果
+func (t *searchTrack) done(err error) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	if t.finished {
+		t.mutex.Unlock()
+		return
+	}
+	t.finished = true
+	t.err = err
+	timedOut := t.timedOut
+	t.mutex.Unlock()
+
+	if timedOut {
+		t.report.finish(err)
+	}
+}
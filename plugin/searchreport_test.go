@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestSearchReport 前台调用和转入后台的搜索全部完成后才汇报结果
+func TestSearchReport(t *testing.T) {
+	errBackground := errors.New("后台搜索失败")
+
+	tests := []struct {
+		name         string
+		run          func(report *SearchReport)
+		wantCacheHit bool
+		wantErr      error
+	}{
+		{
+			name: "前台完成",
+			run: func(report *SearchReport) {
+				report.track().done(nil)
+				report.Done(nil)
+			},
+		},
+		{
+			name: "命中插件级缓存",
+			run: func(report *SearchReport) {
+				report.markCacheHit()
+				report.Done(nil)
+			},
+			wantCacheHit: true,
+		},
+		{
+			name: "响应超时后后台失败",
+			run: func(report *SearchReport) {
+				track := report.track()
+				track.timeout()
+				report.Done(nil)
+				track.done(errBackground)
+			},
+			wantErr: errBackground,
+		},
+		{
+			name: "搜索完成后才响应超时",
+			run: func(report *SearchReport) {
+				track := report.track()
+				track.done(errBackground)
+				track.timeout()
+				report.Done(nil)
+			},
+			wantErr: errBackground,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var gotCacheHit bool
+			var gotErr error
+			ctx, report := WithSearchReport(context.Background(), func(cacheHit bool, err error) {
+				calls++
+				gotCacheHit, gotErr = cacheHit, err
+			})
+			if searchReportFromContext(ctx) != report {
+				t.Fatal("上下文中没有执行情况记录")
+			}
+
+			tt.run(report)
+
+			if calls != 1 {
+				t.Fatalf("onDone调用了%d次，期望1次", calls)
+			}
+			if gotCacheHit != tt.wantCacheHit || gotErr != tt.wantErr {
+				t.Errorf("got (%v, %v), want (%v, %v)", gotCacheHit, gotErr, tt.wantCacheHit, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestSearchReportWaitsForBackground 后台搜索未完成时不汇报结果
+func TestSearchReportWaitsForBackground(t *testing.T) {
+	called := false
+	_, report := WithSearchReport(context.Background(), func(bool, error) { called = true })
+
+	track := report.track()
+	track.timeout()
+	report.Done(nil)
+	if called {
+		t.Fatal("后台搜索未完成时不应汇报结果")
+	}
+	track.done(nil)
+	if !called {
+		t.Fatal("后台搜索完成后应汇报结果")
+	}
+}
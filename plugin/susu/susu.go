@@ -3,9 +3,6 @@ package susu
 import (
 	"encoding/base64"
 	"fmt"
-	"io"
-	"math/rand"
-	"net"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -16,66 +13,28 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/plugin/sdk"
 	"pansou/util/json"
 )
 
-// 缓存相关变量
+// 缓存相关变量，缓存项1小时后过期
 var (
 	// 帖子ID缓存
-	postIDCache = sync.Map{}
+	postIDCache = sdk.NewTTLCache(1 * time.Hour)
 	
 	// 按钮列表缓存
-	buttonListCache = sync.Map{}
+	buttonListCache = sdk.NewTTLCache(1 * time.Hour)
 	
 	// 按钮详情缓存
-	buttonDetailCache = sync.Map{}
+	buttonDetailCache = sdk.NewTTLCache(1 * time.Hour)
 	
 	// JWT解析结果缓存
-	jwtDecodeCache = sync.Map{}
-	
-	// 链接类型判断缓存
-	linkTypeCache = sync.Map{}
+	jwtDecodeCache = sdk.NewTTLCache(1 * time.Hour)
 )
 
-// 常用UA列表
-var userAgents = []string{
-	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36",
-	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36",
-	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15",
-	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:120.0) Gecko/20100101 Firefox/120.0",
-	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36",
-}
-
-// 初始化随机数种子
 func init() {
 	// 注册插件
 	plugin.RegisterGlobalPlugin(NewSusuAsyncPlugin())
-	
-	// 启动缓存清理
-	go startCacheCleaner()
-	
-	// 初始化随机数种子
-	rand.Seed(time.Now().UnixNano())
-}
-
-// startCacheCleaner 定期清理缓存
-func startCacheCleaner() {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
-	
-	for range ticker.C {
-		// 清空所有缓存
-		postIDCache = sync.Map{}
-		buttonListCache = sync.Map{}
-		buttonDetailCache = sync.Map{}
-		jwtDecodeCache = sync.Map{}
-		linkTypeCache = sync.Map{}
-	}
-}
-
-// getRandomUA 获取随机UA
-func getRandomUA() string {
-	return userAgents[rand.Intn(len(userAgents))]
 }
 
 const (
@@ -87,6 +46,9 @@ const (
 	ButtonDetailURL = "https://susuifa.com/wp-json/b2/v1/getDownloadPageData?post_id=%s&index=0&i=%d&guest="
 	// 最大重试次数
 	MaxRetries = 0
+	// 重试退避的初始等待时间和最大等待时间
+	BackoffBase = 500 * time.Millisecond
+	BackoffMax  = 5 * time.Second
 	// 最大并发数
 	MaxConcurrency = 100
 )
@@ -114,7 +76,7 @@ func (p *SusuAsyncPlugin) Search(keyword string, ext map[string]interface{}) ([]
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *SusuAsyncPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.doSearch, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.doSearch, ext)
 }
 
 // doSearch 实际的搜索实现
@@ -122,25 +84,18 @@ func (p *SusuAsyncPlugin) doSearch(client *http.Client, keyword string, ext map[
 	// 构建搜索URL
 	searchURL := fmt.Sprintf(SearchURL, url.QueryEscape(keyword))
 	
-	// 发送请求
-	req, err := http.NewRequest("GET", searchURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
-	}
-	
-	// 设置请求头
-	req.Header.Set("User-Agent", getRandomUA())
-	req.Header.Set("Referer", "https://susuifa.com/")
-	
 	// 发送请求（带重试）
-	resp, err := p.doRequestWithRetry(client, req, MaxRetries)
+	body, err := sdk.Get(searchURL).
+		Header("Referer", "https://susuifa.com/").
+		Retries(MaxRetries).
+		Backoff(BackoffBase, BackoffMax).
+		Bytes(client)
 	if err != nil {
 		return nil, fmt.Errorf("请求失败: %w", err)
 	}
-	defer resp.Body.Close()
 	
 	// 解析HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, err := sdk.ParseHTML(body)
 	if err != nil {
 		return nil, fmt.Errorf("解析HTML失败: %w", err)
 	}
@@ -280,7 +235,7 @@ func (p *SusuAsyncPlugin) extractPostID(s *goquery.Selection) string {
 	cacheKey := fmt.Sprintf("postid:%x", md5sum(html))
 	
 	// 检查缓存
-	if cachedID, ok := postIDCache.Load(cacheKey); ok {
+	if cachedID, ok := postIDCache.Get(cacheKey); ok {
 		return cachedID.(string)
 	}
 	
@@ -288,7 +243,7 @@ func (p *SusuAsyncPlugin) extractPostID(s *goquery.Selection) string {
 	itemID, exists := s.Attr("id")
 	if exists && strings.HasPrefix(itemID, "item-") {
 		postID := strings.TrimPrefix(itemID, "item-")
-		postIDCache.Store(cacheKey, postID)
+		postIDCache.Set(cacheKey, postID)
 		return postID
 	}
 	
@@ -299,7 +254,7 @@ func (p *SusuAsyncPlugin) extractPostID(s *goquery.Selection) string {
 		matches := re.FindStringSubmatch(href)
 		if len(matches) > 1 {
 			postID := matches[1]
-			postIDCache.Store(cacheKey, postID)
+			postIDCache.Set(cacheKey, postID)
 			return postID
 		}
 	}
@@ -310,7 +265,7 @@ func (p *SusuAsyncPlugin) extractPostID(s *goquery.Selection) string {
 // getLinks 获取网盘链接
 func (p *SusuAsyncPlugin) getLinks(client *http.Client, postID string) ([]model.Link, error) {
 	// 检查缓存
-	if cachedLinks, ok := buttonListCache.Load(postID); ok {
+	if cachedLinks, ok := buttonListCache.Get(postID); ok {
 		return cachedLinks.([]model.Link), nil
 	}
 	
@@ -352,7 +307,7 @@ func (p *SusuAsyncPlugin) getLinks(client *http.Client, postID string) ([]model.
 	}
 	
 	// 缓存结果
-	buttonListCache.Store(postID, links)
+	buttonListCache.Set(postID, links)
 	
 	return links, nil
 }
@@ -363,36 +318,22 @@ func (p *SusuAsyncPlugin) getButtonDetail(client *http.Client, postID string, in
 	cacheKey := fmt.Sprintf("%s:%d", postID, index)
 	
 	// 检查缓存
-	if cachedLink, ok := buttonDetailCache.Load(cacheKey); ok {
+	if cachedLink, ok := buttonDetailCache.Get(cacheKey); ok {
 		return cachedLink.(model.Link), nil
 	}
 	
 	// 构建获取按钮详情的URL
 	buttonDetailURL := fmt.Sprintf(ButtonDetailURL, postID, index)
 	
-	// 发送请求
-	req, err := http.NewRequest("POST", buttonDetailURL, nil)
-	if err != nil {
-		return model.Link{}, fmt.Errorf("创建请求失败: %w", err)
-	}
-	
-	// 设置请求头
-	req.Header.Set("User-Agent", getRandomUA())
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Referer", fmt.Sprintf("https://susuifa.com/download?post_id=%s&index=0&i=%d", postID, index))
-	
 	// 发送请求（带重试）
-	resp, err := p.doRequestWithRetry(client, req, MaxRetries)
+	respBody, err := sdk.Post(buttonDetailURL, "application/json", "").
+		Header("Referer", fmt.Sprintf("https://susuifa.com/download?post_id=%s&index=0&i=%d", postID, index)).
+		Retries(MaxRetries).
+		Backoff(BackoffBase, BackoffMax).
+		Bytes(client)
 	if err != nil {
 		return model.Link{}, fmt.Errorf("请求失败: %w", err)
 	}
-	defer resp.Body.Close()
-	
-	// 读取响应体
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return model.Link{}, fmt.Errorf("读取响应失败: %w", err)
-	}
 	
 	// 解析响应
 	var buttonDetail struct {
@@ -419,11 +360,11 @@ func (p *SusuAsyncPlugin) getButtonDetail(client *http.Client, postID string, in
 	// 创建链接
 	link := model.Link{
 		URL:  realURL,
-		Type: p.determineLinkType(realURL, buttonDetail.Button.Name),
+		Type: sdk.LinkType(realURL, buttonDetail.Button.Name),
 	}
 	
 	// 缓存结果
-	buttonDetailCache.Store(cacheKey, link)
+	buttonDetailCache.Set(cacheKey, link)
 	
 	return link, nil
 }
@@ -431,7 +372,7 @@ func (p *SusuAsyncPlugin) getButtonDetail(client *http.Client, postID string, in
 // decodeJWTURL 解析JWT token获取真实链接
 func (p *SusuAsyncPlugin) decodeJWTURL(jwtToken string) (string, error) {
 	// 检查缓存
-	if cachedURL, ok := jwtDecodeCache.Load(jwtToken); ok {
+	if cachedURL, ok := jwtDecodeCache.Get(jwtToken); ok {
 		return cachedURL.(string), nil
 	}
 	
@@ -458,131 +399,11 @@ func (p *SusuAsyncPlugin) decodeJWTURL(jwtToken string) (string, error) {
 	}
 	
 	// 缓存结果
-	jwtDecodeCache.Store(jwtToken, payloadData.Data.URL)
+	jwtDecodeCache.Set(jwtToken, payloadData.Data.URL)
 	
 	return payloadData.Data.URL, nil
 }
 
-// determineLinkType 根据URL和名称确定链接类型
-func (p *SusuAsyncPlugin) determineLinkType(url, name string) string {
-	// 生成缓存键
-	cacheKey := fmt.Sprintf("%s:%s", url, name)
-	
-	// 检查缓存
-	if cachedType, ok := linkTypeCache.Load(cacheKey); ok {
-		return cachedType.(string)
-	}
-	
-	lowerURL := strings.ToLower(url)
-	lowerName := strings.ToLower(name)
-	
-	var linkType string
-	
-	// 根据URL判断
-	switch {
-	case strings.Contains(lowerURL, "pan.baidu.com"):
-		linkType = "baidu"
-	case strings.Contains(lowerURL, "alipan.com") || strings.Contains(lowerURL, "aliyundrive.com"):
-		linkType = "aliyun"
-	case strings.Contains(lowerURL, "pan.xunlei.com"):
-		linkType = "xunlei"
-	case strings.Contains(lowerURL, "pan.quark.cn"):
-		linkType = "quark"
-	case strings.Contains(lowerURL, "cloud.189.cn"):
-		linkType = "tianyi"
-	case strings.Contains(lowerURL, "115.com"):
-		linkType = "115"
-	case strings.Contains(lowerURL, "drive.uc.cn"):
-			linkType = "uc"
-	case strings.Contains(lowerURL, "caiyun.139.com"):
-		linkType = "mobile"
-	case strings.Contains(lowerURL, "123pan.com"):
-		linkType = "123"
-	case strings.Contains(lowerURL, "mypikpak.com"):
-		linkType = "pikpak"
-	default:
-		// 根据名称判断
-		switch {
-		case strings.Contains(lowerName, "百度"):
-			linkType = "baidu"
-		case strings.Contains(lowerName, "阿里"):
-			linkType = "aliyun"
-		case strings.Contains(lowerName, "迅雷"):
-			linkType = "xunlei"
-		case strings.Contains(lowerName, "夸克"):
-			linkType = "quark"
-		case strings.Contains(lowerName, "天翼"):
-			linkType = "tianyi"
-		case strings.Contains(lowerName, "115"):
-			linkType = "115"
-		case strings.Contains(lowerName, "uc"):
-			linkType = "uc"
-		case strings.Contains(lowerName, "移动") || strings.Contains(lowerName, "彩云"):
-			linkType = "mobile"
-		case strings.Contains(lowerName, "123"):
-			linkType = "123"
-		case strings.Contains(lowerName, "pikpak"):
-			linkType = "pikpak"
-		default:
-			linkType = "others"
-		}
-	}
-	
-	// 缓存结果
-	linkTypeCache.Store(cacheKey, linkType)
-	
-	return linkType
-}
-
-// doRequestWithRetry 发送HTTP请求并支持重试
-func (p *SusuAsyncPlugin) doRequestWithRetry(client *http.Client, req *http.Request, maxRetries int) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-	
-	for i := 0; i <= maxRetries; i++ {
-		// 如果不是第一次尝试，等待一段时间
-		if i > 0 {
-			// 指数退避算法
-			backoff := time.Duration(1<<uint(i-1)) * 500 * time.Millisecond
-			if backoff > 5*time.Second {
-				backoff = 5 * time.Second
-			}
-			time.Sleep(backoff)
-		}
-		
-		// 克隆请求，避免重用同一个请求对象
-		reqClone := req.Clone(req.Context())
-		
-		// 发送请求
-		resp, err = client.Do(reqClone)
-		
-		// 如果请求成功或者是不可重试的错误，则退出循环
-		if err == nil || !isRetriableError(err) {
-			break
-		}
-	}
-	
-	return resp, err
-}
-
-// isRetriableError 判断错误是否可以重试
-func isRetriableError(err error) bool {
-	if err == nil {
-		return false
-	}
-	
-	// 判断是否是网络错误或超时错误
-	if netErr, ok := err.(net.Error); ok {
-		return netErr.Timeout() || netErr.Temporary()
-	}
-	
-	// 其他可能需要重试的错误类型
-	errStr := err.Error()
-	return strings.Contains(errStr, "connection refused") ||
-		   strings.Contains(errStr, "connection reset") ||
-		   strings.Contains(errStr, "EOF")
-}
-
 // md5sum 计算字符串的MD5值的简化版本
 func md5sum(s string) uint32 {
 	h := uint32(0)
@@ -108,6 +108,19 @@ func NewThePirateBayPlugin() *ThePirateBayPlugin {
 	}
 }
 
+// Info 返回插件元数据
+func (p *ThePirateBayPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		DisplayName: "海盗湾",
+		Homepage:    "https://tpirbay.xyz",
+		Category:    plugin.CategoryMagnet,
+		CloudTypes:  []string{"magnet"},
+		Ext: []plugin.ExtParam{
+			{Key: "title_en", Type: plugin.ExtString, Description: "英文标题，设置后代替关键词搜索"},
+		},
+	}
+}
+
 // 初始化插件
 func init() {
 	plugin.RegisterGlobalPlugin(NewThePirateBayPlugin())
@@ -139,7 +152,7 @@ func (p *ThePirateBayPlugin) Search(keyword string, ext map[string]interface{})
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *ThePirateBayPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实现具体的搜索逻辑（支持分页）
@@ -152,10 +165,8 @@ func (p *ThePirateBayPlugin) searchImpl(client *http.Client, keyword string, ext
 	// 检查是否提供了英文标题参数 - 对英文搜索更友好
 	searchKeyword := keyword
 	if ext != nil {
-		if titleEn, exists := ext["title_en"]; exists {
-			if titleEnStr, ok := titleEn.(string); ok && titleEnStr != "" {
-				searchKeyword = titleEnStr
-			}
+		if titleEn, ok := plugin.GetString(ext, "title_en"); ok && titleEn != "" {
+			searchKeyword = titleEn
 		}
 	}
 	
@@ -3,7 +3,6 @@ package u3c3
 import (
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -13,6 +12,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -25,15 +25,16 @@ const (
 // U3c3Plugin U3C3插件
 type U3c3Plugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode bool
 	search2   string // 缓存的search2参数
 	lastSync  time.Time
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=u3c3=debug 开启
+var pluginLog = logger.ForPlugin("u3c3")
+
 func init() {
 	p := &U3c3Plugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPluginWithFilter("u3c3", 5, true),
-		debugMode:       false,
 	}
 	plugin.RegisterGlobalPlugin(p)
 }
@@ -49,15 +50,16 @@ func (p *U3c3Plugin) Search(keyword string, ext map[string]interface{}) ([]model
 
 // SearchWithResult 搜索并返回详细结果
 func (p *U3c3Plugin) SearchWithResult(keyword string, ext map[string]interface{}) (*model.PluginSearchResult, error) {
-	if p.debugMode {
-		log.Printf("[U3C3] 开始搜索: %s", keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
 	}
 
 	// 第一步：获取search2参数
 	search2, err := p.getSearch2Parameter()
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[U3C3] 获取search2参数失败: %v", err)
+		if log.DebugEnabled() {
+			log.Debugf("获取search2参数失败: %v", err)
 		}
 		return nil, fmt.Errorf("获取search2参数失败: %v", err)
 	}
@@ -65,14 +67,14 @@ func (p *U3c3Plugin) SearchWithResult(keyword string, ext map[string]interface{}
 	// 第二步：执行搜索
 	results, err := p.doSearch(keyword, search2)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[U3C3] 搜索失败: %v", err)
+		if log.DebugEnabled() {
+			log.Debugf("搜索失败: %v", err)
 		}
 		return nil, err
 	}
 
-	if p.debugMode {
-		log.Printf("[U3C3] 搜索完成，获得 %d 个结果", len(results))
+	if log.DebugEnabled() {
+		log.Debugf("搜索完成，获得 %d 个结果", len(results))
 	}
 
 	// 应用关键词过滤
@@ -94,8 +96,8 @@ func (p *U3c3Plugin) getSearch2Parameter() (string, error) {
 		return p.search2, nil
 	}
 
-	if p.debugMode {
-		log.Printf("[U3C3] 正在获取search2参数...")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("正在获取search2参数...")
 	}
 
 	client := &http.Client{
@@ -152,8 +154,8 @@ func (p *U3c3Plugin) getSearch2Parameter() (string, error) {
 	p.search2 = search2
 	p.lastSync = time.Now()
 
-	if p.debugMode {
-		log.Printf("[U3C3] 获取到search2参数: %s", search2)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("获取到search2参数: %s", search2)
 	}
 
 	return search2, nil
@@ -177,8 +179,8 @@ func (p *U3c3Plugin) extractSearch2FromHTML(html string) string {
 			re := regexp.MustCompile(`var\s+nmefafej\s*=\s*"([^"]+)"`)
 			matches := re.FindStringSubmatch(line)
 			if len(matches) > 1 && len(matches[1]) > 5 {
-				if p.debugMode {
-					log.Printf("[U3C3] 提取到search2参数: %s (来自行: %s)", matches[1], line)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("提取到search2参数: %s (来自行: %s)", matches[1], line)
 				}
 				return matches[1]
 			}
@@ -190,8 +192,8 @@ func (p *U3c3Plugin) extractSearch2FromHTML(html string) string {
 				if end != -1 && end > 5 {
 					candidate := line[start+1 : start+1+end]
 					if len(candidate) > 5 {
-						if p.debugMode {
-							log.Printf("[U3C3] 备用方案提取search2: %s (来自行: %s)", candidate, line)
+						if pluginLog.DebugEnabled() {
+							pluginLog.Debugf("备用方案提取search2: %s (来自行: %s)", candidate, line)
 						}
 						return candidate
 					}
@@ -200,8 +202,8 @@ func (p *U3c3Plugin) extractSearch2FromHTML(html string) string {
 		}
 	}
 
-	if p.debugMode {
-		log.Printf("[U3C3] 未能找到search2参数")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("未能找到search2参数")
 	}
 	return ""
 }
@@ -212,8 +214,8 @@ func (p *U3c3Plugin) doSearch(keyword, search2 string) ([]model.SearchResult, er
 	encodedKeyword := url.QueryEscape(keyword)
 	searchURL := fmt.Sprintf("%s/?search2=%s&search=%s", BaseURL, search2, encodedKeyword)
 
-	if p.debugMode {
-		log.Printf("[U3C3] 搜索URL: %s", searchURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("搜索URL: %s", searchURL)
 	}
 
 	client := &http.Client{
@@ -352,8 +354,8 @@ func (p *U3c3Plugin) parseSearchResults(html string) ([]model.SearchResult, erro
 		results = append(results, result)
 	})
 
-	if p.debugMode {
-		log.Printf("[U3C3] 解析到 %d 个搜索结果", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析到 %d 个搜索结果", len(results))
 	}
 
 	return results, nil
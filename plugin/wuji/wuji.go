@@ -14,6 +14,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 // 常量定义
@@ -83,6 +84,18 @@ func (p *WujiPlugin) Description() string {
 	return "ØMagnet 无极磁链 - 磁力链接搜索引擎"
 }
 
+// Info 返回插件元数据
+func (p *WujiPlugin) Info() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		Homepage:   BaseURL,
+		Category:   plugin.CategoryMagnet,
+		CloudTypes: []string{"magnet"},
+		Ext: []plugin.ExtParam{
+			{Key: "search", Type: plugin.ExtString, Description: "过滤结果时使用的关键词，默认使用搜索关键词"},
+		},
+	}
+}
+
 // Search 执行搜索并返回结果（兼容性方法）
 func (p *WujiPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
 	result, err := p.SearchWithResult(keyword, ext)
@@ -94,7 +107,7 @@ func (p *WujiPlugin) Search(keyword string, ext map[string]interface{}) ([]model
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *WujiPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 实际的搜索实现
@@ -157,10 +170,8 @@ func (p *WujiPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 	
 	// 4. 关键词过滤
 	searchKeyword := keyword
-	if searchParam, ok := ext["search"]; ok {
-		if searchStr, ok := searchParam.(string); ok && searchStr != "" {
-			searchKeyword = searchStr
-		}
+	if searchParam, ok := plugin.GetString(ext, "search"); ok && searchParam != "" {
+		searchKeyword = searchParam
 	}
 	
 	return plugin.FilterResultsByKeyword(finalResults, searchKeyword), nil
@@ -444,7 +455,7 @@ func (p *WujiPlugin) enrichWithMagnetLinks(results []model.SearchResult, client
 				}}
 				mutex.Unlock()
 			} else if err != nil {
-				fmt.Printf("[%s] 获取磁力链接失败 [%d]: %v\n", p.Name(), index, err)
+				pluginLog.Warnf("获取磁力链接失败 [%d]: %v", index, err)
 			}
 		}(i)
 	}
@@ -462,6 +473,9 @@ func (p *WujiPlugin) enrichWithMagnetLinks(results []model.SearchResult, client
 	return validResults
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=wuji=debug 开启
+var pluginLog = logger.ForPlugin("wuji")
+
 // init 注册插件
 func init() {
 	plugin.RegisterGlobalPlugin(NewWujiPlugin())
@@ -4,7 +4,6 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -15,6 +14,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -29,7 +29,6 @@ const (
 // Xb6vPlugin 6v电影插件
 type Xb6vPlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode    bool
 	detailCache  sync.Map // 缓存详情页结果
 	cacheTTL     time.Duration
 	currentBase  string // 当前使用的域名
@@ -43,20 +42,13 @@ type DetailPageInfo struct {
 
 // NewXb6vPlugin 创建新的6v电影插件实例
 func NewXb6vPlugin() *Xb6vPlugin {
-	// 检查调试模式
-	debugMode := false // 启用调试
-	
 	p := &Xb6vPlugin{
 		// 磁力搜索插件：优先级4，跳过Service层过滤
 		BaseAsyncPlugin: plugin.NewBaseAsyncPluginWithFilter("xb6v", 3, true),
-		debugMode:       debugMode,
 		cacheTTL:        30 * time.Minute,
 		currentBase:     BaseURL,
 	}
 	
-	// 设置主缓存键
-	p.BaseAsyncPlugin.SetMainCacheKey(p.Name())
-	
 	return p
 }
 
@@ -86,7 +78,7 @@ func (p *Xb6vPlugin) Search(keyword string, ext map[string]interface{}) ([]model
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *Xb6vPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // setRequestHeaders 设置请求头
@@ -122,20 +114,20 @@ func (p *Xb6vPlugin) doRequest(client *http.Client, method, url, postData string
 	
 	p.setRequestHeaders(req, referer)
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] 发送 %s 请求: %s", method, url)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("发送 %s 请求: %s", method, url)
 	}
 	
 	resp, err := client.Do(req)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Xb6v] 请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("请求失败: %v", err)
 		}
 		return nil, err
 	}
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] 响应状态: %d", resp.StatusCode)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("响应状态: %d", resp.StatusCode)
 	}
 	
 	return resp, nil
@@ -143,6 +135,8 @@ func (p *Xb6vPlugin) doRequest(client *http.Client, method, url, postData string
 
 // searchImpl 实际的搜索实现
 func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+
 	// 先进行URL解码，处理%20等编码
 	decodedKeyword, err := url.QueryUnescape(keyword)
 	if err != nil {
@@ -154,16 +148,16 @@ func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 	originalKeyword := decodedKeyword
 	if spaceIndex := strings.Index(decodedKeyword, " "); spaceIndex > 0 {
 		decodedKeyword = decodedKeyword[:spaceIndex]
-		if p.debugMode {
-			log.Printf("[Xb6v] 关键词优化: '%s' -> '%s'", originalKeyword, decodedKeyword)
+		if log.DebugEnabled() {
+			log.Debugf("关键词优化: '%s' -> '%s'", originalKeyword, decodedKeyword)
 		}
 	}
 	
 	// 使用处理后的关键词
 	keyword = decodedKeyword
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] 开始搜索: %s (原始: %s)", keyword, originalKeyword)
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s (原始: %s)", keyword, originalKeyword)
 	}
 	
 	// 第一步：POST搜索请求
@@ -183,20 +177,20 @@ func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 	}
 	defer resp.Body.Close()
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] POST响应状态码: %d", resp.StatusCode)
+	if log.DebugEnabled() {
+		log.Debugf("POST响应状态码: %d", resp.StatusCode)
 	}
 	
 	// 获取重定向的location
 	location := resp.Header.Get("Location")
-	if p.debugMode {
-		log.Printf("[Xb6v] Location头: '%s'", location)
+	if log.DebugEnabled() {
+		log.Debugf("Location头: '%s'", location)
 	}
 	
 	// 如果没有Location头，可能需要从响应体中解析
 	if location == "" {
-		if p.debugMode {
-			log.Printf("[Xb6v] 未找到Location头，尝试解析响应体")
+		if log.DebugEnabled() {
+			log.Debugf("未找到Location头，尝试解析响应体")
 		}
 		
 		// 读取响应体看看是否包含重定向信息
@@ -211,13 +205,13 @@ func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 		}
 		
 		bodyStr := string(bodyBytes)
-		if p.debugMode {
-			log.Printf("[Xb6v] 响应体长度: %d", len(bodyStr))
+		if log.DebugEnabled() {
+			log.Debugf("响应体长度: %d", len(bodyStr))
 			// 只打印前500个字符避免日志过长
 			if len(bodyStr) > 500 {
-				log.Printf("[Xb6v] 响应体前500字符: %s", bodyStr[:500])
+				log.Debugf("响应体前500字符: %s", bodyStr[:500])
 			} else {
-				log.Printf("[Xb6v] 响应体内容: %s", bodyStr)
+				log.Debugf("响应体内容: %s", bodyStr)
 			}
 		}
 		
@@ -229,8 +223,8 @@ func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 			matches := re.FindStringSubmatch(bodyStr)
 			if len(matches) > 1 {
 				location = matches[1]
-				if p.debugMode {
-					log.Printf("[Xb6v] 从JavaScript中提取到Location: %s", location)
+				if log.DebugEnabled() {
+					log.Debugf("从JavaScript中提取到Location: %s", location)
 				}
 			}
 		}
@@ -243,8 +237,8 @@ func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 			for _, match := range matches {
 				if len(match) > 1 {
 					location = match[1]
-					if p.debugMode {
-						log.Printf("[Xb6v] 从URL模式中提取到Location: %s", location)
+					if log.DebugEnabled() {
+						log.Debugf("从URL模式中提取到Location: %s", location)
 					}
 					break
 				}
@@ -257,8 +251,8 @@ func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 			match := re.FindString(bodyStr)
 			if match != "" {
 				location = match
-				if p.debugMode {
-					log.Printf("[Xb6v] 从正则匹配中提取到Location: %s", location)
+				if log.DebugEnabled() {
+					log.Debugf("从正则匹配中提取到Location: %s", location)
 				}
 			}
 		}
@@ -277,8 +271,8 @@ func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 		resultURL = p.currentBase + "/" + strings.TrimPrefix(location, "/")
 	}
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] 搜索结果页面: %s", resultURL)
+	if log.DebugEnabled() {
+		log.Debugf("搜索结果页面: %s", resultURL)
 	}
 	
 	// 第二步：获取搜索结果页面
@@ -306,8 +300,8 @@ func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 	// 提取搜索结果（详情页链接和日期）
 	detailPages := p.extractDetailURLs(doc)
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] 找到 %d 个详情页链接", len(detailPages))
+	if log.DebugEnabled() {
+		log.Debugf("找到 %d 个详情页链接", len(detailPages))
 	}
 	
 	if len(detailPages) == 0 {
@@ -325,15 +319,15 @@ func (p *Xb6vPlugin) searchImpl(client *http.Client, keyword string, ext map[str
 	// 过滤空结果
 	validResults := p.filterValidResults(results)
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] 去除无链接结果后剩余 %d 个结果", len(validResults))
+	if log.DebugEnabled() {
+		log.Debugf("去除无链接结果后剩余 %d 个结果", len(validResults))
 	}
 	
 	// 插件层关键词过滤（必须执行，因为跳过了Service层过滤）
 	keywordFilteredResults := plugin.FilterResultsByKeyword(validResults, keyword)
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] 关键词过滤后最终返回 %d 个结果", len(keywordFilteredResults))
+	if log.DebugEnabled() {
+		log.Debugf("关键词过滤后最终返回 %d 个结果", len(keywordFilteredResults))
 	}
 	
 	return keywordFilteredResults, nil
@@ -345,8 +339,8 @@ func (p *Xb6vPlugin) getResponseReader(resp *http.Response) (io.Reader, error) {
 	
 	// 检查Content-Encoding
 	contentEncoding := resp.Header.Get("Content-Encoding")
-	if p.debugMode {
-		log.Printf("[Xb6v] Content-Encoding: %s", contentEncoding)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("Content-Encoding: %s", contentEncoding)
 	}
 	
 	// 如果是gzip压缩，手动解压
@@ -379,14 +373,14 @@ func (p *Xb6vPlugin) extractDetailURLs(doc *goquery.Document) []DetailPageInfo {
 			return
 		}
 		
-		if p.debugMode {
-			log.Printf("[Xb6v] 找到搜索结果链接: %s", href)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("找到搜索结果链接: %s", href)
 		}
 		
 		// 检查链接是否符合内容页面格式（分类/子分类/数字.html）
 		if !p.isValidContentURL(href) {
-			if p.debugMode {
-				log.Printf("[Xb6v] 链接格式无效，跳过: %s", href)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("链接格式无效，跳过: %s", href)
 			}
 			return
 		}
@@ -413,14 +407,14 @@ func (p *Xb6vPlugin) extractDetailURLs(doc *goquery.Document) []DetailPageInfo {
 			if parsedDate, err := time.Parse("2006-01-02", dateText); err == nil {
 				publishDate = parsedDate
 			} else {
-				if p.debugMode {
-					log.Printf("[Xb6v] 日期解析失败: %s, 使用当前时间", dateText)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("日期解析失败: %s, 使用当前时间", dateText)
 				}
 				publishDate = time.Now()
 			}
 		} else {
-			if p.debugMode {
-				log.Printf("[Xb6v] 未找到日期信息，使用当前时间")
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("未找到日期信息，使用当前时间")
 			}
 			publishDate = time.Now()
 		}
@@ -431,13 +425,13 @@ func (p *Xb6vPlugin) extractDetailURLs(doc *goquery.Document) []DetailPageInfo {
 			DateTime: publishDate,
 		})
 		
-		if p.debugMode {
-			log.Printf("[Xb6v] 添加有效链接: %s, 日期: %s", fullURL, publishDate.Format("2006-01-02"))
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("添加有效链接: %s, 日期: %s", fullURL, publishDate.Format("2006-01-02"))
 		}
 	})
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] 提取到 %d 个有效详情页链接", len(detailPages))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("提取到 %d 个有效详情页链接", len(detailPages))
 	}
 	
 	return detailPages
@@ -565,8 +559,8 @@ func (p *Xb6vPlugin) fetchMagnetLinksFromDetails(client *http.Client, detailPage
 				mu.Unlock()
 			}
 			
-			if p.debugMode {
-				log.Printf("[Xb6v] 详情页 %d/%d 处理完成，获取到 %d 个结果 (日期: %s)", 
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("详情页 %d/%d 处理完成，获取到 %d 个结果 (日期: %s)", 
 					idx+1, len(detailPages), len(pageResults), pageInfo.DateTime.Format("2006-01-02"))
 			}
 		}(i, detailPage)
@@ -581,8 +575,8 @@ func (p *Xb6vPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL s
 	// 检查缓存
 	if cached, ok := p.detailCache.Load(detailURL); ok {
 		if results, ok := cached.([]model.SearchResult); ok {
-			if p.debugMode {
-				log.Printf("[Xb6v] 使用缓存的详情页结果: %s", detailURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("使用缓存的详情页结果: %s", detailURL)
 			}
 			return results
 		}
@@ -591,16 +585,16 @@ func (p *Xb6vPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL s
 	// 请求详情页
 	resp, err := p.doRequest(client, "GET", detailURL, "", p.currentBase)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Xb6v] 获取详情页失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("获取详情页失败: %v", err)
 		}
 		return nil
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
-		if p.debugMode {
-			log.Printf("[Xb6v] 详情页响应状态码异常: %d", resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页响应状态码异常: %d", resp.StatusCode)
 		}
 		return nil
 	}
@@ -613,8 +607,8 @@ func (p *Xb6vPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL s
 	
 	doc, err := goquery.NewDocumentFromReader(reader)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Xb6v] 解析详情页HTML失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("解析详情页HTML失败: %v", err)
 		}
 		return nil
 	}
@@ -635,8 +629,8 @@ func (p *Xb6vPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL s
 	magnetLinks, linkInfos := p.extractMagnetLinks(doc, title)
 	
 	if len(magnetLinks) == 0 {
-		if p.debugMode {
-			log.Printf("[Xb6v] 详情页无磁力链接: %s", detailURL)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页无磁力链接: %s", detailURL)
 		}
 		return nil
 	}
@@ -673,8 +667,8 @@ func (p *Xb6vPlugin) fetchDetailPageMagnetLinks(client *http.Client, detailURL s
 		p.detailCache.Delete(detailURL)
 	}()
 	
-	if p.debugMode {
-		log.Printf("[Xb6v] 提取到磁力链接: %s, 链接数: %d", title, len(magnetLinks))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("提取到磁力链接: %s, 链接数: %d", title, len(magnetLinks))
 	}
 	
 	return results
@@ -725,8 +719,8 @@ func (p *Xb6vPlugin) extractMagnetLinks(doc *goquery.Document, mainTitle string)
 					SubTitle: subTitle,
 				})
 				
-				if p.debugMode {
-					log.Printf("[Xb6v] 提取磁力链接: %s - %s", mainTitle, subTitle)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("提取磁力链接: %s - %s", mainTitle, subTitle)
 				}
 			})
 		}
@@ -761,8 +755,8 @@ func (p *Xb6vPlugin) extractMagnetLinks(doc *goquery.Document, mainTitle string)
 				SubTitle: subTitle,
 			})
 			
-			if p.debugMode {
-				log.Printf("[Xb6v] 提取磁力链接: %s - %s", mainTitle, subTitle)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("提取磁力链接: %s - %s", mainTitle, subTitle)
 			}
 		})
 	}
@@ -790,14 +784,17 @@ func (p *Xb6vPlugin) filterValidResults(results []model.SearchResult) []model.Se
 	for _, result := range results {
 		if len(result.Links) > 0 {
 			validResults = append(validResults, result)
-		} else if p.debugMode {
-			log.Printf("[Xb6v] 忽略无磁力链接结果: %s", result.Title)
+		} else if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("忽略无磁力链接结果: %s", result.Title)
 		}
 	}
 	
 	return validResults
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=xb6v=debug 开启
+var pluginLog = logger.ForPlugin("xb6v")
+
 func init() {
 	plugin.RegisterGlobalPlugin(NewXb6vPlugin())
 }
\ No newline at end of file
@@ -98,7 +98,7 @@ func (p *XdyhAsyncPlugin) Search(keyword string, ext map[string]interface{}) ([]
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *XdyhAsyncPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 具体的搜索实现
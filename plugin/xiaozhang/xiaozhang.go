@@ -4,7 +4,6 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -15,6 +14,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -28,19 +28,14 @@ const (
 // XiaozhangPlugin 校长影视插件
 type XiaozhangPlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode    bool
 	detailCache  sync.Map // 缓存详情页结果
 	cacheTTL     time.Duration
 }
 
 // NewXiaozhangPlugin 创建新的校长影视插件实例
 func NewXiaozhangPlugin() *XiaozhangPlugin {
-	// 检查调试模式
-	debugMode := false
-	
 	p := &XiaozhangPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPlugin("xiaozhang", 3),
-		debugMode:       debugMode,
 		cacheTTL:        30 * time.Minute,
 	}
 	
@@ -73,7 +68,7 @@ func (p *XiaozhangPlugin) Search(keyword string, ext map[string]interface{}) ([]
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *XiaozhangPlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // setRequestHeaders 设置请求头
@@ -112,20 +107,20 @@ func (p *XiaozhangPlugin) doRequest(client *http.Client, url string, referer str
 	
 	p.setRequestHeaders(req, referer)
 	
-	if p.debugMode {
-		log.Printf("[Xiaozhang] 发送请求: %s", url)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("发送请求: %s", url)
 	}
 	
 	resp, err := tempClient.Do(req)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Xiaozhang] 请求失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("请求失败: %v", err)
 		}
 		return nil, err
 	}
 	
-	if p.debugMode {
-		log.Printf("[Xiaozhang] 响应状态: %d", resp.StatusCode)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("响应状态: %d", resp.StatusCode)
 	}
 	
 	return resp, nil
@@ -133,11 +128,12 @@ func (p *XiaozhangPlugin) doRequest(client *http.Client, url string, referer str
 
 // searchImpl 实际的搜索实现
 func (p *XiaozhangPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
 	searchURL := fmt.Sprintf("%s%s?keyword=%s", BaseURL, SearchPath, url.QueryEscape(keyword))
 	
-	if p.debugMode {
-		log.Printf("[Xiaozhang] 开始搜索: %s", keyword)
-		log.Printf("[Xiaozhang] 搜索URL: %s", searchURL)
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
+		log.Debugf("搜索URL: %s", searchURL)
 	}
 	
 	// 发送搜索请求
@@ -156,9 +152,9 @@ func (p *XiaozhangPlugin) searchImpl(client *http.Client, keyword string, ext ma
 	
 	// 检查Content-Encoding
 	contentEncoding := resp.Header.Get("Content-Encoding")
-	if p.debugMode {
-		log.Printf("[Xiaozhang] Content-Encoding: %s", contentEncoding)
-		log.Printf("[Xiaozhang] Content-Type: %s", resp.Header.Get("Content-Type"))
+	if log.DebugEnabled() {
+		log.Debugf("Content-Encoding: %s", contentEncoding)
+		log.Debugf("Content-Type: %s", resp.Header.Get("Content-Type"))
 	}
 	
 	// 如果是gzip压缩，手动解压
@@ -180,8 +176,8 @@ func (p *XiaozhangPlugin) searchImpl(client *http.Client, keyword string, ext ma
 	// 提取搜索结果
 	results := p.extractSearchResults(doc, keyword)
 	
-	if p.debugMode {
-		log.Printf("[Xiaozhang] 找到 %d 个搜索结果", len(results))
+	if log.DebugEnabled() {
+		log.Debugf("找到 %d 个搜索结果", len(results))
 	}
 	
 	// 并发获取详情页链接
@@ -190,8 +186,8 @@ func (p *XiaozhangPlugin) searchImpl(client *http.Client, keyword string, ext ma
 	// 过滤结果
 	filteredResults := plugin.FilterResultsByKeyword(results, keyword)
 	
-	if p.debugMode {
-		log.Printf("[Xiaozhang] 过滤后剩余 %d 个结果", len(filteredResults))
+	if log.DebugEnabled() {
+		log.Debugf("过滤后剩余 %d 个结果", len(filteredResults))
 	}
 	
 	return filteredResults, nil
@@ -201,14 +197,14 @@ func (p *XiaozhangPlugin) searchImpl(client *http.Client, keyword string, ext ma
 func (p *XiaozhangPlugin) extractSearchResults(doc *goquery.Document, keyword string) []model.SearchResult {
 	var results []model.SearchResult
 	
-	if p.debugMode {
+	if pluginLog.DebugEnabled() {
 		// 调试：检查页面标题
 		pageTitle := doc.Find("title").Text()
-		log.Printf("[Xiaozhang] 页面标题: %s", pageTitle)
+		pluginLog.Debugf("页面标题: %s", pageTitle)
 		
 		// 调试：检查是否找到list-boxes
 		listBoxes := doc.Find(".list-boxes")
-		log.Printf("[Xiaozhang] 找到 .list-boxes 元素数量: %d", listBoxes.Length())
+		pluginLog.Debugf("找到 .list-boxes 元素数量: %d", listBoxes.Length())
 		
 		// 调试：尝试其他可能的选择器
 		if listBoxes.Length() == 0 {
@@ -217,7 +213,7 @@ func (p *XiaozhangPlugin) extractSearchResults(doc *goquery.Document, keyword st
 			if len(bodyHTML) > 500 {
 				bodyHTML = bodyHTML[:500] + "..."
 			}
-			log.Printf("[Xiaozhang] 页面body前500字符: %s", bodyHTML)
+			pluginLog.Debugf("页面body前500字符: %s", bodyHTML)
 		}
 	}
 	
@@ -228,13 +224,13 @@ func (p *XiaozhangPlugin) extractSearchResults(doc *goquery.Document, keyword st
 		title := strings.TrimSpace(titleElem.Text())
 		detailPath, _ := titleElem.Attr("href")
 		
-		if p.debugMode {
-			log.Printf("[Xiaozhang] 处理第 %d 个结果: title=%s, path=%s", i+1, title, detailPath)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("处理第 %d 个结果: title=%s, path=%s", i+1, title, detailPath)
 		}
 		
 		if title == "" || detailPath == "" {
-			if p.debugMode {
-				log.Printf("[Xiaozhang] 跳过第 %d 个结果：标题或链接为空", i+1)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("跳过第 %d 个结果：标题或链接为空", i+1)
 			}
 			return
 		}
@@ -258,8 +254,8 @@ func (p *XiaozhangPlugin) extractSearchResults(doc *goquery.Document, keyword st
 			if err != nil {
 				// 如果解析失败，使用当前时间
 				publishTime = time.Now()
-				if p.debugMode {
-					log.Printf("[Xiaozhang] 解析时间失败: %s, 错误: %v", timeText, err)
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("解析时间失败: %s, 错误: %v", timeText, err)
 				}
 			} else {
 				publishTime = parsedTime
@@ -277,8 +273,8 @@ func (p *XiaozhangPlugin) extractSearchResults(doc *goquery.Document, keyword st
 			resourceID = fmt.Sprintf("%d", time.Now().UnixNano())
 		}
 		
-		if p.debugMode {
-			log.Printf("[Xiaozhang] 提取结果 %d: %s, URL: %s, 时间: %s", i+1, title, detailURL, timeText)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("提取结果 %d: %s, URL: %s, 时间: %s", i+1, title, detailURL, timeText)
 		}
 		
 		result := model.SearchResult{
@@ -306,8 +302,8 @@ func (p *XiaozhangPlugin) enrichWithDetailLinks(client *http.Client, results []m
 		return results
 	}
 	
-	if p.debugMode {
-		log.Printf("[Xiaozhang] 开始获取 %d 个详情页的下载链接", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("开始获取 %d 个详情页的下载链接", len(results))
 	}
 	
 	var wg sync.WaitGroup
@@ -335,8 +331,8 @@ func (p *XiaozhangPlugin) enrichWithDetailLinks(client *http.Client, results []m
 				results[idx].Tags = nil
 				mu.Unlock()
 				
-				if p.debugMode {
-					log.Printf("[Xiaozhang] 详情页 %d/%d 获取到 %d 个链接", idx+1, len(results), len(links))
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("详情页 %d/%d 获取到 %d 个链接", idx+1, len(results), len(links))
 				}
 			}
 		}(i)
@@ -352,8 +348,8 @@ func (p *XiaozhangPlugin) fetchDetailPageLinks(client *http.Client, detailURL st
 	// 检查缓存
 	if cached, ok := p.detailCache.Load(detailURL); ok {
 		if links, ok := cached.([]model.Link); ok {
-			if p.debugMode {
-				log.Printf("[Xiaozhang] 使用缓存的详情页结果: %s", detailURL)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("使用缓存的详情页结果: %s", detailURL)
 			}
 			return links
 		}
@@ -362,8 +358,8 @@ func (p *XiaozhangPlugin) fetchDetailPageLinks(client *http.Client, detailURL st
 	// 第一步：获取重定向位置
 	resp, err := p.doRequest(client, detailURL, BaseURL, false)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Xiaozhang] 获取详情页失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("获取详情页失败: %v", err)
 		}
 		return nil
 	}
@@ -376,31 +372,31 @@ func (p *XiaozhangPlugin) fetchDetailPageLinks(client *http.Client, detailURL st
 		if resp.StatusCode == http.StatusOK {
 			return p.extractDetailPageLinks(resp, detailURL)
 		}
-		if p.debugMode {
-			log.Printf("[Xiaozhang] 未找到重定向位置，状态码: %d", resp.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("未找到重定向位置，状态码: %d", resp.StatusCode)
 		}
 		return nil
 	}
 	
 	// 构建真实的详情页URL
 	realDetailURL := BaseURL + location
-	if p.debugMode {
-		log.Printf("[Xiaozhang] 重定向到: %s", realDetailURL)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("重定向到: %s", realDetailURL)
 	}
 	
 	// 第二步：访问真实的详情页
 	resp2, err := p.doRequest(client, realDetailURL, detailURL, true)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Xiaozhang] 获取真实详情页失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("获取真实详情页失败: %v", err)
 		}
 		return nil
 	}
 	defer resp2.Body.Close()
 	
 	if resp2.StatusCode != http.StatusOK {
-		if p.debugMode {
-			log.Printf("[Xiaozhang] 真实详情页响应状态码异常: %d", resp2.StatusCode)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("真实详情页响应状态码异常: %d", resp2.StatusCode)
 		}
 		return nil
 	}
@@ -429,8 +425,8 @@ func (p *XiaozhangPlugin) extractDetailPageLinks(resp *http.Response, pageURL st
 	if contentEncoding == "gzip" {
 		gzReader, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			if p.debugMode {
-				log.Printf("[Xiaozhang] 创建gzip reader失败: %v", err)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("创建gzip reader失败: %v", err)
 			}
 			return nil
 		}
@@ -440,8 +436,8 @@ func (p *XiaozhangPlugin) extractDetailPageLinks(resp *http.Response, pageURL st
 	
 	doc, err := goquery.NewDocumentFromReader(reader)
 	if err != nil {
-		if p.debugMode {
-			log.Printf("[Xiaozhang] 解析详情页HTML失败: %v", err)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("解析详情页HTML失败: %v", err)
 		}
 		return nil
 	}
@@ -497,8 +493,8 @@ func (p *XiaozhangPlugin) extractDetailPageLinks(resp *http.Response, pageURL st
 				Password: password,
 			}
 			
-			if p.debugMode {
-				log.Printf("[Xiaozhang] 提取链接: %s, 类型: %s, 密码: %s", href, linkType, password)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("提取链接: %s, 类型: %s, 密码: %s", href, linkType, password)
 			}
 			
 			links = append(links, link)
@@ -558,6 +554,9 @@ func determineLinkType(url string) string {
 	return "other"
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=xiaozhang=debug 开启
+var pluginLog = logger.ForPlugin("xiaozhang")
+
 func init() {
 	plugin.RegisterGlobalPlugin(NewXiaozhangPlugin())
 }
\ No newline at end of file
@@ -5,7 +5,6 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -17,6 +16,7 @@ import (
 	"pansou/model"
 	"pansou/plugin"
 	"pansou/util/json"
+	"pansou/util/logger"
 )
 
 const (
@@ -33,7 +33,6 @@ const (
 // XysPlugin 小云搜索插件
 type XysPlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode  bool
 	tokenCache sync.Map // 缓存token，避免频繁获取
 	cacheTTL   time.Duration
 }
@@ -52,6 +51,9 @@ type SearchResponse struct {
 	Data string `json:"data"`
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=xys=debug 开启
+var pluginLog = logger.ForPlugin(PluginName)
+
 // init 注册插件
 func init() {
 	plugin.RegisterGlobalPlugin(NewXysPlugin())
@@ -59,12 +61,8 @@ func init() {
 
 // NewXysPlugin 创建新的小云搜索插件实例
 func NewXysPlugin() *XysPlugin {
-	// 检查调试模式
-	debugMode := false // 生产环境关闭调试
-
 	p := &XysPlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPlugin(PluginName, 3), // 标准网盘插件，启用Service层过滤
-		debugMode:       debugMode,
 		cacheTTL:        30 * time.Minute, // token缓存30分钟
 	}
 
@@ -93,8 +91,9 @@ func (p *XysPlugin) Search(keyword string, ext map[string]interface{}) ([]model.
 
 // searchImpl 搜索实现
 func (p *XysPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	if p.debugMode {
-		log.Printf("[XYS] 开始搜索: %s", keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
 	}
 
 	// 第一步：获取token
@@ -103,8 +102,8 @@ func (p *XysPlugin) searchImpl(client *http.Client, keyword string, ext map[stri
 		return nil, fmt.Errorf("获取token失败: %w", err)
 	}
 
-	if p.debugMode {
-		log.Printf("[XYS] 获取到token: %s", token[:10]+"...")
+	if log.DebugEnabled() {
+		log.Debugf("获取到token: %s", token[:10]+"...")
 	}
 
 	// 第二步：执行搜索
@@ -113,8 +112,8 @@ func (p *XysPlugin) searchImpl(client *http.Client, keyword string, ext map[stri
 		return nil, fmt.Errorf("执行搜索失败: %w", err)
 	}
 
-	if p.debugMode {
-		log.Printf("[XYS] 搜索完成，获取到 %d 个结果", len(results))
+	if log.DebugEnabled() {
+		log.Debugf("搜索完成，获取到 %d 个结果", len(results))
 	}
 
 	return results, nil
@@ -128,8 +127,8 @@ func (p *XysPlugin) getToken(client *http.Client, keyword string) (string, error
 		if tokenCache, ok := cached.(TokenCache); ok {
 			// 检查是否过期
 			if time.Since(tokenCache.Timestamp) < p.cacheTTL {
-				if p.debugMode {
-					log.Printf("[XYS] 使用缓存的token")
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("使用缓存的token")
 				}
 				return tokenCache.Token, nil
 			}
@@ -184,8 +183,8 @@ func (p *XysPlugin) getToken(client *http.Client, keyword string) (string, error
 			matches := re.FindStringSubmatch(scriptContent)
 			if len(matches) > 1 {
 				token = matches[1]
-				if p.debugMode {
-					log.Printf("[XYS] 从script中提取到token: %s", token[:10]+"...")
+				if pluginLog.DebugEnabled() {
+					pluginLog.Debugf("从script中提取到token: %s", token[:10]+"...")
 				}
 			}
 		}
@@ -284,8 +283,8 @@ func (p *XysPlugin) executeSearch(client *http.Client, token, keyword string) ([
 		return nil, fmt.Errorf("[%s] 搜索API返回错误: %s", p.Name(), searchResp.Msg)
 	}
 
-	if p.debugMode {
-		log.Printf("[XYS] 搜索API响应成功，data长度: %d", len(searchResp.Data))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("搜索API响应成功，data长度: %d", len(searchResp.Data))
 	}
 
 	// 解析HTML内容
@@ -313,15 +312,15 @@ func (p *XysPlugin) parseSearchResults(htmlData, keyword string) ([]model.Search
 		}
 	})
 
-	if p.debugMode {
-		log.Printf("[XYS] 解析到 %d 个原始结果", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析到 %d 个原始结果", len(results))
 	}
 
 	// 关键词过滤（标准网盘插件需要过滤）
 	filteredResults := plugin.FilterResultsByKeyword(results, keyword)
 	
-	if p.debugMode {
-		log.Printf("[XYS] 关键词过滤后剩余 %d 个结果", len(filteredResults))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("关键词过滤后剩余 %d 个结果", len(filteredResults))
 	}
 
 	return filteredResults, nil
@@ -360,8 +359,8 @@ func (p *XysPlugin) parseResultItem(s *goquery.Selection, index int) *model.Sear
 	}
 
 	if href == "" {
-		if p.debugMode {
-			log.Printf("[XYS] 跳过无链接的结果: %s", title)
+		if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("跳过无链接的结果: %s", title)
 		}
 		return nil
 	}
@@ -395,8 +394,8 @@ func (p *XysPlugin) parseResultItem(s *goquery.Selection, index int) *model.Sear
 		Tags:      []string{platform},
 	}
 
-	if p.debugMode {
-		log.Printf("[XYS] 解析结果: %s (%s)", title, platform)
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析结果: %s (%s)", title, platform)
 	}
 
 	return &result
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -16,6 +15,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"pansou/model"
 	"pansou/plugin"
+	"pansou/util/logger"
 )
 
 const (
@@ -29,16 +29,17 @@ const (
 // YuhuagePlugin 雨花阁插件
 type YuhuagePlugin struct {
 	*plugin.BaseAsyncPlugin
-	debugMode    bool
 	detailCache  sync.Map // 缓存详情页结果
 	cacheTTL     time.Duration
 	rateLimited  int32    // 429限流标志位
 }
 
+// pluginLog 插件日志器，调试日志通过 PLUGIN_LOG_LEVELS=yuhuage=debug 开启
+var pluginLog = logger.ForPlugin("yuhuage")
+
 func init() {
 	p := &YuhuagePlugin{
 		BaseAsyncPlugin: plugin.NewBaseAsyncPluginWithFilter("yuhuage", 3, true), 
-		debugMode:       false,
 		cacheTTL:        30 * time.Minute,
 	}
 	plugin.RegisterGlobalPlugin(p)
@@ -55,19 +56,20 @@ func (p *YuhuagePlugin) Search(keyword string, ext map[string]interface{}) ([]mo
 
 // SearchWithResult 执行搜索并返回包含IsFinal标记的结果
 func (p *YuhuagePlugin) SearchWithResult(keyword string, ext map[string]interface{}) (model.PluginSearchResult, error) {
-	return p.AsyncSearchWithResult(keyword, p.searchImpl, p.MainCacheKey, ext)
+	return p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
 }
 
 // searchImpl 搜索实现方法
 func (p *YuhuagePlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
-	if p.debugMode {
-		log.Printf("[YUHUAGE] 开始搜索: %s", keyword)
+	log := pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+	if log.DebugEnabled() {
+		log.Debugf("开始搜索: %s", keyword)
 	}
 
 	// 检查限流状态
 	if atomic.LoadInt32(&p.rateLimited) == 1 {
-		if p.debugMode {
-			log.Printf("[YUHUAGE] 当前处于限流状态，跳过搜索")
+		if log.DebugEnabled() {
+			log.Debugf("当前处于限流状态，跳过搜索")
 		}
 		return nil, fmt.Errorf("rate limited")
 	}
@@ -125,8 +127,8 @@ func (p *YuhuagePlugin) searchImpl(client *http.Client, keyword string, ext map[
 		return nil, err
 	}
 
-	if p.debugMode {
-		log.Printf("[YUHUAGE] 搜索完成，获得 %d 个结果", len(results))
+	if log.DebugEnabled() {
+		log.Debugf("搜索完成，获得 %d 个结果", len(results))
 	}
 
 	// 关键词过滤
@@ -181,8 +183,8 @@ func (p *YuhuagePlugin) parseSearchResults(html string) ([]model.SearchResult, e
 		results = append(results, result)
 	})
 
-	if p.debugMode {
-		log.Printf("[YUHUAGE] 解析到 %d 个搜索结果，准备获取详情", len(results))
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("解析到 %d 个搜索结果，准备获取详情", len(results))
 	}
 
 	// 同步获取详情页链接
@@ -214,18 +216,18 @@ func (p *YuhuagePlugin) fetchDetailsSync(detailURLs []string, results []model.Se
 					links := p.fetchDetailLinks(url)
 		if len(links) > 0 {
 			result.Links = links
-			if p.debugMode {
-				log.Printf("[YUHUAGE] 为结果设置了 %d 个链接", len(links))
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("为结果设置了 %d 个链接", len(links))
 			}
-		} else if p.debugMode {
-			log.Printf("[YUHUAGE] 详情页没有找到有效链接: %s", url)
+		} else if pluginLog.DebugEnabled() {
+			pluginLog.Debugf("详情页没有找到有效链接: %s", url)
 		}
 		}(detailURL, &results[i])
 	}
 
 	wg.Wait()
-	if p.debugMode {
-		log.Printf("[YUHUAGE] 详情页获取完成")
+	if pluginLog.DebugEnabled() {
+		pluginLog.Debugf("详情页获取完成")
 	}
 }
 
@@ -309,8 +311,8 @@ func (p *YuhuagePlugin) parseDetailLinks(html string) []model.Link {
 	doc.Find("a.download[href^='magnet:']").Each(func(i int, s *goquery.Selection) {
 		href, exists := s.Attr("href")
 		if exists && href != "" {
-			if p.debugMode {
-				log.Printf("[YUHUAGE] 找到磁力链接: %s", href)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("找到磁力链接: %s", href)
 			}
 			links = append(links, model.Link{
 				URL:  href,
@@ -323,8 +325,8 @@ func (p *YuhuagePlugin) parseDetailLinks(html string) []model.Link {
 	doc.Find("a.download[href^='thunder:']").Each(func(i int, s *goquery.Selection) {
 		href, exists := s.Attr("href")
 		if exists && href != "" {
-			if p.debugMode {
-				log.Printf("[YUHUAGE] 找到迅雷链接: %s", href)
+			if pluginLog.DebugEnabled() {
+				pluginLog.Debugf("找到迅雷链接: %s", href)
 			}
 			links = append(links, model.Link{
 				URL:  href,
@@ -333,8 +335,8 @@ func (p *YuhuagePlugin) parseDetailLinks(html string) []model.Link {
 		}
 	})
 	
-	if p.debugMode && len(links) > 0 {
-		log.Printf("[YUHUAGE] 从详情页解析到 %d 个链接", len(links))
+	if pluginLog.DebugEnabled() && len(links) > 0 {
+		pluginLog.Debugf("从详情页解析到 %d 个链接", len(links))
 	}
 	
 	return links
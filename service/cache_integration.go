@@ -1,12 +1,14 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"pansou/model"
 	"pansou/plugin"
 	"pansou/util/cache"
+	"pansou/util/logger"
 )
 
 // CacheWriteIntegration 缓存写入集成层
@@ -40,7 +42,7 @@ func NewCacheWriteIntegration(mainCache *cache.EnhancedTwoLevelCache) (*CacheWri
 	
 	integration.initialized = true
 	
-	fmt.Printf("[缓存写入集成] 初始化完成\n")
+	logger.Info(context.Background(), "缓存写入集成初始化完成")
 	return integration, nil
 }
 
@@ -85,7 +87,7 @@ func (c *CacheWriteIntegration) HandleCacheWrite(key string, results []model.Sea
 func (c *CacheWriteIntegration) getPluginPriority(pluginName string) int {
 	// 从插件管理器动态获取真实的优先级
 	if pluginInstance, exists := plugin.GetPluginByName(pluginName); exists {
-		return pluginInstance.Priority()
+		return plugin.GetPluginPriority(pluginInstance)
 	}
 	
 	// 如果插件不存在，返回默认等级4（最低优先级）
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/util"
+	"pansou/util/linkcheck"
+)
+
+// 链接检测器，首次使用时创建
+var (
+	linkChecker     *linkcheck.Checker
+	linkCheckerOnce sync.Once
+)
+
+// getLinkChecker 获取链接检测器
+func getLinkChecker() *linkcheck.Checker {
+	linkCheckerOnce.Do(func() {
+		linkChecker = linkcheck.NewChecker(
+			util.GetHTTPClient(),
+			config.AppConfig.LinkCheckTTL,
+			config.AppConfig.LinkCheckTimeout,
+			config.AppConfig.LinkCheckConcurrency,
+		)
+	})
+	return linkChecker
+}
+
+// mergedLinkRef merged_by_type中一个链接的位置
+type mergedLinkRef struct {
+	cloudType string
+	index     int
+}
+
+// CheckLinks 检测响应中merged_by_type链接的有效性并标注status，dropExpired为true时移除已失效的链接
+// 每次最多检测LinkCheckMaxLinks个链接，轮流从各网盘类型中选取；不支持检测的类型和超出数量的链接不标注
+func (s *SearchService) CheckLinks(ctx context.Context, response *model.SearchResponse, dropExpired bool) {
+	if !config.AppConfig.LinkCheckEnabled || len(response.MergedByType) == 0 {
+		return
+	}
+
+	// 复制一份，避免修改缓存中共享的切片
+	merged := make(model.MergedLinks, len(response.MergedByType))
+	types := make([]string, 0, len(response.MergedByType))
+	for cloudType, links := range response.MergedByType {
+		merged[cloudType] = append([]model.MergedLink(nil), links...)
+		if linkcheck.GetProber(cloudType) != nil {
+			types = append(types, cloudType)
+		}
+	}
+	sort.Strings(types)
+
+	// 轮流选取各类型的第i个链接，保证每种网盘类型靠前的链接都会被检测
+	maxLinks := config.AppConfig.LinkCheckMaxLinks
+	var refs []mergedLinkRef
+	for i := 0; len(refs) < maxLinks; i++ {
+		progressed := false
+		for _, cloudType := range types {
+			if i < len(merged[cloudType]) && len(refs) < maxLinks {
+				refs = append(refs, mergedLinkRef{cloudType: cloudType, index: i})
+				progressed = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	targets := make([]linkcheck.Target, len(refs))
+	for i, ref := range refs {
+		link := merged[ref.cloudType][ref.index]
+		targets[i] = linkcheck.Target{URL: link.URL, Password: link.Password}
+	}
+	verdicts := getLinkChecker().CheckAll(ctx, targets)
+	for i, ref := range refs {
+		link := &merged[ref.cloudType][ref.index]
+		link.Status = string(verdicts[i].Status)
+		linkVerdicts.put(link.URL, link.Password, verdicts[i])
+	}
+
+	if dropExpired {
+		removed := 0
+		for cloudType, links := range merged {
+			kept := links[:0]
+			for _, link := range links {
+				if link.Status == string(linkcheck.StatusExpired) {
+					removed++
+					continue
+				}
+				kept = append(kept, link)
+			}
+			if len(kept) == 0 {
+				delete(merged, cloudType)
+			} else {
+				merged[cloudType] = kept
+			}
+		}
+
+		// 只返回merged_by_type时总数为链接数，需要同步扣除
+		if response.Results == nil {
+			response.Total -= removed
+		}
+	}
+
+	response.MergedByType = merged
+}
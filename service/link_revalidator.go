@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/util"
+	"pansou/util/cache"
+	"pansou/util/linkcheck"
+	"pansou/util/logger"
+)
+
+// linkRevalidateInitialDelay 启动后首轮复检的等待时间，等待缓存中积累搜索结果
+const linkRevalidateInitialDelay = time.Minute
+
+// LinkRevalidatorStats 后台链接复检统计
+type LinkRevalidatorStats struct {
+	Rounds            int64         `json:"rounds"`              // 已完成的复检轮数
+	LinksScanned      int64         `json:"links_scanned"`       // 最近一轮从缓存中收集到的待复检链接数
+	Probed            int64         `json:"probed"`              // 累计检测次数
+	Alive             int64         `json:"alive"`               // 累计判定有效（含需要提取码）的次数
+	Dead              int64         `json:"dead"`                // 累计判定失效的次数
+	Unknown           int64         `json:"unknown"`             // 累计无法判定的次数
+	StoredVerdicts    int           `json:"stored_verdicts"`     // 当前保存的判定结果数
+	LastRoundAt       time.Time     `json:"last_round_at"`       // 最近一轮开始时间
+	LastRoundDuration time.Duration `json:"last_round_duration"` // 最近一轮耗时
+}
+
+// LinkRevalidator 后台链接复检器
+// 定期从主缓存最近的搜索结果中收集网盘链接，按网盘类型限制并发重新检测，判定结果持久化到磁盘缓存
+type LinkRevalidator struct {
+	checker     *linkcheck.Checker
+	interval    time.Duration
+	maxLinks    int
+	concurrency int
+
+	stats      LinkRevalidatorStats
+	statsMutex sync.Mutex
+
+	started int32
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// revalidateTarget 待复检的链接
+type revalidateTarget struct {
+	linkType string
+	target   linkcheck.Target
+}
+
+// NewLinkRevalidator 根据配置创建后台链接复检器
+func NewLinkRevalidator() *LinkRevalidator {
+	return &LinkRevalidator{
+		checker:     getLinkChecker(),
+		interval:    config.AppConfig.LinkRevalidateInterval,
+		maxLinks:    config.AppConfig.LinkRevalidateMaxLinks,
+		concurrency: config.AppConfig.LinkRevalidateConcurrency,
+		done:        make(chan struct{}),
+	}
+}
+
+// Start 恢复磁盘中的判定结果并启动后台复检
+func (r *LinkRevalidator) Start() {
+	if !atomic.CompareAndSwapInt32(&r.started, 0, 1) {
+		return
+	}
+
+	if c := enhancedTwoLevelCache; cacheInitialized && c != nil {
+		if err := linkVerdicts.load(c); err != nil {
+			logger.Warn(context.Background(), "恢复链接判定结果失败", "error", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	activeLinkRevalidator.Store(r)
+	go r.run(ctx)
+}
+
+// run 按周期执行复检，直到ctx取消
+func (r *LinkRevalidator) run(ctx context.Context) {
+	defer close(r.done)
+
+	timer := time.NewTimer(linkRevalidateInitialDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			r.revalidate(ctx)
+			timer.Reset(r.interval)
+		}
+	}
+}
+
+// revalidate 执行一轮复检
+func (r *LinkRevalidator) revalidate(ctx context.Context) {
+	c := enhancedTwoLevelCache
+	if !cacheInitialized || c == nil {
+		return
+	}
+
+	start := time.Now()
+	targets, scanned := r.collectTargets(c)
+
+	// 按网盘类型分组，每种类型单独限制并发，避免集中请求同一网盘
+	groups := make(map[string][]linkcheck.Target)
+	for _, t := range targets {
+		groups[t.linkType] = append(groups[t.linkType], t.target)
+	}
+
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		queue := make(chan linkcheck.Target, len(group))
+		for _, target := range group {
+			queue <- target
+		}
+		close(queue)
+
+		workers := r.concurrency
+		if workers > len(group) {
+			workers = len(group)
+		}
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for target := range queue {
+					if ctx.Err() != nil {
+						return
+					}
+					verdict := r.checker.Refresh(ctx, target.URL, target.Password)
+					if ctx.Err() != nil {
+						return
+					}
+					linkVerdicts.put(target.URL, target.Password, verdict)
+					r.recordVerdict(verdict.Status)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	if err := linkVerdicts.save(c); err != nil {
+		logger.Warn(ctx, "保存链接判定结果失败", "error", err)
+	}
+
+	r.statsMutex.Lock()
+	r.stats.Rounds++
+	r.stats.LinksScanned = int64(scanned)
+	r.stats.LastRoundAt = start
+	r.stats.LastRoundDuration = time.Since(start)
+	r.statsMutex.Unlock()
+
+	logger.Info(ctx, "链接复检完成", "scanned", scanned, "probed", len(targets), "elapsed", time.Since(start))
+}
+
+// collectTargets 从内存缓存的搜索结果中收集需要复检的链接
+// 判定结果在复检周期内的链接跳过，每轮最多返回maxLinks个，同时返回待复检链接总数
+func (r *LinkRevalidator) collectTargets(c *cache.EnhancedTwoLevelCache) ([]revalidateTarget, int) {
+	serializer := c.GetSerializer()
+	seen := make(map[string]bool)
+	var targets []revalidateTarget
+	scanned := 0
+
+	for _, item := range c.GetMemoryItems() {
+		// 内存缓存中还有分页快照等其他数据，无法解析为搜索结果的跳过
+		var results []model.SearchResult
+		if err := serializer.Deserialize(item.Data, &results); err != nil {
+			continue
+		}
+
+		for _, result := range results {
+			for _, link := range result.Links {
+				key := linkVerdictKey(link.URL, link.Password)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				linkType := util.GetLinkType(link.URL)
+				if linkcheck.GetProber(linkType) == nil {
+					continue
+				}
+				if verdict, ok := linkVerdicts.get(link.URL, link.Password); ok && time.Since(verdict.CheckedAt) < r.interval {
+					continue
+				}
+
+				scanned++
+				if len(targets) < r.maxLinks {
+					targets = append(targets, revalidateTarget{
+						linkType: linkType,
+						target:   linkcheck.Target{URL: link.URL, Password: link.Password},
+					})
+				}
+			}
+		}
+	}
+
+	return targets, scanned
+}
+
+// recordVerdict 累计检测结果统计
+func (r *LinkRevalidator) recordVerdict(status linkcheck.Status) {
+	r.statsMutex.Lock()
+	defer r.statsMutex.Unlock()
+
+	r.stats.Probed++
+	switch status {
+	case linkcheck.StatusValid, linkcheck.StatusNeedsPassword:
+		r.stats.Alive++
+	case linkcheck.StatusExpired:
+		r.stats.Dead++
+	default:
+		r.stats.Unknown++
+	}
+}
+
+// GetStats 获取复检统计
+func (r *LinkRevalidator) GetStats() LinkRevalidatorStats {
+	r.statsMutex.Lock()
+	stats := r.stats
+	r.statsMutex.Unlock()
+
+	stats.StoredVerdicts = linkVerdicts.count()
+	return stats
+}
+
+// Shutdown 停止后台复检并保存判定结果，超时后返回错误
+func (r *LinkRevalidator) Shutdown(timeout time.Duration) error {
+	if !atomic.CompareAndSwapInt32(&r.started, 1, 0) {
+		return nil // 未启动或已经关闭
+	}
+
+	r.cancel()
+	select {
+	case <-r.done:
+	case <-time.After(timeout):
+		return fmt.Errorf("等待链接复检停止超时")
+	}
+
+	if c := enhancedTwoLevelCache; cacheInitialized && c != nil {
+		if err := linkVerdicts.save(c); err != nil {
+			return fmt.Errorf("保存链接判定结果失败: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/util/cache"
+	jsonutil "pansou/util/json"
+	"pansou/util/linkcheck"
+)
+
+// linkVerdictsCacheKey 链接判定结果在磁盘缓存中的键
+const linkVerdictsCacheKey = "link_verdicts"
+
+// maxLinkVerdicts 最多保存的链接判定结果数，超出时丢弃最早检测的结果
+const maxLinkVerdicts = 100000
+
+// linkVerdictStore 链接判定结果存储，内存中保存供合并结果排序查询，持久化到磁盘缓存
+type linkVerdictStore struct {
+	mutex    sync.RWMutex
+	verdicts map[string]linkcheck.Verdict // 键为链接URL和提取码，见linkVerdictKey
+	max      int                          // 最多保存的判定结果数
+}
+
+// linkVerdicts 全局链接判定结果，由后台复检和check_links检测写入
+var linkVerdicts = newLinkVerdictStore(maxLinkVerdicts)
+
+// newLinkVerdictStore 创建最多保存max个判定结果的存储
+func newLinkVerdictStore(max int) *linkVerdictStore {
+	return &linkVerdictStore{verdicts: make(map[string]linkcheck.Verdict), max: max}
+}
+
+// linkVerdictKey 判定结果的键，同一链接配不同提取码的检测结果不同，分别保存
+func linkVerdictKey(url string, password string) string {
+	return url + "|" + password
+}
+
+// get 获取链接的判定结果，超过LinkCheckTTL的结果视为不存在
+func (s *linkVerdictStore) get(url string, password string) (linkcheck.Verdict, bool) {
+	s.mutex.RLock()
+	verdict, ok := s.verdicts[linkVerdictKey(url, password)]
+	s.mutex.RUnlock()
+	if !ok || time.Since(verdict.CheckedAt) > config.AppConfig.LinkCheckTTL {
+		return linkcheck.Verdict{}, false
+	}
+	return verdict, true
+}
+
+// put 保存链接的判定结果，无法确定的结果不覆盖已有结果；新增结果超出上限时立即清理
+func (s *linkVerdictStore) put(url string, password string, verdict linkcheck.Verdict) {
+	key := linkVerdictKey(url, password)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	existing, ok := s.verdicts[key]
+	if ok && verdict.Status == linkcheck.StatusUnknown && existing.Status != linkcheck.StatusUnknown {
+		return
+	}
+	s.verdicts[key] = verdict
+	if !ok && len(s.verdicts) > s.max {
+		// 多清理十分之一，避免之后每次新增都要排序
+		s.pruneLocked(s.max - s.max/10)
+	}
+}
+
+// isDead 判断链接是否已确认失效
+func (s *linkVerdictStore) isDead(url string, password string) bool {
+	verdict, ok := s.get(url, password)
+	return ok && verdict.Status == linkcheck.StatusExpired
+}
+
+// count 返回保存的判定结果数
+func (s *linkVerdictStore) count() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.verdicts)
+}
+
+// prune 清理过期的判定结果，并限制总数
+func (s *linkVerdictStore) prune() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.pruneLocked(s.max)
+}
+
+// pruneLocked 清理过期的判定结果，超过limit个时丢弃最早检测的结果，调用方需持有写锁
+func (s *linkVerdictStore) pruneLocked(limit int) {
+	for key, verdict := range s.verdicts {
+		if time.Since(verdict.CheckedAt) > config.AppConfig.LinkCheckTTL {
+			delete(s.verdicts, key)
+		}
+	}
+	if len(s.verdicts) <= limit {
+		return
+	}
+
+	keys := make([]string, 0, len(s.verdicts))
+	for key := range s.verdicts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return s.verdicts[keys[i]].CheckedAt.Before(s.verdicts[keys[j]].CheckedAt)
+	})
+	for _, key := range keys[:len(keys)-limit] {
+		delete(s.verdicts, key)
+	}
+}
+
+// save 将判定结果持久化到磁盘缓存
+func (s *linkVerdictStore) save(c *cache.EnhancedTwoLevelCache) error {
+	s.prune()
+
+	s.mutex.RLock()
+	data, err := jsonutil.Marshal(s.verdicts)
+	s.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	return c.SetDiskOnly(linkVerdictsCacheKey, data, config.AppConfig.LinkCheckTTL)
+}
+
+// load 从磁盘缓存恢复判定结果，不覆盖内存中更新的结果
+func (s *linkVerdictStore) load(c *cache.EnhancedTwoLevelCache) error {
+	data, hit, err := c.GetDiskOnly(linkVerdictsCacheKey)
+	if err != nil || !hit {
+		return err
+	}
+
+	var stored map[string]linkcheck.Verdict
+	if err := jsonutil.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key, verdict := range stored {
+		if existing, ok := s.verdicts[key]; !ok || existing.CheckedAt.Before(verdict.CheckedAt) {
+			s.verdicts[key] = verdict
+		}
+	}
+	s.pruneLocked(s.max)
+	return nil
+}
+
+// sortDeadLinksLast 将已确认失效的链接移到各网盘类型的末尾，其余链接保持原有顺序
+func sortDeadLinksLast(mergedLinks model.MergedLinks) {
+	if linkVerdicts.count() == 0 {
+		return
+	}
+	for _, links := range mergedLinks {
+		dead := make(map[string]bool)
+		for _, link := range links {
+			if linkVerdicts.isDead(link.URL, link.Password) {
+				dead[linkVerdictKey(link.URL, link.Password)] = true
+			}
+		}
+		if len(dead) == 0 {
+			continue
+		}
+		sort.SliceStable(links, func(i, j int) bool {
+			return !dead[linkVerdictKey(links[i].URL, links[i].Password)] && dead[linkVerdictKey(links[j].URL, links[j].Password)]
+		})
+	}
+}
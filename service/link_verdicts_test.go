@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"pansou/config"
+	"pansou/util/linkcheck"
+)
+
+// TestLinkVerdictStorePutEnforcesLimit 新增判定结果超出上限时立即丢弃最早检测的结果
+func TestLinkVerdictStorePutEnforcesLimit(t *testing.T) {
+	t.Setenv("CACHE_ENABLED", "false")
+	config.Init()
+	store := newLinkVerdictStore(100)
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 1000; i++ {
+		verdict := linkcheck.Verdict{Status: linkcheck.StatusValid, CheckedAt: start.Add(time.Duration(i) * time.Second)}
+		store.put(fmt.Sprintf("https://pan.quark.cn/s/%d", i), "", verdict)
+		if n := store.count(); n > 100 {
+			t.Fatalf("写入第%d个结果后保存了%d个，超过上限100", i+1, n)
+		}
+	}
+	if _, ok := store.get("https://pan.quark.cn/s/999", ""); !ok {
+		t.Error("最新的判定结果被丢弃")
+	}
+	if _, ok := store.get("https://pan.quark.cn/s/0", ""); ok {
+		t.Error("最早的判定结果未被丢弃")
+	}
+}
+
+// TestLinkVerdictStoreKeyedByPassword 同一链接配不同提取码的判定结果互不影响
+func TestLinkVerdictStoreKeyedByPassword(t *testing.T) {
+	t.Setenv("CACHE_ENABLED", "false")
+	config.Init()
+	store := newLinkVerdictStore(maxLinkVerdicts)
+
+	url := "https://pan.baidu.com/s/1abc"
+	store.put(url, "wrong", linkcheck.Verdict{Status: linkcheck.StatusNeedsPassword, CheckedAt: time.Now()})
+	store.put(url, "right", linkcheck.Verdict{Status: linkcheck.StatusValid, CheckedAt: time.Now()})
+
+	if verdict, ok := store.get(url, "right"); !ok || verdict.Status != linkcheck.StatusValid {
+		t.Errorf("正确提取码的判定结果 = %v, %v，期望valid", verdict.Status, ok)
+	}
+	if verdict, ok := store.get(url, "wrong"); !ok || verdict.Status != linkcheck.StatusNeedsPassword {
+		t.Errorf("错误提取码的判定结果 = %v, %v，期望needs_password", verdict.Status, ok)
+	}
+	if _, ok := store.get(url, ""); ok {
+		t.Error("未检测过的提取码不应有判定结果")
+	}
+
+	store.put(url, "", linkcheck.Verdict{Status: linkcheck.StatusExpired, CheckedAt: time.Now()})
+	if store.isDead(url, "right") {
+		t.Error("其他提取码的失效结果影响了正确提取码的链接")
+	}
+}
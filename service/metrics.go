@@ -0,0 +1,119 @@
+package service
+
+import (
+	"sync/atomic"
+	"time"
+
+	"pansou/config"
+	"pansou/util/metrics"
+)
+
+var (
+	// pluginSearchDuration 插件搜索耗时
+	pluginSearchDuration = metrics.NewHistogramVec(
+		"pansou_plugin_search_duration_seconds",
+		"插件搜索耗时（秒）",
+		metrics.LatencyBuckets,
+		"plugin",
+	)
+
+	// pluginSearchesTotal 插件搜索次数
+	pluginSearchesTotal = metrics.NewCounterVec(
+		"pansou_plugin_searches_total",
+		"插件搜索次数，按结果状态(ok/error/cancelled)区分",
+		"plugin", "status",
+	)
+
+	// pluginResultsTotal 插件返回的结果数
+	pluginResultsTotal = metrics.NewCounterVec(
+		"pansou_plugin_results_total",
+		"插件返回的搜索结果数",
+		"plugin",
+	)
+
+	// channelSearchDuration TG频道搜索耗时
+	channelSearchDuration = metrics.NewHistogramVec(
+		"pansou_channel_search_duration_seconds",
+		"TG频道搜索耗时（秒）",
+		metrics.LatencyBuckets,
+		"channel",
+	)
+
+	// channelSearchesTotal TG频道搜索次数
+	channelSearchesTotal = metrics.NewCounterVec(
+		"pansou_channel_searches_total",
+		"TG频道搜索次数，按结果状态(ok/error/cancelled)区分",
+		"channel", "status",
+	)
+
+	// channelResultsTotal TG频道返回的结果数
+	channelResultsTotal = metrics.NewCounterVec(
+		"pansou_channel_results_total",
+		"TG频道返回的搜索结果数",
+		"channel",
+	)
+)
+
+// activeLinkRevalidator 当前导出指标的后台链接复检器
+var activeLinkRevalidator atomic.Pointer[LinkRevalidator]
+
+func init() {
+	revalidateStat := func(field func(LinkRevalidatorStats) float64) func() float64 {
+		return func() float64 {
+			if r := activeLinkRevalidator.Load(); r != nil {
+				return field(r.GetStats())
+			}
+			return 0
+		}
+	}
+
+	metrics.NewCounterFunc("pansou_link_revalidate_rounds_total", "后台链接复检完成的轮数",
+		revalidateStat(func(s LinkRevalidatorStats) float64 { return float64(s.Rounds) }))
+	metrics.NewCounterFunc("pansou_link_revalidate_probes_total", "后台链接复检的检测次数",
+		revalidateStat(func(s LinkRevalidatorStats) float64 { return float64(s.Probed) }))
+	metrics.NewCounterFunc("pansou_link_revalidate_dead_total", "后台链接复检判定失效的次数",
+		revalidateStat(func(s LinkRevalidatorStats) float64 { return float64(s.Dead) }))
+	metrics.NewGaugeFunc("pansou_link_verdicts", "当前保存的链接判定结果数", func() float64 {
+		return float64(linkVerdicts.count())
+	})
+}
+
+// searchStatus 根据错误和上下文状态返回指标中的结果状态
+func searchStatus(cancelled bool, err error) string {
+	switch {
+	case cancelled:
+		return "cancelled"
+	case err != nil:
+		return "error"
+	default:
+		return "ok"
+	}
+}
+
+// recordPluginMetrics 记录一次插件搜索的指标
+func recordPluginMetrics(name string, latency time.Duration, resultCount int, cancelled bool, err error) {
+	pluginSearchDuration.Observe(latency.Seconds(), name)
+	pluginSearchesTotal.Inc(name, searchStatus(cancelled, err))
+	pluginResultsTotal.Add(float64(resultCount), name)
+}
+
+// channelLabel 频道指标的标签，只有默认频道使用频道名
+// 请求可以指定任意频道，其他频道统一记为other，避免标签数量无限增长
+func channelLabel(channel string) string {
+	if config.AppConfig != nil {
+		for _, defaultChannel := range config.AppConfig.DefaultChannels {
+			if defaultChannel == channel {
+				return channel
+			}
+		}
+	}
+	return "other"
+}
+
+// recordChannelMetrics 记录一次TG频道搜索的指标
+func recordChannelMetrics(channel string, latency time.Duration, resultCount int, cancelled bool, err error) {
+	channel = channelLabel(channel)
+	channelSearchDuration.Observe(latency.Seconds(), channel)
+	channelSearchesTotal.Inc(channel, searchStatus(cancelled, err))
+	channelResultsTotal.Add(float64(resultCount), channel)
+}
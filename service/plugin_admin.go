@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"pansou/plugin"
+	"pansou/util/cache"
+)
+
+// ErrPluginNotFound 插件未编译注册
+var ErrPluginNotFound = errors.New("插件不存在")
+
+// pluginAdminMutex 串行化插件启用和禁用，保证"所有插件"的缓存键与最终启用的插件一致
+var pluginAdminMutex sync.Mutex
+
+// PluginAdminStatus 管理接口返回的插件状态
+type PluginAdminStatus struct {
+	Name               string `json:"name"`
+	Enabled            bool   `json:"enabled"`             // 是否参与搜索
+	Priority           int    `json:"priority"`            // 生效的优先级
+	DefaultPriority    int    `json:"default_priority"`    // 插件内置的优先级
+	PriorityOverridden bool   `json:"priority_overridden"` // 优先级是否被运行时覆盖
+	SkipServiceFilter  bool   `json:"skip_service_filter"` // 是否跳过Service层关键词过滤
+}
+
+// ListPluginStatuses 列出所有已编译注册的插件（包括未启用的），按名称排序
+func (s *SearchService) ListPluginStatuses() []PluginAdminStatus {
+	allPlugins := plugin.GetRegisteredPlugins()
+	statuses := make([]PluginAdminStatus, 0, len(allPlugins))
+	for _, p := range allPlugins {
+		_, overridden := plugin.GetPriorityOverride(p.Name())
+		statuses = append(statuses, PluginAdminStatus{
+			Name:               p.Name(),
+			Enabled:            s.pluginManager != nil && s.pluginManager.HasPlugin(p.Name()),
+			Priority:           plugin.GetPluginPriority(p),
+			DefaultPriority:    p.Priority(),
+			PriorityOverridden: overridden,
+			SkipServiceFilter:  p.SkipServiceFilter(),
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// GetPluginStatus 获取单个插件的状态
+func (s *SearchService) GetPluginStatus(name string) (PluginAdminStatus, error) {
+	for _, status := range s.ListPluginStatuses() {
+		if status.Name == name {
+			return status, nil
+		}
+	}
+	return PluginAdminStatus{}, ErrPluginNotFound
+}
+
+// SetPluginEnabled 运行时启用或禁用插件
+func (s *SearchService) SetPluginEnabled(name string, enabled bool) error {
+	if _, exists := plugin.GetPluginByName(name); !exists || s.pluginManager == nil {
+		return ErrPluginNotFound
+	}
+
+	pluginAdminMutex.Lock()
+	defer pluginAdminMutex.Unlock()
+
+	if !enabled {
+		s.pluginManager.DisablePlugin(name)
+	} else {
+		s.pluginManager.EnablePlugin(name)
+		// 新启用的插件需要注入主缓存更新函数
+		if cacheInitialized && enhancedTwoLevelCache != nil {
+			injectMainCacheToAsyncPlugins(s.pluginManager, enhancedTwoLevelCache)
+		}
+	}
+
+	// 启用的插件变化后，未指定插件的搜索换用新的缓存键，旧结果不再命中
+	updateEnabledPluginsCacheKey(s.pluginManager)
+	return nil
+}
+
+// updateEnabledPluginsCacheKey 按插件管理器中启用的插件更新"所有插件"的缓存键
+func updateEnabledPluginsCacheKey(pluginManager *plugin.PluginManager) {
+	plugins := pluginManager.GetPlugins()
+	names := make([]string, 0, len(plugins))
+	for _, p := range plugins {
+		names = append(names, p.Name())
+	}
+	cache.SetEnabledPlugins(names)
+}
+
+// SetPluginPriority 运行时覆盖插件优先级，priority为0时恢复插件内置优先级
+func (s *SearchService) SetPluginPriority(name string, priority int) error {
+	if _, exists := plugin.GetPluginByName(name); !exists {
+		return ErrPluginNotFound
+	}
+
+	if priority == 0 {
+		plugin.ClearPriorityOverride(name)
+	} else {
+		plugin.SetPriorityOverride(name, priority)
+	}
+
+	// 插件等级已缓存，需要清空后重新计算
+	invalidatePluginLevelCache()
+	return nil
+}
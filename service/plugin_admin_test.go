@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"pansou/plugin"
+	"pansou/util/cache"
+)
+
+// TestSetPluginEnabledRekeysAllPluginsCache 启用或禁用插件后未指定插件的搜索使用新的缓存键
+func TestSetPluginEnabledRekeysAllPluginsCache(t *testing.T) {
+	p := newRecordingPlugin("admin_rekey")
+	plugin.RegisterGlobalPlugin(p)
+	t.Cleanup(func() { plugin.UnregisterGlobalPlugin("admin_rekey") })
+
+	pm := plugin.NewPluginManager()
+	s := NewSearchService(pm)
+	before := cache.GeneratePluginCacheKey("流浪地球", nil, nil)
+
+	if err := s.SetPluginEnabled("admin_rekey", true); err != nil {
+		t.Fatal(err)
+	}
+	enabled := cache.GeneratePluginCacheKey("流浪地球", nil, nil)
+	if enabled == before {
+		t.Error("启用插件后缓存键应变化")
+	}
+
+	if err := s.SetPluginEnabled("admin_rekey", false); err != nil {
+		t.Fatal(err)
+	}
+	if disabled := cache.GeneratePluginCacheKey("流浪地球", nil, nil); disabled != before {
+		t.Error("恢复原插件集合后缓存键应与原来相同")
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"pansou/plugin"
+)
+
+// PluginInfo 插件列表接口返回的插件信息
+type PluginInfo struct {
+	Name              string `json:"name"`
+	Priority          int    `json:"priority"`            // 生效的优先级
+	SkipServiceFilter bool   `json:"skip_service_filter"` // 是否跳过Service层关键词过滤
+	plugin.PluginMetadata
+}
+
+// ListPluginInfos 列出参与搜索的插件及其元数据，按名称排序
+func (s *SearchService) ListPluginInfos() []PluginInfo {
+	if s.pluginManager == nil {
+		return []PluginInfo{}
+	}
+
+	plugins := s.pluginManager.GetPlugins()
+	infos := make([]PluginInfo, 0, len(plugins))
+	for _, p := range plugins {
+		infos = append(infos, PluginInfo{
+			Name:              p.Name(),
+			Priority:          plugin.GetPluginPriority(p),
+			SkipServiceFilter: p.SkipServiceFilter(),
+			PluginMetadata:    plugin.GetPluginMetadata(p),
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// ValidateExt 按本次搜索会调用的插件声明的参数校验ext，返回把值转换为声明的类型后的副本，不修改传入的ext
+func (s *SearchService) ValidateExt(sourceType string, plugins []string, ext map[string]interface{}) (map[string]interface{}, error) {
+	if sourceType == "tg" || s.pluginManager == nil || len(ext) == 0 {
+		return ext, nil
+	}
+
+	requested := make(map[string]bool)
+	for _, name := range plugins {
+		if name != "" {
+			requested[strings.ToLower(name)] = true
+		}
+	}
+
+	var selected []plugin.AsyncSearchPlugin
+	for _, p := range s.pluginManager.GetPlugins() {
+		if len(requested) == 0 || requested[strings.ToLower(p.Name())] {
+			selected = append(selected, p)
+		}
+	}
+	return plugin.ValidateExt(selected, ext)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"pansou/model"
+	"pansou/util/release"
+	"pansou/util/textnorm"
+)
+
+// digitRunPattern 文本中连续的数字，长度为4且以19或20开头的视为年份
+// 不用前后非数字的边界匹配年份，边界字符会被消耗，相邻的年份（如"2019 2023"）只能匹配到第一个
+var digitRunPattern = regexp.MustCompile(`[0-9]+`)
+
+// resultFilter 编译后的结果过滤条件
+type resultFilter struct {
+	excludes    []string
+	phrases     []string
+	years       map[int]bool
+	releaseCond model.ResultFilter // 基于解析出的资源信息的条件
+}
+
+// newResultFilter 编译过滤条件：排除词按标准化形式匹配，精确短语只统一字符形式并保留词序，
+// 分辨率、编码等条件直接与解析出的资源信息比较
+func newResultFilter(filter model.ResultFilter) *resultFilter {
+	f := &resultFilter{years: make(map[int]bool, len(filter.Years)), releaseCond: filter}
+	for _, term := range filter.Excludes {
+		if normalized := textnorm.Normalize(term); normalized != "" {
+			f.excludes = append(f.excludes, normalized)
+		}
+	}
+	for _, phrase := range filter.Phrases {
+		if folded := collapseSpaces(textnorm.Fold(phrase)); folded != "" {
+			f.phrases = append(f.phrases, folded)
+		}
+	}
+	for _, year := range filter.Years {
+		f.years[year] = true
+	}
+	return f
+}
+
+// match 判断结果是否满足所有过滤条件
+func (f *resultFilter) match(result model.SearchResult) bool {
+	text := result.Title + " " + result.Content
+	normalized := textnorm.Normalize(text)
+
+	for _, term := range f.excludes {
+		if strings.Contains(normalized, term) {
+			return false
+		}
+	}
+
+	if len(f.phrases) > 0 {
+		folded := collapseSpaces(textnorm.Fold(text))
+		for _, phrase := range f.phrases {
+			if !strings.Contains(folded, phrase) {
+				return false
+			}
+		}
+	}
+
+	if len(f.years) > 0 && !f.matchYear(text) {
+		return false
+	}
+
+	return f.matchRelease(result.Release)
+}
+
+// matchRelease 判断解析出的资源信息是否满足分辨率、编码、动态范围、季号、字幕和大小条件，
+// 设置了某项条件时，没有解析出该项信息的结果视为不满足
+func (f *resultFilter) matchRelease(info *model.ReleaseInfo) bool {
+	c := f.releaseCond
+	if len(c.Resolutions) == 0 && len(c.Codecs) == 0 && len(c.HDR) == 0 && len(c.Seasons) == 0 &&
+		len(c.Subtitles) == 0 && c.MinSize == 0 && c.MaxSize == 0 {
+		return true
+	}
+	if info == nil {
+		return false
+	}
+
+	if len(c.Resolutions) > 0 && !slices.Contains(c.Resolutions, info.Resolution) {
+		return false
+	}
+	if len(c.Codecs) > 0 && !slices.Contains(c.Codecs, info.Codec) {
+		return false
+	}
+	if len(c.HDR) > 0 && !slices.ContainsFunc(c.HDR, func(want string) bool {
+		return release.MatchHDR(info.HDR, want)
+	}) {
+		return false
+	}
+	if len(c.Seasons) > 0 && !slices.ContainsFunc(c.Seasons, func(season int) bool {
+		return release.MatchSeason(info.Season, info.SeasonEnd, season)
+	}) {
+		return false
+	}
+	if len(c.Subtitles) > 0 && !slices.ContainsFunc(c.Subtitles, func(want string) bool {
+		return release.MatchSubtitle(info.Subtitles, want)
+	}) {
+		return false
+	}
+	if (c.MinSize > 0 || c.MaxSize > 0) && info.SizeBytes == 0 {
+		return false
+	}
+	if c.MinSize > 0 && info.SizeBytes < c.MinSize {
+		return false
+	}
+	if c.MaxSize > 0 && info.SizeBytes > c.MaxSize {
+		return false
+	}
+	return true
+}
+
+// matchYear 判断文本中是否出现任一指定年份
+func (f *resultFilter) matchYear(text string) bool {
+	for _, run := range digitRunPattern.FindAllString(text, -1) {
+		if len(run) != 4 || !(strings.HasPrefix(run, "19") || strings.HasPrefix(run, "20")) {
+			continue
+		}
+		if year, err := strconv.Atoi(run); err == nil && f.years[year] {
+			return true
+		}
+	}
+	return false
+}
+
+// collapseSpaces 去掉首尾空白并将连续空白合并为一个空格
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// filterResults 按查询语法中的过滤条件筛选结果
+func filterResults(results []model.SearchResult, filter model.ResultFilter) []model.SearchResult {
+	if filter.IsEmpty() {
+		return results
+	}
+
+	f := newResultFilter(filter)
+	filtered := make([]model.SearchResult, 0, len(results))
+	for _, result := range results {
+		if f.match(result) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"pansou/model"
+)
+
+// TestResultFilterMatchYear 相邻出现的多个年份都能匹配，更长数字中的四位数不视为年份
+func TestResultFilterMatchYear(t *testing.T) {
+	tests := []struct {
+		title string
+		years []int
+		want  bool
+	}{
+		{"流浪地球 2019 2023 合集", []int{2023}, true},
+		{"流浪地球 2019 2023 合集", []int{2019}, true},
+		{"流浪地球(2019)(2023)", []int{2023}, true},
+		{"流浪地球2 2023", []int{2019}, false},
+		{"编号 120230 资源", []int{2023}, false},
+		{"1080p 3000 MB", []int{2023}, false},
+	}
+	for _, tc := range tests {
+		f := newResultFilter(model.ResultFilter{Years: tc.years})
+		if got := f.match(model.SearchResult{Title: tc.title}); got != tc.want {
+			t.Errorf("标题%q按年份%v过滤 = %v，期望%v", tc.title, tc.years, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/plugin"
+	"pansou/util/cache"
+	"pansou/util/ranking"
+)
+
+// ErrInvalidCursor 分页游标无法解析或与当前查询参数不匹配
+var ErrInvalidCursor = errors.New("无效的分页游标")
+
+// ErrInvalidPage 页码过大，偏移量超出整数范围
+var ErrInvalidPage = errors.New("无效的页码")
+
+// SearchPage 分页搜索
+// 第一页执行正常搜索并保存合并排序后的完整结果快照，后续页（page>1或携带cursor）直接从快照分页，不再重新发起搜索
+func (s *SearchService) SearchPage(ctx context.Context, keyword string, channels []string, concurrency int, forceRefresh bool, resultType string, sourceType string, plugins []string, cloudTypes []string, ext map[string]interface{}, opts model.ResultOptions, page int, pageSize int, cursor string) (model.SearchResponse, error) {
+	// 源类型标准化
+	if sourceType == "" {
+		sourceType = "all"
+	}
+
+	// 快照键包含所有影响结果的参数，保证游标只能用于同一查询
+	snapshotKey := generatePageSnapshotKey(keyword, channels, sourceType, s.normalizePlugins(sourceType, plugins), cloudTypes, resultType, ext, opts)
+
+	// 解析分页参数，cursor优先于page
+	var offset int
+	if cursor != "" {
+		cursorKey, cursorOffset, cursorPageSize, err := decodePageCursor(cursor)
+		if err != nil || cursorKey != snapshotKey {
+			return model.SearchResponse{}, ErrInvalidCursor
+		}
+		offset = cursorOffset
+		pageSize = normalizePageSize(cursorPageSize)
+	} else {
+		pageSize = normalizePageSize(pageSize)
+		if page < 1 {
+			page = 1
+		}
+		if page > math.MaxInt/pageSize {
+			return model.SearchResponse{}, ErrInvalidPage
+		}
+		offset = (page - 1) * pageSize
+	}
+
+	// 后续页直接从快照分页
+	if offset > 0 && !forceRefresh {
+		if response, hit := loadPageSnapshot(snapshotKey); hit {
+			return paginateResponse(response, snapshotKey, offset, pageSize), nil
+		}
+	}
+
+	// 第一页或快照已失效，执行完整搜索并保存快照
+	response, err := s.SearchWithContext(ctx, keyword, channels, concurrency, forceRefresh, resultType, sourceType, plugins, cloudTypes, ext, opts)
+	if err != nil {
+		return model.SearchResponse{}, err
+	}
+	storePageSnapshot(snapshotKey, response)
+
+	return paginateResponse(response, snapshotKey, offset, pageSize), nil
+}
+
+// normalizePageSize 每页数量标准化，未指定时使用默认值，超过上限时截断
+func normalizePageSize(pageSize int) int {
+	if pageSize <= 0 {
+		return config.AppConfig.DefaultPageSize
+	}
+	if pageSize > config.AppConfig.MaxPageSize {
+		return config.AppConfig.MaxPageSize
+	}
+	return pageSize
+}
+
+// generatePageSnapshotKey 生成分页快照的缓存键
+func generatePageSnapshotKey(keyword string, channels []string, sourceType string, plugins []string, cloudTypes []string, resultType string, ext map[string]interface{}, opts model.ResultOptions) string {
+	// 网盘类型不区分顺序和大小写
+	normalizedTypes := make([]string, 0, len(cloudTypes))
+	for _, t := range cloudTypes {
+		normalizedTypes = append(normalizedTypes, strings.ToLower(strings.TrimSpace(t)))
+	}
+	sort.Strings(normalizedTypes)
+
+	// 排序方式和过滤条件不同时快照内容不同，空排序方式与relevance等价
+	sortMode := opts.Sort
+	if sortMode == "" {
+		sortMode = ranking.SortRelevance
+	}
+
+	keyStr := fmt.Sprintf("page:%s:%s:%s:%s:%t:%v:%s", cache.GenerateCacheKey(keyword, channels, sourceType, plugins), resultType, strings.Join(normalizedTypes, ","), sortMode, opts.Explain, opts.Filter, plugin.ExtHash(ext))
+	hash := md5.Sum([]byte(keyStr))
+	return hex.EncodeToString(hash[:])
+}
+
+// encodePageCursor 将快照键、偏移量和每页数量编码为不透明游标
+func encodePageCursor(snapshotKey string, offset int, pageSize int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d:%d", snapshotKey, offset, pageSize)))
+}
+
+// decodePageCursor 解析分页游标
+func decodePageCursor(cursor string) (string, int, int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	parts := strings.Split(string(data), ":")
+	if len(parts) != 3 {
+		return "", 0, 0, ErrInvalidCursor
+	}
+
+	offset, err := strconv.Atoi(parts[1])
+	if err != nil || offset < 0 {
+		return "", 0, 0, ErrInvalidCursor
+	}
+	pageSize, err := strconv.Atoi(parts[2])
+	if err != nil || pageSize <= 0 {
+		return "", 0, 0, ErrInvalidCursor
+	}
+
+	return parts[0], offset, pageSize, nil
+}
+
+// loadPageSnapshot 从缓存读取分页快照
+func loadPageSnapshot(snapshotKey string) (model.SearchResponse, bool) {
+	if !cacheInitialized || !config.AppConfig.CacheEnabled || enhancedTwoLevelCache == nil {
+		return model.SearchResponse{}, false
+	}
+
+	data, hit, err := enhancedTwoLevelCache.Get(snapshotKey)
+	if err != nil || !hit {
+		return model.SearchResponse{}, false
+	}
+
+	var response model.SearchResponse
+	if err := enhancedTwoLevelCache.GetSerializer().Deserialize(data, &response); err != nil {
+		return model.SearchResponse{}, false
+	}
+	return response, true
+}
+
+// storePageSnapshot 将完整结果保存为分页快照（仅内存），有效期与主缓存一致
+func storePageSnapshot(snapshotKey string, response model.SearchResponse) {
+	if !cacheInitialized || !config.AppConfig.CacheEnabled || enhancedTwoLevelCache == nil {
+		return
+	}
+
+	data, err := enhancedTwoLevelCache.GetSerializer().Serialize(response)
+	if err != nil {
+		return
+	}
+
+	ttl := time.Duration(config.AppConfig.CacheTTLMinutes) * time.Minute
+	enhancedTwoLevelCache.SetMemoryOnly(snapshotKey, data, ttl)
+}
+
+// paginateResponse 从完整响应中截取一页，Results和MergedByType分别按偏移量分页
+func paginateResponse(response model.SearchResponse, snapshotKey string, offset int, pageSize int) model.SearchResponse {
+	paged := model.SearchResponse{
+		Total:    response.Total,
+		Page:     offset/pageSize + 1,
+		PageSize: pageSize,
+	}
+
+	if response.Results != nil {
+		var more bool
+		paged.Results, more = pageResults(response.Results, offset, pageSize)
+		paged.HasMore = paged.HasMore || more
+	}
+	if response.MergedByType != nil {
+		var more bool
+		paged.MergedByType, more = pageMergedLinks(response.MergedByType, offset, pageSize)
+		paged.HasMore = paged.HasMore || more
+	}
+
+	if paged.HasMore {
+		paged.NextCursor = encodePageCursor(snapshotKey, offset+pageSize, pageSize)
+	}
+	return paged
+}
+
+// pageResults 截取结果列表的一页，返回该页结果和是否还有更多
+func pageResults(results []model.SearchResult, offset int, pageSize int) ([]model.SearchResult, bool) {
+	if offset < 0 || offset >= len(results) {
+		return []model.SearchResult{}, false
+	}
+	end := offset + pageSize
+	if end > len(results) {
+		end = len(results)
+	}
+	return results[offset:end], end < len(results)
+}
+
+// pageMergedLinks 按网盘类型名称顺序展开合并链接后截取一页，返回该页链接和是否还有更多
+func pageMergedLinks(mergedLinks model.MergedLinks, offset int, pageSize int) (model.MergedLinks, bool) {
+	if offset < 0 {
+		return model.MergedLinks{}, false
+	}
+
+	types := make([]string, 0, len(mergedLinks))
+	for cloudType := range mergedLinks {
+		types = append(types, cloudType)
+	}
+	sort.Strings(types)
+
+	paged := make(model.MergedLinks)
+	skipped, taken := 0, 0
+	for _, cloudType := range types {
+		links := mergedLinks[cloudType]
+
+		// 跳过偏移量之前的链接
+		if skipped+len(links) <= offset {
+			skipped += len(links)
+			continue
+		}
+		start := 0
+		if skipped < offset {
+			start = offset - skipped
+			skipped = offset
+		}
+
+		// 已取满一页，剩余链接说明还有下一页
+		if taken == pageSize {
+			return paged, true
+		}
+
+		end := start + (pageSize - taken)
+		if end > len(links) {
+			end = len(links)
+		}
+		paged[cloudType] = links[start:end]
+		taken += end - start
+
+		if end < len(links) {
+			return paged, true
+		}
+	}
+	return paged, false
+}
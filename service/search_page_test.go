@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"pansou/config"
+	"pansou/model"
+)
+
+// TestGeneratePageSnapshotKeyExt ext不同时快照键不同，键的顺序不影响快照键
+func TestGeneratePageSnapshotKeyExt(t *testing.T) {
+	key := func(ext map[string]interface{}) string {
+		return generatePageSnapshotKey("流浪地球", nil, "all", nil, nil, "merged_by_type", ext, model.ResultOptions{})
+	}
+
+	base := key(nil)
+	if key(map[string]interface{}{}) != base {
+		t.Error("空ext与nil的快照键应相同")
+	}
+
+	a := key(map[string]interface{}{"title_en": "The Wandering Earth", "is_all": true})
+	b := key(map[string]interface{}{"is_all": true, "title_en": "The Wandering Earth"})
+	if a != b {
+		t.Error("相同ext的快照键应相同")
+	}
+	if a == base {
+		t.Error("ext不同时快照键应不同")
+	}
+	if c := key(map[string]interface{}{"title_en": "The Wandering Earth", "is_all": false}); c == a {
+		t.Error("ext值不同时快照键应不同")
+	}
+}
+
+// TestSearchPageRejectsOverflowingPage 页码过大导致偏移量溢出时返回错误，不执行搜索
+func TestSearchPageRejectsOverflowingPage(t *testing.T) {
+	t.Setenv("CACHE_ENABLED", "false")
+	config.Init()
+
+	s := &SearchService{}
+	for _, page := range []int{461168601842738791, math.MaxInt} {
+		_, err := s.SearchPage(context.Background(), "流浪地球", nil, 0, false, "merged_by_type", "all", nil, nil, nil, model.ResultOptions{}, page, 20, "")
+		if !errors.Is(err, ErrInvalidPage) {
+			t.Errorf("page=%d 返回 %v，期望ErrInvalidPage", page, err)
+		}
+	}
+}
+
+// TestPageHelpersNegativeOffset 偏移量为负时返回空页，不越界
+func TestPageHelpersNegativeOffset(t *testing.T) {
+	results := []model.SearchResult{{UniqueID: "a"}, {UniqueID: "b"}}
+	if page, more := pageResults(results, -20, 20); len(page) != 0 || more {
+		t.Errorf("pageResults(-20) = %v, %t", page, more)
+	}
+
+	merged := model.MergedLinks{"quark": {{URL: "https://pan.quark.cn/s/a"}}}
+	if page, more := pageMergedLinks(merged, -20, 20); len(page) != 0 || more {
+		t.Errorf("pageMergedLinks(-20) = %v, %t", page, more)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -17,7 +19,11 @@ import (
 	"pansou/plugin"
 	"pansou/util"
 	"pansou/util/cache"
+	"pansou/util/logger"
 	"pansou/util/pool"
+	"pansou/util/ranking"
+	"pansou/util/release"
+	"pansou/util/textnorm"
 )
 
 // normalizeUrl 标准化URL，将URL编码的中文部分解码为中文，用于去重
@@ -49,38 +55,14 @@ func GetEnhancedTwoLevelCache() *cache.EnhancedTwoLevelCache {
 	return enhancedTwoLevelCache
 }
 
-// 优先关键词列表
-var priorityKeywords = []string{"合集", "系列", "全", "完", "最新", "附", "complete"}
 
-// extractKeywordFromCacheKey 从缓存键中提取关键词（简化版）
-func extractKeywordFromCacheKey(cacheKey string) string {
-	// 这是一个简化的实现，实际中我们会通过传递来获得关键词
-	// 为了演示，这里返回简化的显示
-	return "搜索关键词"
-}
-
-// logAsyncCacheWithKeyword 异步缓存日志输出辅助函数（带关键词）
-func logAsyncCacheWithKeyword(keyword, cacheKey string, format string, args ...interface{}) {
-	// 检查配置开关
+// logAsyncCache 输出异步插件缓存更新日志，受ASYNC_LOG_ENABLED和插件日志级别控制
+func logAsyncCache(call *plugin.SearchCall, pluginName string, msg string, args ...any) {
 	if config.AppConfig == nil || !config.AppConfig.AsyncLogEnabled {
 		return
 	}
-	
-	// 构建显示的关键词信息
-	displayKeyword := keyword
-	if displayKeyword == "" {
-		displayKeyword = "未知"
-	}
-	
-	// 将缓存键替换为简化版本+关键词
-	shortKey := cacheKey
-	if len(cacheKey) > 8 {
-		shortKey = cacheKey[:8] + "..."
-	}
-	
-	// 替换格式字符串中的缓存键
-	enhancedFormat := strings.Replace(format, cacheKey, fmt.Sprintf("%s(关键词:%s)", shortKey, displayKeyword), 1)
-	fmt.Printf(enhancedFormat, args...)
+	args = append([]any{"keyword", call.Keyword, "cache_key", call.MainCacheKey}, args...)
+	logger.ForPlugin(pluginName).Log(call.Ctx, slog.LevelInfo, msg, args...)
 }
 
 // 全局缓存实例和缓存是否初始化标志
@@ -217,6 +199,11 @@ func NewSearchService(pluginManager *plugin.PluginManager) *SearchService {
 	// 将主缓存注入到异步插件中
 	injectMainCacheToAsyncPlugins(pluginManager, enhancedTwoLevelCache)
 	
+	// "所有插件"的缓存键按实际启用的插件计算
+	if pluginManager != nil {
+		updateEnabledPluginsCacheKey(pluginManager)
+	}
+	
 	// 确保缓存写入管理器设置了主缓存更新函数
 	if globalCacheWriteManager != nil && enhancedTwoLevelCache != nil {
 		globalCacheWriteManager.SetMainCacheUpdater(func(key string, data []byte, ttl time.Duration) error {
@@ -243,7 +230,9 @@ func injectMainCacheToAsyncPlugins(pluginManager *plugin.PluginManager, mainCach
 	}
 	
 	// 创建缓存更新函数（支持IsFinal参数）- 接收原始数据并与现有缓存合并
-	cacheUpdater := func(key string, newResults []model.SearchResult, ttl time.Duration, isFinal bool, keyword string, pluginName string) error {
+	cacheUpdater := func(call *plugin.SearchCall, newResults []model.SearchResult, ttl time.Duration, isFinal bool, pluginName string) error {
+		key, keyword := call.MainCacheKey, call.Keyword
+		
 		// 优化：如果新结果为空，跳过缓存更新（避免无效操作）
 		if len(newResults) == 0 {
 			return nil
@@ -256,41 +245,22 @@ func injectMainCacheToAsyncPlugins(pluginManager *plugin.PluginManager, mainCach
 			if err := mainCache.GetSerializer().Deserialize(existingData, &existingResults); err == nil {
 				// 合并新旧结果，去重保留最完整的数据
 				finalResults = mergeSearchResults(existingResults, newResults)
-				if config.AppConfig != nil && config.AppConfig.AsyncLogEnabled {
-					if keyword != "" {
-						fmt.Printf("🔄 [%s:%s] 更新缓存| 原有: %d + 新增: %d = 合并后: %d\n", 
-						pluginName, keyword, len(existingResults), len(newResults), len(finalResults))
-					}
-				}
+				logAsyncCache(call, pluginName, "更新缓存", "existing", len(existingResults), "new", len(newResults), "merged", len(finalResults))
 			} else {
 				// 反序列化失败，使用新结果
 				finalResults = newResults
-							if config.AppConfig != nil && config.AppConfig.AsyncLogEnabled {
-				displayKey := key[:8] + "..."
-				if keyword != "" {
-					fmt.Printf("[异步插件 %s] 缓存反序列化失败，使用新结果: %s(关键词:%s) | 结果数: %d\n", pluginName, displayKey, keyword, len(newResults))
-				} else {
-					fmt.Printf("[异步插件 %s] 缓存反序列化失败，使用新结果: %s | 结果数: %d\n", pluginName, key, len(newResults))
-				}
-			}
+				logAsyncCache(call, pluginName, "缓存反序列化失败，使用新结果", "results", len(newResults), "error", err)
 			}
 		} else {
 			// 无现有缓存，直接使用新结果
 			finalResults = newResults
-					if config.AppConfig != nil && config.AppConfig.AsyncLogEnabled {
-			displayKey := key[:8] + "..."
-			if keyword != "" {
-				fmt.Printf("[异步插件 %s] 初始缓存创建: %s(关键词:%s) | 结果数: %d\n", pluginName, displayKey, keyword, len(newResults))
-			} else {
-				fmt.Printf("[异步插件 %s] 初始缓存创建: %s | 结果数: %d\n", pluginName, key, len(newResults))
-			}
-		}
+			logAsyncCache(call, pluginName, "初始缓存创建", "results", len(newResults))
 		}
 		
 		// 序列化合并后的结果
 		data, err := mainCache.GetSerializer().Serialize(finalResults)
 		if err != nil {
-			fmt.Printf("[缓存更新] 序列化失败: %s | 错误: %v\n", key, err)
+			logger.Error(call.Ctx, "缓存序列化失败", "plugin", pluginName, "cache_key", key, "error", err)
 			return err
 		}
 		
@@ -334,12 +304,12 @@ func injectMainCacheToAsyncPlugins(pluginManager *plugin.PluginManager, mainCach
 	
 	// 遍历所有插件，找出异步插件
 	for _, p := range plugins {
-		// 检查插件是否实现了SetMainCacheUpdater方法（修复后的签名，增加关键词参数）
-		if asyncPlugin, ok := p.(interface{ SetMainCacheUpdater(func(string, []model.SearchResult, time.Duration, bool, string) error) }); ok {
+		// 检查插件是否实现了SetMainCacheUpdater方法（关键词和主缓存键随调用状态传入）
+		if asyncPlugin, ok := p.(interface{ SetMainCacheUpdater(plugin.MainCacheUpdater) }); ok {
 			// 为每个插件创建专门的缓存更新函数，绑定插件名称
 			pluginName := p.Name()
-			pluginCacheUpdater := func(key string, newResults []model.SearchResult, ttl time.Duration, isFinal bool, keyword string) error {
-				return cacheUpdater(key, newResults, ttl, isFinal, keyword, pluginName)
+			pluginCacheUpdater := func(call *plugin.SearchCall, newResults []model.SearchResult, ttl time.Duration, isFinal bool) error {
+				return cacheUpdater(call, newResults, ttl, isFinal, pluginName)
 			}
 			// 注入缓存更新函数
 			asyncPlugin.SetMainCacheUpdater(pluginCacheUpdater)
@@ -347,8 +317,13 @@ func injectMainCacheToAsyncPlugins(pluginManager *plugin.PluginManager, mainCach
 	}
 }
 
-// Search 执行搜索
+// Search 执行搜索（兼容方法，不随请求取消）
 func (s *SearchService) Search(keyword string, channels []string, concurrency int, forceRefresh bool, resultType string, sourceType string, plugins []string, cloudTypes []string, ext map[string]interface{}) (model.SearchResponse, error) {
+	return s.SearchWithContext(context.Background(), keyword, channels, concurrency, forceRefresh, resultType, sourceType, plugins, cloudTypes, ext, model.ResultOptions{})
+}
+
+// SearchWithContext 执行搜索，ctx取消时中断进行中的频道和插件请求，opts控制结果的排序和展示
+func (s *SearchService) SearchWithContext(ctx context.Context, keyword string, channels []string, concurrency int, forceRefresh bool, resultType string, sourceType string, plugins []string, cloudTypes []string, ext map[string]interface{}, opts model.ResultOptions) (model.SearchResponse, error) {
 	// 确保ext不为nil
 	if ext == nil {
 		ext = make(map[string]interface{})
@@ -361,6 +336,60 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 	}
 
 	// 插件参数规范化处理
+	plugins = s.normalizePlugins(sourceType, plugins)
+
+	// 如果未指定并发数，使用配置中的默认值
+	if concurrency <= 0 {
+		concurrency = config.AppConfig.DefaultConcurrency
+	}
+
+	// 并行获取TG搜索和插件搜索结果
+	var tgResults []model.SearchResult
+	var pluginResults []model.SearchResult
+	
+	var wg sync.WaitGroup
+	var tgErr, pluginErr error
+	
+	// 如果需要搜索TG
+	if sourceType == "all" || sourceType == "tg" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tgResults, tgErr = s.searchTG(ctx, keyword, channels, forceRefresh)
+		}()
+	}
+	// 如果需要搜索插件（且插件功能已启用）
+	if (sourceType == "all" || sourceType == "plugin") && config.AppConfig.AsyncPluginEnabled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// 对于插件搜索，我们总是希望获取最新的缓存数据
+			// 因此，即使forceRefresh=false，我们也需要确保获取到最新的缓存
+			pluginResults, pluginErr = s.searchPlugins(ctx, keyword, plugins, forceRefresh, concurrency, ext)
+		}()
+	}
+	
+	// 等待所有搜索完成
+	wg.Wait()
+	
+	// 请求已取消，不再合并结果
+	if err := ctx.Err(); err != nil {
+		return model.SearchResponse{}, err
+	}
+	
+	// 检查错误
+	if tgErr != nil {
+		return model.SearchResponse{}, tgErr
+	}
+	if pluginErr != nil {
+		return model.SearchResponse{}, pluginErr
+	}
+	
+	return buildSearchResponse(tgResults, pluginResults, keyword, cloudTypes, resultType, opts), nil
+}
+
+// normalizePlugins 插件参数规范化处理，未指定或包含全部插件时统一返回nil
+func (s *SearchService) normalizePlugins(sourceType string, plugins []string) []string {
 	if sourceType == "tg" {
 		// 对于只搜索Telegram的请求，忽略插件参数
 		plugins = nil
@@ -422,54 +451,24 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 			}
 		}
 	}
-	
-	// 如果未指定并发数，使用配置中的默认值
-	if concurrency <= 0 {
-		concurrency = config.AppConfig.DefaultConcurrency
-	}
+	return plugins
+}
 
-	// 并行获取TG搜索和插件搜索结果
-	var tgResults []model.SearchResult
-	var pluginResults []model.SearchResult
-	
-	var wg sync.WaitGroup
-	var tgErr, pluginErr error
-	
-	// 如果需要搜索TG
-	if sourceType == "all" || sourceType == "tg" {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			tgResults, tgErr = s.searchTG(keyword, channels, forceRefresh)
-		}()
-	}
-	// 如果需要搜索插件（且插件功能已启用）
-	if (sourceType == "all" || sourceType == "plugin") && config.AppConfig.AsyncPluginEnabled {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			// 对于插件搜索，我们总是希望获取最新的缓存数据
-			// 因此，即使forceRefresh=false，我们也需要确保获取到最新的缓存
-			pluginResults, pluginErr = s.searchPlugins(keyword, plugins, forceRefresh, concurrency, ext)
-		}()
-	}
-	
-	// 等待所有搜索完成
-	wg.Wait()
-	
-	// 检查错误
-	if tgErr != nil {
-		return model.SearchResponse{}, tgErr
-	}
-	if pluginErr != nil {
-		return model.SearchResponse{}, pluginErr
-	}
-	
+// buildSearchResponse 合并TG与插件结果，排序、按网盘类型分组并构建响应
+func buildSearchResponse(tgResults, pluginResults []model.SearchResult, keyword string, cloudTypes []string, resultType string, opts model.ResultOptions) model.SearchResponse {
 	// 合并结果
 	allResults := mergeSearchResults(tgResults, pluginResults)
 
-	// 按照优化后的规则排序结果
-	sortResultsByTimeAndKeywords(allResults)
+	// 解析资源信息，用于过滤、排序和展示
+	for i := range allResults {
+		allResults[i].Release = release.Parse(allResults[i].Title, allResults[i].Content)
+	}
+
+	// 按查询语法中的条件过滤结果
+	allResults = filterResults(allResults, opts.Filter)
+
+	// 按排序方式排序结果
+	rankResults(allResults, keyword, opts)
 
 	// 过滤结果，只保留有时间的结果或包含优先关键词的结果或高等级插件结果到Results中
 	filteredForResults := make([]model.SearchResult, 0, len(allResults))
@@ -478,7 +477,7 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 		pluginLevel := getPluginLevelBySource(source)
 		
 		// 有时间的结果或包含优先关键词的结果或高等级插件(1-2级)结果保留在Results中
-		if !result.Datetime.IsZero() || getKeywordPriority(result.Title) > 0 || pluginLevel <= 2 {
+		if !result.Datetime.IsZero() || ranking.KeywordPriority(result.Title) > 0 || pluginLevel <= 2 {
 			filteredForResults = append(filteredForResults, result)
 		}
 	}
@@ -506,7 +505,7 @@ func (s *SearchService) Search(keyword string, channels []string, concurrency in
 	}
 
 	// 根据resultType过滤返回结果
-	return filterResponseByType(response, resultType), nil
+	return filterResponseByType(response, resultType)
 }
 
 // filterResponseByType 根据结果类型过滤响应
@@ -538,57 +537,51 @@ func filterResponseByType(response model.SearchResponse, resultType string) mode
 	}
 }
 
-// 根据时间和关键词排序结果
-func sortResultsByTimeAndKeywords(results []model.SearchResult) {
+// rankResults 按排序方式对结果排序，explain为true时在结果中附带得分明细
+func rankResults(results []model.SearchResult, keyword string, opts model.ResultOptions) {
+	ranker := ranking.NewRanker(map[string]float64{
+		ranking.ComponentTime:    config.AppConfig.RankWeightTime,
+		ranking.ComponentKeyword: config.AppConfig.RankWeightKeyword,
+		ranking.ComponentPlugin:  config.AppConfig.RankWeightPlugin,
+		ranking.ComponentTitle:   config.AppConfig.RankWeightTitle,
+		ranking.ComponentQuality: config.AppConfig.RankWeightQuality,
+	})
+
 	// 1. 计算每个结果的综合得分
-	scores := make([]ResultScore, len(results))
-	
+	candidates := make([]ranking.Candidate, len(results))
+	scores := make([]model.ScoreBreakdown, len(results))
 	for i, result := range results {
-		source := getResultSource(result)
-		
-		scores[i] = ResultScore{
-			Result:       result,
-			TimeScore:    calculateTimeScore(result.Datetime),
-			KeywordScore: getKeywordPriority(result.Title),
-			PluginScore:  getPluginLevelScore(source),
-			TotalScore:   0, // 稍后计算
+		candidates[i] = ranking.Candidate{
+			Title:       result.Title,
+			Datetime:    result.Datetime,
+			PluginLevel: getPluginLevelBySource(getResultSource(result)),
+			Release:     result.Release,
 		}
-		
-		// 计算综合得分
-		scores[i].TotalScore = scores[i].TimeScore + 
-							  float64(scores[i].KeywordScore) + 
-							  float64(scores[i].PluginScore)
+		scores[i] = ranker.Score(keyword, candidates[i])
 	}
-	
-	// 2. 按综合得分排序
-	sort.Slice(scores, func(i, j int) bool {
-		return scores[i].TotalScore > scores[j].TotalScore
-	})
-	
-	// 3. 更新原数组
-	for i, score := range scores {
-		results[i] = score.Result
-	}
-}
-
-
-
-
 
-// 获取标题中包含优先关键词的优先级
-func getKeywordPriority(title string) int {
-	title = strings.ToLower(title)
-	for i, keyword := range priorityKeywords {
-		if strings.Contains(title, keyword) {
-			// 返回优先级得分（数组索引越小，优先级越高，最高400分）
-			return (len(priorityKeywords) - i) * 70
+	// 2. 按排序方式排序并更新原数组
+	sorted := make([]model.SearchResult, len(results))
+	for i, index := range ranking.Order(opts.Sort, candidates, scores) {
+		sorted[i] = results[index]
+		if opts.Explain {
+			score := scores[index]
+			sorted[i].Score = &score
 		}
 	}
-	return 0
+	copy(results, sorted)
 }
 
-// 搜索单个频道
-func (s *SearchService) searchChannel(keyword string, channel string) ([]model.SearchResult, error) {
+// searchChannel 搜索单个频道并记录指标
+func (s *SearchService) searchChannel(ctx context.Context, keyword string, channel string) ([]model.SearchResult, error) {
+	start := time.Now()
+	results, err := s.fetchChannel(ctx, keyword, channel)
+	recordChannelMetrics(channel, time.Since(start), len(results), ctx.Err() != nil, err)
+	return results, err
+}
+
+// fetchChannel 请求并解析单个频道的搜索结果
+func (s *SearchService) fetchChannel(ctx context.Context, keyword string, channel string) ([]model.SearchResult, error) {
 	// 构建搜索URL
 	url := util.BuildSearchURL(channel, keyword, "")
 
@@ -596,7 +589,7 @@ func (s *SearchService) searchChannel(keyword string, channel string) ([]model.S
 	client := util.GetHTTPClient()
 
 	// 创建一个带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
 	defer cancel()
 
 	// 创建请求
@@ -939,11 +932,12 @@ func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes
 	// 创建合并结果的映射
 	mergedLinks := make(model.MergedLinks, 12) // 预分配容量，假设有12种不同的网盘类型
 
-	// 用于去重的映射，键为URL
+	// 用于去重的映射，键为链接指纹，同一分享的不同写法（查询参数、域名、磁力tracker等不同）视为同一链接
 	uniqueLinks := make(map[string]model.MergedLink)
 
-	// 将关键词转为小写，用于不区分大小写的匹配
-	lowerKeyword := strings.ToLower(keyword)
+	// 将关键词标准化，忽略大小写、全半角、繁简体、空格标点和数字写法的差异
+	normalizedKeyword := textnorm.Normalize(keyword)
+	symbolTerms := textnorm.SymbolTerms(keyword)
 
 	// 遍历所有搜索结果
 	for _, result := range results {
@@ -1037,7 +1031,8 @@ func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes
 			// 关键词过滤：现在我们有了准确的链接-标题对应关系，只需检查每个链接的具体标题
 			if !skipKeywordFilter && keyword != "" {
 				// 只检查链接的具体标题，无论是TG来源还是插件来源
-				if !strings.Contains(strings.ToLower(title), lowerKeyword) {
+				if !strings.Contains(textnorm.Normalize(title), normalizedKeyword) ||
+					(len(symbolTerms) > 0 && !textnorm.ContainsAll(textnorm.Fold(title), symbolTerms)) {
 					continue
 				}
 			}
@@ -1060,23 +1055,31 @@ func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes
 			
 			// 创建合并后的链接
 			mergedLink := model.MergedLink{
-				URL:      link.URL,
-				Password: link.Password,
-				Note:     title, // 使用找到的特定标题
-				Datetime: result.Datetime,
-				Source:   source, // 添加数据来源字段
-				Images:   result.Images, // 添加TG消息中的图片链接
+				URL:       link.URL,
+				Password:  link.Password,
+				Note:      title, // 使用找到的特定标题
+				Datetime:  result.Datetime,
+				Source:    source, // 添加数据来源字段
+				Images:    result.Images, // 添加TG消息中的图片链接
+				Score:     result.Score,  // 排序得分明细
+				Sources:   []string{source},
+				FirstSeen: result.Datetime,
+				LastSeen:  result.Datetime,
+				Release:   result.Release,
 			}
 
-			// 检查是否已存在相同URL的链接
-			if existingLink, exists := uniqueLinks[link.URL]; exists {
-				// 如果已存在，只有当当前链接的时间更新时才替换
-				if mergedLink.Datetime.After(existingLink.Datetime) {
-					uniqueLinks[link.URL] = mergedLink
-				}
+			// 多链接消息中每个链接有自己的标题时，按该标题解析资源信息
+			if title != result.Title {
+				mergedLink.Release = release.Parse(title, "")
+			}
+
+			// 检查是否已存在相同指纹的链接
+			fingerprint := util.LinkFingerprint(link.URL)
+			if existingLink, exists := uniqueLinks[fingerprint]; exists {
+				uniqueLinks[fingerprint] = mergeDuplicateLink(existingLink, mergedLink)
 			} else {
 				// 如果不存在，直接添加
-				uniqueLinks[link.URL] = mergedLink
+				uniqueLinks[fingerprint] = mergedLink
 			}
 		}
 	}
@@ -1087,21 +1090,14 @@ func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes
 	linkTypeMap := make(map[string]string) // URL -> Type的映射
 	
 	// 按原始results的顺序收集唯一链接
+	added := make(map[string]bool, len(uniqueLinks))
 	for _, result := range results {
 		for _, link := range result.Links {
-			if mergedLink, exists := uniqueLinks[link.URL]; exists {
-				// 检查是否已经添加过这个链接
-				found := false
-				for _, existing := range orderedLinks {
-					if existing.URL == link.URL {
-						found = true
-						break
-					}
-				}
-				if !found {
-					orderedLinks = append(orderedLinks, mergedLink)
-					linkTypeMap[link.URL] = link.Type
-				}
+			fingerprint := util.LinkFingerprint(link.URL)
+			if mergedLink, exists := uniqueLinks[fingerprint]; exists && !added[fingerprint] {
+				added[fingerprint] = true
+				orderedLinks = append(orderedLinks, mergedLink)
+				linkTypeMap[mergedLink.URL] = link.Type
 			}
 		}
 	}
@@ -1118,6 +1114,8 @@ func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes
 		mergedLinks[linkType] = append(mergedLinks[linkType], mergedLink)
 	}
 
+	// 已确认失效的链接排在最后
+	sortDeadLinksLast(mergedLinks)
 
 	// 如果指定了cloudTypes，则过滤结果
 	if len(cloudTypes) > 0 {
@@ -1143,8 +1141,40 @@ func mergeResultsByType(results []model.SearchResult, keyword string, cloudTypes
 	return mergedLinks
 }
 
+// mergeDuplicateLink 合并指纹相同的两个链接
+// 保留时间较新的链接信息，合并来源列表和最早/最晚发布时间，较新的链接没有提取码时沿用已有的提取码
+func mergeDuplicateLink(existing, current model.MergedLink) model.MergedLink {
+	merged := existing
+	if current.Datetime.After(existing.Datetime) {
+		merged = current
+		if merged.Password == "" {
+			merged.Password = existing.Password
+		}
+		if merged.Release == nil {
+			merged.Release = existing.Release
+		}
+	}
+
+	merged.Sources = existing.Sources
+	for _, source := range current.Sources {
+		if !slices.Contains(merged.Sources, source) {
+			merged.Sources = append(merged.Sources, source)
+		}
+	}
+
+	merged.FirstSeen = existing.FirstSeen
+	if merged.FirstSeen.IsZero() || (!current.FirstSeen.IsZero() && current.FirstSeen.Before(merged.FirstSeen)) {
+		merged.FirstSeen = current.FirstSeen
+	}
+	merged.LastSeen = existing.LastSeen
+	if current.LastSeen.After(merged.LastSeen) {
+		merged.LastSeen = current.LastSeen
+	}
+	return merged
+}
+
 // searchTG 搜索TG频道
-func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh bool) ([]model.SearchResult, error) {
+func (s *SearchService) searchTG(ctx context.Context, keyword string, channels []string, forceRefresh bool) ([]model.SearchResult, error) {
 	// 生成缓存键
 	cacheKey := cache.GenerateTGCacheKey(keyword, channels)
 	
@@ -1177,7 +1207,7 @@ func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh
 	for _, channel := range channels {
 		ch := channel // 创建副本，避免闭包问题
 		tasks = append(tasks, func() interface{} {
-			results, err := s.searchChannel(keyword, ch)
+			results, err := s.searchChannel(ctx, keyword, ch)
 			if err != nil {
 				return nil
 			}
@@ -1186,7 +1216,7 @@ func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh
 	}
 	
 	// 执行搜索任务并获取结果
-	taskResults := pool.ExecuteBatchWithTimeout(tasks, len(channels), config.AppConfig.PluginTimeout)
+	taskResults := pool.ExecuteBatchWithContext(ctx, tasks, len(channels), config.AppConfig.PluginTimeout)
 	
 	// 合并所有频道的结果
 	for _, result := range taskResults {
@@ -1196,8 +1226,8 @@ func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh
 		}
 	}
 	
-	// 异步缓存结果
-	if cacheInitialized && config.AppConfig.CacheEnabled {
+	// 异步缓存结果（请求已取消时结果不完整，不写入缓存）
+	if cacheInitialized && config.AppConfig.CacheEnabled && ctx.Err() == nil {
 		go func(res []model.SearchResult) {
 			ttl := time.Duration(config.AppConfig.CacheTTLMinutes) * time.Minute
 			
@@ -1216,14 +1246,14 @@ func (s *SearchService) searchTG(keyword string, channels []string, forceRefresh
 }
 
 // searchPlugins 搜索插件
-func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRefresh bool, concurrency int, ext map[string]interface{}) ([]model.SearchResult, error) {
+func (s *SearchService) searchPlugins(ctx context.Context, keyword string, plugins []string, forceRefresh bool, concurrency int, ext map[string]interface{}) ([]model.SearchResult, error) {
 	// 确保ext不为nil
 	if ext == nil {
 		ext = make(map[string]interface{})
 	}
 	
 	// 生成缓存键
-	cacheKey := cache.GeneratePluginCacheKey(keyword, plugins)
+	cacheKey := cache.GeneratePluginCacheKey(keyword, plugins, ext)
 	
 	
 	// 如果未启用强制刷新，尝试从缓存获取结果
@@ -1243,60 +1273,20 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 				var results []model.SearchResult
 				if err := enhancedTwoLevelCache.GetSerializer().Deserialize(data, &results); err == nil {
 					// 返回缓存数据
-					fmt.Printf("✅ [%s] 命中缓存 结果数: %d\n", keyword,  len(results))
+					logger.Info(ctx, "插件搜索命中缓存", "keyword", keyword, "results", len(results))
 					return results, nil
 				} else {
-					displayKey := cacheKey[:8] + "..."
-					fmt.Printf("[主服务] 缓存反序列化失败: %s(关键词:%s) | 错误: %v\n", displayKey, keyword, err)
+					logger.Warn(ctx, "缓存反序列化失败", "keyword", keyword, "cache_key", cacheKey, "error", err)
 				}
 			}
 		}
 	}
 	
 	// 缓存未命中或强制刷新，执行实际搜索
-	fmt.Printf("🔍 [%s] 开始插件搜索，缓存未命中\n", keyword)
+	logger.Info(ctx, "开始插件搜索，缓存未命中", "keyword", keyword)
 
 	// 获取所有可用插件
-	var availablePlugins []plugin.AsyncSearchPlugin
-	if s.pluginManager != nil {
-		allPlugins := s.pluginManager.GetPlugins()
-		fmt.Printf("📦 [%s] 插件管理器中的插件总数: %d\n", keyword, len(allPlugins))
-		
-		// 确保plugins不为nil并且有非空元素
-		hasPlugins := plugins != nil && len(plugins) > 0
-		hasNonEmptyPlugin := false
-		
-		if hasPlugins {
-			for _, p := range plugins {
-				if p != "" {
-					hasNonEmptyPlugin = true
-					break
-				}
-			}
-		}
-		
-		// 只有当plugins数组包含非空元素时才进行过滤
-		if hasPlugins && hasNonEmptyPlugin {
-			pluginMap := make(map[string]bool)
-			for _, p := range plugins {
-				if p != "" { // 忽略空字符串
-					pluginMap[strings.ToLower(p)] = true
-				}
-			}
-			
-			for _, p := range allPlugins {
-				if pluginMap[strings.ToLower(p.Name())] {
-					availablePlugins = append(availablePlugins, p)
-				}
-			}
-		} else {
-			// 如果plugins为nil、空数组或只包含空字符串，视为未指定，使用所有插件
-			availablePlugins = allPlugins
-			fmt.Printf("✅ [%s] 使用所有插件: %d 个\n", keyword, len(availablePlugins))
-		}
-	} else {
-		fmt.Printf("⚠️  [%s] 插件管理器为nil\n", keyword)
-	}
+	availablePlugins := s.selectPlugins(ctx, keyword, plugins)
 	
 	// 控制并发数
 	if concurrency <= 0 {
@@ -1309,16 +1299,7 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 	for _, p := range availablePlugins {
 		plugin := p // 创建副本，避免闭包问题
 		tasks = append(tasks, func() interface{} {
-			// 设置主缓存键和当前关键词
-			plugin.SetMainCacheKey(cacheKey)
-			plugin.SetCurrentKeyword(keyword)
-			
-			// 调用异步插件的AsyncSearch方法
-			results, err := plugin.AsyncSearch(keyword, func(client *http.Client, kw string, extParams map[string]interface{}) ([]model.SearchResult, error) {
-				// 使用插件的Search方法作为搜索函数
-				return plugin.Search(kw, extParams)
-			}, cacheKey, ext)
-			
+			results, err := s.searchSinglePlugin(ctx, plugin, keyword, cacheKey, ext)
 			if err != nil {
 				return nil
 			}
@@ -1327,27 +1308,22 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 	}
 
 	// 执行搜索任务并获取结果
-	fmt.Printf("🚀 [%s] 开始执行 %d 个插件搜索任务，并发数: %d\n", keyword, len(tasks), concurrency)
-	results := pool.ExecuteBatchWithTimeout(tasks, concurrency, config.AppConfig.PluginTimeout)
-	fmt.Printf("📥 [%s] 插件搜索完成，收到 %d 个结果\n", keyword, len(results))
+	logger.Debug(ctx, "开始执行插件搜索任务", "keyword", keyword, "tasks", len(tasks), "concurrency", concurrency)
+	results := pool.ExecuteBatchWithContext(ctx, tasks, concurrency, config.AppConfig.PluginTimeout)
+	logger.Debug(ctx, "插件搜索任务完成", "keyword", keyword, "completed", len(results))
 	
 	// 合并所有插件的结果，过滤掉无链接的结果
 	var allResults []model.SearchResult
 	for _, result := range results {
 		if result != nil {
-			pluginResults := result.([]model.SearchResult)
 			// 只添加有链接的结果到最终结果中
-			for _, pluginResult := range pluginResults {
-				if len(pluginResult.Links) > 0 {
-					allResults = append(allResults, pluginResult)
-				}
-			}
+			allResults = append(allResults, filterResultsWithLinks(result.([]model.SearchResult))...)
 		}
 	}
-	fmt.Printf("✨ [%s] 合并后有效结果数: %d\n", keyword, len(allResults))
+	logger.Info(ctx, "插件搜索完成", "keyword", keyword, "results", len(allResults))
 	
-	// 恢复主程序缓存更新：确保最终合并结果被正确缓存
-	if cacheInitialized && config.AppConfig.CacheEnabled {
+	// 恢复主程序缓存更新：确保最终合并结果被正确缓存（请求已取消时结果不完整，不写入缓存）
+	if cacheInitialized && config.AppConfig.CacheEnabled && ctx.Err() == nil {
 		go func(res []model.SearchResult, kw string, key string) {
 			ttl := time.Duration(config.AppConfig.CacheTTLMinutes) * time.Minute
 			
@@ -1355,16 +1331,15 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 			if enhancedTwoLevelCache != nil {
 				data, err := enhancedTwoLevelCache.GetSerializer().Serialize(res)
 				if err != nil {
-					fmt.Printf("[主程序] 缓存序列化失败: %s | 错误: %v\n", key, err)
+					logger.Error(ctx, "缓存序列化失败", "cache_key", key, "error", err)
 					return
 				}
 				
-			// 主程序最后更新，覆盖可能有问题的异步插件缓存
-			// 使用同步方式确保数据写入磁盘
-			enhancedTwoLevelCache.SetBothLevels(key, data, ttl)
+				// 主程序最后更新，覆盖可能有问题的异步插件缓存
+				// 使用同步方式确保数据写入磁盘
+				enhancedTwoLevelCache.SetBothLevels(key, data, ttl)
 				if config.AppConfig != nil && config.AppConfig.AsyncLogEnabled {
-					fmt.Printf("[主程序] 缓存更新完成: %s | 结果数: %d", 
-						key, len(res))
+					logger.Debug(ctx, "缓存更新完成", "cache_key", key, "results", len(res))
 				}
 			}
 		}(allResults, keyword, cacheKey)
@@ -1375,6 +1350,127 @@ func (s *SearchService) searchPlugins(keyword string, plugins []string, forceRef
 
 
 
+// selectPlugins 根据请求的插件列表筛选可用插件，未指定时返回全部插件
+func (s *SearchService) selectPlugins(ctx context.Context, keyword string, plugins []string) []plugin.AsyncSearchPlugin {
+	var availablePlugins []plugin.AsyncSearchPlugin
+	if s.pluginManager != nil {
+		allPlugins := s.pluginManager.GetPlugins()
+		logger.Debug(ctx, "插件管理器中的插件总数", "keyword", keyword, "plugins", len(allPlugins))
+		
+		// 确保plugins不为nil并且有非空元素
+		hasPlugins := plugins != nil && len(plugins) > 0
+		hasNonEmptyPlugin := false
+		
+		if hasPlugins {
+			for _, p := range plugins {
+				if p != "" {
+					hasNonEmptyPlugin = true
+					break
+				}
+			}
+		}
+		
+		// 只有当plugins数组包含非空元素时才进行过滤
+		if hasPlugins && hasNonEmptyPlugin {
+			pluginMap := make(map[string]bool)
+			for _, p := range plugins {
+				if p != "" { // 忽略空字符串
+					pluginMap[strings.ToLower(p)] = true
+				}
+			}
+			
+			for _, p := range allPlugins {
+				if pluginMap[strings.ToLower(p.Name())] {
+					availablePlugins = append(availablePlugins, p)
+				}
+			}
+		} else {
+			// 如果plugins为nil、空数组或只包含空字符串，视为未指定，使用所有插件
+			availablePlugins = allPlugins
+			logger.Debug(ctx, "使用所有插件", "keyword", keyword, "plugins", len(availablePlugins))
+		}
+	} else {
+		logger.Warn(ctx, "插件管理器为nil", "keyword", keyword)
+		return availablePlugins
+	}
+
+	// 跳过已熔断的插件
+	allowedPlugins := make([]plugin.AsyncSearchPlugin, 0, len(availablePlugins))
+	for _, p := range availablePlugins {
+		if s.pluginManager.AllowPlugin(p.Name()) {
+			allowedPlugins = append(allowedPlugins, p)
+		} else {
+			logger.Info(ctx, "插件已熔断，跳过", "keyword", keyword, "plugin", p.Name())
+		}
+	}
+	return allowedPlugins
+}
+
+// searchSinglePlugin 调用单个异步插件执行搜索，ctx取消时中断插件请求
+// 关键词和主缓存键按调用传入，插件Search收到的ext中携带本次调用状态，并发搜索互不干扰
+// 搜索结果和耗时记录到插件管理器，用于插件熔断：只有插件返回的错误和后台搜索的错误（含超过插件超时时间）计为失败，
+// 无结果不计为失败；响应超时转入后台的搜索在后台完成后再记录
+func (s *SearchService) searchSinglePlugin(ctx context.Context, p plugin.AsyncSearchPlugin, keyword string, cacheKey string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	start := time.Now()
+
+	// 合并全局参数和ext.plugins中该插件的参数
+	ext = plugin.PluginExt(ext, p.Name())
+
+	var cancelled bool
+	ctx, report := plugin.WithSearchReport(ctx, func(cacheHit bool, err error) {
+		// 客户端断开导致的中断不计入插件健康状态
+		if s.pluginManager == nil || cancelled {
+			return
+		}
+		// 命中插件级缓存时没有访问插件站点，不计入健康状态，也不作为半开状态的探测结果
+		if cacheHit {
+			s.pluginManager.ReleasePluginProbe(p.Name())
+			return
+		}
+		s.pluginManager.RecordPluginResult(p.Name(), time.Since(start), err)
+	})
+
+	results, err := p.AsyncSearchCtx(ctx, keyword, func(client *http.Client, kw string, extParams map[string]interface{}) ([]model.SearchResult, error) {
+		// 使用插件的Search方法作为搜索函数
+		return p.Search(kw, extParams)
+	}, cacheKey, ext)
+
+	cancelled = ctx.Err() != nil
+	recordPluginMetrics(p.Name(), time.Since(start), len(results), cancelled, err)
+	report.Done(err)
+
+	return results, err
+}
+
+// filterResultsWithLinks 只保留有链接的结果
+func filterResultsWithLinks(results []model.SearchResult) []model.SearchResult {
+	filtered := make([]model.SearchResult, 0, len(results))
+	for _, result := range results {
+		if len(result.Links) > 0 {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// loadCachedResults 从主缓存读取搜索结果
+func loadCachedResults(cacheKey string) ([]model.SearchResult, bool) {
+	if !cacheInitialized || !config.AppConfig.CacheEnabled || enhancedTwoLevelCache == nil {
+		return nil, false
+	}
+
+	data, hit, err := enhancedTwoLevelCache.Get(cacheKey)
+	if err != nil || !hit {
+		return nil, false
+	}
+
+	var results []model.SearchResult
+	if err := enhancedTwoLevelCache.GetSerializer().Deserialize(data, &results); err != nil {
+		return nil, false
+	}
+	return results, true
+}
+
 // GetPluginManager 获取插件管理器
 func (s *SearchService) GetPluginManager() *plugin.PluginManager {
 	return s.pluginManager
@@ -1384,20 +1480,19 @@ func (s *SearchService) GetPluginManager() *plugin.PluginManager {
 // 轻量级插件优先级排序实现
 // =============================================================================
 
-// ResultScore 搜索结果评分结构
-type ResultScore struct {
-	Result       model.SearchResult
-	TimeScore    float64  // 时间得分
-	KeywordScore int      // 关键词得分  
-	PluginScore  int      // 插件等级得分
-	TotalScore   float64  // 综合得分
-}
-
 // 插件等级缓存
 var (
-	pluginLevelCache = sync.Map{} // 插件等级缓存
+	pluginLevelCache = sync.Map{} // 插件等级缓存，插件优先级被覆盖时需要清空
 )
 
+// invalidatePluginLevelCache 清空插件等级缓存
+func invalidatePluginLevelCache() {
+	pluginLevelCache.Range(func(key, _ interface{}) bool {
+		pluginLevelCache.Delete(key)
+		return true
+	})
+}
+
 // getResultSource 从SearchResult推断数据来源
 func getResultSource(result model.SearchResult) string {
 	if result.Channel != "" {
@@ -1445,55 +1540,8 @@ func getPluginLevelBySource(source string) int {
 func getPluginPriorityByName(pluginName string) int {
 	// 从插件管理器动态获取真实的优先级 (O(1)哈希查找)
 	if pluginInstance, exists := plugin.GetPluginByName(pluginName); exists {
-		return pluginInstance.Priority()
+		return plugin.GetPluginPriority(pluginInstance)
 	}
 	return 3 // 默认等级
 }
 
-// getPluginLevelScore 获取插件等级得分
-func getPluginLevelScore(source string) int {
-	level := getPluginLevelBySource(source)
-	
-	switch level {
-	case 1:
-		return 1000  // 等级1插件：1000分
-	case 2:
-		return 500   // 等级2插件：500分
-	case 3:
-		return 0     // 等级3插件：0分
-	case 4:
-		return -200  // 等级4插件：-200分
-	default:
-		return 0     // 默认使用等级3得分
-	}
-}
-
-// calculateTimeScore 计算时间得分
-func calculateTimeScore(datetime time.Time) float64 {
-	if datetime.IsZero() {
-		return 0 // 无时间信息得0分
-	}
-	
-	now := time.Now()
-	daysDiff := now.Sub(datetime).Hours() / 24
-	
-	// 时间得分：越新得分越高，最大500分（增加时间权重）
-	switch {
-	case daysDiff <= 1:
-		return 500  // 1天内
-	case daysDiff <= 3:
-		return 400  // 3天内
-	case daysDiff <= 7:
-		return 300  // 1周内
-	case daysDiff <= 30:
-		return 200  // 1月内
-	case daysDiff <= 90:
-		return 100  // 3月内
-	case daysDiff <= 365:
-		return 50   // 1年内
-	default:
-		return 20   // 1年以上
-	}
-}
-
-
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/plugin"
+	"pansou/util/cache"
+)
+
+// callRecord 插件收到的一次调用状态
+type callRecord struct {
+	keyword      string // 插件搜索函数收到的关键词
+	callKeyword  string // SearchCall中的关键词
+	mainCacheKey string // SearchCall中的主缓存键
+	tag          interface{}
+	titles       []string // 写入主缓存的结果标题
+}
+
+// recordingPlugin 记录每次搜索和主缓存更新收到的调用状态的测试插件
+type recordingPlugin struct {
+	*plugin.BaseAsyncPlugin
+	mutex    sync.Mutex
+	searches []callRecord
+	updates  []callRecord
+}
+
+func newRecordingPlugin(name string) *recordingPlugin {
+	p := &recordingPlugin{BaseAsyncPlugin: plugin.NewBaseAsyncPlugin(name, 1)}
+	p.SetMainCacheUpdater(func(call *plugin.SearchCall, results []model.SearchResult, ttl time.Duration, isFinal bool) error {
+		record := callRecord{
+			callKeyword:  call.Keyword,
+			mainCacheKey: call.MainCacheKey,
+			tag:          call.Ext["tag"],
+		}
+		for _, result := range results {
+			record.titles = append(record.titles, result.Title)
+		}
+		p.mutex.Lock()
+		p.updates = append(p.updates, record)
+		p.mutex.Unlock()
+		return nil
+	})
+	return p
+}
+
+func (p *recordingPlugin) Search(keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	result, err := p.AsyncSearchWithResult(keyword, p.searchImpl, ext)
+	if err != nil {
+		return nil, err
+	}
+	return result.Results, nil
+}
+
+func (p *recordingPlugin) searchImpl(client *http.Client, keyword string, ext map[string]interface{}) ([]model.SearchResult, error) {
+	record := callRecord{keyword: keyword, tag: ext["tag"]}
+	if call := plugin.SearchCallFromExt(ext); call != nil {
+		record.callKeyword = call.Keyword
+		record.mainCacheKey = call.MainCacheKey
+	}
+	p.mutex.Lock()
+	p.searches = append(p.searches, record)
+	p.mutex.Unlock()
+
+	// 让并发调用交错执行
+	time.Sleep(5 * time.Millisecond)
+
+	return []model.SearchResult{{
+		UniqueID: p.Name() + "-" + keyword,
+		Title:    keyword,
+		Datetime: time.Now(),
+		Links:    []model.Link{{Type: "baidu", URL: "https://pan.baidu.com/s/1" + p.Name() + fmt.Sprint(len(keyword))}},
+	}}, nil
+}
+
+func (p *recordingPlugin) records() ([]callRecord, []callRecord) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]callRecord(nil), p.searches...), append([]callRecord(nil), p.updates...)
+}
+
+// TestSearchWithContextConcurrentCallState 并发搜索时每次调用的关键词和主缓存键只到达自己的插件调用和主缓存更新
+func TestSearchWithContextConcurrentCallState(t *testing.T) {
+	t.Setenv("CACHE_ENABLED", "false")
+	t.Setenv("ASYNC_PLUGIN_ENABLED", "true")
+	config.Init()
+
+	plugins := []*recordingPlugin{newRecordingPlugin("race_a"), newRecordingPlugin("race_b"), newRecordingPlugin("race_c")}
+	pm := plugin.NewPluginManager()
+	for _, p := range plugins {
+		pm.RegisterPlugin(p)
+	}
+	s := NewSearchService(pm)
+
+	keywords := make([]string, 16)
+	expectedKeys := make(map[string]string, len(keywords))
+	for i := range keywords {
+		keywords[i] = fmt.Sprintf("%s关键词%d", t.Name(), i)
+		expectedKeys[keywords[i]] = cache.GeneratePluginCacheKey(keywords[i], nil, map[string]interface{}{"tag": keywords[i]})
+	}
+
+	var wg sync.WaitGroup
+	for _, keyword := range keywords {
+		wg.Add(1)
+		go func(keyword string) {
+			defer wg.Done()
+			ext := map[string]interface{}{"tag": keyword}
+			resp, err := s.SearchWithContext(context.Background(), keyword, nil, 0, true, "results", "plugin", nil, nil, ext, model.ResultOptions{})
+			if err != nil {
+				t.Errorf("搜索%q失败: %v", keyword, err)
+				return
+			}
+			if len(resp.Results) != len(plugins) {
+				t.Errorf("搜索%q返回%d条结果，期望%d条", keyword, len(resp.Results), len(plugins))
+			}
+			for _, result := range resp.Results {
+				if result.Title != keyword {
+					t.Errorf("搜索%q返回了其他调用的结果%q", keyword, result.Title)
+				}
+			}
+		}(keyword)
+	}
+	wg.Wait()
+
+	// 主缓存更新在返回响应后异步进行
+	deadline := time.Now().Add(5 * time.Second)
+	for _, p := range plugins {
+		for {
+			_, updates := p.records()
+			seen := make(map[string]bool)
+			for _, u := range updates {
+				seen[u.callKeyword] = true
+			}
+			if len(seen) >= len(keywords) || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	for _, p := range plugins {
+		searches, updates := p.records()
+		searched := make(map[string]bool)
+		for _, r := range searches {
+			searched[r.keyword] = true
+			if r.callKeyword != r.keyword || r.tag != r.keyword || r.mainCacheKey != expectedKeys[r.keyword] {
+				t.Errorf("%s: 搜索%q收到的调用状态不属于本次调用: keyword=%q tag=%v key=%q",
+					p.Name(), r.keyword, r.callKeyword, r.tag, r.mainCacheKey)
+			}
+		}
+		updated := make(map[string]bool)
+		for _, u := range updates {
+			updated[u.callKeyword] = true
+			if u.tag != u.callKeyword || u.mainCacheKey != expectedKeys[u.callKeyword] {
+				t.Errorf("%s: 关键词%q的主缓存更新收到了其他调用的状态: tag=%v key=%q",
+					p.Name(), u.callKeyword, u.tag, u.mainCacheKey)
+			}
+			for _, title := range u.titles {
+				if title != u.callKeyword {
+					t.Errorf("%s: 关键词%q的主缓存更新包含其他调用的结果%q", p.Name(), u.callKeyword, title)
+				}
+			}
+		}
+		for _, keyword := range keywords {
+			if !searched[keyword] {
+				t.Errorf("%s: 未收到关键词%q的搜索", p.Name(), keyword)
+			}
+			if !updated[keyword] {
+				t.Errorf("%s: 未收到关键词%q的主缓存更新", p.Name(), keyword)
+			}
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"pansou/config"
+	"pansou/model"
+	"pansou/util/cache"
+	"pansou/util/release"
+)
+
+// streamTask 流式搜索中的单个来源任务
+type streamTask struct {
+	source string
+	run    func() ([]model.SearchResult, error)
+}
+
+// SearchStream 流式搜索，每个TG频道或插件完成时立即通过onSource推送事件，全部完成后返回合并结果
+// onSource会在多个goroutine中触发，内部已保证串行调用；ctx取消时中断所有来源并返回ctx错误
+func (s *SearchService) SearchStream(ctx context.Context, keyword string, channels []string, concurrency int, forceRefresh bool, resultType string, sourceType string, plugins []string, cloudTypes []string, ext map[string]interface{}, opts model.ResultOptions, onSource func(model.SearchStreamEvent)) (model.SearchResponse, error) {
+	// 确保ext不为nil
+	if ext == nil {
+		ext = make(map[string]interface{})
+	}
+
+	// 源类型标准化
+	if sourceType == "" {
+		sourceType = "all"
+	}
+
+	// 插件参数规范化处理
+	plugins = s.normalizePlugins(sourceType, plugins)
+
+	// 如果未指定并发数，使用配置中的默认值
+	if concurrency <= 0 {
+		concurrency = config.AppConfig.DefaultConcurrency
+	}
+
+	// 串行化事件推送，并统一计算耗时；每批结果按最终结果的规则过滤和排序，不推送最终结果中不会出现的链接
+	start := time.Now()
+	var emitMutex sync.Mutex
+	emit := func(event model.SearchStreamEvent) {
+		event.Results = filterStreamBatch(event.Results, keyword, cloudTypes, opts)
+		event.Count = len(event.Results)
+		event.ElapsedMs = time.Since(start).Milliseconds()
+		emitMutex.Lock()
+		defer emitMutex.Unlock()
+		onSource(event)
+	}
+
+	var tgResults []model.SearchResult
+	var pluginResults []model.SearchResult
+	var wg sync.WaitGroup
+
+	// 如果需要搜索TG
+	if sourceType == "all" || sourceType == "tg" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tgResults = s.streamTG(ctx, keyword, channels, forceRefresh, emit)
+		}()
+	}
+	// 如果需要搜索插件（且插件功能已启用）
+	if (sourceType == "all" || sourceType == "plugin") && config.AppConfig.AsyncPluginEnabled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pluginResults = s.streamPlugins(ctx, keyword, plugins, forceRefresh, concurrency, ext, emit)
+		}()
+	}
+
+	// 等待所有来源完成
+	wg.Wait()
+
+	// 请求已取消，不再合并结果
+	if err := ctx.Err(); err != nil {
+		return model.SearchResponse{}, err
+	}
+
+	return buildSearchResponse(tgResults, pluginResults, keyword, cloudTypes, resultType, opts), nil
+}
+
+// streamTG 流式搜索TG频道，缓存命中时一次性推送缓存结果
+func (s *SearchService) streamTG(ctx context.Context, keyword string, channels []string, forceRefresh bool, emit func(model.SearchStreamEvent)) []model.SearchResult {
+	// 与searchTG使用相同的缓存键
+	cacheKey := cache.GenerateTGCacheKey(keyword, channels)
+
+	if !forceRefresh {
+		if results, hit := loadCachedResults(cacheKey); hit {
+			emit(model.SearchStreamEvent{Source: "tg", Count: len(results), Cached: true, Results: results})
+			return results
+		}
+	}
+
+	tasks := make([]streamTask, 0, len(channels))
+	for _, channel := range channels {
+		ch := channel // 创建副本，避免闭包问题
+		tasks = append(tasks, streamTask{
+			source: "tg:" + ch,
+			run: func() ([]model.SearchResult, error) {
+				return s.searchChannel(ctx, keyword, ch)
+			},
+		})
+	}
+
+	results, complete := runStreamTasks(ctx, tasks, len(channels), config.AppConfig.PluginTimeout, emit)
+
+	// 异步缓存结果（超时或请求已取消时结果不完整，不写入缓存）
+	if complete {
+		go storeCachedResults(cacheKey, results, false)
+	}
+
+	return results
+}
+
+// streamPlugins 流式搜索插件，缓存命中时一次性推送缓存结果
+func (s *SearchService) streamPlugins(ctx context.Context, keyword string, plugins []string, forceRefresh bool, concurrency int, ext map[string]interface{}, emit func(model.SearchStreamEvent)) []model.SearchResult {
+	// 与searchPlugins使用相同的缓存键
+	cacheKey := cache.GeneratePluginCacheKey(keyword, plugins, ext)
+
+	if !forceRefresh {
+		if results, hit := loadCachedResults(cacheKey); hit {
+			emit(model.SearchStreamEvent{Source: "plugin", Count: len(results), Cached: true, Results: results})
+			return results
+		}
+	}
+
+	availablePlugins := s.selectPlugins(ctx, keyword, plugins)
+	tasks := make([]streamTask, 0, len(availablePlugins))
+	for _, p := range availablePlugins {
+		plugin := p // 创建副本，避免闭包问题
+		tasks = append(tasks, streamTask{
+			source: "plugin:" + plugin.Name(),
+			run: func() ([]model.SearchResult, error) {
+				results, err := s.searchSinglePlugin(ctx, plugin, keyword, cacheKey, ext)
+				// 只保留有链接的结果
+				return filterResultsWithLinks(results), err
+			},
+		})
+	}
+
+	results, complete := runStreamTasks(ctx, tasks, concurrency, config.AppConfig.PluginTimeout, emit)
+
+	// 异步缓存最终合并结果（超时或请求已取消时结果不完整，不写入缓存）
+	if complete {
+		go storeCachedResults(cacheKey, results, true)
+	}
+
+	return results
+}
+
+// runStreamTasks 并发执行来源任务，每个任务完成时立即推送事件，超时或ctx取消后不再等待剩余任务
+// 返回已收集的结果，以及是否所有任务都已完成（超时或ctx取消时为false）
+func runStreamTasks(ctx context.Context, tasks []streamTask, concurrency int, timeout time.Duration, emit func(model.SearchStreamEvent)) ([]model.SearchResult, bool) {
+	if len(tasks) == 0 {
+		return nil, ctx.Err() == nil
+	}
+	if concurrency <= 0 || concurrency > len(tasks) {
+		concurrency = len(tasks)
+	}
+
+	type taskResult struct {
+		source  string
+		results []model.SearchResult
+		err     error
+	}
+
+	resultChan := make(chan taskResult, len(tasks))
+	slots := make(chan struct{}, concurrency)
+	done := make(chan struct{})
+	defer close(done)
+
+	for _, task := range tasks {
+		go func(t streamTask) {
+			// 获取并发槽，超时后不再启动新任务
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slots }()
+
+			results, err := t.run()
+			resultChan <- taskResult{source: t.source, results: results, err: err}
+		}(task)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var allResults []model.SearchResult
+	for i := 0; i < len(tasks); i++ {
+		select {
+		case r := <-resultChan:
+			event := model.SearchStreamEvent{Source: r.source, Count: len(r.results), Results: r.results}
+			if r.err != nil {
+				event.Error = r.err.Error()
+			}
+			emit(event)
+			allResults = append(allResults, r.results...)
+		case <-timer.C:
+			// 超时，返回已收集的结果
+			return allResults, false
+		case <-ctx.Done():
+			// 请求已取消，返回已收集的结果
+			return allResults, false
+		}
+	}
+
+	return allResults, ctx.Err() == nil
+}
+
+// filterStreamBatch 对单个来源的一批结果应用与最终结果相同的查询语法过滤、排序、关键词过滤和网盘类型过滤，
+// 每条结果只保留最终merged_by_type中会出现的链接；返回新切片，不修改传入的结果（原结果仍用于合并和缓存）
+func filterStreamBatch(results []model.SearchResult, keyword string, cloudTypes []string, opts model.ResultOptions) []model.SearchResult {
+	if len(results) == 0 {
+		return results
+	}
+
+	batch := make([]model.SearchResult, len(results))
+	copy(batch, results)
+	for i := range batch {
+		batch[i].Release = release.Parse(batch[i].Title, batch[i].Content)
+	}
+	batch = filterResults(batch, opts.Filter)
+	rankResults(batch, keyword, opts)
+
+	kept := make([]model.SearchResult, 0, len(batch))
+	for _, result := range batch {
+		allowed := make(map[string]bool)
+		for _, links := range mergeResultsByType([]model.SearchResult{result}, keyword, cloudTypes) {
+			for _, link := range links {
+				allowed[link.URL] = true
+			}
+		}
+
+		links := make([]model.Link, 0, len(result.Links))
+		for _, link := range result.Links {
+			if allowed[link.URL] {
+				links = append(links, link)
+			}
+		}
+		if len(links) == 0 {
+			continue
+		}
+		result.Links = links
+		kept = append(kept, result)
+	}
+	return kept
+}
+
+// storeCachedResults 将搜索结果写入主缓存
+// bothLevels为true时同步写入内存和磁盘，否则由缓存自行决定写入方式
+func storeCachedResults(cacheKey string, results []model.SearchResult, bothLevels bool) {
+	if !cacheInitialized || !config.AppConfig.CacheEnabled || enhancedTwoLevelCache == nil {
+		return
+	}
+
+	data, err := enhancedTwoLevelCache.GetSerializer().Serialize(results)
+	if err != nil {
+		return
+	}
+
+	ttl := time.Duration(config.AppConfig.CacheTTLMinutes) * time.Minute
+	if bothLevels {
+		enhancedTwoLevelCache.SetBothLevels(cacheKey, data, ttl)
+	} else {
+		enhancedTwoLevelCache.Set(cacheKey, data, ttl)
+	}
+}
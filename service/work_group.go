@@ -0,0 +1,175 @@
+package service
+
+import (
+	"sort"
+
+	"pansou/model"
+	"pansou/util/release"
+	"pansou/util/textnorm"
+)
+
+// 链接分组方式
+const (
+	GroupByType = "type" // 按网盘类型分组（默认）
+	GroupByWork = "work" // 按作品分组
+)
+
+// IsValidGroup 判断分组方式是否有效，空字符串表示默认分组
+func IsValidGroup(group string) bool {
+	switch group {
+	case "", GroupByType, GroupByWork:
+		return true
+	}
+	return false
+}
+
+// workKey 作品分组键
+type workKey struct {
+	title     string // 标准化后的作品名
+	year      int
+	season    int
+	seasonEnd int
+}
+
+// workBuilder 构建中的作品分组
+type workBuilder struct {
+	key         workKey
+	group       model.WorkGroup
+	titleCounts map[string]int
+	titleOrder  []string
+	lastSeen    int64
+}
+
+// GroupResponseByWork 将响应中merged_by_type的链接按作品（标准化的作品名+年份+季）重新分组到works中，
+// 每组内仍按网盘类型分组并保持原有顺序；没有年份的链接在同名同季的作品只有一个年份时归入该作品
+func GroupResponseByWork(response *model.SearchResponse) {
+	if len(response.MergedByType) == 0 {
+		return
+	}
+	response.Works = groupLinksByWork(response.MergedByType)
+	response.MergedByType = nil
+}
+
+// groupLinksByWork 按作品分组链接
+func groupLinksByWork(merged model.MergedLinks) []model.WorkGroup {
+	types := make([]string, 0, len(merged))
+	for cloudType := range merged {
+		types = append(types, cloudType)
+	}
+	sort.Strings(types)
+
+	builders := make(map[workKey]*workBuilder)
+	var order []*workBuilder
+	for _, cloudType := range types {
+		for _, link := range merged[cloudType] {
+			title := release.Title(link.Note)
+			key := workKey{title: textnorm.Normalize(title)}
+			if link.Release != nil {
+				key.year = link.Release.Year
+				key.season, key.seasonEnd = link.Release.Season, link.Release.SeasonEnd
+			}
+
+			b, ok := builders[key]
+			if !ok {
+				b = &workBuilder{
+					key: key,
+					group: model.WorkGroup{
+						Year:         key.year,
+						Season:       key.season,
+						SeasonEnd:    key.seasonEnd,
+						MergedByType: make(model.MergedLinks),
+					},
+					titleCounts: make(map[string]int),
+				}
+				builders[key] = b
+				order = append(order, b)
+			}
+			b.add(cloudType, title, link)
+		}
+	}
+
+	// 没有年份的分组归入同名同季的唯一有年份的分组
+	yearsByTitle := make(map[workKey][]*workBuilder)
+	for _, b := range order {
+		if b.key.year != 0 {
+			key := b.key
+			key.year = 0
+			yearsByTitle[key] = append(yearsByTitle[key], b)
+		}
+	}
+	works := make([]*workBuilder, 0, len(order))
+	for _, b := range order {
+		if b.key.year == 0 {
+			if candidates := yearsByTitle[b.key]; len(candidates) == 1 {
+				candidates[0].absorb(b)
+				continue
+			}
+		}
+		works = append(works, b)
+	}
+
+	// 链接多的作品在前，数量相同时最近更新的在前
+	sort.SliceStable(works, func(i, j int) bool {
+		if works[i].group.Total != works[j].group.Total {
+			return works[i].group.Total > works[j].group.Total
+		}
+		return works[i].lastSeen > works[j].lastSeen
+	})
+
+	groups := make([]model.WorkGroup, len(works))
+	for i, b := range works {
+		groups[i] = b.build()
+	}
+	return groups
+}
+
+// add 将链接加入分组
+func (b *workBuilder) add(cloudType string, title string, link model.MergedLink) {
+	b.group.MergedByType[cloudType] = append(b.group.MergedByType[cloudType], link)
+	b.group.Total++
+
+	if _, ok := b.titleCounts[title]; !ok {
+		b.titleOrder = append(b.titleOrder, title)
+	}
+	b.titleCounts[title]++
+
+	if b.group.Image == "" && len(link.Images) > 0 {
+		b.group.Image = link.Images[0]
+	}
+	if seen := link.LastSeen.Unix(); seen > b.lastSeen {
+		b.lastSeen = seen
+	}
+}
+
+// absorb 合并另一个分组的链接，按网盘类型追加在已有链接之后
+func (b *workBuilder) absorb(other *workBuilder) {
+	for cloudType, links := range other.group.MergedByType {
+		b.group.MergedByType[cloudType] = append(b.group.MergedByType[cloudType], links...)
+	}
+	b.group.Total += other.group.Total
+
+	for _, title := range other.titleOrder {
+		if _, ok := b.titleCounts[title]; !ok {
+			b.titleOrder = append(b.titleOrder, title)
+		}
+		b.titleCounts[title] += other.titleCounts[title]
+	}
+	if b.group.Image == "" {
+		b.group.Image = other.group.Image
+	}
+	if other.lastSeen > b.lastSeen {
+		b.lastSeen = other.lastSeen
+	}
+}
+
+// build 选出代表标题并返回分组
+func (b *workBuilder) build() model.WorkGroup {
+	best := 0
+	for _, title := range b.titleOrder {
+		if count := b.titleCounts[title]; count > best {
+			best = count
+			b.group.Title = title
+		}
+	}
+	return b.group
+}
@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jsonutil "pansou/util/json"
+	"pansou/util/ratelimit"
+)
+
+// APIKey API密钥配置
+type APIKey struct {
+	Key        string  `json:"key"`
+	Name       string  `json:"name"`        // 备注名称，用于日志
+	DailyQuota int     `json:"daily_quota"` // 每日请求上限，0表示不限
+	RateLimit  float64 `json:"rate_limit"`  // 每秒请求数上限，0表示不限
+	Burst      int     `json:"burst"`       // 允许的突发请求数，0表示与速率一致
+}
+
+// Decision 密钥校验结果
+type Decision struct {
+	Allowed    bool
+	Status     int           // 拒绝时的HTTP状态码
+	Message    string        // 拒绝原因
+	RetryAfter time.Duration // 被限流或超出配额时建议的重试等待时间
+	Remaining  int           // 当日剩余配额，-1表示不限
+}
+
+// keyState 单个密钥的配额和限流状态
+type keyState struct {
+	APIKey
+	limiter *ratelimit.TokenBucket
+	day     string // 配额计数对应的日期
+	used    int    // 当日已用次数
+	mutex   sync.Mutex
+}
+
+// KeyStore API密钥存储，未配置任何密钥时不启用鉴权
+type KeyStore struct {
+	keys map[string]*keyState
+}
+
+// LoadKeyStore 从环境变量和密钥文件加载API密钥
+// keysEnv格式：key[:每日配额[:每秒请求数]]，多个密钥用英文逗号分隔
+// keysFile为JSON数组文件，元素字段与APIKey一致
+func LoadKeyStore(keysEnv string, keysFile string) (*KeyStore, error) {
+	var keys []APIKey
+
+	for _, item := range strings.Split(keysEnv, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, err := parseKeySpec(item)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if keysFile != "" {
+		data, err := os.ReadFile(keysFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取API密钥文件失败: %v", err)
+		}
+		var fileKeys []APIKey
+		if err := jsonutil.Unmarshal(data, &fileKeys); err != nil {
+			return nil, fmt.Errorf("解析API密钥文件失败: %v", err)
+		}
+		keys = append(keys, fileKeys...)
+	}
+
+	return NewKeyStore(keys)
+}
+
+// NewKeyStore 根据密钥列表创建密钥存储
+func NewKeyStore(keys []APIKey) (*KeyStore, error) {
+	store := &KeyStore{keys: make(map[string]*keyState, len(keys))}
+	for _, key := range keys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("API密钥不能为空")
+		}
+		if key.DailyQuota < 0 || key.RateLimit < 0 {
+			return nil, fmt.Errorf("API密钥 %s 的配额和速率不能为负数", key.displayName())
+		}
+		state := &keyState{APIKey: key}
+		if key.RateLimit > 0 {
+			state.limiter = ratelimit.NewTokenBucket(key.RateLimit, key.Burst)
+		}
+		store.keys[key.Key] = state
+	}
+	return store, nil
+}
+
+// parseKeySpec 解析环境变量中的单个密钥定义
+func parseKeySpec(spec string) (APIKey, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) > 3 {
+		return APIKey{}, fmt.Errorf("无效的API密钥格式: %s", spec)
+	}
+
+	key := APIKey{Key: parts[0]}
+	if len(parts) > 1 && parts[1] != "" {
+		quota, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return APIKey{}, fmt.Errorf("无效的API密钥配额: %s", spec)
+		}
+		key.DailyQuota = quota
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		rate, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return APIKey{}, fmt.Errorf("无效的API密钥速率: %s", spec)
+		}
+		key.RateLimit = rate
+	}
+	return key, nil
+}
+
+// Enabled 是否配置了API密钥
+func (s *KeyStore) Enabled() bool {
+	return s != nil && len(s.keys) > 0
+}
+
+// Count 返回密钥数量
+func (s *KeyStore) Count() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.keys)
+}
+
+// Authenticate 只校验密钥是否有效，不检查速率也不扣减配额
+// 用于一个HTTP请求包含多次调用的接口（如MCP），配额在每次调用时通过Check扣减
+func (s *KeyStore) Authenticate(key string) Decision {
+	if key == "" {
+		return Decision{Status: 401, Message: "缺少API密钥"}
+	}
+	if _, ok := s.keys[key]; !ok {
+		return Decision{Status: 401, Message: "API密钥无效"}
+	}
+	return Decision{Allowed: true, Remaining: -1}
+}
+
+// Check 校验密钥，通过时扣减当日配额
+func (s *KeyStore) Check(key string) Decision {
+	if decision := s.Authenticate(key); !decision.Allowed {
+		return decision
+	}
+	state := s.keys[key]
+
+	// 先检查速率，被限流的请求不消耗配额
+	if state.limiter != nil {
+		if allowed, wait := state.limiter.Allow(); !allowed {
+			return Decision{Status: 429, Message: "请求过于频繁", RetryAfter: wait, Remaining: state.remaining()}
+		}
+	}
+
+	return state.consume()
+}
+
+// consume 扣减当日配额
+func (k *keyState) consume() Decision {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.DailyQuota <= 0 {
+		return Decision{Allowed: true, Remaining: -1}
+	}
+
+	now := time.Now()
+	k.resetIfNewDay(now)
+	if k.used >= k.DailyQuota {
+		return Decision{Status: 429, Message: "今日配额已用完", RetryAfter: untilTomorrow(now), Remaining: 0}
+	}
+
+	k.used++
+	return Decision{Allowed: true, Remaining: k.DailyQuota - k.used}
+}
+
+// remaining 返回当日剩余配额，-1表示不限
+func (k *keyState) remaining() int {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.DailyQuota <= 0 {
+		return -1
+	}
+	k.resetIfNewDay(time.Now())
+	return k.DailyQuota - k.used
+}
+
+// resetIfNewDay 跨天时重置配额计数（调用方需持有锁）
+func (k *keyState) resetIfNewDay(now time.Time) {
+	if today := now.Format("2006-01-02"); k.day != today {
+		k.day = today
+		k.used = 0
+	}
+}
+
+// displayName 返回用于日志的密钥名称，避免输出完整密钥
+func (k APIKey) displayName() string {
+	if k.Name != "" {
+		return k.Name
+	}
+	if len(k.Key) > 4 {
+		return k.Key[:4] + "***"
+	}
+	return "***"
+}
+
+// untilTomorrow 返回距离次日零点的时间
+func untilTomorrow(now time.Time) time.Duration {
+	year, month, day := now.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, now.Location()).Sub(now)
+}
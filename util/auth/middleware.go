@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pansou/model"
+)
+
+// APIKeyMiddleware API密钥鉴权中间件，密钥存储为空时直接放行
+// 密钥可通过 X-API-Key 请求头、Authorization: Bearer <key> 或 api_key 查询参数传递
+func APIKeyMiddleware(store *KeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !store.Enabled() || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		decision := store.Check(RequestAPIKey(c))
+		if decision.Remaining >= 0 {
+			c.Header("X-Quota-Remaining", strconv.Itoa(decision.Remaining))
+		}
+		if !decision.Allowed {
+			if decision.RetryAfter > 0 {
+				c.Header("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+			}
+			c.AbortWithStatusJSON(decision.Status, model.NewErrorResponse(decision.Status, decision.Message))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequestAPIKey 从请求中提取API密钥
+func RequestAPIKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if authorization := c.GetHeader("Authorization"); strings.HasPrefix(authorization, "Bearer ") {
+		return strings.TrimPrefix(authorization, "Bearer ")
+	}
+	return c.Query("api_key")
+}
+
+// CORSMiddleware 跨域中间件
+// allowedOrigins为空时允许任意来源（不携带凭证），否则只对白名单内的来源返回跨域头并允许携带凭证
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	allowAll := len(allowedOrigins) == 0
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+
+		if allowAll {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else if origin != "" {
+			c.Header("Vary", "Origin")
+			if !allowed[origin] {
+				// 不在白名单内的预检请求直接拒绝，普通请求不返回跨域头由浏览器拦截
+				if c.Request.Method == http.MethodOptions {
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+				c.Next()
+				return
+			}
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PATCH, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Admin-Token, X-API-Key, Mcp-Session-Id, Mcp-Protocol-Version")
+		c.Header("Access-Control-Expose-Headers", "X-Quota-Remaining, Retry-After")
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	
 	"pansou/plugin"
+	"pansou/util/textnorm"
 )
 
 // 预计算的哈希值映射
@@ -46,10 +47,25 @@ func init() {
 	precomputedHashes.Store("all_channels", allChannelsHash)
 }
 
+// SetEnabledPlugins 按当前启用的插件重新计算"所有插件"的哈希
+// 运行时启用或禁用插件后，未指定插件的搜索使用新的缓存键，不再命中按旧插件集合缓存的结果
+func SetEnabledPlugins(names []string) {
+	sortedNames := make([]string, len(names))
+	copy(sortedNames, names)
+	sort.Strings(sortedNames)
+	precomputedHashes.Store("all_plugins", calculateListHash([]string{strings.Join(sortedNames, ",")}))
+}
+
+// normalizeKeyword 缓存键中的关键词标准化，与关键词过滤使用相同的规则（如"复仇者联盟4"和"复仇者联盟 4"共用缓存），
+// 保留C++、C#等带符号的词，避免它们与"C"共用缓存
+func normalizeKeyword(keyword string) string {
+	return textnorm.Key(keyword)
+}
+
 // GenerateTGCacheKey 为TG搜索生成缓存键
 func GenerateTGCacheKey(keyword string, channels []string) string {
 	// 关键词标准化
-	normalizedKeyword := strings.ToLower(strings.TrimSpace(keyword))
+	normalizedKeyword := normalizeKeyword(keyword)
 	
 	// 获取频道列表哈希
 	channelsHash := getChannelsHash(channels)
@@ -60,16 +76,19 @@ func GenerateTGCacheKey(keyword string, channels []string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// GeneratePluginCacheKey 为插件搜索生成缓存键
-func GeneratePluginCacheKey(keyword string, plugins []string) string {
+// GeneratePluginCacheKey 为插件搜索生成缓存键，ext不同时插件返回的结果可能不同，ext非空时加入ext的哈希
+func GeneratePluginCacheKey(keyword string, plugins []string, ext map[string]interface{}) string {
 	// 关键词标准化
-	normalizedKeyword := strings.ToLower(strings.TrimSpace(keyword))
+	normalizedKeyword := normalizeKeyword(keyword)
 	
 	// 获取插件列表哈希
 	pluginsHash := getPluginsHash(plugins)
 	
 	// 生成插件搜索特定的缓存键
 	keyStr := fmt.Sprintf("plugin:%s:%s", normalizedKeyword, pluginsHash)
+	if extHash := plugin.ExtHash(ext); extHash != "" {
+		keyStr += ":" + extHash
+	}
 	hash := md5.Sum([]byte(keyStr))
 	return hex.EncodeToString(hash[:])
 }
@@ -77,7 +96,7 @@ func GeneratePluginCacheKey(keyword string, plugins []string) string {
 // GenerateCacheKey 根据所有影响搜索结果的参数生成缓存键
 func GenerateCacheKey(keyword string, channels []string, sourceType string, plugins []string) string {
 	// 关键词标准化
-	normalizedKeyword := strings.ToLower(strings.TrimSpace(keyword))
+	normalizedKeyword := normalizeKeyword(keyword)
 	
 	// 获取频道列表哈希
 	channelsHash := getChannelsHash(channels)
@@ -0,0 +1,49 @@
+package cache
+
+import "testing"
+
+// TestCacheKeyKeepsSymbols 只有符号不同的关键词不共用缓存键，匹配时等价的写法共用缓存键
+func TestCacheKeyKeepsSymbols(t *testing.T) {
+	distinct := []string{"C++ Primer", "C# Primer", "C Primer"}
+	seen := make(map[string]string)
+	for _, keyword := range distinct {
+		for name, key := range map[string]string{
+			"tg":     GenerateTGCacheKey(keyword, nil),
+			"plugin": GeneratePluginCacheKey(keyword, nil, nil),
+			"main":   GenerateCacheKey(keyword, nil, "all", nil),
+		} {
+			if other, ok := seen[name+key]; ok {
+				t.Errorf("%s缓存键冲突: %q和%q", name, keyword, other)
+			}
+			seen[name+key] = keyword
+		}
+	}
+
+	same := [][2]string{
+		{"復仇者聯盟  4", "复仇者联盟 4"},
+		{"复仇者联盟4", "复仇者联盟 4"},
+		{"复仇者联盟：4", "复仇者联盟四"},
+		{"C++ Primer", "c++primer"},
+		{"ＣＨＡＴＧＰＴ 教程", " chatgpt 教程 "},
+	}
+	for _, pair := range same {
+		if GeneratePluginCacheKey(pair[0], nil, nil) != GeneratePluginCacheKey(pair[1], nil, nil) {
+			t.Errorf("%q和%q应共用插件缓存键", pair[0], pair[1])
+		}
+		if GenerateCacheKey(pair[0], nil, "all", nil) != GenerateCacheKey(pair[1], nil, "all", nil) {
+			t.Errorf("%q和%q应共用主缓存键", pair[0], pair[1])
+		}
+	}
+}
+
+// TestPluginCacheKeyIncludesExt ext不同时插件缓存键不同，空ext与未指定ext相同
+func TestPluginCacheKeyIncludesExt(t *testing.T) {
+	base := GeneratePluginCacheKey("流浪地球", nil, nil)
+	if GeneratePluginCacheKey("流浪地球", nil, map[string]interface{}{}) != base {
+		t.Error("空ext与nil的缓存键应相同")
+	}
+	scoped := map[string]interface{}{"plugins": map[string]interface{}{"sdso": map[string]interface{}{"pages_per_type": 5}}}
+	if GeneratePluginCacheKey("流浪地球", nil, scoped) == base {
+		t.Error("ext不同时缓存键应不同")
+	}
+}
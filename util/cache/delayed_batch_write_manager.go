@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"pansou/model"
+	"pansou/util/logger"
 )
 
 // CacheWriteStrategy 缓存写入策略
@@ -367,7 +368,10 @@ func (m *DelayedBatchWriteManager) Initialize() error {
 	// 启动全局缓冲区监控
 	go m.globalBufferMonitor()
 	
-	fmt.Printf("缓存写入策略: %s\n", m.strategy)
+	// 导出队列和刷新统计指标
+	activeWriteManager.Store(m)
+	
+	logger.Info(context.Background(), "缓存写入管理器已启动", "strategy", m.strategy)
 	return nil
 }
 
@@ -485,14 +489,14 @@ func (m *DelayedBatchWriteManager) checkAndFlushExpiredBuffers() {
 				continue
 			}
 			// 只有真正的错误才打印警告
-			fmt.Printf("[全局缓冲区] 刷新缓冲区失败 %s: %v\n", bufferID, err)
+			logger.Error(context.Background(), "刷新全局缓冲区失败", "buffer", bufferID, "error", err)
 		} else {
 			flushedCount++
 		}
 	}
 	
 	if flushedCount > 0 {
-		fmt.Printf("[全局缓冲区] 刷新完成，处理 %d 个过期缓冲区\n", flushedCount)
+		logger.Debug(context.Background(), "全局缓冲区刷新完成", "flushed", flushedCount)
 	}
 }
 
@@ -644,19 +648,19 @@ func (m *DelayedBatchWriteManager) Shutdown(timeout time.Duration) error {
 		
 		// 第一步：强制刷新全局缓冲区（优先级最高）
 		if err := m.flushAllGlobalBuffers(); err != nil {
-			fmt.Printf("[数据保护] 全局缓冲区刷新失败: %v\n", err)
+			logger.Error(context.Background(), "关闭前刷新全局缓冲区失败", "error", err)
 			lastErr = err
 		} 
 		
 		// 第二步：刷新本地队列
 		if err := m.flushAllPendingData(); err != nil {
-			fmt.Printf("[数据保护] 本地队列刷新失败: %v\n", err)
+			logger.Error(context.Background(), "关闭前刷新本地队列失败", "error", err)
 			lastErr = err
 		} 
 		
 		// 第三步：关闭全局缓冲区管理器
 		if err := m.globalBufferManager.Shutdown(); err != nil {
-			fmt.Printf("[数据保护] 全局缓冲区管理器关闭失败: %v\n", err)
+			logger.Error(context.Background(), "全局缓冲区管理器关闭失败", "error", err)
 			lastErr = err
 		} 
 		
@@ -684,7 +688,7 @@ func (m *DelayedBatchWriteManager) flushAllGlobalBuffers() error {
 	for bufferID, operations := range allBuffers {
 		if len(operations) > 0 {
 			if err := m.batchWriteToDisk(operations); err != nil {
-				fmt.Printf("[全局缓冲区] 缓冲区 %s 刷新失败: %v\n", bufferID, err)
+				logger.Error(context.Background(), "刷新全局缓冲区失败", "buffer", bufferID, "error", err)
 				lastErr = fmt.Errorf("刷新全局缓冲区 %s 失败: %v", bufferID, err)
 				continue
 			}
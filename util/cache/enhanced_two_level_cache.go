@@ -1,11 +1,12 @@
 package cache
 
 import (
-	"fmt"
+	"context"
 	"sync"
 	"time"
 
 	"pansou/config"
+	"pansou/util/logger"
 )
 
 // EnhancedTwoLevelCache 改进的两级缓存
@@ -71,6 +72,21 @@ func (c *EnhancedTwoLevelCache) SetMemoryOnly(key string, data []byte, ttl time.
 	return nil
 }
 
+// SetDiskOnly 仅更新磁盘缓存，用于不需要常驻内存的持久化数据
+func (c *EnhancedTwoLevelCache) SetDiskOnly(key string, data []byte, ttl time.Duration) error {
+	return c.disk.Set(key, data, ttl)
+}
+
+// GetDiskOnly 仅从磁盘缓存读取，不回填内存缓存
+func (c *EnhancedTwoLevelCache) GetDiskOnly(key string) ([]byte, bool, error) {
+	return c.disk.Get(key)
+}
+
+// GetMemoryItems 获取内存缓存中所有未过期的项，即最近写入或读取过的数据
+func (c *EnhancedTwoLevelCache) GetMemoryItems() map[string]*MemoryCacheItem {
+	return c.memory.GetAllItems()
+}
+
 // SetBothLevels 更新内存和磁盘缓存
 func (c *EnhancedTwoLevelCache) SetBothLevels(key string, data []byte, ttl time.Duration) error {
 	now := time.Now()
@@ -97,8 +113,10 @@ func (c *EnhancedTwoLevelCache) Get(key string) ([]byte, bool, error) {
 	// 检查内存缓存
 	data, _, memHit := c.memory.GetWithTimestamp(key)
 	if memHit {
+		cacheRequestsTotal.Inc("memory", "hit")
 		return data, true, nil
 	}
+	cacheRequestsTotal.Inc("memory", "miss")
 
     // 尝试从磁盘读取数据
 	diskData, diskHit, diskErr := c.disk.Get(key)
@@ -107,8 +125,10 @@ func (c *EnhancedTwoLevelCache) Get(key string) ([]byte, bool, error) {
 		diskLastModified, _ := c.disk.GetLastModified(key)
 		ttl := time.Duration(config.AppConfig.CacheTTLMinutes) * time.Minute
 		c.memory.SetWithTimestamp(key, diskData, ttl, diskLastModified)
+		cacheRequestsTotal.Inc("disk", "hit")
 		return diskData, true, nil
 	}
+	cacheRequestsTotal.Inc("disk", "miss")
 	
 	return nil, false, nil
 }
@@ -155,7 +175,7 @@ func (c *EnhancedTwoLevelCache) FlushMemoryToDisk() error {
 	for key, item := range allItems {
 		// 同步写入到磁盘缓存
 		if err := c.disk.Set(key, item.Data, item.TTL); err != nil {
-			fmt.Printf("[内存同步] 同步失败: %s -> %v\n", key, err)
+			logger.Error(context.Background(), "内存缓存同步到磁盘失败", "cache_key", key, "error", err)
 			lastErr = err
 			continue
 		}
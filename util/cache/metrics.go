@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"sync/atomic"
+
+	"pansou/util/metrics"
+)
+
+// cacheRequestsTotal 主缓存各级别的命中/未命中次数
+var cacheRequestsTotal = metrics.NewCounterVec(
+	"pansou_cache_requests_total",
+	"主缓存读取次数，按缓存级别(memory/disk)和结果(hit/miss)区分",
+	"level", "result",
+)
+
+// activeWriteManager 当前导出指标的延迟批量写入管理器
+var activeWriteManager atomic.Pointer[DelayedBatchWriteManager]
+
+func init() {
+	writeStat := func(field func(*WriteManagerStats) *int64) func() float64 {
+		return func() float64 {
+			if m := activeWriteManager.Load(); m != nil {
+				return float64(atomic.LoadInt64(field(m.stats)))
+			}
+			return 0
+		}
+	}
+
+	metrics.NewGaugeFunc("pansou_cache_write_queue_size", "延迟批量写入队列中等待写入的操作数", func() float64 {
+		if m := activeWriteManager.Load(); m != nil {
+			return float64(atomic.LoadInt32(&m.stats.CurrentQueueSize))
+		}
+		return 0
+	})
+	metrics.NewCounterFunc("pansou_cache_write_operations_total", "提交到延迟批量写入管理器的操作数",
+		writeStat(func(s *WriteManagerStats) *int64 { return &s.TotalOperations }))
+	metrics.NewCounterFunc("pansou_cache_write_merged_operations_total", "写入前被合并的重复操作数",
+		writeStat(func(s *WriteManagerStats) *int64 { return &s.MergedOperations }))
+	metrics.NewCounterFunc("pansou_cache_write_immediate_total", "立即写入次数",
+		writeStat(func(s *WriteManagerStats) *int64 { return &s.ImmediateWrites }))
+	metrics.NewCounterFunc("pansou_cache_write_flushes_total", "批量刷新次数",
+		writeStat(func(s *WriteManagerStats) *int64 { return &s.BatchWrites }))
+	metrics.NewCounterFunc("pansou_cache_write_failures_total", "写入失败次数",
+		writeStat(func(s *WriteManagerStats) *int64 { return &s.FailedWrites }))
+}
@@ -0,0 +1,493 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pansou/model"
+	jsonutil "pansou/util/json"
+)
+
+// 导出格式
+const (
+	FormatJSON  = "json"  // 默认的JSON响应
+	FormatCSV   = "csv"   // CSV表格
+	FormatJSONL = "jsonl" // JSON Lines，每行一条记录
+	FormatRSS   = "rss"   // RSS 2.0订阅
+	FormatAtom  = "atom"  // Atom订阅
+)
+
+// flushEvery 每写入多少条记录刷新一次输出
+const flushEvery = 100
+
+// contentTypes 各导出格式的Content-Type
+var contentTypes = map[string]string{
+	FormatJSON:  "application/json; charset=utf-8",
+	FormatCSV:   "text/csv; charset=utf-8",
+	FormatJSONL: "application/x-ndjson; charset=utf-8",
+	FormatRSS:   "application/rss+xml; charset=utf-8",
+	FormatAtom:  "application/atom+xml; charset=utf-8",
+}
+
+// mediaTypes Accept头中的媒体类型对应的导出格式
+var mediaTypes = map[string]string{
+	"application/json":     FormatJSON,
+	"text/csv":             FormatCSV,
+	"application/x-ndjson": FormatJSONL,
+	"application/jsonl":    FormatJSONL,
+	"application/rss+xml":  FormatRSS,
+	"application/atom+xml": FormatAtom,
+}
+
+// IsValidFormat 判断导出格式是否有效，空字符串表示默认的JSON
+func IsValidFormat(format string) bool {
+	if format == "" {
+		return true
+	}
+	_, ok := contentTypes[format]
+	return ok
+}
+
+// ContentType 获取导出格式的Content-Type
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Negotiate 根据Accept头选择导出格式，按q值从高到低取第一个支持的媒体类型，都不支持时返回JSON
+func Negotiate(accept string) string {
+	best, bestQ := FormatJSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		format, ok := mediaTypes[strings.ToLower(strings.TrimSpace(fields[0]))]
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if name == "q" {
+				if v, err := strconv.ParseFloat(value, 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	return best
+}
+
+// FeedInfo RSS/Atom订阅的频道信息
+type FeedInfo struct {
+	Title string // 订阅标题
+	Link  string // 订阅地址
+}
+
+// NewFeedInfo 根据搜索请求生成订阅信息，订阅地址即当前请求的地址
+func NewFeedInfo(r *http.Request, keyword string) FeedInfo {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return FeedInfo{
+		Title: "PanSou: " + keyword,
+		Link:  scheme + "://" + r.Host + r.URL.RequestURI(),
+	}
+}
+
+// Write 将搜索响应按导出格式（csv/jsonl/rss/atom）逐条写入w：响应中有results时导出结果，否则导出merged_by_type（或works）中的链接
+func Write(w io.Writer, format string, response model.SearchResponse, feed FeedInfo) error {
+	out := newFlushWriter(w)
+	var err error
+	switch format {
+	case FormatCSV:
+		err = writeCSV(out, response)
+	case FormatJSONL:
+		err = writeJSONL(out, response)
+	case FormatRSS:
+		err = writeRSS(out, response, feed)
+	case FormatAtom:
+		err = writeAtom(out, response, feed)
+	default:
+		return fmt.Errorf("不支持的导出格式: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// LinkRecord 导出的一条链接记录
+type LinkRecord struct {
+	Type string `json:"type" sonic:"type"` // 网盘类型
+	model.MergedLink
+}
+
+// exportsResults 判断是否导出results而不是链接
+func exportsResults(response model.SearchResponse) bool {
+	return len(response.Results) > 0
+}
+
+// eachLink 按网盘类型名称顺序遍历响应中的链接，按作品分组时依次遍历各作品
+func eachLink(response model.SearchResponse, fn func(LinkRecord) error) error {
+	groups := []model.MergedLinks{response.MergedByType}
+	if len(response.Works) > 0 {
+		groups = groups[:0]
+		for _, work := range response.Works {
+			groups = append(groups, work.MergedByType)
+		}
+	}
+
+	for _, merged := range groups {
+		types := make([]string, 0, len(merged))
+		for cloudType := range merged {
+			types = append(types, cloudType)
+		}
+		sort.Strings(types)
+
+		for _, cloudType := range types {
+			for _, link := range merged[cloudType] {
+				if err := fn(LinkRecord{Type: cloudType, MergedLink: link}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// formatTime 格式化时间，零值返回空字符串
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// flushWriter 带缓冲的输出，每写入flushEvery条记录时把缓冲区的内容推送给客户端
+type flushWriter struct {
+	*bufio.Writer
+	flusher http.Flusher
+	records int
+}
+
+// newFlushWriter 创建带缓冲的输出
+func newFlushWriter(w io.Writer) *flushWriter {
+	flusher, _ := w.(http.Flusher)
+	return &flushWriter{Writer: bufio.NewWriter(w), flusher: flusher}
+}
+
+// recordDone 记录写完一条，达到flushEvery条时刷新
+func (w *flushWriter) recordDone() error {
+	w.records++
+	if w.records%flushEvery != 0 {
+		return nil
+	}
+	return w.Flush()
+}
+
+// Flush 写出缓冲区并刷新底层输出
+func (w *flushWriter) Flush() error {
+	if err := w.Writer.Flush(); err != nil {
+		return err
+	}
+	if w.flusher != nil {
+		w.flusher.Flush()
+	}
+	return nil
+}
+
+// writeCSV 导出CSV，开头写入UTF-8 BOM以便Excel正确识别中文
+func writeCSV(out *flushWriter, response model.SearchResponse) error {
+	if _, err := out.WriteString("\uFEFF"); err != nil {
+		return err
+	}
+	w := csv.NewWriter(out)
+	write := func(row []string) error {
+		for i, cell := range row {
+			row[i] = escapeCSVCell(cell)
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+		w.Flush()
+		return out.recordDone()
+	}
+
+	if exportsResults(response) {
+		if err := w.Write([]string{"unique_id", "channel", "datetime", "title", "content", "links", "tags"}); err != nil {
+			return err
+		}
+		for _, result := range response.Results {
+			links := make([]string, 0, len(result.Links))
+			for _, link := range result.Links {
+				links = append(links, link.URL)
+			}
+			err := write([]string{
+				result.UniqueID, result.Channel, formatTime(result.Datetime), result.Title, result.Content,
+				strings.Join(links, " "), strings.Join(result.Tags, " "),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		if err := w.Write([]string{"type", "url", "password", "note", "datetime", "source", "status"}); err != nil {
+			return err
+		}
+		err := eachLink(response, func(link LinkRecord) error {
+			return write([]string{link.Type, link.URL, link.Password, link.Note, formatTime(link.Datetime), link.Source, link.Status})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// escapeCSVCell 以公式字符开头的单元格前加单引号，避免表格软件把来自频道和插件的文本当作公式执行
+func escapeCSVCell(cell string) string {
+	if cell == "" {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + cell
+	}
+	return cell
+}
+
+// writeJSONL 导出JSON Lines，每行是一条结果或一条链接
+func writeJSONL(out *flushWriter, response model.SearchResponse) error {
+	writeLine := func(v interface{}) error {
+		data, err := jsonutil.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := out.Write(append(data, '\n')); err != nil {
+			return err
+		}
+		return out.recordDone()
+	}
+
+	if exportsResults(response) {
+		for _, result := range response.Results {
+			if err := writeLine(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return eachLink(response, func(link LinkRecord) error {
+		return writeLine(link)
+	})
+}
+
+// feedItem 订阅中的一条内容，由结果或链接转换而来
+type feedItem struct {
+	id          string
+	title       string
+	link        string
+	description string
+	category    string
+	published   time.Time
+}
+
+// eachFeedItem 遍历响应中的订阅内容
+func eachFeedItem(response model.SearchResponse, fn func(feedItem) error) error {
+	if exportsResults(response) {
+		for _, result := range response.Results {
+			item := feedItem{
+				id:          result.UniqueID,
+				title:       result.Title,
+				description: result.Content,
+				category:    result.Channel,
+				published:   result.Datetime,
+			}
+			if len(result.Links) > 0 {
+				item.link = result.Links[0].URL
+			}
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return eachLink(response, func(link LinkRecord) error {
+		description := link.Note
+		if link.Password != "" {
+			description += " 提取码: " + link.Password
+		}
+		return fn(feedItem{
+			id:          link.URL,
+			title:       link.Note,
+			link:        link.URL,
+			description: description,
+			category:    link.Type,
+			published:   link.Datetime,
+		})
+	})
+}
+
+// rssItem RSS 2.0中的item
+type rssItem struct {
+	XMLName     xml.Name `xml:"item"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Category    string   `xml:"category,omitempty"`
+}
+
+// rssGUID RSS 2.0中的guid
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// writeRSS 导出RSS 2.0订阅
+func writeRSS(out *flushWriter, response model.SearchResponse, feed FeedInfo) error {
+	if _, err := out.WriteString(xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	rss := xml.StartElement{Name: xml.Name{Local: "rss"}, Attr: []xml.Attr{{Name: xml.Name{Local: "version"}, Value: "2.0"}}}
+	channel := xml.StartElement{Name: xml.Name{Local: "channel"}}
+	if err := encodeTokens(enc, rss, channel); err != nil {
+		return err
+	}
+	for _, el := range [][2]string{
+		{"title", feed.Title},
+		{"link", feed.Link},
+		{"description", feed.Title},
+		{"lastBuildDate", time.Now().Format(time.RFC1123Z)},
+	} {
+		if err := enc.EncodeElement(el[1], xml.StartElement{Name: xml.Name{Local: el[0]}}); err != nil {
+			return err
+		}
+	}
+
+	err := eachFeedItem(response, func(item feedItem) error {
+		entry := rssItem{
+			Title:       item.title,
+			Link:        item.link,
+			GUID:        rssGUID{Value: item.id},
+			Description: item.description,
+			Category:    item.category,
+		}
+		if !item.published.IsZero() {
+			entry.PubDate = item.published.Format(time.RFC1123Z)
+		}
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+		return out.recordDone()
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := encodeTokens(enc, channel.End(), rss.End()); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// atomLink Atom中的link
+type atomLink struct {
+	XMLName xml.Name `xml:"link"`
+	Href    string   `xml:"href,attr"`
+	Rel     string   `xml:"rel,attr,omitempty"`
+}
+
+// atomCategory Atom中的category
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// atomEntry Atom中的entry
+type atomEntry struct {
+	XMLName  xml.Name      `xml:"entry"`
+	Title    string        `xml:"title"`
+	ID       string        `xml:"id"`
+	Updated  string        `xml:"updated"`
+	Link     *atomLink     `xml:"link,omitempty"`
+	Summary  string        `xml:"summary,omitempty"`
+	Category *atomCategory `xml:"category,omitempty"`
+}
+
+// writeAtom 导出Atom订阅，没有发布时间的内容使用当前时间作为updated
+func writeAtom(out *flushWriter, response model.SearchResponse, feed FeedInfo) error {
+	if _, err := out.WriteString(xml.Header); err != nil {
+		return err
+	}
+	now := time.Now().Format(time.RFC3339)
+	enc := xml.NewEncoder(out)
+	root := xml.StartElement{
+		Name: xml.Name{Local: "feed"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "http://www.w3.org/2005/Atom"}},
+	}
+	if err := encodeTokens(enc, root); err != nil {
+		return err
+	}
+	for _, el := range [][2]string{{"title", feed.Title}, {"id", feed.Link}, {"updated", now}} {
+		if err := enc.EncodeElement(el[1], xml.StartElement{Name: xml.Name{Local: el[0]}}); err != nil {
+			return err
+		}
+	}
+	if err := enc.Encode(atomLink{Href: feed.Link, Rel: "self"}); err != nil {
+		return err
+	}
+
+	err := eachFeedItem(response, func(item feedItem) error {
+		entry := atomEntry{Title: item.title, ID: item.id, Updated: now, Summary: item.description}
+		if !item.published.IsZero() {
+			entry.Updated = item.published.Format(time.RFC3339)
+		}
+		if item.link != "" {
+			entry.Link = &atomLink{Href: item.link}
+		}
+		if item.category != "" {
+			entry.Category = &atomCategory{Term: item.category}
+		}
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+		return out.recordDone()
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := encodeTokens(enc, root.End()); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// encodeTokens 依次写入XML标记
+func encodeTokens(enc *xml.Encoder, tokens ...xml.Token) error {
+	for _, token := range tokens {
+		if err := enc.EncodeToken(token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
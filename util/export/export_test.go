@@ -0,0 +1,39 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"pansou/model"
+)
+
+// TestWriteCSVEscapesFormulas 以公式字符开头的单元格加单引号前缀
+func TestWriteCSVEscapesFormulas(t *testing.T) {
+	response := model.SearchResponse{
+		Results: []model.SearchResult{{
+			UniqueID: "tg-1",
+			Title:    `=HYPERLINK("https://evil.example","点击")`,
+			Content:  "+1-2",
+			Tags:     []string{"@cmd"},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, response, FeedInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("输出%d行，期望2行: %q", len(lines), buf.String())
+	}
+	if want := `tg-1,,,"'=HYPERLINK(""https://evil.example"",""点击"")",'+1-2,,'@cmd`; lines[1] != want {
+		t.Errorf("数据行 = %q\n期望 %q", lines[1], want)
+	}
+
+	for cell, want := range map[string]string{"": "", "流浪地球": "流浪地球", "-x": "'-x", "\tx": "'\tx", "\rx": "'\rx", "a=b": "a=b"} {
+		if got := escapeCSVCell(cell); got != want {
+			t.Errorf("escapeCSVCell(%q) = %q，期望%q", cell, got, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	netUrl "net/url"
+	"regexp"
+	"strings"
+)
+
+// 磁力链接的btih哈希，支持40位十六进制和32位base32两种形式
+var btihPattern = regexp.MustCompile(`(?i)xt=urn:btih:([a-z0-9]{32,40})`)
+
+// ed2k链接中的文件哈希
+var ed2kHashPattern = regexp.MustCompile(`(?i)ed2k://\|file\|[^|]*\|\d+\|([a-f0-9]{32})\|`)
+
+// LinkFingerprint 计算链接的内容指纹，指向同一资源的不同写法得到相同的指纹：
+// 网盘链接使用"类型:分享ID"（忽略域名、查询参数和锚点），磁力链接使用btih哈希，ed2k链接使用文件哈希；
+// 无法识别的链接去掉锚点后原样返回
+func LinkFingerprint(link string) string {
+	link = strings.TrimSpace(link)
+	linkType := GetLinkType(link)
+
+	switch linkType {
+	case "magnet":
+		if hash := magnetHash(link); hash != "" {
+			return "magnet:" + hash
+		}
+	case "ed2k":
+		if m := ed2kHashPattern.FindStringSubmatch(link); m != nil {
+			return "ed2k:" + strings.ToLower(m[1])
+		}
+	case "others":
+	default:
+		if id := shareID(linkType, cleanPanURL(linkType, link)); id != "" {
+			return linkType + ":" + id
+		}
+	}
+
+	if idx := strings.Index(link, "#"); idx > 0 {
+		link = link[:idx]
+	}
+	return link
+}
+
+// magnetHash 提取磁力链接的btih哈希，统一为小写十六进制
+func magnetHash(link string) string {
+	m := btihPattern.FindStringSubmatch(link)
+	if m == nil {
+		return ""
+	}
+
+	hash := m[1]
+	switch len(hash) {
+	case 40:
+		return strings.ToLower(hash)
+	case 32:
+		decoded, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash))
+		if err != nil {
+			return ""
+		}
+		return hex.EncodeToString(decoded)
+	}
+	return ""
+}
+
+// cleanPanURL 使用对应网盘的清理函数去掉链接前后的无关文本
+func cleanPanURL(linkType string, link string) string {
+	switch linkType {
+	case "baidu":
+		return CleanBaiduPanURL(link)
+	case "tianyi":
+		return CleanTianyiPanURL(link)
+	case "uc":
+		return CleanUCPanURL(link)
+	case "123":
+		return Clean123PanURL(link)
+	case "115":
+		return Clean115PanURL(link)
+	case "aliyun":
+		return CleanAliyunPanURL(link)
+	}
+	return link
+}
+
+// shareID 从清理后的网盘链接中提取分享ID
+func shareID(linkType string, link string) string {
+	u, err := netUrl.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	// 百度网盘旧格式：/share/init?surl=xxx，对应新格式/s/1xxx
+	if linkType == "baidu" {
+		if surl := u.Query().Get("surl"); surl != "" {
+			return "1" + surl
+		}
+	}
+
+	// 移动云盘：/w/i/xxx 或 /m/i?xxx
+	if linkType == "mobile" {
+		if idx := strings.Index(u.Path, "/i/"); idx >= 0 {
+			return firstSegment(u.Path[idx+len("/i/"):])
+		}
+		return u.RawQuery
+	}
+
+	// 其他网盘：/s/xxx，天翼云盘为/t/xxx
+	for _, prefix := range []string{"/s/", "/t/"} {
+		if idx := strings.Index(u.Path, prefix); idx >= 0 {
+			return firstSegment(u.Path[idx+len(prefix):])
+		}
+	}
+	return ""
+}
+
+// firstSegment 返回路径的第一段，并去掉天翼云盘链接中附带的中文访问码说明
+func firstSegment(path string) string {
+	if idx := strings.IndexAny(path, "/（("); idx >= 0 {
+		path = path[:idx]
+	}
+	return path
+}
@@ -0,0 +1,74 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func init() {
+	Register("aliyun", &AliyunProber{BaseURL: "https://api.aliyundrive.com"})
+}
+
+// AliyunProber 阿里云盘探测器
+type AliyunProber struct {
+	BaseURL string // 接口地址
+}
+
+// aliyunResponse 阿里云盘接口响应，出错时包含code和message
+type aliyunResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	ShareName string `json:"share_name"`
+}
+
+// Probe 实现Prober接口，匿名获取分享信息判断是否有效，有提取码时再校验提取码
+func (p *AliyunProber) Probe(ctx context.Context, client *http.Client, shareURL string, password string) (Status, error) {
+	shareID := shareCode(shareURL, "/s/")
+	if shareID == "" {
+		return StatusUnknown, fmt.Errorf("无法解析分享ID")
+	}
+
+	req, err := newRequest(ctx, http.MethodPost, p.BaseURL+"/adrive/v3/share_link/get_share_by_anonymous?share_id="+shareID, map[string]string{"share_id": shareID})
+	if err != nil {
+		return StatusUnknown, err
+	}
+	var info aliyunResponse
+	if _, err := doJSON(client, req, &info); err != nil {
+		return StatusUnknown, err
+	}
+	if info.Code != "" {
+		return classifyAliyunCode(info.Code, info.Message), nil
+	}
+	if password == "" {
+		return StatusValid, nil
+	}
+
+	// 校验提取码
+	req, err = newRequest(ctx, http.MethodPost, p.BaseURL+"/v2/share_link/get_share_token", map[string]string{"share_id": shareID, "share_pwd": password})
+	if err != nil {
+		return StatusUnknown, err
+	}
+	var token aliyunResponse
+	if _, err := doJSON(client, req, &token); err != nil {
+		return StatusUnknown, err
+	}
+	if token.Code != "" {
+		return classifyAliyunCode(token.Code, token.Message), nil
+	}
+	return StatusValid, nil
+}
+
+// classifyAliyunCode 根据阿里云盘错误码判断状态
+func classifyAliyunCode(code string, message string) Status {
+	lower := strings.ToLower(code)
+	switch {
+	case strings.Contains(lower, "pwd") || strings.Contains(lower, "password"):
+		return StatusNeedsPassword
+	case strings.HasPrefix(lower, "sharelink.") || strings.HasPrefix(lower, "notfound."):
+		return StatusExpired
+	default:
+		return classifyMessage(message)
+	}
+}
@@ -0,0 +1,120 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	Register("baidu", &BaiduProber{BaseURL: "https://pan.baidu.com"})
+}
+
+// BaiduProber 百度网盘探测器
+type BaiduProber struct {
+	BaseURL string // 网盘地址
+}
+
+// baiduVerifyResponse 提取码校验接口响应
+type baiduVerifyResponse struct {
+	Errno int `json:"errno"`
+}
+
+// baiduExpiredMarkers 分享页面中表示分享失效的内容
+var baiduExpiredMarkers = []string{
+	"你所访问的页面不存在了", "分享的文件已经被取消了", "分享的文件已经被删除了", "啊哦，你来晚了",
+	"此链接分享内容可能因为涉及侵权", "链接不存在", "分享已过期", "share_nofound",
+}
+
+// Probe 实现Prober接口，有提取码时调用校验接口，否则根据分享页面判断
+func (p *BaiduProber) Probe(ctx context.Context, client *http.Client, shareURL string, password string) (Status, error) {
+	// 支持 /s/1xxx 和 /share/init?surl=xxx 两种链接
+	surl := shareCode(shareURL, "/s/")
+	if surl == "" {
+		if initSurl := queryValue(shareURL, "surl"); initSurl != "" {
+			surl = "1" + initSurl
+		}
+	}
+	if surl == "" {
+		return StatusUnknown, fmt.Errorf("无法解析分享ID")
+	}
+	if password == "" {
+		password = queryValue(shareURL, "pwd")
+	}
+
+	if password != "" {
+		return p.verify(ctx, client, surl, password)
+	}
+	return p.probePage(ctx, client, surl)
+}
+
+// verify 校验提取码
+func (p *BaiduProber) verify(ctx context.Context, client *http.Client, surl string, password string) (Status, error) {
+	verifyURL := p.BaseURL + "/share/verify?surl=" + url.QueryEscape(strings.TrimPrefix(surl, "1")) + "&web=1&clienttype=0&channel=chunlei"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, verifyURL, strings.NewReader("pwd="+url.QueryEscape(password)+"&vcode=&vcode_str="))
+	if err != nil {
+		return StatusUnknown, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", p.BaseURL+"/share/init?surl="+url.QueryEscape(strings.TrimPrefix(surl, "1")))
+
+	var resp baiduVerifyResponse
+	if _, err := doJSON(client, req, &resp); err != nil {
+		return StatusUnknown, err
+	}
+	switch resp.Errno {
+	case 0:
+		return StatusValid, nil
+	case -9:
+		// 提取码错误
+		return StatusNeedsPassword, nil
+	case -7, 2, 105, 115, -21:
+		// 链接错误、分享不存在、已取消或被封禁
+		return StatusExpired, nil
+	default:
+		// -62等需要验证码的情况无法判断
+		return StatusUnknown, nil
+	}
+}
+
+// probePage 访问分享页面，需要提取码的分享会重定向到/share/init
+func (p *BaiduProber) probePage(ctx context.Context, client *http.Client, surl string) (Status, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"/s/"+surl, nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, body, err := readBody(noRedirectClient(client), req)
+	if err != nil {
+		return StatusUnknown, err
+	}
+
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		location := resp.Header.Get("Location")
+		switch {
+		case strings.Contains(location, "share/init"):
+			return StatusNeedsPassword, nil
+		case strings.Contains(location, "error"):
+			return StatusExpired, nil
+		default:
+			return StatusUnknown, nil
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return StatusExpired, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return StatusUnknown, nil
+	}
+	if containsAny(body, baiduExpiredMarkers) {
+		return StatusExpired, nil
+	}
+	if strings.Contains(body, "请输入提取码") {
+		return StatusNeedsPassword, nil
+	}
+	return StatusValid, nil
+}
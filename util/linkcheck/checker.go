@@ -0,0 +1,208 @@
+// Package linkcheck 网盘分享链接有效性检测，按网盘类型注册探测器并缓存检测结果
+package linkcheck
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"pansou/util"
+	"pansou/util/cache"
+	jsonutil "pansou/util/json"
+	"pansou/util/logger"
+	"pansou/util/metrics"
+)
+
+// Status 链接状态
+type Status string
+
+const (
+	StatusValid         Status = "valid"          // 分享有效
+	StatusExpired       Status = "expired"        // 分享已失效（取消、删除、过期或违规）
+	StatusNeedsPassword Status = "needs_password" // 需要提取码或提取码错误
+	StatusUnknown       Status = "unknown"        // 无法确定（不支持的类型、网络错误或接口变化）
+)
+
+// unknownTTL 无法确定状态时的最长缓存时间，避免临时错误长期生效
+const unknownTTL = time.Minute
+
+// Verdict 链接检测结果
+type Verdict struct {
+	Status    Status    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Target 待检测的链接
+type Target struct {
+	URL      string
+	Password string
+}
+
+// Prober 网盘分享链接探测器
+type Prober interface {
+	// Probe 探测分享链接状态，无法确定时返回StatusUnknown
+	Probe(ctx context.Context, client *http.Client, shareURL string, password string) (Status, error)
+}
+
+// 按网盘类型注册的探测器，类型与util.GetLinkType一致
+var (
+	probers      = make(map[string]Prober)
+	probersMutex sync.RWMutex
+)
+
+// linkChecksTotal 实际发起的探测次数（不含缓存命中）
+var linkChecksTotal = metrics.NewCounterVec(
+	"pansou_link_checks_total",
+	"链接有效性探测次数，按网盘类型和检测结果区分",
+	"type", "status",
+)
+
+// Register 注册或替换网盘类型的探测器
+func Register(linkType string, prober Prober) {
+	probersMutex.Lock()
+	defer probersMutex.Unlock()
+	probers[linkType] = prober
+}
+
+// GetProber 获取网盘类型的探测器，未注册时返回nil
+func GetProber(linkType string) Prober {
+	probersMutex.RLock()
+	defer probersMutex.RUnlock()
+	return probers[linkType]
+}
+
+// SupportedTypes 返回已注册探测器的网盘类型
+func SupportedTypes() []string {
+	probersMutex.RLock()
+	defer probersMutex.RUnlock()
+	types := make([]string, 0, len(probers))
+	for linkType := range probers {
+		types = append(types, linkType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Checker 链接检测器，检测结果按链接和提取码缓存
+type Checker struct {
+	client      *http.Client
+	cache       *cache.ShardedMemoryCache
+	ttl         time.Duration
+	timeout     time.Duration
+	concurrency int
+}
+
+// NewChecker 创建链接检测器
+// ttl为检测结果缓存时间，timeout为单个链接的检测超时，concurrency为CheckAll的并发数
+func NewChecker(client *http.Client, ttl time.Duration, timeout time.Duration, concurrency int) *Checker {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	verdictCache := cache.NewShardedMemoryCache(100000, 16)
+	verdictCache.StartCleanupTask()
+
+	return &Checker{
+		client:      client,
+		cache:       verdictCache,
+		ttl:         ttl,
+		timeout:     timeout,
+		concurrency: concurrency,
+	}
+}
+
+// Check 检测单个链接，优先使用缓存的检测结果
+func (c *Checker) Check(ctx context.Context, shareURL string, password string) Verdict {
+	linkType := util.GetLinkType(shareURL)
+	prober := GetProber(linkType)
+	if prober == nil {
+		return Verdict{Status: StatusUnknown, CheckedAt: time.Now()}
+	}
+
+	if data, ok := c.cache.Get(verdictCacheKey(linkType, shareURL, password)); ok {
+		var verdict Verdict
+		if err := jsonutil.Unmarshal(data, &verdict); err == nil {
+			return verdict
+		}
+	}
+
+	return c.probe(ctx, prober, linkType, shareURL, password)
+}
+
+// Refresh 忽略缓存重新检测链接，并用新结果更新缓存
+func (c *Checker) Refresh(ctx context.Context, shareURL string, password string) Verdict {
+	linkType := util.GetLinkType(shareURL)
+	prober := GetProber(linkType)
+	if prober == nil {
+		return Verdict{Status: StatusUnknown, CheckedAt: time.Now()}
+	}
+	return c.probe(ctx, prober, linkType, shareURL, password)
+}
+
+// probe 调用探测器检测链接并缓存结果
+func (c *Checker) probe(ctx context.Context, prober Prober, linkType string, shareURL string, password string) Verdict {
+	probeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	status, err := prober.Probe(probeCtx, c.client, shareURL, password)
+	if err != nil {
+		logger.Debug(ctx, "链接检测失败", "type", linkType, "url", shareURL, "error", err)
+		status = StatusUnknown
+	}
+	verdict := Verdict{Status: status, CheckedAt: time.Now()}
+
+	// 调用方已取消时结果不可信，不写入缓存
+	if ctx.Err() != nil {
+		return verdict
+	}
+	linkChecksTotal.Inc(linkType, string(status))
+
+	ttl := c.ttl
+	if status == StatusUnknown && ttl > unknownTTL {
+		ttl = unknownTTL
+	}
+	if data, err := jsonutil.Marshal(verdict); err == nil {
+		c.cache.Set(verdictCacheKey(linkType, shareURL, password), data, ttl)
+	}
+	return verdict
+}
+
+// verdictCacheKey 检测结果的缓存键
+func verdictCacheKey(linkType string, shareURL string, password string) string {
+	return linkType + "|" + shareURL + "|" + password
+}
+
+// CheckAll 并发检测多个链接，返回与targets一一对应的检测结果
+func (c *Checker) CheckAll(ctx context.Context, targets []Target) []Verdict {
+	verdicts := make([]Verdict, len(targets))
+
+	// 相同链接只检测一次
+	indexes := make(map[Target][]int, len(targets))
+	unique := make([]Target, 0, len(targets))
+	for i, target := range targets {
+		if _, exists := indexes[target]; !exists {
+			unique = append(unique, target)
+		}
+		indexes[target] = append(indexes[target], i)
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, c.concurrency)
+	for _, target := range unique {
+		wg.Add(1)
+		go func(t Target) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			verdict := c.Check(ctx, t.URL, t.Password)
+			for _, i := range indexes[t] {
+				verdicts[i] = verdict
+			}
+		}(target)
+	}
+	wg.Wait()
+
+	return verdicts
+}
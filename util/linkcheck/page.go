@@ -0,0 +1,61 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	// 以下网盘的分享接口需要验证码或加密参数，只能根据分享页面判断是否已失效
+	Register("xunlei", &PageProber{BaseURL: "https://pan.xunlei.com"})
+	Register("pikpak", &PageProber{BaseURL: "https://mypikpak.com"})
+	Register("mobile", &PageProber{BaseURL: "https://caiyun.139.com"})
+}
+
+// PageProber 通用分享页面探测器，只能识别明确失效的分享，其余情况返回StatusUnknown
+type PageProber struct {
+	BaseURL        string   // 网盘地址，分享链接的路径拼接到该地址上访问，不访问分享链接中的域名
+	ExpiredMarkers []string // 页面中表示分享失效的内容，单页应用的页面内容不可靠，默认只根据状态码判断
+}
+
+// Probe 实现Prober接口
+func (p *PageProber) Probe(ctx context.Context, client *http.Client, shareURL string, password string) (Status, error) {
+	pageURL, err := p.pageURL(shareURL)
+	if err != nil {
+		return StatusUnknown, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, body, err := readBody(client, req)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return StatusExpired, nil
+	}
+	if resp.StatusCode == http.StatusOK && len(p.ExpiredMarkers) > 0 && containsAny(body, p.ExpiredMarkers) {
+		return StatusExpired, nil
+	}
+	return StatusUnknown, nil
+}
+
+// pageURL 返回实际访问的页面地址
+// 网盘类型按链接中是否包含网盘域名判断，链接的域名可能是任意地址，因此只保留路径和查询参数
+func (p *PageProber) pageURL(shareURL string) (string, error) {
+	if p.BaseURL == "" {
+		return "", fmt.Errorf("未配置网盘地址")
+	}
+	u, err := url.Parse(shareURL)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(p.BaseURL, "/") + u.RequestURI(), nil
+}
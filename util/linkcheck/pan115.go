@@ -0,0 +1,48 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+func init() {
+	Register("115", &Pan115Prober{BaseURL: "https://webapi.115.com"})
+}
+
+// Pan115Prober 115网盘探测器
+type Pan115Prober struct {
+	BaseURL string // 接口地址
+}
+
+// pan115Response 分享快照接口响应
+type pan115Response struct {
+	State bool   `json:"state"`
+	Error string `json:"error"`
+}
+
+// Probe 实现Prober接口，读取分享快照的第一项判断分享状态
+func (p *Pan115Prober) Probe(ctx context.Context, client *http.Client, shareURL string, password string) (Status, error) {
+	code := shareCode(shareURL, "/s/")
+	if code == "" {
+		return StatusUnknown, fmt.Errorf("无法解析分享码")
+	}
+	if password == "" {
+		password = queryValue(shareURL, "password")
+	}
+
+	snapURL := p.BaseURL + "/share/snap?share_code=" + url.QueryEscape(code) + "&receive_code=" + url.QueryEscape(password) + "&offset=0&limit=1&cid="
+	req, err := newRequest(ctx, http.MethodGet, snapURL, nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	var resp pan115Response
+	if _, err := doJSON(client, req, &resp); err != nil {
+		return StatusUnknown, err
+	}
+	if resp.State {
+		return StatusValid, nil
+	}
+	return classifyMessage(resp.Error), nil
+}
@@ -0,0 +1,55 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	Register("123", &Pan123Prober{BaseURL: "https://www.123pan.com"})
+}
+
+// Pan123Prober 123网盘探测器，各域名的分享使用同一接口
+type Pan123Prober struct {
+	BaseURL string // 接口地址
+}
+
+// pan123Response 分享文件列表接口响应
+type pan123Response struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Probe 实现Prober接口，读取分享根目录的第一项判断分享状态
+func (p *Pan123Prober) Probe(ctx context.Context, client *http.Client, shareURL string, password string) (Status, error) {
+	key := strings.TrimSuffix(shareCode(shareURL, "/s/"), ".html")
+	if key == "" {
+		return StatusUnknown, fmt.Errorf("无法解析分享码")
+	}
+	if password == "" {
+		password = queryValue(shareURL, "pwd", "提取码")
+	}
+
+	listURL := p.BaseURL + "/b/api/share/get?limit=1&next=1&orderBy=file_name&orderDirection=asc&ParentFileId=0&Page=1&shareKey=" +
+		url.QueryEscape(key) + "&SharePwd=" + url.QueryEscape(password)
+	req, err := newRequest(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	var resp pan123Response
+	if _, err := doJSON(client, req, &resp); err != nil {
+		return StatusUnknown, err
+	}
+	switch resp.Code {
+	case 0:
+		return StatusValid, nil
+	case 5103:
+		// 提取码错误
+		return StatusNeedsPassword, nil
+	default:
+		return classifyMessage(resp.Message), nil
+	}
+}
@@ -0,0 +1,146 @@
+package linkcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	jsonutil "pansou/util/json"
+)
+
+// userAgent 探测请求使用的浏览器UA，部分网盘会拒绝非浏览器请求
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+// maxBodySize 读取响应体的最大长度
+const maxBodySize = 1 << 20
+
+// 失效和需要提取码的常见提示，用于从接口消息或页面内容判断状态
+var (
+	expiredMarkers = []string{
+		"失效", "不存在", "已删除", "被删除", "已过期", "过期", "已取消", "取消分享", "违规", "违反", "封禁",
+		"来晚了", "涉及侵权", "页面不存在", "not found", "notfound", "expired", "cancelled", "canceled",
+	}
+	passwordMarkers = []string{
+		"提取码", "访问码", "密码", "passcode", "password", "share_pwd", "sharepwd",
+	}
+)
+
+// classifyMessage 根据接口返回的提示判断状态，无法判断时返回StatusUnknown
+// 需要提取码的提示优先，避免"提取码错误"被误判为失效
+func classifyMessage(message string) Status {
+	lower := strings.ToLower(message)
+	if containsAny(lower, passwordMarkers) {
+		return StatusNeedsPassword
+	}
+	if containsAny(lower, expiredMarkers) {
+		return StatusExpired
+	}
+	return StatusUnknown
+}
+
+// containsAny 判断s是否包含任意一个子串
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// shareCode 提取分享链接中marker之后的路径段，如 /s/abc?pwd=1 中的abc
+func shareCode(shareURL string, marker string) string {
+	idx := strings.Index(shareURL, marker)
+	if idx < 0 {
+		return ""
+	}
+	code := shareURL[idx+len(marker):]
+	if end := strings.IndexAny(code, "/?#&"); end >= 0 {
+		code = code[:end]
+	}
+	return strings.TrimSpace(code)
+}
+
+// queryValue 读取分享链接中的查询参数，用于获取链接中自带的提取码
+func queryValue(shareURL string, names ...string) string {
+	u, err := url.Parse(shareURL)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	for _, name := range names {
+		if value := strings.TrimSpace(query.Get(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// newRequest 创建带浏览器请求头的探测请求，body不为nil时以JSON发送
+func newRequest(ctx context.Context, method string, requestURL string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := jsonutil.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json, text/plain, */*")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// doJSON 发送请求并解析JSON响应，接口的错误响应也带有JSON内容，因此不按状态码判断成功与否
+func doJSON(client *http.Client, req *http.Request, out interface{}) (int, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := jsonutil.Unmarshal(data, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("解析响应失败(HTTP %d): %w", resp.StatusCode, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// readBody 发送请求并读取响应内容
+func readBody(client *http.Client, req *http.Request) (*http.Response, string, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return resp, "", err
+	}
+	return resp, string(data), nil
+}
+
+// noRedirectClient 返回不跟随重定向的客户端副本，用于通过重定向目标判断状态
+func noRedirectClient(client *http.Client) *http.Client {
+	clientCopy := *client
+	clientCopy.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &clientCopy
+}
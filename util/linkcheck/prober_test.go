@@ -0,0 +1,287 @@
+package linkcheck
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// probeCase 探测器对一个分享链接的期望结果
+type probeCase struct {
+	name     string
+	shareURL string
+	password string
+	want     Status
+}
+
+// runProbeCases 依次探测各链接并检查结果
+func runProbeCases(t *testing.T, prober Prober, cases []probeCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _ := prober.Probe(context.Background(), http.DefaultClient, tc.shareURL, tc.password)
+			if got != tc.want {
+				t.Errorf("Probe(%q, %q) = %s，期望%s", tc.shareURL, tc.password, got, tc.want)
+			}
+		})
+	}
+}
+
+// writeJSON 写入JSON响应
+func writeJSON(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, body)
+}
+
+func TestBaiduProber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/share/verify":
+			r.ParseForm()
+			switch r.PostForm.Get("pwd") {
+			case "good":
+				writeJSON(w, `{"errno":0}`)
+			case "bad":
+				writeJSON(w, `{"errno":-9}`)
+			case "gone":
+				writeJSON(w, `{"errno":-7}`)
+			default:
+				writeJSON(w, `{"errno":-62}`)
+			}
+		case r.URL.Path == "/s/1valid":
+			io.WriteString(w, "<html>分享的文件</html>")
+		case r.URL.Path == "/s/1expired":
+			io.WriteString(w, "<html>啊哦，你来晚了，分享的文件已经被删除了</html>")
+		case r.URL.Path == "/s/1locked":
+			http.Redirect(w, r, "/share/init?surl=locked", http.StatusFound)
+		case r.URL.Path == "/s/1missing":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	runProbeCases(t, &BaiduProber{BaseURL: srv.URL}, []probeCase{
+		{"valid_page", "https://pan.baidu.com/s/1valid", "", StatusValid},
+		{"expired_page", "https://pan.baidu.com/s/1expired", "", StatusExpired},
+		{"expired_404", "https://pan.baidu.com/s/1missing", "", StatusExpired},
+		{"needs_password_redirect", "https://pan.baidu.com/s/1locked", "", StatusNeedsPassword},
+		{"unknown_page", "https://pan.baidu.com/s/1broken", "", StatusUnknown},
+		{"valid_password", "https://pan.baidu.com/s/1abc", "good", StatusValid},
+		{"password_in_url", "https://pan.baidu.com/share/init?surl=abc&pwd=good", "", StatusValid},
+		{"wrong_password", "https://pan.baidu.com/s/1abc", "bad", StatusNeedsPassword},
+		{"expired_verify", "https://pan.baidu.com/s/1abc", "gone", StatusExpired},
+		{"unknown_verify", "https://pan.baidu.com/s/1abc", "captcha", StatusUnknown},
+	})
+}
+
+func TestQuarkProber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body := string(data)
+		switch {
+		case r.URL.Path != "/1/clouddrive/share/sharepage/token":
+			w.WriteHeader(http.StatusNotFound)
+		case strings.Contains(body, `"valid"`):
+			writeJSON(w, `{"status":200,"code":0,"message":"ok"}`)
+		case strings.Contains(body, `"expired"`):
+			writeJSON(w, `{"status":404,"code":41006,"message":"分享不存在"}`)
+		case strings.Contains(body, `"locked"`):
+			writeJSON(w, `{"status":400,"code":41008,"message":"提取码错误"}`)
+		case strings.Contains(body, `"busy"`):
+			writeJSON(w, `{"status":500,"code":50000,"message":"系统繁忙"}`)
+		default:
+			io.WriteString(w, "<html>")
+		}
+	}))
+	defer srv.Close()
+
+	runProbeCases(t, &QuarkProber{BaseURL: srv.URL, Params: "pr=ucpro&fr=pc"}, []probeCase{
+		{"valid", "https://pan.quark.cn/s/valid", "", StatusValid},
+		{"expired", "https://pan.quark.cn/s/expired", "", StatusExpired},
+		{"needs_password", "https://pan.quark.cn/s/locked?pwd=1234", "", StatusNeedsPassword},
+		{"unknown_message", "https://pan.quark.cn/s/busy", "", StatusUnknown},
+		{"unknown_invalid_json", "https://pan.quark.cn/s/other", "", StatusUnknown},
+	})
+}
+
+func TestAliyunProber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body := string(data)
+		switch r.URL.Path {
+		case "/adrive/v3/share_link/get_share_by_anonymous":
+			switch r.URL.Query().Get("share_id") {
+			case "valid":
+				writeJSON(w, `{"share_name":"资源"}`)
+			case "expired":
+				writeJSON(w, `{"code":"ShareLink.Cancelled","message":"share link is cancelled"}`)
+			default:
+				writeJSON(w, `{"code":"TooManyRequests","message":"slow down"}`)
+			}
+		case "/v2/share_link/get_share_token":
+			if strings.Contains(body, `"share_pwd":"good"`) {
+				writeJSON(w, `{"share_token":"token"}`)
+			} else {
+				writeJSON(w, `{"code":"InvalidResource.SharePwd","message":"share pwd is invalid"}`)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	runProbeCases(t, &AliyunProber{BaseURL: srv.URL}, []probeCase{
+		{"valid", "https://www.alipan.com/s/valid", "", StatusValid},
+		{"valid_password", "https://www.alipan.com/s/valid", "good", StatusValid},
+		{"expired", "https://www.aliyundrive.com/s/expired", "", StatusExpired},
+		{"needs_password", "https://www.alipan.com/s/valid", "bad", StatusNeedsPassword},
+		{"unknown", "https://www.alipan.com/s/limited", "", StatusUnknown},
+	})
+}
+
+func TestPan115Prober(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("share_code") == "expired":
+			writeJSON(w, `{"state":false,"error":"分享已取消"}`)
+		case query.Get("receive_code") == "good":
+			writeJSON(w, `{"state":true}`)
+		case query.Get("receive_code") == "":
+			writeJSON(w, `{"state":false,"error":"请输入访问码"}`)
+		default:
+			writeJSON(w, `{"state":false,"error":"操作频繁"}`)
+		}
+	}))
+	defer srv.Close()
+
+	runProbeCases(t, &Pan115Prober{BaseURL: srv.URL}, []probeCase{
+		{"valid", "https://115.com/s/abc?password=good", "", StatusValid},
+		{"expired", "https://115cdn.com/s/expired", "", StatusExpired},
+		{"needs_password", "https://115.com/s/abc", "", StatusNeedsPassword},
+		{"unknown", "https://115.com/s/abc", "busy", StatusUnknown},
+	})
+}
+
+func TestPan123Prober(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("shareKey") == "expired":
+			writeJSON(w, `{"code":5100,"message":"分享页面不存在"}`)
+		case query.Get("SharePwd") == "bad":
+			writeJSON(w, `{"code":5103,"message":"提取码错误"}`)
+		case query.Get("shareKey") == "valid":
+			writeJSON(w, `{"code":0,"message":"ok"}`)
+		default:
+			writeJSON(w, `{"code":429,"message":"请求过快"}`)
+		}
+	}))
+	defer srv.Close()
+
+	runProbeCases(t, &Pan123Prober{BaseURL: srv.URL}, []probeCase{
+		{"valid", "https://www.123pan.com/s/valid.html", "", StatusValid},
+		{"expired", "https://www.123684.com/s/expired", "", StatusExpired},
+		{"needs_password", "https://www.123pan.com/s/valid?pwd=bad", "", StatusNeedsPassword},
+		{"unknown", "https://www.123pan.com/s/other", "", StatusUnknown},
+	})
+}
+
+func TestTianyiProber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/open/share/getShareInfoByCodeV2.action":
+			switch query.Get("shareCode") {
+			case "valid":
+				writeJSON(w, `{"res_code":0,"needAccessCode":0}`)
+			case "locked":
+				writeJSON(w, `{"res_code":0,"needAccessCode":1}`)
+			case "expired":
+				writeJSON(w, `{"res_code":"ShareNotFound","res_message":"分享不存在"}`)
+			default:
+				writeJSON(w, `{"res_code":"InternalError","res_message":"系统异常"}`)
+			}
+		case "/api/open/share/checkAccessCode.action":
+			if query.Get("accessCode") == "good" {
+				writeJSON(w, `{"res_code":0,"shareId":123}`)
+			} else {
+				writeJSON(w, `{"res_code":0}`)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	runProbeCases(t, &TianyiProber{BaseURL: srv.URL}, []probeCase{
+		{"valid", "https://cloud.189.cn/t/valid", "", StatusValid},
+		{"valid_access_code", "https://cloud.189.cn/web/share?code=locked", "good", StatusValid},
+		{"expired", "https://cloud.189.cn/t/expired", "", StatusExpired},
+		{"needs_password", "https://cloud.189.cn/t/locked", "", StatusNeedsPassword},
+		{"wrong_access_code", "https://cloud.189.cn/t/locked", "bad", StatusNeedsPassword},
+		{"unknown", "https://cloud.189.cn/t/other", "", StatusUnknown},
+	})
+}
+
+func TestPageProber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/s/missing":
+			http.NotFound(w, r)
+		case "/s/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/s/removed":
+			io.WriteString(w, "<html>分享已失效</html>")
+		default:
+			io.WriteString(w, "<html><div id=app></div></html>")
+		}
+	}))
+	defer srv.Close()
+
+	runProbeCases(t, &PageProber{BaseURL: srv.URL, ExpiredMarkers: []string{"分享已失效"}}, []probeCase{
+		{"expired_404", "https://pan.xunlei.com/s/missing", "", StatusExpired},
+		{"expired_410", "https://mypikpak.com/s/gone", "", StatusExpired},
+		{"expired_marker", "https://pan.xunlei.com/s/removed", "", StatusExpired},
+		{"unknown", "https://pan.xunlei.com/s/valid", "", StatusUnknown},
+	})
+}
+
+// TestPageProberFixedHost 分享链接中的域名不是网盘域名时也只访问网盘地址
+func TestPageProberFixedHost(t *testing.T) {
+	var internalHits int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&internalHits, 1)
+		http.NotFound(w, r)
+	}))
+	defer internal.Close()
+
+	var paths []string
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		io.WriteString(w, "<html></html>")
+	}))
+	defer provider.Close()
+
+	// 包含网盘域名的任意地址会被识别为迅雷链接
+	shareURL := internal.URL + "/admin?next=pan.xunlei.com/s/abc"
+	got, err := (&PageProber{BaseURL: provider.URL}).Probe(context.Background(), http.DefaultClient, shareURL, "")
+	if err != nil || got != StatusUnknown {
+		t.Fatalf("Probe() = %s, %v，期望unknown", got, err)
+	}
+	if hits := atomic.LoadInt32(&internalHits); hits != 0 {
+		t.Errorf("访问了分享链接中的域名%d次", hits)
+	}
+	if len(paths) != 1 || paths[0] != "/admin?next=pan.xunlei.com/s/abc" {
+		t.Errorf("网盘地址收到的请求 = %v", paths)
+	}
+
+	if _, err := (&PageProber{}).Probe(context.Background(), http.DefaultClient, shareURL, ""); err == nil {
+		t.Error("未配置网盘地址时应返回错误")
+	}
+	if hits := atomic.LoadInt32(&internalHits); hits != 0 {
+		t.Errorf("未配置网盘地址时访问了分享链接中的域名%d次", hits)
+	}
+}
@@ -0,0 +1,51 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+func init() {
+	Register("quark", &QuarkProber{BaseURL: "https://drive-h.quark.cn", Params: "pr=ucpro&fr=pc"})
+	Register("uc", &QuarkProber{BaseURL: "https://pc-api.uc.cn", Params: "entry=ft&fr=pc&pr=UCBrowser"})
+}
+
+// QuarkProber 夸克网盘探测器，UC网盘使用相同的分享接口
+type QuarkProber struct {
+	BaseURL string // 接口地址
+	Params  string // 接口公共参数，夸克和UC不同
+}
+
+// quarkTokenResponse 获取分享token接口的响应
+type quarkTokenResponse struct {
+	Status  int    `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Probe 实现Prober接口，通过获取分享token判断分享状态
+func (p *QuarkProber) Probe(ctx context.Context, client *http.Client, shareURL string, password string) (Status, error) {
+	pwdID := shareCode(shareURL, "/s/")
+	if pwdID == "" {
+		return StatusUnknown, fmt.Errorf("无法解析分享ID")
+	}
+	if password == "" {
+		password = queryValue(shareURL, "pwd", "passcode")
+	}
+
+	body := map[string]string{"pwd_id": pwdID, "passcode": password}
+	req, err := newRequest(ctx, http.MethodPost, p.BaseURL+"/1/clouddrive/share/sharepage/token?"+p.Params, body)
+	if err != nil {
+		return StatusUnknown, err
+	}
+
+	var resp quarkTokenResponse
+	if _, err := doJSON(client, req, &resp); err != nil {
+		return StatusUnknown, err
+	}
+	if resp.Code == 0 {
+		return StatusValid, nil
+	}
+	return classifyMessage(resp.Message), nil
+}
@@ -0,0 +1,87 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	Register("tianyi", &TianyiProber{BaseURL: "https://cloud.189.cn"})
+}
+
+// TianyiProber 天翼云盘探测器
+type TianyiProber struct {
+	BaseURL string // 网盘地址
+}
+
+// tianyiResponse 天翼云盘分享接口响应，res_code成功时为0，失败时为错误码字符串
+type tianyiResponse struct {
+	ResCode        interface{} `json:"res_code"`
+	ResMessage     string      `json:"res_message"`
+	NeedAccessCode int         `json:"needAccessCode"`
+	ShareID        interface{} `json:"shareId"`
+}
+
+// ok 判断接口是否调用成功
+func (r *tianyiResponse) ok() bool {
+	return fmt.Sprint(r.ResCode) == "0"
+}
+
+// Probe 实现Prober接口，获取分享信息，需要访问码时再校验访问码
+func (p *TianyiProber) Probe(ctx context.Context, client *http.Client, shareURL string, password string) (Status, error) {
+	// 支持 /t/xxx 和 /web/share?code=xxx 两种链接
+	code := shareCode(shareURL, "/t/")
+	if code == "" {
+		code = queryValue(shareURL, "code")
+	}
+	if code == "" {
+		return StatusUnknown, fmt.Errorf("无法解析分享码")
+	}
+
+	req, err := newRequest(ctx, http.MethodGet, p.BaseURL+"/api/open/share/getShareInfoByCodeV2.action?shareCode="+url.QueryEscape(code), nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	var info tianyiResponse
+	if _, err := doJSON(client, req, &info); err != nil {
+		return StatusUnknown, err
+	}
+	if !info.ok() {
+		return classifyTianyiCode(fmt.Sprint(info.ResCode), info.ResMessage), nil
+	}
+	if info.NeedAccessCode != 1 {
+		return StatusValid, nil
+	}
+	if password == "" {
+		return StatusNeedsPassword, nil
+	}
+
+	// 校验访问码
+	req, err = newRequest(ctx, http.MethodGet, p.BaseURL+"/api/open/share/checkAccessCode.action?shareCode="+url.QueryEscape(code)+"&accessCode="+url.QueryEscape(password), nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	var check tianyiResponse
+	if _, err := doJSON(client, req, &check); err != nil {
+		return StatusUnknown, err
+	}
+	if check.ok() && check.ShareID != nil {
+		return StatusValid, nil
+	}
+	return StatusNeedsPassword, nil
+}
+
+// classifyTianyiCode 根据天翼云盘错误码判断状态
+func classifyTianyiCode(code string, message string) Status {
+	switch {
+	case strings.Contains(code, "NotFound") || strings.Contains(code, "Expired") || strings.Contains(code, "AuditNotPass"):
+		return StatusExpired
+	case strings.Contains(code, "AccessCode"):
+		return StatusNeedsPassword
+	default:
+		return classifyMessage(message)
+	}
+}
@@ -0,0 +1,234 @@
+// Package logger 基于log/slog的日志门面，支持日志级别、JSON输出、请求ID和按插件设置日志级别
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// state 当前日志配置
+type state struct {
+	handler      slog.Handler          // 底层输出，不做级别过滤
+	level        slog.Level            // 全局日志级别
+	pluginLevels map[string]slog.Level // 插件日志级别，键为小写插件名
+}
+
+var current atomic.Pointer[state]
+
+func init() {
+	// 未调用Init前使用文本格式和Info级别
+	setup(os.Stdout, "text", slog.LevelInfo, nil)
+}
+
+// Init 根据配置初始化日志
+// level为debug/info/warn/error，format为text或json，pluginLevels为插件名到日志级别的映射
+func Init(level string, format string, pluginLevels map[string]string) {
+	levels := make(map[string]slog.Level, len(pluginLevels))
+	for name, value := range pluginLevels {
+		levels[strings.ToLower(name)] = ParseLevel(value)
+	}
+	setup(os.Stdout, format, ParseLevel(level), levels)
+}
+
+// setup 创建日志输出并替换slog默认日志器，标准库log的输出也会转为结构化日志
+func setup(w io.Writer, format string, level slog.Level, pluginLevels map[string]slog.Level) {
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handler slog.Handler
+	if strings.EqualFold(format, "json") {
+		handler = slog.NewJSONHandler(w, options)
+	} else {
+		handler = slog.NewTextHandler(w, options)
+	}
+
+	current.Store(&state{handler: handler, level: level, pluginLevels: pluginLevels})
+	slog.SetDefault(slog.New(&contextHandler{}))
+}
+
+// ParseLevel 解析日志级别，无法识别时返回Info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// contextHandler 按全局级别过滤，并从上下文中补充请求ID
+type contextHandler struct {
+	attrs  []slog.Attr
+	groups []string
+}
+
+// Enabled 实现slog.Handler接口
+func (h *contextHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= current.Load().level
+}
+
+// Handle 实现slog.Handler接口
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.build().Handle(ctx, withRequestID(ctx, r))
+}
+
+// WithAttrs 实现slog.Handler接口
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{attrs: append(append([]slog.Attr(nil), h.attrs...), attrs...), groups: h.groups}
+}
+
+// WithGroup 实现slog.Handler接口
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{attrs: h.attrs, groups: append(append([]string(nil), h.groups...), name)}
+}
+
+// build 基于当前配置的底层输出重建属性和分组，使Init之前创建的日志器也能使用新配置
+func (h *contextHandler) build() slog.Handler {
+	handler := current.Load().handler
+	if len(h.attrs) > 0 {
+		handler = handler.WithAttrs(h.attrs)
+	}
+	for _, group := range h.groups {
+		handler = handler.WithGroup(group)
+	}
+	return handler
+}
+
+// withRequestID 为日志记录补充上下文中的请求ID
+func withRequestID(ctx context.Context, r slog.Record) slog.Record {
+	if id := RequestID(ctx); id != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return r
+}
+
+// Debug 输出调试日志
+func Debug(ctx context.Context, msg string, args ...any) {
+	slog.DebugContext(ctx, msg, args...)
+}
+
+// Info 输出信息日志
+func Info(ctx context.Context, msg string, args ...any) {
+	slog.InfoContext(ctx, msg, args...)
+}
+
+// Warn 输出警告日志
+func Warn(ctx context.Context, msg string, args ...any) {
+	slog.WarnContext(ctx, msg, args...)
+}
+
+// Error 输出错误日志
+func Error(ctx context.Context, msg string, args ...any) {
+	slog.ErrorContext(ctx, msg, args...)
+}
+
+// requestIDKey 请求ID在上下文中的键
+type requestIDKey struct{}
+
+// WithRequestID 返回携带请求ID的上下文
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID 从上下文中获取请求ID
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID 生成新的请求ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// PluginLogger 插件日志器，级别由PLUGIN_LOG_LEVELS单独控制，未配置时使用全局级别
+type PluginLogger struct {
+	name string
+	ctx  context.Context // Debugf等格式化方法使用的上下文，nil表示context.Background()
+}
+
+// ForPlugin 返回指定插件的日志器，可以在包初始化时创建，级别在输出时按当前配置判断
+func ForPlugin(name string) *PluginLogger {
+	return &PluginLogger{name: name}
+}
+
+// WithContext 返回绑定上下文的日志器，Debugf等格式化方法输出的日志带上请求ID
+// 插件在搜索函数中使用：pluginLog.WithContext(plugin.SearchContextFromExt(ext))
+func (l *PluginLogger) WithContext(ctx context.Context) *PluginLogger {
+	return &PluginLogger{name: l.name, ctx: ctx}
+}
+
+// Enabled 判断指定级别的日志是否输出
+func (l *PluginLogger) Enabled(level slog.Level) bool {
+	s := current.Load()
+	if pluginLevel, ok := s.pluginLevels[strings.ToLower(l.name)]; ok {
+		return level >= pluginLevel
+	}
+	return level >= s.level
+}
+
+// DebugEnabled 判断是否输出调试日志，用于跳过代价较高的调试信息构造
+func (l *PluginLogger) DebugEnabled() bool {
+	return l.Enabled(slog.LevelDebug)
+}
+
+// Log 输出带上下文的结构化日志
+func (l *PluginLogger) Log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r.AddAttrs(slog.String("plugin", l.name))
+	r.Add(args...)
+	_ = current.Load().handler.Handle(ctx, withRequestID(ctx, r))
+}
+
+// Debugf 输出格式化的调试日志
+func (l *PluginLogger) Debugf(format string, args ...any) {
+	l.logf(slog.LevelDebug, format, args...)
+}
+
+// Infof 输出格式化的信息日志
+func (l *PluginLogger) Infof(format string, args ...any) {
+	l.logf(slog.LevelInfo, format, args...)
+}
+
+// Warnf 输出格式化的警告日志
+func (l *PluginLogger) Warnf(format string, args ...any) {
+	l.logf(slog.LevelWarn, format, args...)
+}
+
+// Errorf 输出格式化的错误日志
+func (l *PluginLogger) Errorf(format string, args ...any) {
+	l.logf(slog.LevelError, format, args...)
+}
+
+// logf 格式化消息后输出，级别不满足时不做格式化
+func (l *PluginLogger) logf(level slog.Level, format string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.Log(l.ctx, level, strings.TrimSuffix(fmt.Sprintf(format, args...), "\n"))
+}
@@ -0,0 +1,302 @@
+// Package metrics 提供轻量的Prometheus文本格式指标，不依赖外部库
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LatencyBuckets 默认的耗时直方图分桶（秒）
+var LatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// collector 可导出为Prometheus文本格式的指标
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+var (
+	registry      []collector
+	registryNames = make(map[string]bool)
+	registryMutex sync.RWMutex
+)
+
+// register 注册指标，指标名重复时panic（属于编程错误）
+func register(name string, c collector) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if registryNames[name] {
+		panic("metrics: 重复注册指标 " + name)
+	}
+	registryNames[name] = true
+	registry = append(registry, c)
+}
+
+// WriteText 以Prometheus文本格式输出所有已注册指标
+func WriteText(w io.Writer) error {
+	registryMutex.RLock()
+	collectors := make([]collector, len(registry))
+	copy(collectors, registry)
+	registryMutex.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	return bw.Flush()
+}
+
+// series 一组标签值对应的时间序列
+type series struct {
+	labelValues []string
+	value       float64
+}
+
+// vec 按标签值区分的时间序列集合
+type vec struct {
+	name   string
+	help   string
+	labels []string
+	series map[string]*series
+	mutex  sync.Mutex
+}
+
+// get 获取或创建标签值对应的时间序列（调用方需持有锁）
+func (v *vec) get(labelValues []string) *series {
+	if len(labelValues) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: 指标 %s 需要 %d 个标签值，实际为 %d 个", v.name, len(v.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		v.series[key] = s
+	}
+	return s
+}
+
+// sortedSeries 按标签值排序返回所有时间序列（调用方需持有锁）
+func (v *vec) sortedSeries() []*series {
+	list := make([]*series, 0, len(v.series))
+	for _, s := range v.series {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.Join(list[i].labelValues, "\xff") < strings.Join(list[j].labelValues, "\xff")
+	})
+	return list
+}
+
+// writeHeader 输出HELP和TYPE行
+func writeHeader(w *bufio.Writer, name, help, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+// CounterVec 带标签的计数器
+type CounterVec struct {
+	vec
+}
+
+// NewCounterVec 创建并注册带标签的计数器
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec{name: name, help: help, labels: labels, series: make(map[string]*series)}}
+	register(name, c)
+	return c
+}
+
+// Inc 计数加1
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add 计数增加指定值，负数会被忽略
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	c.mutex.Lock()
+	c.get(labelValues).value += value
+	c.mutex.Unlock()
+}
+
+// write 实现collector接口
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+	for _, s := range c.sortedSeries() {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, s.labelValues, "", ""), formatValue(s.value))
+	}
+}
+
+// histogramSeries 直方图的一组标签值对应的统计
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // 各分桶的非累计计数
+	sum         float64
+	count       uint64
+}
+
+// HistogramVec 带标签的直方图
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	series  map[string]*histogramSeries
+	mutex   sync.Mutex
+}
+
+// NewHistogramVec 创建并注册带标签的直方图，buckets为升序的分桶上界
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	h := &HistogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: sorted,
+		series:  make(map[string]*histogramSeries),
+	}
+	register(name, h)
+	return h
+}
+
+// Observe 记录一个观测值
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	if len(labelValues) != len(h.labels) {
+		panic(fmt.Sprintf("metrics: 指标 %s 需要 %d 个标签值，实际为 %d 个", h.name, len(h.labels), len(labelValues)))
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = s
+	}
+
+	// 超过最大上界的观测值只计入+Inf
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.sum += value
+	s.count++
+}
+
+// write 实现collector接口
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writeHeader(w, h.name, h.help, "histogram")
+	for _, key := range keys {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, s.labelValues, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, s.labelValues, "", ""), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, s.labelValues, "", ""), s.count)
+	}
+}
+
+// funcMetric 抓取时通过回调取值的指标
+type funcMetric struct {
+	name       string
+	help       string
+	metricType string
+	fn         func() float64
+}
+
+// NewGaugeFunc 注册抓取时通过回调取值的仪表盘指标
+func NewGaugeFunc(name, help string, fn func() float64) {
+	register(name, &funcMetric{name: name, help: help, metricType: "gauge", fn: fn})
+}
+
+// NewCounterFunc 注册抓取时通过回调取值的计数器，回调返回值应单调递增
+func NewCounterFunc(name, help string, fn func() float64) {
+	register(name, &funcMetric{name: name, help: help, metricType: "counter", fn: fn})
+}
+
+// write 实现collector接口
+func (m *funcMetric) write(w *bufio.Writer) {
+	writeHeader(w, m.name, m.help, m.metricType)
+	fmt.Fprintf(w, "%s %s\n", m.name, formatValue(m.fn()))
+}
+
+// formatLabels 格式化标签，extraName非空时追加一个额外标签（用于直方图的le）
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(name)
+		sb.WriteString(`="`)
+		sb.WriteString(escapeLabelValue(values[i]))
+		sb.WriteByte('"')
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(extraName)
+		sb.WriteString(`="`)
+		sb.WriteString(extraValue)
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// formatValue 按Prometheus文本格式输出数值
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escapeLabelValue 转义标签值中的反斜杠、双引号和换行
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// escapeHelp 转义帮助文本中的反斜杠和换行
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
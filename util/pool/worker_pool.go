@@ -71,7 +71,12 @@ func (p *WorkerPool) startWorkers() {
 					
 					// 执行任务并发送结果
 					result := task()
-					p.results <- result
+					select {
+					case p.results <- result:
+					case <-p.ctx.Done():
+						// 上下文已取消，无人接收结果，直接退出避免阻塞Close
+						return
+					}
 					
 				case <-p.ctx.Done():
 					return
@@ -145,6 +150,11 @@ func ExecuteBatch(tasks []Task, maxWorkers int) []interface{} {
 
 // ExecuteBatchWithTimeout 批量执行任务，带有超时控制，并返回结果
 func ExecuteBatchWithTimeout(tasks []Task, maxWorkers int, timeout time.Duration) []interface{} {
+	return ExecuteBatchWithContext(context.Background(), tasks, maxWorkers, timeout)
+}
+
+// ExecuteBatchWithContext 批量执行任务，超时或父上下文取消时返回已收集的结果
+func ExecuteBatchWithContext(parent context.Context, tasks []Task, maxWorkers int, timeout time.Duration) []interface{} {
 	if len(tasks) == 0 {
 		return []interface{}{}
 	}
@@ -155,7 +165,7 @@ func ExecuteBatchWithTimeout(tasks []Task, maxWorkers int, timeout time.Duration
 	}
 	
 	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(parent, timeout)
 	defer cancel()
 	
 	// 创建工作池
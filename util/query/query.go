@@ -0,0 +1,351 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"pansou/model"
+	"pansou/util/release"
+)
+
+// Query 解析后的搜索查询
+type Query struct {
+	Keyword     string   // 正向关键词（含精确短语的内容），用于请求频道和插件
+	Excludes    []string // 排除词，-term
+	Phrases     []string // 精确短语，"exact phrase"
+	CloudTypes  []string // 网盘类型，type:quark
+	Years       []int    // 年份，year:2023或year:2020-2023（展开为范围内的每一年）
+	Resolutions []string // 分辨率，res:4k
+	Codecs      []string // 视频编码，codec:x265
+	HDR         []string // 动态范围，hdr:dv
+	Seasons     []int    // 季号，season:2
+	Subtitles   []string // 字幕语言，sub:chs
+	MinSize     int64    // 文件大小下限，size:>10gb
+	MaxSize     int64    // 文件大小上限，size:<5gb
+	SourceType  string   // 数据来源，src:tg
+}
+
+// CloudTypes 支持的网盘类型
+var CloudTypes = []string{
+	"baidu", "aliyun", "quark", "tianyi", "uc", "mobile", "115",
+	"pikpak", "xunlei", "123", "magnet", "ed2k", "others",
+}
+
+// IsCloudType 判断是否为支持的网盘类型
+func IsCloudType(cloudType string) bool {
+	for _, t := range CloudTypes {
+		if t == cloudType {
+			return true
+		}
+	}
+	return false
+}
+
+// maxYearRange year范围过滤最多展开的年数
+const maxYearRange = 50
+
+// Parse 解析查询语法：
+// -term 排除词，"exact phrase" 精确短语，type:quark 网盘类型，year:2023 年份，res:4k 分辨率，
+// codec:x265 视频编码，hdr:dv 动态范围，season:2 季号，sub:chs 字幕语言，size:>10gb 文件大小，src:tg 数据来源；
+// 未知字段（如"Re:Zero"）和无法解析的值按普通关键词处理
+func Parse(input string) Query {
+	var q Query
+	var keywords []string
+
+	for _, tok := range tokenize(input) {
+		// 精确短语
+		if tok.quoted {
+			if tok.exclude {
+				q.Excludes = append(q.Excludes, tok.text)
+			} else {
+				q.Phrases = append(q.Phrases, tok.text)
+				keywords = append(keywords, tok.text)
+			}
+			continue
+		}
+
+		// 排除词
+		if tok.exclude {
+			q.Excludes = append(q.Excludes, tok.text)
+			continue
+		}
+
+		// 字段过滤
+		if q.applyField(tok.text) {
+			continue
+		}
+
+		keywords = append(keywords, tok.text)
+	}
+
+	q.Keyword = strings.Join(keywords, " ")
+	return q
+}
+
+// applyField 解析field:value形式的过滤条件，不是有效的过滤条件时返回false
+func (q *Query) applyField(text string) bool {
+	text = strings.Replace(text, "：", ":", 1)
+	field, value, ok := strings.Cut(text, ":")
+	if !ok || value == "" {
+		return false
+	}
+
+	switch strings.ToLower(field) {
+	case "type":
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+				q.CloudTypes = append(q.CloudTypes, t)
+			}
+		}
+		return true
+	case "year":
+		years, ok := parseYears(value)
+		if !ok {
+			return false
+		}
+		q.Years = append(q.Years, years...)
+		return true
+	case "res":
+		values, ok := canonicalValues(value, release.CanonicalResolution)
+		q.Resolutions = append(q.Resolutions, values...)
+		return ok
+	case "codec":
+		values, ok := canonicalValues(value, release.CanonicalCodec)
+		q.Codecs = append(q.Codecs, values...)
+		return ok
+	case "hdr":
+		values, ok := canonicalValues(value, release.CanonicalHDR)
+		q.HDR = append(q.HDR, values...)
+		return ok
+	case "sub":
+		values, ok := canonicalValues(value, release.CanonicalSubtitle)
+		q.Subtitles = append(q.Subtitles, values...)
+		return ok
+	case "season":
+		for _, v := range strings.Split(value, ",") {
+			season, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil || season <= 0 {
+				return false
+			}
+			q.Seasons = append(q.Seasons, season)
+		}
+		return true
+	case "size":
+		return q.parseSize(value)
+	case "src":
+		switch value = strings.ToLower(value); value {
+		case "all", "tg", "plugin":
+			q.SourceType = value
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalValues 将逗号分隔的多个值转换为标准值，任一值无法识别时返回false
+func canonicalValues(value string, canonical func(string) string) ([]string, bool) {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		c := canonical(v)
+		if c == "" {
+			return nil, false
+		}
+		values = append(values, c)
+	}
+	return values, len(values) > 0
+}
+
+// parseSize 解析文件大小条件：>10gb 下限，<5gb 上限，10gb-20gb 范围
+func (q *Query) parseSize(value string) bool {
+	// 大小是估算值，>=和>、<=和<不做区分
+	value = strings.Replace(strings.Replace(value, ">=", ">", 1), "<=", "<", 1)
+	switch {
+	case strings.HasPrefix(value, ">"):
+		size, ok := release.ParseSize(value[1:])
+		if ok {
+			q.MinSize = size
+		}
+		return ok
+	case strings.HasPrefix(value, "<"):
+		size, ok := release.ParseSize(value[1:])
+		if ok {
+			q.MaxSize = size
+		}
+		return ok
+	}
+
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return false
+	}
+	minSize, ok1 := release.ParseSize(from)
+	maxSize, ok2 := release.ParseSize(to)
+	if !ok1 || !ok2 || minSize > maxSize {
+		return false
+	}
+	q.MinSize, q.MaxSize = minSize, maxSize
+	return true
+}
+
+// parseYears 解析年份或年份范围
+func parseYears(value string) ([]int, bool) {
+	from, to, isRange := strings.Cut(value, "-")
+	start, err := strconv.Atoi(from)
+	if err != nil || start < 1900 || start > 2100 {
+		return nil, false
+	}
+	if !isRange {
+		return []int{start}, true
+	}
+
+	end, err := strconv.Atoi(to)
+	if err != nil || end < start || end-start >= maxYearRange {
+		return nil, false
+	}
+	years := make([]int, 0, end-start+1)
+	for y := start; y <= end; y++ {
+		years = append(years, y)
+	}
+	return years, true
+}
+
+// token 查询中的一个词
+type token struct {
+	text    string
+	quoted  bool // 是否是引号包围的短语
+	exclude bool // 是否以-开头
+}
+
+// tokenize 按空白切分查询，引号（包括中文引号）内的内容作为一个整体
+func tokenize(input string) []token {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		exclude := false
+		start := i
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			exclude = true
+			i++
+		}
+
+		// 引号短语
+		if closing, ok := closingQuote(runes[i]); ok {
+			if end := indexRune(runes, closing, i+1); end > i+1 {
+				if text := strings.TrimSpace(string(runes[i+1 : end])); text != "" {
+					tokens = append(tokens, token{text: text, quoted: true, exclude: exclude})
+				}
+				i = end + 1
+				continue
+			}
+		}
+
+		// 普通词
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			i++
+		}
+		text := string(runes[start:i])
+		if exclude {
+			text = text[1:]
+		}
+		tokens = append(tokens, token{text: text, exclude: exclude})
+	}
+	return tokens
+}
+
+// closingQuote 返回开引号对应的闭引号
+func closingQuote(r rune) (rune, bool) {
+	switch r {
+	case '"':
+		return '"', true
+	case '“':
+		return '”', true
+	}
+	return 0, false
+}
+
+// indexRune 从from开始查找字符的位置，找不到时返回-1
+func indexRune(runes []rune, r rune, from int) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// Apply 解析请求关键词中的查询语法并改写请求：
+// 关键词只保留正向部分，type与CloudTypes取交集，src映射到SourceType，其余条件作为结果过滤条件；
+// type中有不支持的网盘类型或与CloudTypes没有交集时返回错误
+func Apply(req *model.SearchRequest) error {
+	q := Parse(req.Keyword)
+	req.Keyword = q.Keyword
+
+	cloudTypes, err := MergeCloudTypes(req.CloudTypes, q.CloudTypes)
+	if err != nil {
+		return err
+	}
+	req.CloudTypes = cloudTypes
+	if q.SourceType != "" {
+		req.SourceType = q.SourceType
+	}
+
+	req.Filter = q.Filter()
+	return nil
+}
+
+// MergeCloudTypes 合并请求参数中的网盘类型和查询语法中的type条件：
+// 只指定一方时使用该方，同时指定时取交集，两个条件都要满足；
+// type中有不支持的网盘类型或交集为空时返回错误，避免静默返回空结果或放宽过滤条件
+func MergeCloudTypes(explicit []string, types []string) ([]string, error) {
+	for _, t := range types {
+		if !IsCloudType(t) {
+			return nil, fmt.Errorf("不支持的网盘类型 type:%s，支持：%s", t, strings.Join(CloudTypes, "、"))
+		}
+	}
+	if len(types) == 0 {
+		return explicit, nil
+	}
+	if len(explicit) == 0 {
+		return types, nil
+	}
+
+	var merged []string
+	for _, t := range types {
+		for _, e := range explicit {
+			if strings.EqualFold(strings.TrimSpace(e), t) {
+				merged = append(merged, t)
+				break
+			}
+		}
+	}
+	if len(merged) == 0 {
+		return nil, fmt.Errorf("type:%s与cloud_types=%s没有相同的网盘类型", strings.Join(types, ","), strings.Join(explicit, ","))
+	}
+	return merged, nil
+}
+
+// Filter 返回需要在搜索结果上执行的过滤条件
+func (q Query) Filter() model.ResultFilter {
+	return model.ResultFilter{
+		Excludes:    q.Excludes,
+		Phrases:     q.Phrases,
+		Years:       q.Years,
+		Resolutions: q.Resolutions,
+		Codecs:      q.Codecs,
+		HDR:         q.HDR,
+		Seasons:     q.Seasons,
+		Subtitles:   q.Subtitles,
+		MinSize:     q.MinSize,
+		MaxSize:     q.MaxSize,
+	}
+}
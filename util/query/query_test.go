@@ -0,0 +1,35 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"pansou/model"
+)
+
+// TestApplyCloudTypes type条件与cloud_types参数取交集，不支持的类型和空交集返回错误
+func TestApplyCloudTypes(t *testing.T) {
+	tests := []struct {
+		keyword    string
+		cloudTypes []string
+		want       []string
+		wantErr    bool
+	}{
+		{"流浪地球", []string{"baidu"}, []string{"baidu"}, false},
+		{"流浪地球 type:quark,baidu", nil, []string{"quark", "baidu"}, false},
+		{"流浪地球 type:quark,baidu", []string{"Baidu", "aliyun"}, []string{"baidu"}, false},
+		{"流浪地球 type:quark", []string{"baidu"}, nil, true},
+		{"流浪地球 type:bogus", nil, nil, true},
+	}
+	for _, tc := range tests {
+		req := model.SearchRequest{Keyword: tc.keyword, CloudTypes: tc.cloudTypes}
+		err := Apply(&req)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("Apply(%q, %v) 错误 = %v", tc.keyword, tc.cloudTypes, err)
+			continue
+		}
+		if err == nil && (req.Keyword != "流浪地球" || !reflect.DeepEqual(req.CloudTypes, tc.want)) {
+			t.Errorf("Apply(%q, %v) = %q %v，期望%v", tc.keyword, tc.cloudTypes, req.Keyword, req.CloudTypes, tc.want)
+		}
+	}
+}
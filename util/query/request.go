@@ -0,0 +1,140 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"pansou/model"
+	"pansou/util"
+	jsonutil "pansou/util/json"
+)
+
+// ParseRequest 从URL参数解析搜索请求（GET方式），常驻服务和Serverless入口共用
+func ParseRequest(values url.Values) (model.SearchRequest, error) {
+	// 获取keyword，兼容两种参数名
+	keyword := values.Get("kw")
+	if keyword == "" {
+		keyword = values.Get("keyword")
+	}
+
+	// 处理并发数
+	concurrency := 0
+	concStr := values.Get("conc")
+	if concStr != "" && concStr != " " {
+		concurrency = util.StringToInt(concStr)
+	}
+
+	// 处理强制刷新
+	forceRefresh := values.Get("refresh") == "true"
+
+	// 处理结果类型和来源类型
+	resultType := values.Get("res")
+	if resultType == "" || resultType == " " {
+		resultType = "merge" // 直接设置为默认值merge
+	}
+
+	sourceType := values.Get("src")
+	if sourceType == "" || sourceType == " " {
+		sourceType = "all" // 直接设置为默认值all
+	}
+
+	// 处理ext参数，JSON格式
+	var ext map[string]interface{}
+	extStr := values.Get("ext")
+	if extStr != "" && extStr != " " {
+		// 处理特殊情况：ext={}
+		if extStr == "{}" {
+			ext = make(map[string]interface{})
+		} else {
+			if err := jsonutil.Unmarshal([]byte(extStr), &ext); err != nil {
+				return model.SearchRequest{}, fmt.Errorf("无效的ext参数格式: %v", err)
+			}
+		}
+	}
+	// 确保ext不为nil
+	if ext == nil {
+		ext = make(map[string]interface{})
+	}
+
+	// ext.<参数>和ext.plugins.<插件>.<参数>形式的查询参数，与ext中的参数合并，同名时查询参数优先
+	for name, vals := range values {
+		path, ok := strings.CutPrefix(name, "ext.")
+		if !ok || len(vals) == 0 {
+			continue
+		}
+		var value interface{} = vals[0]
+		if len(vals) > 1 {
+			value = vals
+		}
+		if err := setExtParam(ext, strings.Split(path, "."), value); err != nil {
+			return model.SearchRequest{}, err
+		}
+	}
+
+	return model.SearchRequest{
+		Keyword:      keyword,
+		Channels:     splitList(values, "channels", false),
+		Concurrency:  concurrency,
+		ForceRefresh: forceRefresh,
+		ResultType:   resultType,
+		SourceType:   sourceType,
+		Plugins:      splitList(values, "plugins", true),
+		CloudTypes:   splitList(values, "cloud_types", true),
+		Ext:          ext,
+		Page:         util.StringToInt(values.Get("page")),
+		PageSize:     util.StringToInt(values.Get("page_size")),
+		Cursor:       strings.TrimSpace(values.Get("cursor")),
+		CheckLinks:   values.Get("check_links") == "true",
+		DropDead:     values.Get("drop_dead") == "true",
+		Sort:         strings.TrimSpace(values.Get("sort")),
+		Explain:      values.Get("explain") == "true",
+		Group:        strings.TrimSpace(values.Get("group")),
+		Format:       values.Get("format"),
+	}, nil
+}
+
+// setExtParam 按点分隔的路径设置ext参数，中间层不存在时创建对象
+func setExtParam(ext map[string]interface{}, path []string, value interface{}) error {
+	for i, key := range path {
+		if key == "" {
+			return fmt.Errorf("无效的ext参数名: ext.%s", strings.Join(path, "."))
+		}
+		if i == len(path)-1 {
+			ext[key] = value
+			return nil
+		}
+		next, ok := ext[key].(map[string]interface{})
+		if !ok {
+			if _, exists := ext[key]; exists {
+				return fmt.Errorf("ext参数%s不是对象", strings.Join(path[:i+1], "."))
+			}
+			next = make(map[string]interface{})
+			ext[key] = next
+		}
+		ext = next
+	}
+	return nil
+}
+
+// splitList 解析逗号分隔的URL参数
+// nilIfMissing为true时，请求中不存在该参数返回nil，用于区分"未指定"和"指定为空"
+func splitList(values url.Values, name string, nilIfMissing bool) []string {
+	if nilIfMissing && !values.Has(name) {
+		return nil
+	}
+
+	var list []string
+	value := values.Get(name)
+	// 只有当参数非空时才处理
+	if value != "" && value != " " {
+		parts := strings.Split(value, ",")
+		for _, part := range parts {
+			trimmed := strings.TrimSpace(part)
+			if trimmed != "" {
+				list = append(list, trimmed)
+			}
+		}
+	}
+	return list
+}
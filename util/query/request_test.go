@@ -0,0 +1,32 @@
+package query
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// TestParseRequestExtParams ext.<参数>和ext.plugins.<插件>.<参数>与JSON形式的ext合并，同名时查询参数优先
+func TestParseRequestExtParams(t *testing.T) {
+	values, _ := url.ParseQuery(`kw=流浪地球&ext={"title_en":"old","is_all":true}&ext.title_en=The+Wandering+Earth&ext.plugins.sdso.pages_per_type=5&ext.tags=a&ext.tags=b`)
+	req, err := ParseRequest(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"title_en": "The Wandering Earth",
+		"is_all":   true,
+		"tags":     []string{"a", "b"},
+		"plugins":  map[string]interface{}{"sdso": map[string]interface{}{"pages_per_type": "5"}},
+	}
+	if !reflect.DeepEqual(req.Ext, want) {
+		t.Errorf("ext = %v，期望%v", req.Ext, want)
+	}
+
+	for _, query := range []string{"ext.=1", "ext.plugins..x=1", `ext={"plugins":1}&ext.plugins.sdso.x=1`} {
+		values, _ := url.ParseQuery(query)
+		if _, err := ParseRequest(values); err == nil {
+			t.Errorf("%s 应返回错误", query)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package ranking
+
+import (
+	"strings"
+	"time"
+
+	"pansou/util/textnorm"
+)
+
+// 内置排序分量名称
+const (
+	ComponentTime    = "time"    // 发布时间
+	ComponentKeyword = "keyword" // 标题中的优先关键词
+	ComponentPlugin  = "plugin"  // 来源插件等级
+	ComponentTitle   = "title"   // 标题与查询的匹配程度
+	ComponentQuality = "quality" // 解析出的画质和完结信息
+)
+
+func init() {
+	Register(timeComponent{})
+	Register(keywordComponent{})
+	Register(pluginComponent{})
+	Register(titleComponent{})
+	Register(qualityComponent{})
+}
+
+// timeComponent 时间分量：越新得分越高，最高500分
+type timeComponent struct{}
+
+// Name 返回分量名称
+func (timeComponent) Name() string { return ComponentTime }
+
+// Score 计算时间得分
+func (timeComponent) Score(_ string, c Candidate) float64 {
+	if c.Datetime.IsZero() {
+		return 0 // 无时间信息得0分
+	}
+
+	daysDiff := time.Since(c.Datetime).Hours() / 24
+	switch {
+	case daysDiff <= 1:
+		return 500 // 1天内
+	case daysDiff <= 3:
+		return 400 // 3天内
+	case daysDiff <= 7:
+		return 300 // 1周内
+	case daysDiff <= 30:
+		return 200 // 1月内
+	case daysDiff <= 90:
+		return 100 // 3月内
+	case daysDiff <= 365:
+		return 50 // 1年内
+	default:
+		return 20 // 1年以上
+	}
+}
+
+// priorityKeywords 优先关键词列表，越靠前优先级越高
+var priorityKeywords = []string{"合集", "系列", "全", "完", "最新", "附", "complete"}
+
+// KeywordPriority 获取标题中包含优先关键词的得分（最高490分），不包含时返回0
+func KeywordPriority(title string) int {
+	title = strings.ToLower(title)
+	for i, keyword := range priorityKeywords {
+		if strings.Contains(title, keyword) {
+			return (len(priorityKeywords) - i) * 70
+		}
+	}
+	return 0
+}
+
+// keywordComponent 优先关键词分量
+type keywordComponent struct{}
+
+// Name 返回分量名称
+func (keywordComponent) Name() string { return ComponentKeyword }
+
+// Score 计算优先关键词得分
+func (keywordComponent) Score(_ string, c Candidate) float64 {
+	return float64(KeywordPriority(c.Title))
+}
+
+// pluginComponent 插件等级分量
+type pluginComponent struct{}
+
+// Name 返回分量名称
+func (pluginComponent) Name() string { return ComponentPlugin }
+
+// Score 计算插件等级得分
+func (pluginComponent) Score(_ string, c Candidate) float64 {
+	switch c.PluginLevel {
+	case 1:
+		return 1000 // 等级1插件：1000分
+	case 2:
+		return 500 // 等级2插件：500分
+	case 4:
+		return -200 // 等级4插件：-200分
+	default:
+		return 0 // 等级3插件和TG频道：0分
+	}
+}
+
+// titleComponent 标题匹配分量
+type titleComponent struct{}
+
+// Name 返回分量名称
+func (titleComponent) Name() string { return ComponentTitle }
+
+// Score 计算标题匹配得分
+func (titleComponent) Score(query string, c Candidate) float64 {
+	return TitleMatchScore(query, c.Title)
+}
+
+// qualityComponent 画质分量
+type qualityComponent struct{}
+
+// Name 返回分量名称
+func (qualityComponent) Name() string { return ComponentQuality }
+
+// Score 计算画质得分（最高350分）：2160p及以上200分，1080p 150分，720p 50分，HDR/杜比视界加50分，完结或全集加100分
+func (qualityComponent) Score(_ string, c Candidate) float64 {
+	if c.Release == nil {
+		return 0
+	}
+
+	var score float64
+	switch c.Release.Resolution {
+	case "4320p", "2160p":
+		score = 200
+	case "1440p", "1080p":
+		score = 150
+	case "720p":
+		score = 50
+	}
+	if len(c.Release.HDR) > 0 {
+		score += 50
+	}
+	if c.Release.Complete {
+		score += 100
+	}
+	return score
+}
+
+// TitleMatchScore 计算标题与查询的匹配得分（最高1000分）
+// 比较前使用textnorm.Normalize统一文本形式；完全相同1000分，前缀匹配900分，包含800分，
+// 否则按查询词命中比例最高600分，拼音首字母匹配500分
+func TitleMatchScore(query string, title string) float64 {
+	q := textnorm.Normalize(query)
+	t := textnorm.Normalize(title)
+	if q == "" || t == "" {
+		return 0
+	}
+
+	switch {
+	case t == q:
+		return 1000
+	case strings.HasPrefix(t, q):
+		return 900
+	case strings.Contains(t, q):
+		return 800
+	}
+
+	var score float64
+	if tokens := textnorm.Tokens(query); len(tokens) > 1 {
+		matched := 0
+		for _, token := range tokens {
+			if strings.Contains(t, token) {
+				matched++
+			}
+		}
+		score = 600 * float64(matched) / float64(len(tokens))
+	}
+
+	if textnorm.IsPinyinQuery(q) && strings.Contains(textnorm.PinyinInitials(title), q) && score < 500 {
+		score = 500
+	}
+	return score
+}
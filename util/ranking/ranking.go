@@ -0,0 +1,128 @@
+package ranking
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"pansou/model"
+)
+
+// 排序方式
+const (
+	SortRelevance = "relevance" // 按综合得分排序（默认）
+	SortTime      = "time"      // 按发布时间排序，时间相同时按综合得分
+	SortSource    = "source"    // 按来源插件等级排序，等级相同时按综合得分
+)
+
+// IsValidSort 判断排序方式是否有效，空字符串表示默认排序
+func IsValidSort(mode string) bool {
+	switch mode {
+	case "", SortRelevance, SortTime, SortSource:
+		return true
+	}
+	return false
+}
+
+// Candidate 待评分的搜索结果
+type Candidate struct {
+	Title       string             // 标题
+	Datetime    time.Time          // 发布时间
+	PluginLevel int                // 来源插件等级（1-4），TG频道为3
+	Release     *model.ReleaseInfo // 从标题和内容中解析出的资源信息，可能为nil
+}
+
+// Component 排序分量，返回未加权的原始得分
+type Component interface {
+	// Name 分量名称，与权重配置中的名称对应
+	Name() string
+	// Score 计算结果相对查询的得分
+	Score(query string, c Candidate) float64
+}
+
+var (
+	components      []Component
+	componentsMutex sync.RWMutex
+)
+
+// Register 注册排序分量，同名分量会被替换
+func Register(component Component) {
+	componentsMutex.Lock()
+	defer componentsMutex.Unlock()
+
+	for i, c := range components {
+		if c.Name() == component.Name() {
+			components[i] = component
+			return
+		}
+	}
+	components = append(components, component)
+}
+
+// registeredComponents 获取已注册分量的快照
+func registeredComponents() []Component {
+	componentsMutex.RLock()
+	defer componentsMutex.RUnlock()
+	return append([]Component(nil), components...)
+}
+
+// Ranker 按权重组合各分量计算综合得分
+type Ranker struct {
+	weights map[string]float64
+}
+
+// NewRanker 创建排序器，weights中未配置的分量权重为1
+func NewRanker(weights map[string]float64) *Ranker {
+	return &Ranker{weights: weights}
+}
+
+// weight 获取分量权重
+func (r *Ranker) weight(name string) float64 {
+	if w, ok := r.weights[name]; ok {
+		return w
+	}
+	return 1
+}
+
+// Score 计算结果的综合得分及各分量加权后的得分
+func (r *Ranker) Score(query string, c Candidate) model.ScoreBreakdown {
+	registered := registeredComponents()
+	breakdown := model.ScoreBreakdown{Components: make(map[string]float64, len(registered))}
+	for _, component := range registered {
+		w := r.weight(component.Name())
+		if w == 0 {
+			continue
+		}
+		score := component.Score(query, c) * w
+		breakdown.Components[component.Name()] = score
+		breakdown.Total += score
+	}
+	return breakdown
+}
+
+// Order 按排序方式返回结果的排列顺序，candidates与scores一一对应，得分相同时保持原顺序
+func Order(mode string, candidates []Candidate, scores []model.ScoreBreakdown) []int {
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		switch mode {
+		case SortTime:
+			ti, tj := candidates[i].Datetime, candidates[j].Datetime
+			if !ti.Equal(tj) {
+				// 没有时间的结果排在最后
+				return ti.After(tj)
+			}
+		case SortSource:
+			li, lj := candidates[i].PluginLevel, candidates[j].PluginLevel
+			if li != lj {
+				return li < lj
+			}
+		}
+		return scores[i].Total > scores[j].Total
+	})
+	return order
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// KeyedLimiter 按键（如客户端IP、关键词）分别限流的令牌桶集合
+// 长时间未使用的令牌桶会被定期清理，避免大量一次性客户端占用内存
+type KeyedLimiter struct {
+	rate        float64
+	burst       int
+	idleTimeout time.Duration
+	buckets     map[string]*TokenBucket
+	lastCleanup time.Time
+	mutex       sync.Mutex
+}
+
+// NewKeyedLimiter 创建按键限流器，rate为每秒补充的令牌数，burst为允许的突发请求数
+func NewKeyedLimiter(rate float64, burst int) *KeyedLimiter {
+	// 空闲超过桶被完全填满所需的时间后，重建的新桶与旧桶状态一致，可以安全清理
+	idleTimeout := time.Minute
+	if rate > 0 {
+		if fill := time.Duration(float64(burst+1) / rate * float64(time.Second)); fill > idleTimeout {
+			idleTimeout = fill
+		}
+	}
+
+	return &KeyedLimiter{
+		rate:        rate,
+		burst:       burst,
+		idleTimeout: idleTimeout,
+		buckets:     make(map[string]*TokenBucket),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Allow 尝试为指定键取出一个令牌，失败时返回需要等待的时间
+func (l *KeyedLimiter) Allow(key string) (bool, time.Duration) {
+	return l.bucket(key).Allow()
+}
+
+// bucket 获取或创建指定键的令牌桶
+func (l *KeyedLimiter) bucket(key string) *TokenBucket {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastCleanup) > l.idleTimeout {
+		l.cleanup(now)
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = NewTokenBucket(l.rate, l.burst)
+		l.buckets[key] = bucket
+	}
+	return bucket
+}
+
+// cleanup 清理空闲的令牌桶（调用方需持有锁）
+func (l *KeyedLimiter) cleanup(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.idleSince(now) > l.idleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastCleanup = now
+}
+
+// Len 返回当前跟踪的键数量
+func (l *KeyedLimiter) Len() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return len(l.buckets)
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket 令牌桶限流器，以固定速率补充令牌，允许不超过容量的突发请求
+type TokenBucket struct {
+	rate       float64 // 每秒补充的令牌数
+	capacity   float64 // 桶容量（允许的突发请求数）
+	tokens     float64
+	lastRefill time.Time
+	mutex      sync.Mutex
+}
+
+// NewTokenBucket 创建令牌桶，burst小于1时使用速率向上取整（至少为1）
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	capacity := float64(burst)
+	if capacity < 1 {
+		capacity = math.Max(1, math.Ceil(rate))
+	}
+	return &TokenBucket{
+		rate:       rate,
+		capacity:   capacity,
+		tokens:     capacity,
+		lastRefill: time.Now(),
+	}
+}
+
+// Allow 尝试取出一个令牌，失败时返回需要等待的时间
+func (b *TokenBucket) Allow() (bool, time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.lastRefill).Seconds()*b.rate)
+	b.lastRefill = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	// 计算补足一个令牌需要的时间
+	wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	return false, wait
+}
+
+// idleSince 返回距离上次取令牌的时间
+func (b *TokenBucket) idleSince(now time.Time) time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return now.Sub(b.lastRefill)
+}
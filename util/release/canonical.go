@@ -0,0 +1,84 @@
+package release
+
+import (
+	"strings"
+
+	"pansou/util/textnorm"
+)
+
+// subtitleAliases 字幕语言过滤条件的等价写法
+var subtitleAliases = map[string]string{
+	"zh-hans": "zh-hans", "chs": "zh-hans", "简中": "zh-hans", "简体": "zh-hans",
+	"zh-hant": "zh-hant", "cht": "zh-hant", "繁中": "zh-hant", "繁体": "zh-hant",
+	"zh": "zh", "chi": "zh", "中文": "zh", "中字": "zh",
+	"en": "en", "eng": "en", "英文": "en", "英字": "en",
+}
+
+// CanonicalResolution 将分辨率写法（如4k、uhd、1080i）转换为解析结果中的标准值，无法识别时返回空字符串
+func CanonicalResolution(s string) string {
+	text := textnorm.FoldNumbers(s)
+	for _, p := range resolutionPatterns {
+		if p.re.MatchString(text) {
+			return p.value
+		}
+	}
+	return ""
+}
+
+// CanonicalCodec 将视频编码写法（如x265、hevc）转换为解析结果中的标准值，无法识别时返回空字符串
+func CanonicalCodec(s string) string {
+	text := textnorm.FoldNumbers(s)
+	for _, p := range codecPatterns {
+		if p.re.MatchString(text) {
+			return p.value
+		}
+	}
+	return ""
+}
+
+// CanonicalHDR 将动态范围写法（如dv、dolby vision）转换为解析结果中的标准值，无法识别时返回空字符串
+func CanonicalHDR(s string) string {
+	text := textnorm.FoldNumbers(s)
+	for _, p := range hdrPatterns {
+		if p.re.MatchString(text) {
+			return p.value
+		}
+	}
+	if genericHDRPattern.MatchString(text) {
+		return "HDR"
+	}
+	return ""
+}
+
+// CanonicalSubtitle 将字幕语言写法（如chs、简中）转换为解析结果中的标准值，无法识别时返回空字符串
+func CanonicalSubtitle(s string) string {
+	return subtitleAliases[textnorm.Fold(strings.TrimSpace(s))]
+}
+
+// MatchHDR 判断解析出的动态范围是否满足标准值表示的条件，HDR表示任意HDR格式（不含DV和HLG）
+func MatchHDR(values []string, want string) bool {
+	for _, v := range values {
+		if v == want || (want == "HDR" && strings.HasPrefix(v, "HDR")) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchSubtitle 判断解析出的字幕语言是否满足标准值表示的条件，zh同时匹配简体和繁体
+func MatchSubtitle(values []string, want string) bool {
+	for _, v := range values {
+		if v == want || (want == "zh" && strings.HasPrefix(v, "zh-")) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchSeason 判断解析出的季号（或季范围）是否包含指定季
+func MatchSeason(start int, end int, season int) bool {
+	if end == 0 {
+		return start == season
+	}
+	return start <= season && season <= end
+}
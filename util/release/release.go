@@ -0,0 +1,357 @@
+package release
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"pansou/model"
+	"pansou/util/textnorm"
+)
+
+// maxContentRunes 解析内容时最多读取的字符数，内容过长时后面通常是其他资源或广告
+const maxContentRunes = 500
+
+// 分辨率规则，按顺序匹配，第一个命中的生效
+var resolutionPatterns = []struct {
+	re    *regexp.Regexp
+	value string
+}{
+	{regexp.MustCompile(`\b(?:4320[pi]|8k)\b`), "4320p"},
+	{regexp.MustCompile(`\b(?:2160[pi]|4k|uhd)\b`), "2160p"},
+	{regexp.MustCompile(`\b(?:1440[pi]|2k)\b`), "1440p"},
+	{regexp.MustCompile(`\b(?:1080[pi]|fhd)\b`), "1080p"},
+	{regexp.MustCompile(`\b720[pi]\b`), "720p"},
+	{regexp.MustCompile(`\b576[pi]\b`), "576p"},
+	{regexp.MustCompile(`\b480[pi]\b`), "480p"},
+}
+
+// 动态范围规则，全部匹配
+var hdrPatterns = []struct {
+	re    *regexp.Regexp
+	value string
+}{
+	{regexp.MustCompile(`\bhdr10(?:\+|plus)`), "HDR10+"},
+	{regexp.MustCompile(`\bhdr10\b`), "HDR10"},
+	{regexp.MustCompile(`\b(?:dv|dovi)\b|dolby\s*vision|杜比视界`), "DV"},
+	{regexp.MustCompile(`\bhlg\b`), "HLG"},
+}
+
+// genericHDRPattern 未注明具体标准的HDR
+var genericHDRPattern = regexp.MustCompile(`\bhdr\b`)
+
+// 视频编码规则，按顺序匹配，第一个命中的生效
+var codecPatterns = []struct {
+	re    *regexp.Regexp
+	value string
+}{
+	{regexp.MustCompile(`\b[hx]\.?265\b|\bhevc\b`), "H.265"},
+	{regexp.MustCompile(`\bav1\b`), "AV1"},
+	{regexp.MustCompile(`\bvp9\b`), "VP9"},
+	{regexp.MustCompile(`\b[hx]\.?264\b|\bavc\b`), "H.264"},
+}
+
+// 音频格式规则，全部匹配；格式后可以直接跟声道数，如DDP5.1、AAC2.0
+var audioPatterns = []struct {
+	re    *regexp.Regexp
+	value string
+}{
+	{regexp.MustCompile(`\batmos\b|全景声`), "Atmos"},
+	{regexp.MustCompile(`\btruehd(?:\d\.\d)?\b`), "TrueHD"},
+	{regexp.MustCompile(`\bdts[-. ]?hd\b`), "DTS-HD"},
+	{regexp.MustCompile(`\bdts[-: ]?x\b`), "DTS:X"},
+	{regexp.MustCompile(`\b(?:ddp|e-?ac-?3)(?:\d\.\d)?\b|\bdd\+`), "DDP"},
+	{regexp.MustCompile(`\bac-?3(?:\d\.\d)?\b`), "AC3"},
+	{regexp.MustCompile(`\baac(?:\d\.\d)?\b`), "AAC"},
+	{regexp.MustCompile(`\bflac(?:\d\.\d)?\b`), "FLAC"},
+}
+
+// plainDTSPattern 未注明具体格式的DTS
+var plainDTSPattern = regexp.MustCompile(`\bdts\b`)
+
+// 季集规则，输入已统一为小写并将中文数字转为阿拉伯数字
+var (
+	seasonEpisodePattern = regexp.MustCompile(`\bs(\d{1,2})\s*e(\d{1,4})(?:\s*-\s*(?:s\d{1,2})?e?(\d{1,4}))?\b`)
+	seasonRangePattern   = regexp.MustCompile(`\bs(\d{1,2})\s*-\s*s?(\d{1,2})\b`)
+	seasonCNPattern      = regexp.MustCompile(`第\s*(\d{1,2})(?:\s*[-~至到]\s*(\d{1,2}))?\s*季`)
+	seasonENPattern      = regexp.MustCompile(`\bseason\s*(\d{1,2})\b`)
+	seasonShortPattern   = regexp.MustCompile(`\bs(\d{1,2})\b`)
+	episodeCNPattern     = regexp.MustCompile(`第\s*(\d{1,4})(?:\s*[-~至到]\s*(\d{1,4}))?\s*[集话期]`)
+	episodeENPattern     = regexp.MustCompile(`\be(?:p|pisode)?\s*(\d{1,4})(?:\s*-\s*e?p?(\d{1,4}))?\b`)
+	episodeTotalPattern  = regexp.MustCompile(`全\s*(\d{1,4})\s*[集话期]`)
+	episodeUpdatePattern = regexp.MustCompile(`更新?至\s*第?\s*(\d{1,4})`)
+	completePattern      = regexp.MustCompile(`完结|全集|\bcomplete\b`)
+)
+
+// sizePattern 文件大小
+var sizePattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(tib|gib|mib|tb|gb|mb|t|g)(?:[^a-z0-9]|$)`)
+
+// sizeFilterPattern 过滤条件中的大小，允许用m表示MB
+var sizeFilterPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(tib|gib|mib|tb|gb|mb|t|g|m)$`)
+
+// yearPattern 独立出现的四位年份，排除1920x1080这类分辨率和2000mb这类大小
+var yearPattern = regexp.MustCompile(`(?:^|[^0-9a-z×])((?:19|20)\d{2})(?:[^0-9a-z×]|$)`)
+
+// 字幕语言规则，全部匹配
+var subtitlePatterns = []struct {
+	re    *regexp.Regexp
+	value string
+}{
+	{regexp.MustCompile(`简中|简体|简英|简日|\bchs\b`), "zh-hans"},
+	{regexp.MustCompile(`繁中|繁体|繁英|\bcht\b`), "zh-hant"},
+	{regexp.MustCompile(`中字|中文字幕|中英|双语|双字`), "zh"},
+	{regexp.MustCompile(`英字|英文字幕|中英|简英|繁英|双语|双字|\beng\b`), "en"},
+}
+
+// Parse 从标题和内容中解析资源信息，以标题为准，标题中没有的字段再从内容开头补充；没有识别出任何信息时返回nil
+func Parse(title string, content string) *model.ReleaseInfo {
+	info := parseText(title)
+	if content != "" {
+		if runes := []rune(content); len(runes) > maxContentRunes {
+			content = string(runes[:maxContentRunes])
+		}
+		fillMissing(&info, parseText(content))
+	}
+
+	if isEmpty(info) {
+		return nil
+	}
+	return &info
+}
+
+// parseText 解析一段文本
+func parseText(text string) model.ReleaseInfo {
+	var info model.ReleaseInfo
+	if text == "" {
+		return info
+	}
+	text = textnorm.FoldNumbers(text)
+
+	for _, p := range resolutionPatterns {
+		if p.re.MatchString(text) {
+			info.Resolution = p.value
+			break
+		}
+	}
+
+	info.HDR = parseHDR(text)
+
+	for _, p := range codecPatterns {
+		if p.re.MatchString(text) {
+			info.Codec = p.value
+			break
+		}
+	}
+
+	for _, p := range audioPatterns {
+		if p.re.MatchString(text) {
+			info.Audio = append(info.Audio, p.value)
+		}
+	}
+	if plainDTSPattern.MatchString(text) && !containsString(info.Audio, "DTS-HD") && !containsString(info.Audio, "DTS:X") {
+		info.Audio = append(info.Audio, "DTS")
+	}
+
+	parseSeasonEpisode(text, &info)
+	info.Size, info.SizeBytes = parseLargestSize(text)
+	info.Year = parseYear(text)
+
+	for _, p := range subtitlePatterns {
+		if p.re.MatchString(text) {
+			info.Subtitles = append(info.Subtitles, p.value)
+		}
+	}
+	return info
+}
+
+// parseHDR 解析动态范围，HDR10+不再重复记为HDR10，未注明具体标准的HDR记为HDR
+func parseHDR(text string) []string {
+	var values []string
+	for _, p := range hdrPatterns {
+		if p.value == "HDR10" && containsString(values, "HDR10+") {
+			continue
+		}
+		if p.re.MatchString(text) {
+			values = append(values, p.value)
+		}
+	}
+	if !containsString(values, "HDR10+") && !containsString(values, "HDR10") && genericHDRPattern.MatchString(text) {
+		values = append([]string{"HDR"}, values...)
+	}
+	return values
+}
+
+// parseSeasonEpisode 解析季集信息和是否完结
+func parseSeasonEpisode(text string, info *model.ReleaseInfo) {
+	if m := seasonEpisodePattern.FindStringSubmatch(text); m != nil {
+		info.Season = atoi(m[1])
+		info.Episode = atoi(m[2])
+		info.EpisodeEnd = atoi(m[3])
+	}
+
+	if info.Season == 0 {
+		if m := seasonRangePattern.FindStringSubmatch(text); m != nil {
+			info.Season, info.SeasonEnd = atoi(m[1]), atoi(m[2])
+		} else if m := seasonCNPattern.FindStringSubmatch(text); m != nil {
+			info.Season, info.SeasonEnd = atoi(m[1]), atoi(m[2])
+		} else if m := seasonENPattern.FindStringSubmatch(text); m != nil {
+			info.Season = atoi(m[1])
+		} else if m := seasonShortPattern.FindStringSubmatch(text); m != nil {
+			info.Season = atoi(m[1])
+		}
+	}
+
+	if info.Episode == 0 {
+		if m := episodeTotalPattern.FindStringSubmatch(text); m != nil {
+			// 全12集：第1-12集，且已完结
+			info.Episode, info.EpisodeEnd = 1, atoi(m[1])
+			info.Complete = true
+		} else if m := episodeUpdatePattern.FindStringSubmatch(text); m != nil {
+			// 更新至第12集：第1-12集
+			info.Episode, info.EpisodeEnd = 1, atoi(m[1])
+		} else if m := episodeCNPattern.FindStringSubmatch(text); m != nil {
+			info.Episode, info.EpisodeEnd = atoi(m[1]), atoi(m[2])
+		} else if m := episodeENPattern.FindStringSubmatch(text); m != nil {
+			info.Episode, info.EpisodeEnd = atoi(m[1]), atoi(m[2])
+		}
+	}
+
+	// 范围的起止相同或结束小于开始时不作为范围
+	if info.SeasonEnd <= info.Season {
+		info.SeasonEnd = 0
+	}
+	if info.EpisodeEnd <= info.Episode {
+		info.EpisodeEnd = 0
+	}
+
+	if completePattern.MatchString(text) {
+		info.Complete = true
+	}
+}
+
+// parseLargestSize 解析文本中最大的文件大小（多个大小时通常较大的是合集总大小）
+func parseLargestSize(text string) (string, int64) {
+	var size string
+	var largest int64
+	for _, m := range sizePattern.FindAllStringSubmatch(text, -1) {
+		if bytes, ok := sizeBytes(m[1], m[2]); ok && bytes > largest {
+			largest = bytes
+			size = m[1] + displayUnit(m[2])
+		}
+	}
+	return size, largest
+}
+
+// sizeUnits 大小单位对应的字节数
+var sizeUnits = map[string]float64{
+	"t": 1 << 40, "tb": 1 << 40, "tib": 1 << 40,
+	"g": 1 << 30, "gb": 1 << 30, "gib": 1 << 30,
+	"mb": 1 << 20, "mib": 1 << 20,
+}
+
+// sizeBytes 将数值和单位转换为字节数
+func sizeBytes(number string, unit string) (int64, bool) {
+	value, err := strconv.ParseFloat(number, 64)
+	multiplier, ok := sizeUnits[strings.ToLower(unit)]
+	if err != nil || !ok || value <= 0 {
+		return 0, false
+	}
+	return int64(value * multiplier), true
+}
+
+// displayUnit 单位的展示形式
+func displayUnit(unit string) string {
+	switch strings.ToLower(unit) {
+	case "t", "tb", "tib":
+		return "TB"
+	case "g", "gb", "gib":
+		return "GB"
+	default:
+		return "MB"
+	}
+}
+
+// ParseSize 解析大小字符串（如"10GB"、"500m"），用于大小过滤条件
+func ParseSize(s string) (int64, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, false
+	}
+	m := sizeFilterPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	if m[2] == "m" {
+		m[2] = "mb"
+	}
+	return sizeBytes(m[1], m[2])
+}
+
+// parseYear 解析第一个合理的年份（不晚于明年）
+func parseYear(text string) int {
+	maxYear := time.Now().Year() + 1
+	for _, m := range yearPattern.FindAllStringSubmatch(text, -1) {
+		if year := atoi(m[1]); year >= 1900 && year <= maxYear {
+			return year
+		}
+	}
+	return 0
+}
+
+// fillMissing 用other中的字段补充info中缺失的字段
+func fillMissing(info *model.ReleaseInfo, other model.ReleaseInfo) {
+	if info.Resolution == "" {
+		info.Resolution = other.Resolution
+	}
+	if len(info.HDR) == 0 {
+		info.HDR = other.HDR
+	}
+	if info.Codec == "" {
+		info.Codec = other.Codec
+	}
+	if len(info.Audio) == 0 {
+		info.Audio = other.Audio
+	}
+	if info.Season == 0 {
+		info.Season, info.SeasonEnd = other.Season, other.SeasonEnd
+	}
+	if info.Episode == 0 {
+		info.Episode, info.EpisodeEnd = other.Episode, other.EpisodeEnd
+	}
+	if !info.Complete {
+		info.Complete = other.Complete
+	}
+	if info.SizeBytes == 0 {
+		info.Size, info.SizeBytes = other.Size, other.SizeBytes
+	}
+	if info.Year == 0 {
+		info.Year = other.Year
+	}
+	if len(info.Subtitles) == 0 {
+		info.Subtitles = other.Subtitles
+	}
+}
+
+// isEmpty 判断是否没有识别出任何信息
+func isEmpty(info model.ReleaseInfo) bool {
+	return info.Resolution == "" && len(info.HDR) == 0 && info.Codec == "" && len(info.Audio) == 0 &&
+		info.Season == 0 && info.Episode == 0 && !info.Complete && info.SizeBytes == 0 &&
+		info.Year == 0 && len(info.Subtitles) == 0
+}
+
+// atoi 解析可选的数字分组，空字符串返回0
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// containsString 判断字符串切片是否包含指定值
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package release
+
+import (
+	"reflect"
+	"testing"
+
+	"pansou/model"
+)
+
+// gb 与解析时相同的方式将GB数转换为字节数
+func gb(value float64) int64 {
+	return int64(value * (1 << 30))
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		title   string
+		content string
+		want    *model.ReleaseInfo
+	}{
+		{
+			title: "4K HDR 杜比视界 S02 全12集 58.3GB",
+			want: &model.ReleaseInfo{
+				Resolution: "2160p", HDR: []string{"HDR", "DV"}, Season: 2, Episode: 1, EpisodeEnd: 12,
+				Complete: true, Size: "58.3GB", SizeBytes: gb(58.3),
+			},
+		},
+		{
+			title: "The.Mandalorian.S01E01-E08.2160p.DSNP.WEB-DL.DDP5.1.Atmos.DV.HDR10+.H.265",
+			want: &model.ReleaseInfo{
+				Resolution: "2160p", HDR: []string{"HDR10+", "DV"}, Codec: "H.265", Audio: []string{"Atmos", "DDP"},
+				Season: 1, Episode: 1, EpisodeEnd: 8,
+			},
+		},
+		// 音频格式后直接跟声道数
+		{title: "流浪地球2 WEB-DL AAC2.0 H264", want: &model.ReleaseInfo{Codec: "H.264", Audio: []string{"AAC"}}},
+		// 季范围
+		{title: "老友记 S01-S10 完结", want: &model.ReleaseInfo{Season: 1, SeasonEnd: 10, Complete: true}},
+		{title: "老友记 第一至十季", want: &model.ReleaseInfo{Season: 1, SeasonEnd: 10}},
+		{title: "权力的游戏 第8季 更新至第6集", want: &model.ReleaseInfo{Season: 8, Episode: 1, EpisodeEnd: 6}},
+		{title: "Breaking Bad Season 5 EP16", want: &model.ReleaseInfo{Season: 5, Episode: 16}},
+		// 1920x1080和2000MB不是年份
+		{title: "某纪录片 1920x1080 2000MB", want: &model.ReleaseInfo{Size: "2000MB", SizeBytes: 2000 << 20}},
+		{title: "流浪地球 2019 1080P 国语中字", want: &model.ReleaseInfo{Resolution: "1080p", Year: 2019, Subtitles: []string{"zh"}}},
+		// 多个大小时取最大的
+		{title: "合集 单集1.2G 总计35.6G", want: &model.ReleaseInfo{Size: "35.6GB", SizeBytes: gb(35.6)}},
+		// 标题中没有的字段从内容补充，标题中已有的字段以标题为准
+		{
+			title:   "三体 1080p",
+			content: "4K版本见评论 | 全30集 简英双字 x264",
+			want: &model.ReleaseInfo{
+				Resolution: "1080p", Codec: "H.264", Episode: 1, EpisodeEnd: 30, Complete: true,
+				Subtitles: []string{"zh-hans", "zh", "en"},
+			},
+		},
+		{title: "流浪地球", content: "", want: nil},
+	}
+
+	for _, tc := range tests {
+		if got := Parse(tc.title, tc.content); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Parse(%q, %q) = %+v\n期望 %+v", tc.title, tc.content, got, tc.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+		ok    bool
+	}{
+		{"10GB", 10 << 30, true},
+		{"500m", 500 << 20, true},
+		{" 1.5 TB ", int64(1.5 * (1 << 40)), true},
+		{"10", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tc := range tests {
+		if got, ok := ParseSize(tc.input); got != tc.want || ok != tc.ok {
+			t.Errorf("ParseSize(%q) = %d, %t，期望%d, %t", tc.input, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"4K HDR 杜比视界 S02 全12集 58.3GB", "4K HDR 杜比视界 S02 全12集 58.3GB"},
+		{"【4K】【完结】流浪地球2 2023 2160p HDR", "流浪地球2"},
+		{"[电影] 三体 第一季 全30集 国语中字", "三体"},
+		{"【流浪地球】1080p", "流浪地球"},
+		{"The.Wandering.Earth.2019.1080p.BluRay.x264", "The Wandering Earth"},
+		{"复仇者联盟4：终局之战 蓝光 1080P", "复仇者联盟4：终局之战"},
+		{"Breaking Bad S05E16 720p", "Breaking Bad"},
+		{"  怪奇物语  ", "怪奇物语"},
+	}
+	for _, tc := range tests {
+		if got := Title(tc.input); got != tc.want {
+			t.Errorf("Title(%q) = %q，期望%q", tc.input, got, tc.want)
+		}
+	}
+}
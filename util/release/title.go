@@ -0,0 +1,90 @@
+package release
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"pansou/util/textnorm"
+)
+
+// titleCutPattern 作品名之后常见的资源标记，标题从第一个标记处截断；输入已统一为小写半角。
+// 第一个分组是英文和数字标记（前面不能紧跟字母或数字），第二个分组是中文标记
+var titleCutPattern = regexp.MustCompile(`(?:^|[^0-9a-z])((?:19|20)\d{2}(?:[^0-9a-z]|$)|\d{3,4}[pi]\b|[248]k\b|uhd\b|s\d{1,2}(?:\s*e\d{1,4})?\b|season\s*\d|ep?\d{1,4}\b|hdr|dovi\b|[hx]\.?26[45]\b|hevc\b|web-?dl\b|web-?rip\b|blu-?ray\b|remux\b|bdrip\b|hdtv\b|\d+(?:\.\d+)?\s*(?:tb|gb|mb)\b)` +
+	`|(第\s*[0-9零〇一二两三四五六七八九十百]+\s*[季部集话期]|全\s*[0-9零〇一二两三四五六七八九十百]+\s*[集话期]|更新?至|完结|全集|杜比视界|[简繁]中|中字|国语|粤语|双语|蓝光|高清|超清)`)
+
+// titleTagPattern 标题开头方括号中常见的分类和推广标签
+var titleTagPattern = regexp.MustCompile(`电影|剧集|电视剧|动漫|动画|纪录片|综艺|国漫|日漫|美剧|韩剧|日剧|英剧|国产剧|合集|网盘|资源|更新|推荐|独家|首发|精品|热播|新片`)
+
+// maxTagRunes 方括号中超过该长度的内容视为作品名而不是标签
+const maxTagRunes = 8
+
+// leadingBracketPattern 标题开头的方括号
+var leadingBracketPattern = regexp.MustCompile(`^\s*(?:【([^】]*)】|\[([^\]]*)\]|［([^］]*)］)`)
+
+// Title 从资源标题中提取作品名：去掉开头的标签（如【4K】【完结】），
+// 并在年份、分辨率、季集、编码、大小等第一个资源标记处截断；无法提取时返回去掉首尾空白的原标题
+func Title(title string) string {
+	rest := strings.TrimSpace(title)
+
+	// 去掉开头的标签，作品名本身在括号中时保留括号内容
+	for {
+		m := leadingBracketPattern.FindStringSubmatchIndex(rest)
+		if m == nil {
+			break
+		}
+		var content string
+		for i := 2; i < len(m); i += 2 {
+			if m[i] >= 0 {
+				content = rest[m[i]:m[i+1]]
+			}
+		}
+		after := strings.TrimSpace(rest[m[1]:])
+		if after != "" && isTitleTag(content) {
+			rest = after
+			continue
+		}
+		rest = strings.TrimSpace(content + " " + after)
+		break
+	}
+
+	// Fold逐字符转换，截断位置按字符数对应回原文
+	folded := textnorm.Fold(rest)
+	if cut := cutIndex(folded); cut >= 0 {
+		rest = string([]rune(rest)[:utf8.RuneCountInString(folded[:cut])])
+	}
+
+	// 英文资源名常用点和下划线分隔单词
+	rest = strings.NewReplacer(".", " ", "_", " ").Replace(rest)
+	rest = strings.TrimFunc(rest, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+	rest = strings.Join(strings.Fields(rest), " ")
+
+	if rest == "" {
+		return strings.TrimSpace(title)
+	}
+	return rest
+}
+
+// isTitleTag 判断标题开头方括号中的内容是否是标签（如4K、完结、电影）而不是作品名
+func isTitleTag(content string) bool {
+	folded := textnorm.Fold(strings.TrimSpace(content))
+	if utf8.RuneCountInString(folded) > maxTagRunes {
+		return false
+	}
+	return folded == "" || cutIndex(folded) >= 0 || titleTagPattern.MatchString(folded)
+}
+
+// cutIndex 返回第一个资源标记的字节位置，没有时返回-1
+func cutIndex(folded string) int {
+	m := titleCutPattern.FindStringSubmatchIndex(folded)
+	if m == nil {
+		return -1
+	}
+	if m[2] >= 0 {
+		return m[2]
+	}
+	return m[4]
+}
@@ -0,0 +1,51 @@
+package textnorm
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// episodePattern 季集标记的匹配规则，统一转换为s{季}和e{集}
+type episodePattern struct {
+	re     *regexp.Regexp
+	season int // 季号所在的分组，0表示没有
+	ep     int // 集号所在的分组，0表示没有
+}
+
+// episodePatterns 常见的季集写法，输入已经过Fold和中文数字转换
+var episodePatterns = []episodePattern{
+	{regexp.MustCompile(`\bs(\d{1,2})\s*e(\d{1,4})\b`), 1, 2},      // S01E02
+	{regexp.MustCompile(`\bseason\s*(\d{1,2})\b`), 1, 0},           // Season 1
+	{regexp.MustCompile(`第\s*(\d{1,2})\s*季`), 1, 0},                // 第1季
+	{regexp.MustCompile(`\bs(\d{1,2})\b`), 1, 0},                   // S01
+	{regexp.MustCompile(`第\s*(\d{1,4})\s*[集话期]`), 0, 1},            // 第2集
+	{regexp.MustCompile(`\b(?:ep|episode|e)\s*(\d{1,4})\b`), 0, 1}, // EP02
+}
+
+// replaceEpisodeMarks 将季集标记统一为s{季}e{集}形式，去掉前导零，如"S01E02"和"第1季第2集"都转为"s1e2"
+func replaceEpisodeMarks(s string) string {
+	for _, p := range episodePatterns {
+		p := p
+		s = p.re.ReplaceAllStringFunc(s, func(match string) string {
+			groups := p.re.FindStringSubmatch(match)
+			result := ""
+			if p.season > 0 {
+				result += "s" + trimLeadingZeros(groups[p.season])
+			}
+			if p.ep > 0 {
+				result += "e" + trimLeadingZeros(groups[p.ep])
+			}
+			return result
+		})
+	}
+	return s
+}
+
+// trimLeadingZeros 去掉数字的前导零
+func trimLeadingZeros(digits string) string {
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return digits
+	}
+	return strconv.Itoa(n)
+}
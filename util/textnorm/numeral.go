@@ -0,0 +1,81 @@
+package textnorm
+
+import (
+	"strconv"
+	"strings"
+)
+
+// chineseDigits 中文数字对应的值
+var chineseDigits = map[rune]int{
+	'零': 0, '〇': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4,
+	'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+// chineseUnits 中文数字单位对应的值
+var chineseUnits = map[rune]int{'十': 10, '百': 100, '千': 1000}
+
+// isChineseNumeral 判断字符是否是中文数字或单位
+func isChineseNumeral(r rune) bool {
+	if _, ok := chineseDigits[r]; ok {
+		return true
+	}
+	_, ok := chineseUnits[r]
+	return ok
+}
+
+// replaceChineseNumerals 将文本中连续的中文数字转换为阿拉伯数字，如"第十二集"转为"第12集"、"二〇二三"转为"2023"
+func replaceChineseNumerals(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(runes); {
+		if !isChineseNumeral(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(runes) && isChineseNumeral(runes[j]) {
+			j++
+		}
+		b.WriteString(parseChineseNumber(runes[i:j]))
+		i = j
+	}
+	return b.String()
+}
+
+// parseChineseNumber 解析一段中文数字，不含单位时按位读出（如年份）
+func parseChineseNumber(runes []rune) string {
+	hasUnit := false
+	for _, r := range runes {
+		if _, ok := chineseUnits[r]; ok {
+			hasUnit = true
+			break
+		}
+	}
+
+	if !hasUnit {
+		var b strings.Builder
+		for _, r := range runes {
+			b.WriteByte(byte('0' + chineseDigits[r]))
+		}
+		return b.String()
+	}
+
+	total, number := 0, 0
+	for _, r := range runes {
+		if digit, ok := chineseDigits[r]; ok {
+			number = digit
+			continue
+		}
+		unit := chineseUnits[r]
+		if number == 0 {
+			// "十二"中省略的"一"
+			number = 1
+		}
+		total += number * unit
+		number = 0
+	}
+	return strconv.Itoa(total + number)
+}
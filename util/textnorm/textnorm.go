@@ -0,0 +1,172 @@
+package textnorm
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// Fold 统一字符形式：全角转半角、转小写、繁体转简体
+func Fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		b.WriteRune(foldRune(r))
+	}
+	return b.String()
+}
+
+// foldRune 统一单个字符的形式
+func foldRune(r rune) rune {
+	switch {
+	case r == '　':
+		// 全角空格
+		return ' '
+	case r >= '！' && r <= '～':
+		// 全角ASCII字符
+		r -= 0xFEE0
+	}
+	if simplified, ok := traditionalToSimplified[r]; ok {
+		return simplified
+	}
+	return unicode.ToLower(r)
+}
+
+// FoldNumbers 统一字符形式后将中文数字转换为阿拉伯数字，保留空白和标点
+func FoldNumbers(s string) string {
+	return replaceChineseNumerals(Fold(s))
+}
+
+// Key 生成与匹配规则一致的关键词键，用于缓存键等需要判断两个关键词是否等价的场景：
+// 在Normalize的基础上附加带符号的词，"复仇者联盟4"和"复仇者联盟 4"的键相同，"C++"、"C#"和"C"的键不同；
+// 只有标点和符号的关键词标准化后为空，使用合并空白后的原文
+func Key(s string) string {
+	normalized := Normalize(s)
+	if normalized == "" {
+		return strings.Join(strings.Fields(Fold(s)), " ")
+	}
+	if terms := SymbolTerms(s); len(terms) > 0 {
+		return normalized + "|" + strings.Join(terms, ",")
+	}
+	return normalized
+}
+
+// symbolTermPattern 字母或数字后紧跟区分含义的符号的词，如C++、C#、F#
+var symbolTermPattern = regexp.MustCompile(`[\p{L}\p{N}]+[+#]+`)
+
+// SymbolTerms 返回关键词中带有区分含义的符号的词（统一字符形式后），Normalize会去掉这些符号，
+// 匹配时除比较标准化形式外，还需检查Fold后的文本包含这些词，避免"C++"匹配到只含"C"的文本
+func SymbolTerms(s string) []string {
+	return symbolTermPattern.FindAllString(Fold(s), -1)
+}
+
+// ContainsAll 判断Fold后的text是否包含所有terms
+func ContainsAll(folded string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(folded, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// Normalize 将文本转换为用于匹配的标准形式：
+// 全角转半角、转小写、繁体转简体，中文数字转阿拉伯数字，季集标记统一为s{季}e{集}，
+// 最后去掉空白和标点，只保留字母、数字和汉字
+func Normalize(s string) string {
+	s = replaceEpisodeMarks(FoldNumbers(s))
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Contains 判断标准化后的text是否包含标准化后的keyword
+func Contains(text string, keyword string) bool {
+	return strings.Contains(Normalize(text), Normalize(keyword))
+}
+
+// Tokens 按空白和标点切分为词，每个词都转换为标准形式
+func Tokens(s string) []string {
+	fields := strings.FieldsFunc(Fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if token := Normalize(field); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// gb2312InitialBounds GB2312一级汉字按拼音排序，各声母首字的编码
+var gb2312InitialBounds = []struct {
+	code    int
+	initial byte
+}{
+	{0xB0A1, 'a'}, {0xB0C5, 'b'}, {0xB2C1, 'c'}, {0xB4EE, 'd'}, {0xB6EA, 'e'},
+	{0xB7A2, 'f'}, {0xB8C1, 'g'}, {0xB9FE, 'h'}, {0xBBF7, 'j'}, {0xBFA6, 'k'},
+	{0xC0AC, 'l'}, {0xC2E8, 'm'}, {0xC4C3, 'n'}, {0xC5B6, 'o'}, {0xC5BE, 'p'},
+	{0xC6DA, 'q'}, {0xC8BB, 'r'}, {0xC8F6, 's'}, {0xCBFA, 't'}, {0xCDDA, 'w'},
+	{0xCEF4, 'x'}, {0xD1B9, 'y'}, {0xD4D1, 'z'},
+}
+
+// gb2312Level1End GB2312一级汉字的最后一个编码
+const gb2312Level1End = 0xD7F9
+
+// PinyinInitials 返回文本的拼音首字母，字母和数字原样保留（转小写），
+// 只支持GB2312一级常用汉字，其他字符忽略
+func PinyinInitials(s string) string {
+	encoder := simplifiedchinese.GBK.NewEncoder()
+	var b strings.Builder
+	for _, r := range Fold(s) {
+		if r <= unicode.MaxASCII {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		if !unicode.Is(unicode.Han, r) {
+			continue
+		}
+
+		encoded, err := encoder.String(string(r))
+		if err != nil || len(encoded) != 2 {
+			continue
+		}
+		code := int(encoded[0])<<8 | int(encoded[1])
+		if code < gb2312InitialBounds[0].code || code > gb2312Level1End {
+			continue
+		}
+		for i := len(gb2312InitialBounds) - 1; i >= 0; i-- {
+			if code >= gb2312InitialBounds[i].code {
+				b.WriteByte(gb2312InitialBounds[i].initial)
+				break
+			}
+		}
+	}
+	return b.String()
+}
+
+// IsPinyinQuery 判断查询是否可能是拼音首字母（至少两个ASCII字母，不含其他字符）
+func IsPinyinQuery(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
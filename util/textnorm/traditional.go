@@ -0,0 +1,34 @@
+package textnorm
+
+// traditionalPairs 常用繁体字与简体字对照，每两个字符为一组：繁体在前，简体在后
+// 只收录影视、资源标题中常见的字，用于匹配而非完整转换
+const traditionalPairs = "" +
+	"萬万與与專专業业叢丛東东絲丝兩两嚴严喪丧個个豐丰臨临為为麗丽舉举麼么義义烏乌樂乐喬乔習习鄉乡書书買买亂乱爭争於于虧亏雲云亞亚產产親亲億亿僅仅從从倉仓儀仪們们價价" +
+	"眾众優优會会傘伞偉伟傳传傷伤倫伦偽伪體体餘余俠侠侶侣偵侦側侧僑侨兒儿黨党蘭兰關关興兴養养獸兽內内岡冈冊册寫写軍军農农馮冯沖冲決决況况凍冻淨净涼凉減减湊凑幾几鳳凤" +
+	"憑凭凱凯擊击劃划劉刘則则剛刚創创刪删別别劑剂劍剑劇剧勸劝辦办務务動动勵励勁劲勞劳勢势匯汇區区醫医華华協协單单賣卖盧卢衛卫卻却廠厂廳厅歷历厲厉壓压縣县參参雙双發发" +
+	"變变敘叙疊叠葉叶號号嘆叹歎叹嚇吓嗎吗啟启啓启吳吴呂吕聽听員员週周響响問问喚唤團团園园圍围國国圖图圓圆聖圣場场壞坏塊块堅坚壇坛墳坟墜坠壯壮聲声殼壳壺壶處处備备復复" +
+	"複复夠够頭头夾夹奪夺奮奋獎奖妝妆婦妇媽妈嬌娇孫孙學学寧宁寶宝實实寵宠審审憲宪寬宽賓宾對对尋寻導导壽寿將将爾尔塵尘嘗尝堯尧屍尸層层屬属歲岁豈岂嶼屿峽峡崗岗嵐岚島岛" +
+	"嶺岭嶽岳幣币帥帅師师帳帐帶带幫帮幹干並并廣广莊庄慶庆廬庐庫库應应廟庙廢废龐庞開开棄弃張张彌弥彎弯彈弹強强歸归當当錄录彙汇徹彻徑径徵征後后憶忆懷怀態态總总戀恋愛爱" +
+	"恆恒懇恳惡恶惱恼悅悦懸悬驚惊慘惨憤愤慣惯懶懒懼惧戰战戲戏戶户執执擴扩掃扫揚扬擾扰撫抚拋抛搶抢護护報报擔担擬拟擁拥擇择擋挡據据擠挤撈捞捲卷掛挂擲掷揮挥損损搖摇擺摆" +
+	"攜携攝摄攤摊撐撑敵敌數数齋斋斬斩斷断時时曠旷晝昼顯显晉晋曬晒曉晓暈晕暫暂術术機机殺杀雜杂權权條条來来楊杨極极構构槍枪棟栋標标樓楼樣样橋桥樹树檔档歡欢歐欧殘残氣气" +
+	"漢汉湯汤溝沟沒没淚泪潑泼澤泽潔洁灑洒測测濃浓湧涌濤涛滿满濾滤濫滥濕湿灣湾漲涨潛潜潤润灘滩滯滞滾滚滲渗漁渔漸渐濟济滅灭點点煉炼熱热煩烦燒烧燈灯燦灿營营爐炉爛烂爺爷" +
+	"牆墙狀状猶犹獨独獄狱獅狮獲获獵猎瑪玛環环現现瓊琼畢毕畫画療疗瘋疯盡尽監监盤盘睜睁瞞瞒礦矿碼码磚砖確确礎础禮礼禍祸離离種种積积稱称穩稳窩窝窮穷竄窜競竞筆笔築筑簡简" +
+	"簽签籌筹類类糧粮糾纠紀纪約约紅红紋纹納纳純纯紗纱紙纸級级紛纷紡纺細细終终紹绍組组結结絕绝給给統统經经綁绑綜综綠绿維维網网緊紧緒绪線线締缔編编緣缘練练緯纬縮缩績绩" +
+	"織织繞绕繪绘繼继纏缠續续罰罚罷罢羅罗聞闻聯联聰聪職职膽胆腦脑腫肿腳脚脫脱臉脸臘腊臟脏艦舰艱艰藝艺節节範范薦荐莖茎蒼苍蓋盖蘆芦蘇苏薩萨蘋苹藍蓝藥药蟲虫蝦虾蠍蝎螢萤" +
+	"蠟蜡蠻蛮衝冲補补裝装裡里裏里製制褲裤襲袭見见規规視视覽览覺觉觀观觸触訂订計计訊讯討讨訓训記记講讲許许論论設设訪访證证評评識识詐诈詞词試试詩诗詭诡詠咏誠诚話话該该" +
+	"詳详語语誤误說说請请諸诸課课誰谁調调談谈謀谋謝谢謠谣謎谜謙谦譜谱譯译議议讀读讓让豬猪貓猫貝贝負负財财貢贡貧贫貨货販贩貫贯責责貴贵貸贷費费貼贴貿贸賀贺資资賊贼賠赔" +
+	"賞赏賜赐賢贤賤贱賦赋質质賬账賭赌購购贈赠贊赞趙赵趕赶躍跃車车軌轨軟软載载轉转輪轮輛辆輝辉輩辈輯辑輸输轎轿辭辞邊边達达遷迁過过運运還还這这進进遠远違违連连遲迟適适" +
+	"選选遺遗遼辽郵邮鄰邻醜丑釋释鐵铁鈔钞鉛铅銀银銅铜銷销鋒锋鋼钢錢钱錯错錶表鍋锅鍵键鎖锁鏡镜鐘钟鑽钻長长門门閃闪閉闭閒闲間间閣阁閱阅闆板闊阔陣阵陰阴陳陈陸陆陽阳隊队" +
+	"際际隨随險险隱隐隻只雞鸡雖虽雛雏難难電电霧雾靜静頁页頂顶項项順顺須须預预頑顽頒颁頓顿頗颇領领頻频題题額额顏颜願愿顧顾風风飛飞飯饭飲饮飽饱餓饿館馆馬马駕驾駛驶騎骑" +
+	"騙骗驅驱驗验髮发鬥斗鬧闹魚鱼鮮鲜鳥鸟鳴鸣鴨鸭鴻鸿鵝鹅鷹鹰麥麦黃黄齊齐齒齿龍龙龜龟遊游誌志託托鬆松麵面僕仆闖闯殭僵鬱郁纖纤憂忧傑杰勝胜囉啰蹤踪靈灵驢驴巖岩嘯啸壩坝" +
+	"曆历穌稣榮荣癡痴鑒鉴鑑鉴讚赞瀏浏綫线鬍胡蘿萝蔔卜"
+
+// traditionalToSimplified 繁体字到简体字的映射
+var traditionalToSimplified = func() map[rune]rune {
+	runes := []rune(traditionalPairs)
+	m := make(map[rune]rune, len(runes)/2)
+	for i := 0; i+1 < len(runes); i += 2 {
+		m[runes[i]] = runes[i+1]
+	}
+	return m
+}()